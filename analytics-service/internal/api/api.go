@@ -0,0 +1,149 @@
+package api
+
+import (
+	"analytics-service/internal/stats"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const queryTimeout = 4 * time.Second
+
+// Handler — read-only API аналитики. /stats/* доступны только администраторам,
+// /me/stats — creator'ам и площадкам по своим данным. Пользователь и роль
+// приходят от gateway в X-User-ID и X-User-Role, роль admin для /stats/*
+// проверяет политика доступа gateway (gateway/internal/routing/routes.json).
+type Handler struct {
+	store *stats.Store
+}
+
+func NewHandler(store *stats.Store) *Handler {
+	return &Handler{store: store}
+}
+
+// Register регистрирует ручки /stats/* в mux
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /stats/funnel", h.Funnel)
+	mux.HandleFunc("GET /stats/registrations", h.Registrations)
+	mux.HandleFunc("GET /stats/top-categories", h.TopCategories)
+	mux.HandleFunc("GET /stats/top-cities", h.TopCities)
+	mux.HandleFunc("GET /stats/event-lifecycle", h.EventLifecycle)
+	mux.HandleFunc("GET /me/stats", h.MyStats)
+}
+
+// statsResponse — общий ответ: параметры запроса и ряды
+type statsResponse struct {
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	Granularity string      `json:"granularity"`
+	Data        interface{} `json:"data"`
+}
+
+func (h *Handler) Funnel(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func(ctx context.Context, p stats.Params) (interface{}, error) {
+		return h.store.Funnel(ctx, p)
+	})
+}
+
+func (h *Handler) Registrations(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func(ctx context.Context, p stats.Params) (interface{}, error) {
+		return h.store.Registrations(ctx, p)
+	})
+}
+
+func (h *Handler) TopCategories(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func(ctx context.Context, p stats.Params) (interface{}, error) {
+		return h.store.TopCategories(ctx, p)
+	})
+}
+
+func (h *Handler) TopCities(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func(ctx context.Context, p stats.Params) (interface{}, error) {
+		return h.store.TopCities(ctx, p)
+	})
+}
+
+// EventLifecycle — время до первой заявки и до матча по когортам публикации
+func (h *Handler) EventLifecycle(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func(ctx context.Context, p stats.Params) (interface{}, error) {
+		return h.store.EventLifecycle(ctx, p)
+	})
+}
+
+// MyStats — статистика вызывающего пользователя; чужой user_id передать нельзя
+func (h *Handler) MyStats(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.Header.Get("X-User-ID"))
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing user context")
+		return
+	}
+	role := r.Header.Get("X-User-Role")
+	if role != "creator" && role != "venue" {
+		writeError(w, http.StatusForbidden, "ACCESS_DENIED", "Statistics are available to creators and venues only")
+		return
+	}
+
+	h.serve(w, r, func(ctx context.Context, p stats.Params) (interface{}, error) {
+		return h.store.UserStats(ctx, userID, role, p)
+	})
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, query func(context.Context, stats.Params) (interface{}, error)) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	p, err := stats.ParseParams(q.Get("from"), q.Get("to"), q.Get("granularity"), limit)
+	if err != nil {
+		writeParamsError(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	data, err := query(ctx, p)
+	if err != nil {
+		log.Printf("[api] %s failed: %v", r.URL.Path, err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to load statistics")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, statsResponse{
+		From:        p.From.Format("2006-01-02"),
+		To:          p.To.Format("2006-01-02"),
+		Granularity: p.Granularity,
+		Data:        data,
+	})
+}
+
+func writeParamsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, stats.ErrInvalidDate):
+		writeError(w, http.StatusBadRequest, "INVALID_DATE", "Dates must be in YYYY-MM-DD format")
+	case errors.Is(err, stats.ErrInvalidRange):
+		writeError(w, http.StatusBadRequest, "INVALID_RANGE", "from must not be after to, range is limited to 3 years")
+	case errors.Is(err, stats.ErrInvalidGranularity):
+		writeError(w, http.StatusBadRequest, "INVALID_GRANULARITY", "granularity must be one of day, week, month")
+	default:
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	}
+}
+
+// writeError отдаёт ошибку в общем для сервисов формате {"errors":[...]}
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("[api] encode response: %v", err)
+	}
+}
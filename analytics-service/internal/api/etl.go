@@ -0,0 +1,89 @@
+package api
+
+import (
+	"analytics-service/internal/etl"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultRunsLimit = 20
+	maxRunsLimit     = 100
+)
+
+// ETLHandler — служебные ручки управления ETL внутри сети сервисов
+type ETLHandler struct {
+	runner *etl.Runner
+}
+
+func NewETLHandler(runner *etl.Runner) *ETLHandler {
+	return &ETLHandler{runner: runner}
+}
+
+func (h *ETLHandler) Register(mux *http.ServeMux) {
+	// Роль admin проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	mux.HandleFunc("POST /run-etl", h.StartRun)
+	mux.HandleFunc("GET /etl/runs", h.ListRuns)
+	mux.HandleFunc("GET /etl/runs/{id}", h.GetRun)
+}
+
+// StartRun ставит ETL в работу и сразу отвечает 202 с id запуска.
+// ?kind=incremental — догрузка по водяным знакам, по умолчанию полная перестройка.
+func (h *ETLHandler) StartRun(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = etl.KindFull
+	}
+
+	log.Printf("/run-etl triggered manually (%s)", kind)
+	run, err := h.runner.Start(kind, "manual")
+	switch {
+	case errors.Is(err, etl.ErrInvalidKind):
+		writeError(w, http.StatusBadRequest, "INVALID_ETL_KIND", "kind must be full or incremental")
+		return
+	case errors.Is(err, etl.ErrRunInProgress):
+		writeError(w, http.StatusConflict, "ETL_RUN_IN_PROGRESS", "Another ETL run is in progress")
+		return
+	case err != nil:
+		log.Printf("[api] start etl run: %v", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to start ETL run")
+		return
+	}
+
+	w.Header().Set("Location", "/etl/runs/"+run.ID)
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (h *ETLHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	if limit > maxRunsLimit {
+		limit = maxRunsLimit
+	}
+
+	runs, err := h.runner.List(r.Context(), limit)
+	if err != nil {
+		log.Printf("[api] list etl runs: %v", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list ETL runs")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"runs": runs})
+}
+
+func (h *ETLHandler) GetRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.runner.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, etl.ErrRunNotFound) {
+		writeError(w, http.StatusNotFound, "ETL_RUN_NOT_FOUND", "ETL run not found")
+		return
+	}
+	if err != nil {
+		log.Printf("[api] get etl run: %v", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to load ETL run")
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"analytics-service/internal/recommend"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RecommendationsHandler отдаёт предрассчитанные ETL рекомендации
+// вызывающему пользователю: creator'у — площадки для его мероприятия,
+// площадке — мероприятия из каталога
+type RecommendationsHandler struct {
+	store *recommend.Store
+}
+
+func NewRecommendationsHandler(store *recommend.Store) *RecommendationsHandler {
+	return &RecommendationsHandler{store: store}
+}
+
+func (h *RecommendationsHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /me/recommendations/venues", h.VenuesForEvent)
+	mux.HandleFunc("GET /me/recommendations/events", h.EventsForVenue)
+}
+
+type recommendationsResponse struct {
+	ComputedAt *time.Time  `json:"computed_at"`
+	Data       interface{} `json:"data"`
+}
+
+// VenuesForEvent — GET /me/recommendations/venues?event_id=&limit=, только creator
+func (h *RecommendationsHandler) VenuesForEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireRole(w, r, "creator")
+	if !ok {
+		return
+	}
+	eventID, err := strconv.Atoi(r.URL.Query().Get("event_id"))
+	if err != nil || eventID <= 0 {
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "event_id is required")
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	ctx, cancel := context.WithTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	data, computedAt, err := h.store.VenuesForEvent(ctx, userID, eventID, limit)
+	if errors.Is(err, recommend.ErrEventNotFound) {
+		writeError(w, http.StatusNotFound, "EVENT_NOT_FOUND", "Event not found")
+		return
+	}
+	if err != nil {
+		log.Printf("[api] venue recommendations: %v", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to load recommendations")
+		return
+	}
+	writeJSON(w, http.StatusOK, recommendationsResponse{ComputedAt: nonZero(computedAt), Data: data})
+}
+
+// EventsForVenue — GET /me/recommendations/events?limit=, только площадка
+func (h *RecommendationsHandler) EventsForVenue(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireRole(w, r, "venue")
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	ctx, cancel := context.WithTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	data, computedAt, err := h.store.EventsForVenue(ctx, userID, limit)
+	if err != nil {
+		log.Printf("[api] event recommendations: %v", err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to load recommendations")
+		return
+	}
+	writeJSON(w, http.StatusOK, recommendationsResponse{ComputedAt: nonZero(computedAt), Data: data})
+}
+
+func requireRole(w http.ResponseWriter, r *http.Request, role string) (int, bool) {
+	userID, err := strconv.Atoi(r.Header.Get("X-User-ID"))
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing user context")
+		return 0, false
+	}
+	if r.Header.Get("X-User-Role") != role {
+		writeError(w, http.StatusForbidden, "ACCESS_DENIED", "Insufficient permissions")
+		return 0, false
+	}
+	return userID, true
+}
+
+func nonZero(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
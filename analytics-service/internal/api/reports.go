@@ -0,0 +1,193 @@
+package api
+
+import (
+	"analytics-service/internal/reports"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// ReportsHandler — сохранённые отчёты (только администраторы) и
+// скачивание выгрузок по подписанным ссылкам (без авторизации)
+type ReportsHandler struct {
+	svc *reports.Service
+}
+
+func NewReportsHandler(svc *reports.Service) *ReportsHandler {
+	return &ReportsHandler{svc: svc}
+}
+
+func (h *ReportsHandler) Register(mux *http.ServeMux) {
+	// Роль admin проверяет политика доступа gateway (gateway/internal/routing/routes.json),
+	// /report-files/** открыт: доступ дает подпись в ссылке
+	mux.HandleFunc("GET /reports", h.List)
+	mux.HandleFunc("POST /reports", h.Create)
+	mux.HandleFunc("GET /reports/{id}", h.Get)
+	mux.HandleFunc("PUT /reports/{id}", h.Update)
+	mux.HandleFunc("DELETE /reports/{id}", h.Delete)
+	mux.HandleFunc("POST /reports/{id}/run", h.Run)
+	mux.HandleFunc("GET /reports/{id}/runs", h.ListRuns)
+	mux.HandleFunc("GET /report-files/{key...}", h.Download)
+}
+
+func (h *ReportsHandler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.List()
+	if err != nil {
+		writeReportError(w, "list reports", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"reports": list})
+}
+
+func (h *ReportsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, _ := strconv.Atoi(r.Header.Get("X-User-ID"))
+
+	var in reports.Input
+	if !decodeInput(w, r, &in) {
+		return
+	}
+	report, err := h.svc.Create(in, userID)
+	if err != nil {
+		writeReportError(w, "create report", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, report)
+}
+
+func (h *ReportsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+	report, err := h.svc.Get(id)
+	if err != nil {
+		writeReportError(w, "get report", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (h *ReportsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+	var in reports.Input
+	if !decodeInput(w, r, &in) {
+		return
+	}
+	report, err := h.svc.Update(id, in)
+	if err != nil {
+		writeReportError(w, "update report", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (h *ReportsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(id); err != nil {
+		writeReportError(w, "delete report", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Run формирует отчёт вне расписания; ответ 202, результат — в /reports/{id}/runs
+func (h *ReportsHandler) Run(w http.ResponseWriter, r *http.Request) {
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+	run, err := h.svc.Run(id, "manual")
+	if err != nil {
+		writeReportError(w, "run report", err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (h *ReportsHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	runs, err := h.svc.ListRuns(id, limit)
+	if err != nil {
+		writeReportError(w, "list report runs", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"runs": runs})
+}
+
+// Download отдаёт файл выгрузки по подписанной ссылке из /reports/{id}/runs или письма
+func (h *ReportsHandler) Download(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	key := r.PathValue("key")
+
+	obj, err := h.svc.OpenFile(r.Context(), key, q.Get("expires"), q.Get("sig"))
+	if err != nil {
+		writeReportError(w, "download report file", err)
+		return
+	}
+	defer obj.Body.Close()
+
+	w.Header().Set("Content-Type", obj.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(obj.Size, 10))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+path.Base(key)+`"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, obj.Body); err != nil {
+		log.Printf("[api] stream report file %s: %v", key, err)
+	}
+}
+
+func reportID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid report ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeInput(w http.ResponseWriter, r *http.Request, in *reports.Input) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(in); err != nil {
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request body")
+		return false
+	}
+	return true
+}
+
+func writeReportError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, reports.ErrReportNotFound):
+		writeError(w, http.StatusNotFound, "REPORT_NOT_FOUND", "Report not found")
+	case errors.Is(err, reports.ErrRunInProgress):
+		writeError(w, http.StatusConflict, "REPORT_RUN_IN_PROGRESS", "Report is already being generated")
+	case errors.Is(err, reports.ErrInvalidKind):
+		writeError(w, http.StatusBadRequest, "INVALID_REPORT_KIND", "kind must be one of funnel, registrations, top-categories, top-cities, event-lifecycle")
+	case errors.Is(err, reports.ErrInvalidSchedule):
+		writeError(w, http.StatusBadRequest, "INVALID_SCHEDULE", "schedule must be a standard 5-field cron expression")
+	case errors.Is(err, reports.ErrInvalidFormat):
+		writeError(w, http.StatusBadRequest, "INVALID_FORMAT", "formats must contain csv and/or xlsx")
+	case errors.Is(err, reports.ErrInvalidRecipient), errors.Is(err, reports.ErrValidation):
+		writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, reports.ErrLinkInvalid):
+		writeError(w, http.StatusForbidden, "LINK_INVALID", "Download link is invalid")
+	case errors.Is(err, reports.ErrLinkExpired):
+		writeError(w, http.StatusGone, "LINK_EXPIRED", "Download link has expired")
+	case errors.Is(err, reports.ErrFileNotFound):
+		writeError(w, http.StatusNotFound, "FILE_NOT_FOUND", "Report file not found")
+	default:
+		log.Printf("[api] %s: %v", op, err)
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to process report request")
+	}
+}
@@ -40,6 +40,11 @@ func NewClient(host, database, username, password string) (*Client, error) {
 	return &Client{conn: conn}, nil
 }
 
+// NewClientFromConn оборачивает уже открытое соединение (например, тестовое)
+func NewClientFromConn(conn driver.Conn) *Client {
+	return &Client{conn: conn}
+}
+
 func (c *Client) Close() error {
 	return c.conn.Close()
 }
@@ -56,6 +61,31 @@ func (c *Client) InitSchemas() error {
 	ctx := context.Background()
 
 	schemas := []string{
+		// --- ETL service tables ---
+		// Водяной знак CDC: наибольший updated_at, загруженный в витрину
+		`CREATE TABLE IF NOT EXISTS etl_watermarks (
+			table_name String,
+			watermark  DateTime64(6, 'UTC'),
+			updated_at DateTime
+		) ENGINE = ReplacingMergeTree(updated_at)
+		ORDER BY table_name`,
+
+		// Журнал запусков ETL; строка перезаписывается по мере прогресса
+		`CREATE TABLE IF NOT EXISTS etl_runs (
+			run_id      String,
+			kind        String,
+			trigger     String,
+			status      String,
+			started_at  DateTime64(3, 'UTC'),
+			finished_at Nullable(DateTime64(3, 'UTC')),
+			duration_ms UInt64,
+			tables      String,
+			error       String,
+			updated_at  DateTime64(3, 'UTC')
+		) ENGINE = ReplacingMergeTree(updated_at)
+		ORDER BY run_id
+		TTL toDateTime(started_at) + INTERVAL 90 DAY`,
+
 		// --- Dimension tables ---
 		`CREATE TABLE IF NOT EXISTS dim_date (
 			date_id      UInt32,
@@ -94,6 +124,38 @@ func (c *Client) InitSchemas() error {
 		) ENGINE = ReplacingMergeTree(updated_at)
 		ORDER BY event_id`,
 
+		// История измерений (SCD type 2): версия действует в [valid_from, valid_to).
+		// Закрытие версии перезаписывает строку с тем же ключом и большим version.
+		`CREATE TABLE IF NOT EXISTS dim_user_history (
+			user_id        UInt32,
+			role           String,
+			name           String,
+			city           String,
+			capacity       UInt32,
+			profile_filled UInt8,
+			attrs_hash     UInt64,
+			valid_from     DateTime,
+			valid_to       DateTime,
+			is_current     UInt8,
+			version        DateTime64(6)
+		) ENGINE = ReplacingMergeTree(version)
+		ORDER BY (user_id, valid_from)`,
+
+		`CREATE TABLE IF NOT EXISTS dim_event_history (
+			event_id     UInt32,
+			creator_id   UInt32,
+			title        String,
+			category_ids Array(UInt32),
+			is_active    UInt8,
+			is_completed UInt8,
+			attrs_hash   UInt64,
+			valid_from   DateTime,
+			valid_to     DateTime,
+			is_current   UInt8,
+			version      DateTime64(6)
+		) ENGINE = ReplacingMergeTree(version)
+		ORDER BY (event_id, valid_from)`,
+
 		// --- Fact tables ---
 		`CREATE TABLE IF NOT EXISTS fact_applications (
 			application_id UInt32,
@@ -123,6 +185,49 @@ func (c *Client) InitSchemas() error {
 			updated_at       DateTime
 		) ENGINE = ReplacingMergeTree(updated_at)
 		ORDER BY (date, collaboration_id)`,
+
+		`CREATE TABLE IF NOT EXISTS fact_favorites (
+			kind           String,
+			date           Date,
+			date_id        UInt32,
+			user_id        UInt32,
+			target_user_id UInt32,
+			event_id       UInt32,
+			created_at     DateTime
+		) ENGINE = MergeTree()
+		ORDER BY (target_user_id, date)`,
+
+		// Переходы мероприятий между статусами (active/inactive/completed).
+		// event_created_at денормализован для расчёта длительностей без JOIN.
+		`CREATE TABLE IF NOT EXISTS fact_event_status_changes (
+			change_id        UInt64,
+			date             Date,
+			date_id          UInt32,
+			event_id         UInt32,
+			creator_id       UInt32,
+			from_status      String,
+			to_status        String,
+			reason           String,
+			changed_at       DateTime,
+			event_created_at DateTime
+		) ENGINE = ReplacingMergeTree(changed_at)
+		ORDER BY (date, change_id)`,
+
+		// --- Recommendations ---
+		// Пары мероприятие–площадка из etl.BuildRecommendations. Основной порядок —
+		// выдача для мероприятия, проекция by_venue — выдача для площадки.
+		`CREATE TABLE IF NOT EXISTS rec_matches (
+			event_id    UInt32,
+			creator_id  UInt32,
+			venue_id    UInt32,
+			score       Float32,
+			reasons     Array(String),
+			event_rank  UInt16,
+			venue_rank  UInt16,
+			computed_at DateTime,
+			PROJECTION by_venue (SELECT * ORDER BY venue_id, venue_rank)
+		) ENGINE = MergeTree()
+		ORDER BY (event_id, event_rank)`,
 	}
 
 	for _, ddl := range schemas {
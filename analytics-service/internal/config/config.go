@@ -1,14 +1,32 @@
 package config
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 type Config struct {
-	Port             string
-	PostgresDSN      string
-	ClickHouseHost   string
-	ClickHouseDB     string
-	ClickHouseUser   string
-	ClickHousePass   string
+	Port           string
+	PostgresDSN    string
+	ClickHouseHost string
+	ClickHouseDB   string
+	ClickHouseUser string
+	ClickHousePass string
+
+	MinioEndpoint    string
+	MinioAccessKey   string
+	MinioSecretKey   string
+	MinioUseSSL      bool
+	ReportsBucket    string
+	ReportLinkSecret string
+	ReportLinkTTL    time.Duration
+	ReportsPublicURL string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
 }
 
 func Load() *Config {
@@ -19,6 +37,22 @@ func Load() *Config {
 		ClickHouseDB:   getEnv("CLICKHOUSE_DB", "analytics_db"),
 		ClickHouseUser: getEnv("CLICKHOUSE_USER", "analytics_user"),
 		ClickHousePass: getEnv("CLICKHOUSE_PASSWORD", ""),
+
+		MinioEndpoint:    getEnv("MINIO_ENDPOINT", "minio:9000"),
+		MinioAccessKey:   getEnv("MINIO_ACCESS_KEY", ""),
+		MinioSecretKey:   getEnv("MINIO_SECRET_KEY", ""),
+		MinioUseSSL:      getEnv("MINIO_USE_SSL", "false") == "true",
+		ReportsBucket:    getEnv("REPORTS_BUCKET", "analytics-reports"),
+		ReportLinkSecret: getEnv("REPORT_LINK_SECRET", ""),
+		ReportLinkTTL:    getDuration("REPORT_LINK_TTL", 7*24*time.Hour),
+		// Внешний адрес analytics-service за gateway — ссылки из писем открываются снаружи
+		ReportsPublicURL: getEnv("REPORTS_PUBLIC_URL", "/api/analytics"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUser:     getEnv("SMTP_USER", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
 	}
 }
 
@@ -28,3 +62,10 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return defaultValue
+}
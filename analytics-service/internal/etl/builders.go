@@ -2,8 +2,6 @@ package etl
 
 import (
 	"analytics-service/internal/clickhouse"
-	"log"
-	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,28 +15,37 @@ func NewBuilders(db *gorm.DB, ch *clickhouse.Client) *Builders {
 	return &Builders{db: db, ch: ch}
 }
 
-// RunFull полная перестройка всех таблиц. Запускается при старте сервиса.
-func (b *Builders) RunFull() {
-	run("dim_date", func() error { return BuildDimDate(b.ch) })
-	run("dim_user", func() error { return BuildDimUser(b.db, b.ch) })
-	run("dim_event", func() error { return BuildDimEvent(b.db, b.ch) })
-	run("fact_applications", func() error { return BuildFactApplicationsAll(b.db, b.ch) })
-	run("fact_collaborations", func() error { return BuildFactCollaborationsAll(b.db, b.ch) })
+// step — загрузка одной витрины; возвращает число загруженных строк
+type step struct {
+	table string
+	fn    func() (int, error)
 }
 
-func (b *Builders) RunDaily() {
-	since := time.Now().AddDate(0, 0, -2)
-	run("dim_user", func() error { return BuildDimUser(b.db, b.ch) })
-	run("dim_event", func() error { return BuildDimEvent(b.db, b.ch) })
-	run("fact_applications", func() error { return BuildFactApplicationsIncremental(b.db, b.ch, since) })
-	run("fact_collaborations", func() error { return BuildFactCollaborationsIncremental(b.db, b.ch, since) })
+// fullSteps — полная перестройка всех таблиц через теневые копии.
+// Запускается при старте сервиса, ночью и вручную через /run-etl.
+func (b *Builders) fullSteps() []step {
+	return []step{
+		{"dim_date", func() (int, error) { return BuildDimDate(b.ch) }},
+		{"dim_user", func() (int, error) { return BuildDimUser(b.db, b.ch) }},
+		{"dim_event", func() (int, error) { return BuildDimEvent(b.db, b.ch) }},
+		{"fact_applications", func() (int, error) { return BuildFactApplicationsAll(b.db, b.ch) }},
+		{"fact_collaborations", func() (int, error) { return BuildFactCollaborationsAll(b.db, b.ch) }},
+		{"fact_favorites", func() (int, error) { return BuildFactFavorites(b.db, b.ch) }},
+		{"fact_event_status_changes", func() (int, error) { return BuildFactEventStatusChanges(b.db, b.ch) }},
+		{"rec_matches", func() (int, error) { return BuildRecommendations(b.db, b.ch) }},
+	}
 }
 
-func run(name string, fn func() error) {
-	log.Printf("[etl] starting %s", name)
-	if err := fn(); err != nil {
-		log.Printf("[etl] %s failed: %v", name, err)
-	} else {
-		log.Printf("[etl] %s done", name)
+// incrementalSteps догружают изменения после водяных знаков в etl_watermarks
+func (b *Builders) incrementalSteps() []step {
+	return []step{
+		{"dim_user", func() (int, error) { return BuildDimUserIncremental(b.db, b.ch) }},
+		{"dim_event", func() (int, error) { return BuildDimEventIncremental(b.db, b.ch) }},
+		{"fact_applications", func() (int, error) { return BuildFactApplicationsIncremental(b.db, b.ch) }},
+		{"fact_collaborations", func() (int, error) { return BuildFactCollaborationsIncremental(b.db, b.ch) }},
+		{"fact_favorites", func() (int, error) { return BuildFactFavorites(b.db, b.ch) }},
+		{"fact_event_status_changes", func() (int, error) { return BuildFactEventStatusChangesIncremental(b.db, b.ch) }},
+		// Рекомендации всегда пересчитываются целиком, чтобы выдача не отставала от каталога
+		{"rec_matches", func() (int, error) { return BuildRecommendations(b.db, b.ch) }},
 	}
 }
@@ -0,0 +1,120 @@
+package etl
+
+import (
+	"analytics-service/internal/clickhouse"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// watermarkOverlap — насколько раньше водяного знака начинаем следующую
+// выгрузку. Транзакции в Postgres коммитятся не в порядке updated_at,
+// а повторная загрузка строк безопасна: витрины — ReplacingMergeTree.
+const watermarkOverlap = 5 * time.Minute
+
+// pgTimestamp — формат для сравнения с TIMESTAMP без зоны в Postgres
+const pgTimestamp = "2006-01-02 15:04:05.999999"
+
+// LoadFunc выгружает строки с updated_at > since в таблицу target и
+// возвращает их число и наибольший updated_at среди них
+type LoadFunc func(ctx context.Context, target string, since time.Time) (int, time.Time, error)
+
+// LoadFull перестраивает таблицу целиком в теневой копии и атомарно
+// подменяет ею основную, так что дашборды не видят пустую таблицу.
+func LoadFull(ctx context.Context, ch *clickhouse.Client, table string, load LoadFunc) (int, error) {
+	shadow := table + "_shadow"
+
+	// Теневую таблицу пересоздаём, чтобы она повторяла текущую схему
+	if err := ch.Exec(ctx, "DROP TABLE IF EXISTS "+shadow); err != nil {
+		return 0, fmt.Errorf("%s drop shadow: %w", table, err)
+	}
+	if err := ch.Exec(ctx, fmt.Sprintf("CREATE TABLE %s AS %s", shadow, table)); err != nil {
+		return 0, fmt.Errorf("%s create shadow: %w", table, err)
+	}
+
+	loaded, maxUpdated, err := load(ctx, shadow, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := ch.Exec(ctx, fmt.Sprintf("EXCHANGE TABLES %s AND %s", table, shadow)); err != nil {
+		return 0, fmt.Errorf("%s exchange: %w", table, err)
+	}
+	if err := ch.Exec(ctx, "DROP TABLE IF EXISTS "+shadow); err != nil {
+		// Не критично: старая копия будет удалена при следующей перестройке
+		log.Printf("[%s] drop old shadow: %v", table, err)
+	}
+
+	return loaded, setWatermark(ctx, ch, table, maxUpdated)
+}
+
+// LoadIncremental догружает строки, изменившиеся после водяного знака,
+// прямо в основную таблицу (ReplacingMergeTree схлопывает версии).
+func LoadIncremental(ctx context.Context, ch *clickhouse.Client, table string, load LoadFunc) (int, error) {
+	watermark, err := getWatermark(ctx, ch, table)
+	if err != nil {
+		return 0, err
+	}
+
+	since := time.Time{}
+	if !watermark.IsZero() {
+		since = watermark.Add(-watermarkOverlap)
+	}
+
+	loaded, maxUpdated, err := load(ctx, table, since)
+	if err != nil {
+		return 0, err
+	}
+	if !maxUpdated.After(watermark) {
+		return loaded, nil
+	}
+	return loaded, setWatermark(ctx, ch, table, maxUpdated)
+}
+
+func getWatermark(ctx context.Context, ch *clickhouse.Client, table string) (time.Time, error) {
+	var watermarks []time.Time
+	rows, err := ch.Conn().Query(ctx,
+		`SELECT watermark FROM etl_watermarks FINAL WHERE table_name = ?`, table)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s get watermark: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var wm time.Time
+		if err := rows.Scan(&wm); err != nil {
+			return time.Time{}, fmt.Errorf("%s scan watermark: %w", table, err)
+		}
+		watermarks = append(watermarks, wm)
+	}
+	if len(watermarks) == 0 {
+		return time.Time{}, rows.Err()
+	}
+	return watermarks[0].UTC(), rows.Err()
+}
+
+func setWatermark(ctx context.Context, ch *clickhouse.Client, table string, watermark time.Time) error {
+	if watermark.IsZero() {
+		// Таблица-источник пуста — следующая выгрузка снова начнётся с начала
+		return nil
+	}
+	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO etl_watermarks")
+	if err != nil {
+		return fmt.Errorf("%s prepare watermark: %w", table, err)
+	}
+	if err := batch.Append(table, watermark, time.Now().UTC()); err != nil {
+		return fmt.Errorf("%s append watermark: %w", table, err)
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("%s send watermark: %w", table, err)
+	}
+	log.Printf("[%s] watermark = %s", table, watermark.Format(time.RFC3339Nano))
+	return nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
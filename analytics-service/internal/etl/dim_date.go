@@ -21,17 +21,17 @@ type dimDateRow struct {
 
 // BuildDimDate генерирует календарь с 2020-01-01 по 2030-12-31.
 // Пропускает если таблица уже заполнена.
-func BuildDimDate(ch *clickhouse.Client) error {
+func BuildDimDate(ch *clickhouse.Client) (int, error) {
 	ctx := context.Background()
 
 	var count uint64
 	row := ch.Conn().QueryRow(ctx, "SELECT count() FROM dim_date")
 	if err := row.Scan(&count); err != nil {
-		return fmt.Errorf("dim_date count: %w", err)
+		return 0, fmt.Errorf("dim_date count: %w", err)
 	}
 	if count > 0 {
 		log.Println("[dim_date] already populated, skipping")
-		return nil
+		return 0, nil
 	}
 
 	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -54,20 +54,20 @@ func BuildDimDate(ch *clickhouse.Client) error {
 
 	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO dim_date")
 	if err != nil {
-		return fmt.Errorf("dim_date prepare batch: %w", err)
+		return 0, fmt.Errorf("dim_date prepare batch: %w", err)
 	}
 	for _, r := range rows {
 		if err := batch.Append(r.DateID, r.Date, r.DayOfWeek, r.DayOfMonth,
 			r.Month, r.Quarter, r.Year, r.IsWeekend); err != nil {
-			return fmt.Errorf("dim_date append: %w", err)
+			return 0, fmt.Errorf("dim_date append: %w", err)
 		}
 	}
 	if err := batch.Send(); err != nil {
-		return fmt.Errorf("dim_date send: %w", err)
+		return 0, fmt.Errorf("dim_date send: %w", err)
 	}
 
 	log.Printf("[dim_date] generated %d rows", len(rows))
-	return nil
+	return len(rows), nil
 }
 
 func boolToUint8(b bool) uint8 {
@@ -2,6 +2,7 @@ package etl
 
 import (
 	"analytics-service/internal/clickhouse"
+	"analytics-service/internal/scd"
 	"context"
 	"fmt"
 	"log"
@@ -17,6 +18,7 @@ type dimEventRow struct {
 	IsActive    bool      `gorm:"column:is_active"`
 	IsCompleted bool      `gorm:"column:is_completed"`
 	CreatedDate time.Time `gorm:"column:created_date"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
 	UpdatedAt   time.Time `gorm:"column:updated_at"`
 }
 
@@ -25,62 +27,87 @@ type eventCategoryRow struct {
 	CategoryID int `gorm:"column:category_id"`
 }
 
-func BuildDimEvent(db *gorm.DB, ch *clickhouse.Client) error {
-	log.Println("[dim_event] extracting from PostgreSQL...")
+// BuildDimEvent полностью перестраивает dim_event через теневую таблицу
+func BuildDimEvent(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "dim_event", dimEventLoader(db, ch))
+}
 
-	var events []dimEventRow
-	if err := db.Raw(`
-		SELECT id AS event_id, creator_id, title, is_active, is_completed,
-		       DATE(created_at) AS created_date, updated_at
-		FROM events
-	`).Scan(&events).Error; err != nil {
-		return fmt.Errorf("dim_event extract events: %w", err)
-	}
+// BuildDimEventIncremental догружает мероприятия, изменившиеся после водяного знака.
+// Категории меняются вместе с мероприятием (UpdateEvent обновляет updated_at).
+func BuildDimEventIncremental(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadIncremental(context.Background(), ch, "dim_event", dimEventLoader(db, ch))
+}
 
-	var catRows []eventCategoryRow
-	if err := db.Raw(`SELECT event_id, category_id FROM event_categories`).Scan(&catRows).Error; err != nil {
-		return fmt.Errorf("dim_event extract categories: %w", err)
-	}
+func dimEventLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, since time.Time) (int, time.Time, error) {
+		log.Println("[dim_event] extracting from PostgreSQL...")
 
-	// Строим map eventID → []categoryID
-	catMap := make(map[int][]uint32, len(catRows))
-	for _, c := range catRows {
-		catMap[c.EventID] = append(catMap[c.EventID], uint32(c.CategoryID))
-	}
+		var events []dimEventRow
+		if err := db.Raw(`
+			SELECT id AS event_id, creator_id, title, is_active, is_completed,
+			       DATE(created_at) AS created_date, created_at, updated_at
+			FROM events
+			WHERE updated_at > ?
+		`, since.Format(pgTimestamp)).Scan(&events).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_event extract events: %w", err)
+		}
+		log.Printf("[dim_event] extracted %d events", len(events))
 
-	log.Printf("[dim_event] extracted %d events", len(events))
+		if len(events) == 0 {
+			return 0, time.Time{}, nil
+		}
 
-	ctx := context.Background()
+		eventIDs := make([]int, len(events))
+		for i, e := range events {
+			eventIDs[i] = e.EventID
+		}
 
-	if err := ch.Exec(ctx, "TRUNCATE TABLE dim_event"); err != nil {
-		return fmt.Errorf("dim_event truncate: %w", err)
-	}
-	if len(events) == 0 {
-		return nil
-	}
+		var catRows []eventCategoryRow
+		if err := db.Raw(`SELECT event_id, category_id FROM event_categories WHERE event_id IN ? ORDER BY event_id, category_id`, eventIDs).
+			Scan(&catRows).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_event extract categories: %w", err)
+		}
 
-	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO dim_event")
-	if err != nil {
-		return fmt.Errorf("dim_event prepare batch: %w", err)
-	}
-	for _, e := range events {
-		categoryIDs := catMap[e.EventID]
-		if categoryIDs == nil {
-			categoryIDs = []uint32{}
+		// Строим map eventID → []categoryID
+		catMap := make(map[int][]uint32, len(catRows))
+		for _, c := range catRows {
+			catMap[c.EventID] = append(catMap[c.EventID], uint32(c.CategoryID))
 		}
-		if err := batch.Append(
-			uint32(e.EventID), uint32(e.CreatorID), e.Title,
-			categoryIDs,
-			boolToUint8(e.IsActive), boolToUint8(e.IsCompleted),
-			e.CreatedDate, e.UpdatedAt,
-		); err != nil {
-			return fmt.Errorf("dim_event append: %w", err)
+
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_event prepare batch: %w", err)
+		}
+		var maxUpdated time.Time
+		history := make([]scd.EventVersion, 0, len(events))
+		for _, e := range events {
+			categoryIDs := catMap[e.EventID]
+			if categoryIDs == nil {
+				categoryIDs = []uint32{}
+			}
+			if err := batch.Append(
+				uint32(e.EventID), uint32(e.CreatorID), e.Title,
+				categoryIDs,
+				boolToUint8(e.IsActive), boolToUint8(e.IsCompleted),
+				e.CreatedDate, e.UpdatedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("dim_event append: %w", err)
+			}
+			maxUpdated = maxTime(maxUpdated, e.UpdatedAt)
+			history = append(history, scd.NewEventVersion(scd.Event{
+				EventID: e.EventID, CreatorID: e.CreatorID, Title: e.Title,
+				IsActive: e.IsActive, IsCompleted: e.IsCompleted,
+				CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt,
+			}, categoryIDs))
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_event send: %w", err)
+		}
+		if _, err := applySCD2(ctx, ch, "dim_event_history", history); err != nil {
+			return 0, time.Time{}, err
 		}
-	}
-	if err := batch.Send(); err != nil {
-		return fmt.Errorf("dim_event send: %w", err)
-	}
 
-	log.Printf("[dim_event] loaded %d rows into ClickHouse", len(events))
-	return nil
+		log.Printf("[dim_event] loaded %d rows into %s", len(events), target)
+		return len(events), maxUpdated, nil
+	}
 }
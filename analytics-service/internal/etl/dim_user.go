@@ -2,6 +2,7 @@ package etl
 
 import (
 	"analytics-service/internal/clickhouse"
+	"analytics-service/internal/scd"
 	"context"
 	"fmt"
 	"log"
@@ -19,6 +20,7 @@ type dimUserRow struct {
 	Capacity         int       `gorm:"column:capacity"`
 	RegistrationDate time.Time `gorm:"column:registration_date"`
 	ProfileFilled    int       `gorm:"column:profile_filled"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
 	UpdatedAt        time.Time `gorm:"column:updated_at"`
 }
 
@@ -36,6 +38,7 @@ SELECT
          AND COALESCE(NULLIF(c.description, ''), NULLIF(v.description, '')) IS NOT NULL
         THEN 1 ELSE 0
     END                                                               AS profile_filled,
+    u.created_at                                                      AS created_at,
     GREATEST(
         u.updated_at,
         COALESCE(c.updated_at, v.updated_at, u.updated_at)
@@ -47,41 +50,60 @@ LEFT JOIN cities   ci ON ci.id      = v.city_id
 WHERE u.role IN ('creator', 'venue')
 `
 
-func BuildDimUser(db *gorm.DB, ch *clickhouse.Client) error {
-	log.Println("[dim_user] extracting from PostgreSQL...")
+// BuildDimUser полностью перестраивает dim_user через теневую таблицу
+func BuildDimUser(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "dim_user", dimUserLoader(db, ch))
+}
 
-	var rows []dimUserRow
-	if err := db.Raw(dimUserQuery).Scan(&rows).Error; err != nil {
-		return fmt.Errorf("dim_user extract: %w", err)
-	}
-	log.Printf("[dim_user] extracted %d rows", len(rows))
+// BuildDimUserIncremental догружает пользователей, изменившихся после водяного знака
+func BuildDimUserIncremental(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadIncremental(context.Background(), ch, "dim_user", dimUserLoader(db, ch))
+}
 
-	ctx := context.Background()
+func dimUserLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, since time.Time) (int, time.Time, error) {
+		log.Println("[dim_user] extracting from PostgreSQL...")
 
-	if err := ch.Exec(ctx, "TRUNCATE TABLE dim_user"); err != nil {
-		return fmt.Errorf("dim_user truncate: %w", err)
-	}
-	if len(rows) == 0 {
-		return nil
-	}
+		var rows []dimUserRow
+		query := `SELECT * FROM (` + dimUserQuery + `) src WHERE updated_at > ?`
+		if err := db.Raw(query, since.Format(pgTimestamp)).Scan(&rows).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_user extract: %w", err)
+		}
+		log.Printf("[dim_user] extracted %d rows", len(rows))
 
-	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO dim_user")
-	if err != nil {
-		return fmt.Errorf("dim_user prepare batch: %w", err)
-	}
-	for _, r := range rows {
-		if err := batch.Append(
-			uint32(r.UserID), r.Role, r.Name, r.Description,
-			r.City, uint32(r.Capacity), r.RegistrationDate,
-			uint8(r.ProfileFilled), r.UpdatedAt,
-		); err != nil {
-			return fmt.Errorf("dim_user append: %w", err)
+		if len(rows) == 0 {
+			return 0, time.Time{}, nil
 		}
-	}
-	if err := batch.Send(); err != nil {
-		return fmt.Errorf("dim_user send: %w", err)
-	}
 
-	log.Printf("[dim_user] loaded %d rows into ClickHouse", len(rows))
-	return nil
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_user prepare batch: %w", err)
+		}
+		var maxUpdated time.Time
+		history := make([]scd.UserVersion, 0, len(rows))
+		for _, r := range rows {
+			if err := batch.Append(
+				uint32(r.UserID), r.Role, r.Name, r.Description,
+				r.City, uint32(r.Capacity), r.RegistrationDate,
+				uint8(r.ProfileFilled), r.UpdatedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("dim_user append: %w", err)
+			}
+			maxUpdated = maxTime(maxUpdated, r.UpdatedAt)
+			history = append(history, scd.NewUserVersion(scd.User{
+				UserID: r.UserID, Role: r.Role, Name: r.Name, City: r.City,
+				Capacity: r.Capacity, ProfileFilled: r.ProfileFilled,
+				CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+			}))
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("dim_user send: %w", err)
+		}
+		if _, err := applySCD2(ctx, ch, "dim_user_history", history); err != nil {
+			return 0, time.Time{}, err
+		}
+
+		log.Printf("[dim_user] loaded %d rows into %s", len(rows), target)
+		return len(rows), maxUpdated, nil
+	}
 }
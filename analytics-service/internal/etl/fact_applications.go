@@ -28,62 +28,58 @@ SELECT id AS application_id, DATE(created_at) AS date,
        sender_id, sender_type, receiver_id, receiver_type,
        event_id, status, created_at, updated_at
 FROM applications
+WHERE updated_at > ?
 `
 
-const factApplicationsIncrementalQuery = `
-SELECT id AS application_id, DATE(created_at) AS date,
-       sender_id, sender_type, receiver_id, receiver_type,
-       event_id, status, created_at, updated_at
-FROM applications
-WHERE updated_at >= ?
-`
-
-// BuildFactApplicationsAll полная перестройка.
-func BuildFactApplicationsAll(db *gorm.DB, ch *clickhouse.Client) error {
-	return buildFactApplications(db, ch, factApplicationsQuery)
+// BuildFactApplicationsAll полная перестройка через теневую таблицу.
+func BuildFactApplicationsAll(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "fact_applications", factApplicationsLoader(db, ch))
 }
 
-// BuildFactApplicationsIncremental обновляет строки, изменившиеся с указанной даты.
-func BuildFactApplicationsIncremental(db *gorm.DB, ch *clickhouse.Client, since time.Time) error {
-	return buildFactApplications(db, ch, factApplicationsIncrementalQuery, since.Format("2006-01-02"))
+// BuildFactApplicationsIncremental обновляет строки, изменившиеся после водяного знака.
+func BuildFactApplicationsIncremental(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadIncremental(context.Background(), ch, "fact_applications", factApplicationsLoader(db, ch))
 }
 
-func buildFactApplications(db *gorm.DB, ch *clickhouse.Client, query string, args ...interface{}) error {
-	log.Println("[fact_applications] extracting from PostgreSQL...")
+func factApplicationsLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, since time.Time) (int, time.Time, error) {
+		log.Println("[fact_applications] extracting from PostgreSQL...")
 
-	var rows []factApplicationRow
-	if err := db.Raw(query, args...).Scan(&rows).Error; err != nil {
-		return fmt.Errorf("fact_applications extract: %w", err)
-	}
-	log.Printf("[fact_applications] extracted %d rows", len(rows))
+		var rows []factApplicationRow
+		if err := db.Raw(factApplicationsQuery, since.Format(pgTimestamp)).Scan(&rows).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_applications extract: %w", err)
+		}
+		log.Printf("[fact_applications] extracted %d rows", len(rows))
 
-	if len(rows) == 0 {
-		return nil
-	}
+		if len(rows) == 0 {
+			return 0, time.Time{}, nil
+		}
 
-	ctx := context.Background()
-	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO fact_applications")
-	if err != nil {
-		return fmt.Errorf("fact_applications prepare batch: %w", err)
-	}
-	for _, r := range rows {
-		dateID := toDateID(r.Date)
-		if err := batch.Append(
-			uint32(r.ApplicationID), r.Date, dateID,
-			uint32(r.SenderID), r.SenderType,
-			uint32(r.ReceiverID), r.ReceiverType,
-			uint32(r.EventID), r.Status,
-			r.CreatedAt, r.UpdatedAt,
-		); err != nil {
-			return fmt.Errorf("fact_applications append: %w", err)
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_applications prepare batch: %w", err)
+		}
+		var maxUpdated time.Time
+		for _, r := range rows {
+			dateID := toDateID(r.Date)
+			if err := batch.Append(
+				uint32(r.ApplicationID), r.Date, dateID,
+				uint32(r.SenderID), r.SenderType,
+				uint32(r.ReceiverID), r.ReceiverType,
+				uint32(r.EventID), r.Status,
+				r.CreatedAt, r.UpdatedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("fact_applications append: %w", err)
+			}
+			maxUpdated = maxTime(maxUpdated, r.UpdatedAt)
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_applications send: %w", err)
 		}
-	}
-	if err := batch.Send(); err != nil {
-		return fmt.Errorf("fact_applications send: %w", err)
-	}
 
-	log.Printf("[fact_applications] loaded %d rows into ClickHouse", len(rows))
-	return nil
+		log.Printf("[fact_applications] loaded %d rows into %s", len(rows), target)
+		return len(rows), maxUpdated, nil
+	}
 }
 
 func toDateID(t time.Time) uint32 {
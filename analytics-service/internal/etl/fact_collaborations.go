@@ -27,59 +27,55 @@ SELECT id AS collaboration_id, DATE(created_at) AS date,
        application_id, event_id, creator_user_id AS creator_id, venue_user_id AS venue_id,
        status, created_at, updated_at
 FROM collaborations
+WHERE updated_at > ?
 `
 
-const factCollaborationsIncrementalQuery = `
-SELECT id AS collaboration_id, DATE(created_at) AS date,
-       application_id, event_id, creator_user_id AS creator_id, venue_user_id AS venue_id,
-       status, created_at, updated_at
-FROM collaborations
-WHERE updated_at >= ?
-`
-
-// BuildFactCollaborationsAll полная перестройка.
-func BuildFactCollaborationsAll(db *gorm.DB, ch *clickhouse.Client) error {
-	return buildFactCollaborations(db, ch, factCollaborationsQuery)
+// BuildFactCollaborationsAll полная перестройка через теневую таблицу.
+func BuildFactCollaborationsAll(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "fact_collaborations", factCollaborationsLoader(db, ch))
 }
 
-// BuildFactCollaborationsIncremental обновляет строки, изменившиеся с указанной даты.
-func BuildFactCollaborationsIncremental(db *gorm.DB, ch *clickhouse.Client, since time.Time) error {
-	return buildFactCollaborations(db, ch, factCollaborationsIncrementalQuery, since.Format("2006-01-02"))
+// BuildFactCollaborationsIncremental обновляет строки, изменившиеся после водяного знака.
+func BuildFactCollaborationsIncremental(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadIncremental(context.Background(), ch, "fact_collaborations", factCollaborationsLoader(db, ch))
 }
 
-func buildFactCollaborations(db *gorm.DB, ch *clickhouse.Client, query string, args ...interface{}) error {
-	log.Println("[fact_collaborations] extracting from PostgreSQL...")
+func factCollaborationsLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, since time.Time) (int, time.Time, error) {
+		log.Println("[fact_collaborations] extracting from PostgreSQL...")
 
-	var rows []factCollaborationRow
-	if err := db.Raw(query, args...).Scan(&rows).Error; err != nil {
-		return fmt.Errorf("fact_collaborations extract: %w", err)
-	}
-	log.Printf("[fact_collaborations] extracted %d rows", len(rows))
+		var rows []factCollaborationRow
+		if err := db.Raw(factCollaborationsQuery, since.Format(pgTimestamp)).Scan(&rows).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_collaborations extract: %w", err)
+		}
+		log.Printf("[fact_collaborations] extracted %d rows", len(rows))
 
-	if len(rows) == 0 {
-		return nil
-	}
+		if len(rows) == 0 {
+			return 0, time.Time{}, nil
+		}
 
-	ctx := context.Background()
-	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO fact_collaborations")
-	if err != nil {
-		return fmt.Errorf("fact_collaborations prepare batch: %w", err)
-	}
-	for _, r := range rows {
-		dateID := toDateID(r.Date)
-		if err := batch.Append(
-			uint32(r.CollaborationID), r.Date, dateID,
-			uint32(r.ApplicationID), uint32(r.EventID),
-			uint32(r.CreatorID), uint32(r.VenueID),
-			r.Status, r.CreatedAt, r.UpdatedAt,
-		); err != nil {
-			return fmt.Errorf("fact_collaborations append: %w", err)
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_collaborations prepare batch: %w", err)
+		}
+		var maxUpdated time.Time
+		for _, r := range rows {
+			dateID := toDateID(r.Date)
+			if err := batch.Append(
+				uint32(r.CollaborationID), r.Date, dateID,
+				uint32(r.ApplicationID), uint32(r.EventID),
+				uint32(r.CreatorID), uint32(r.VenueID),
+				r.Status, r.CreatedAt, r.UpdatedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("fact_collaborations append: %w", err)
+			}
+			maxUpdated = maxTime(maxUpdated, r.UpdatedAt)
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_collaborations send: %w", err)
 		}
-	}
-	if err := batch.Send(); err != nil {
-		return fmt.Errorf("fact_collaborations send: %w", err)
-	}
 
-	log.Printf("[fact_collaborations] loaded %d rows into ClickHouse", len(rows))
-	return nil
+		log.Printf("[fact_collaborations] loaded %d rows into %s", len(rows), target)
+		return len(rows), maxUpdated, nil
+	}
 }
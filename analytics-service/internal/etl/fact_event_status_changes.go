@@ -0,0 +1,84 @@
+package etl
+
+import (
+	"analytics-service/internal/clickhouse"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type factEventStatusChangeRow struct {
+	ChangeID       int64     `gorm:"column:change_id"`
+	Date           time.Time `gorm:"column:date"`
+	EventID        int       `gorm:"column:event_id"`
+	CreatorID      int       `gorm:"column:creator_id"`
+	FromStatus     string    `gorm:"column:from_status"`
+	ToStatus       string    `gorm:"column:to_status"`
+	Reason         string    `gorm:"column:reason"`
+	ChangedAt      time.Time `gorm:"column:changed_at"`
+	EventCreatedAt time.Time `gorm:"column:event_created_at"`
+}
+
+// История статусов пишется event-service при каждом переходе и не меняется,
+// поэтому водяной знак ведётся по changed_at
+const factEventStatusChangesQuery = `
+SELECT h.id AS change_id, DATE(h.changed_at) AS date,
+       h.event_id, e.creator_id,
+       COALESCE(h.from_status, '') AS from_status, h.to_status, h.reason,
+       h.changed_at, e.created_at AS event_created_at
+FROM event_status_history h
+JOIN events e ON e.id = h.event_id
+WHERE h.changed_at > ?
+`
+
+// BuildFactEventStatusChanges полная перестройка через теневую таблицу
+func BuildFactEventStatusChanges(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "fact_event_status_changes", factEventStatusChangesLoader(db, ch))
+}
+
+// BuildFactEventStatusChangesIncremental догружает переходы после водяного знака
+func BuildFactEventStatusChangesIncremental(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadIncremental(context.Background(), ch, "fact_event_status_changes", factEventStatusChangesLoader(db, ch))
+}
+
+func factEventStatusChangesLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, since time.Time) (int, time.Time, error) {
+		log.Println("[fact_event_status_changes] extracting from PostgreSQL...")
+
+		var rows []factEventStatusChangeRow
+		if err := db.Raw(factEventStatusChangesQuery, since.Format(pgTimestamp)).Scan(&rows).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_event_status_changes extract: %w", err)
+		}
+		log.Printf("[fact_event_status_changes] extracted %d rows", len(rows))
+
+		if len(rows) == 0 {
+			return 0, time.Time{}, nil
+		}
+
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_event_status_changes prepare batch: %w", err)
+		}
+		var maxChanged time.Time
+		for _, r := range rows {
+			if err := batch.Append(
+				uint64(r.ChangeID), r.Date, toDateID(r.Date),
+				uint32(r.EventID), uint32(r.CreatorID),
+				r.FromStatus, r.ToStatus, r.Reason,
+				r.ChangedAt, r.EventCreatedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("fact_event_status_changes append: %w", err)
+			}
+			maxChanged = maxTime(maxChanged, r.ChangedAt)
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_event_status_changes send: %w", err)
+		}
+
+		log.Printf("[fact_event_status_changes] loaded %d rows into %s", len(rows), target)
+		return len(rows), maxChanged, nil
+	}
+}
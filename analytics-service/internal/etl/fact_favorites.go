@@ -0,0 +1,80 @@
+package etl
+
+import (
+	"analytics-service/internal/clickhouse"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type factFavoriteRow struct {
+	Kind         string    `gorm:"column:kind"`
+	Date         time.Time `gorm:"column:date"`
+	UserID       int       `gorm:"column:user_id"`
+	TargetUserID int       `gorm:"column:target_user_id"`
+	EventID      int       `gorm:"column:event_id"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+// Избранное: creator → площадка и площадка → мероприятие.
+// target_user_id — владелец того, что добавили в избранное.
+const factFavoritesQuery = `
+SELECT 'venue' AS kind, DATE(f.created_at) AS date,
+       f.creator_user_id AS user_id, f.venue_user_id AS target_user_id,
+       0 AS event_id, f.created_at
+FROM creator_favorite_venues f
+UNION ALL
+SELECT 'event' AS kind, DATE(f.created_at) AS date,
+       f.venue_user_id AS user_id, e.creator_id AS target_user_id,
+       f.event_id, f.created_at
+FROM venue_favorite_events f
+JOIN events e ON e.id = f.event_id
+`
+
+// BuildFactFavorites перестраивает витрину целиком при каждом запуске:
+// удалённые из избранного записи должны пропадать, а CDC удалений не видит.
+// Объём небольшой, подмена через теневую таблицу атомарна.
+func BuildFactFavorites(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "fact_favorites", factFavoritesLoader(db, ch))
+}
+
+func factFavoritesLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, _ time.Time) (int, time.Time, error) {
+		log.Println("[fact_favorites] extracting from PostgreSQL...")
+
+		var rows []factFavoriteRow
+		if err := db.Raw(factFavoritesQuery).Scan(&rows).Error; err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_favorites extract: %w", err)
+		}
+		log.Printf("[fact_favorites] extracted %d rows", len(rows))
+
+		if len(rows) == 0 {
+			return 0, time.Time{}, nil
+		}
+
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_favorites prepare batch: %w", err)
+		}
+		var maxCreated time.Time
+		for _, r := range rows {
+			if err := batch.Append(
+				r.Kind, r.Date, toDateID(r.Date),
+				uint32(r.UserID), uint32(r.TargetUserID), uint32(r.EventID),
+				r.CreatedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("fact_favorites append: %w", err)
+			}
+			maxCreated = maxTime(maxCreated, r.CreatedAt)
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("fact_favorites send: %w", err)
+		}
+
+		log.Printf("[fact_favorites] loaded %d rows into %s", len(rows), target)
+		return len(rows), maxCreated, nil
+	}
+}
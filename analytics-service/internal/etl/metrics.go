@@ -0,0 +1,59 @@
+package etl
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	etlRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "analytics_etl_runs_total",
+		Help: "Number of finished ETL runs",
+	}, []string{"kind", "status"})
+
+	etlRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "analytics_etl_running",
+		Help: "1 while an ETL run is in progress",
+	})
+
+	etlLastRunDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "analytics_etl_last_run_duration_seconds",
+		Help: "Duration of the last finished ETL run",
+	}, []string{"kind"})
+
+	etlLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "analytics_etl_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful ETL run",
+	}, []string{"kind"})
+
+	etlRowsLoaded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "analytics_etl_rows_loaded_total",
+		Help: "Rows loaded into ClickHouse marts",
+	}, []string{"table"})
+
+	etlTableFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "analytics_etl_table_failures_total",
+		Help: "Failed mart loads",
+	}, []string{"table"})
+
+	etlTableDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "analytics_etl_table_duration_seconds",
+		Help: "Duration of the last load of a mart",
+	}, []string{"table"})
+)
+
+func observeTable(res TableResult) {
+	etlRowsLoaded.WithLabelValues(res.Table).Add(float64(res.Rows))
+	etlTableDuration.WithLabelValues(res.Table).Set(float64(res.DurationMs) / 1000)
+	if res.Error != "" {
+		etlTableFailures.WithLabelValues(res.Table).Inc()
+	}
+}
+
+func observeRun(run *Run) {
+	etlRuns.WithLabelValues(run.Kind, run.Status).Inc()
+	etlLastRunDuration.WithLabelValues(run.Kind).Set(float64(run.DurationMs) / 1000)
+	if run.Status == StatusSucceeded && run.FinishedAt != nil {
+		etlLastSuccess.WithLabelValues(run.Kind).Set(float64(run.FinishedAt.Unix()))
+	}
+}
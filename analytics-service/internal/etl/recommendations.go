@@ -0,0 +1,191 @@
+package etl
+
+import (
+	"analytics-service/internal/clickhouse"
+	"analytics-service/internal/recommend"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recTopN — сколько лучших пар хранится для каждого мероприятия и каждой площадки
+const recTopN = 50
+
+// BuildRecommendations пересчитывает рекомендации целиком и подменяет
+// rec_matches через теневую таблицу. Скоры зависят от всех пар сразу
+// (места в выдаче), поэтому инкрементальной догрузки нет.
+func BuildRecommendations(db *gorm.DB, ch *clickhouse.Client) (int, error) {
+	return LoadFull(context.Background(), ch, "rec_matches", recommendationsLoader(db, ch))
+}
+
+func recommendationsLoader(db *gorm.DB, ch *clickhouse.Client) LoadFunc {
+	return func(ctx context.Context, target string, _ time.Time) (int, time.Time, error) {
+		log.Println("[rec_matches] extracting from PostgreSQL...")
+
+		in, err := extractRecommendationInput(db)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("rec_matches extract: %w", err)
+		}
+		log.Printf("[rec_matches] scoring %d events × %d venues", len(in.Events), len(in.Venues))
+
+		computedAt := time.Now().UTC()
+		matches := recommend.Compute(in, recTopN)
+		if len(matches) == 0 {
+			return 0, computedAt, nil
+		}
+
+		batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+target)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("rec_matches prepare batch: %w", err)
+		}
+		for _, m := range matches {
+			if err := batch.Append(
+				uint32(m.EventID), uint32(m.CreatorID), uint32(m.VenueID),
+				float32(m.Score), m.Reasons,
+				uint16(m.EventRank), uint16(m.VenueRank),
+				computedAt,
+			); err != nil {
+				return 0, time.Time{}, fmt.Errorf("rec_matches append: %w", err)
+			}
+		}
+		if err := batch.Send(); err != nil {
+			return 0, time.Time{}, fmt.Errorf("rec_matches send: %w", err)
+		}
+
+		log.Printf("[rec_matches] loaded %d rows into %s", len(matches), target)
+		return len(matches), computedAt, nil
+	}
+}
+
+func extractRecommendationInput(db *gorm.DB) (recommend.Input, error) {
+	in := recommend.Input{
+		CreatorFavorites: make(map[int]map[int]bool),
+		VenueFavorites:   make(map[int]map[int]bool),
+		Applied:          make(map[[2]int]bool),
+	}
+
+	var eventCats []eventCategoryRow
+	if err := db.Raw(`SELECT event_id, category_id FROM event_categories`).Scan(&eventCats).Error; err != nil {
+		return in, err
+	}
+	catsByEvent := make(map[int][]int)
+	for _, c := range eventCats {
+		catsByEvent[c.EventID] = append(catsByEvent[c.EventID], c.CategoryID)
+	}
+
+	var events []struct {
+		ID        int `gorm:"column:id"`
+		CreatorID int `gorm:"column:creator_id"`
+	}
+	if err := db.Raw(`SELECT id, creator_id FROM events WHERE is_active AND NOT is_completed`).Scan(&events).Error; err != nil {
+		return in, err
+	}
+	for _, e := range events {
+		in.Events = append(in.Events, recommend.Event{ID: e.ID, CreatorID: e.CreatorID, Categories: catsByEvent[e.ID]})
+	}
+
+	var venues []struct {
+		UserID   int `gorm:"column:user_id"`
+		CityID   int `gorm:"column:city_id"`
+		Capacity int `gorm:"column:capacity"`
+	}
+	if err := db.Raw(`
+		SELECT v.user_id, COALESCE(v.city_id, 0) AS city_id, COALESCE(v.capacity, 0) AS capacity
+		FROM venues v
+		JOIN users u ON u.id = v.user_id
+	`).Scan(&venues).Error; err != nil {
+		return in, err
+	}
+	var venueCats []struct {
+		UserID     int `gorm:"column:user_id"`
+		CategoryID int `gorm:"column:category_id"`
+	}
+	if err := db.Raw(`
+		SELECT v.user_id, vc.category_id
+		FROM venue_categories vc
+		JOIN venues v ON v.id = vc.venue_id
+		WHERE v.user_id IS NOT NULL
+	`).Scan(&venueCats).Error; err != nil {
+		return in, err
+	}
+	catsByVenue := make(map[int][]int)
+	for _, c := range venueCats {
+		catsByVenue[c.UserID] = append(catsByVenue[c.UserID], c.CategoryID)
+	}
+	for _, v := range venues {
+		in.Venues = append(in.Venues, recommend.Venue{UserID: v.UserID, CityID: v.CityID, Capacity: v.Capacity, Categories: catsByVenue[v.UserID]})
+	}
+
+	var collabs []struct {
+		EventID   int    `gorm:"column:event_id"`
+		CreatorID int    `gorm:"column:creator_user_id"`
+		VenueID   int    `gorm:"column:venue_user_id"`
+		Status    string `gorm:"column:status"`
+	}
+	if err := db.Raw(`
+		SELECT event_id, creator_user_id, venue_user_id, status
+		FROM collaborations
+		WHERE status <> 'cancelled'
+	`).Scan(&collabs).Error; err != nil {
+		return in, err
+	}
+	for _, c := range collabs {
+		in.Collaborations = append(in.Collaborations, recommend.Collaboration{
+			EventID:    c.EventID,
+			CreatorID:  c.CreatorID,
+			VenueID:    c.VenueID,
+			Completed:  c.Status == "completed",
+			Categories: catsByEvent[c.EventID],
+		})
+	}
+
+	var creatorFavs []struct {
+		CreatorID int `gorm:"column:creator_user_id"`
+		VenueID   int `gorm:"column:venue_user_id"`
+	}
+	if err := db.Raw(`SELECT creator_user_id, venue_user_id FROM creator_favorite_venues`).Scan(&creatorFavs).Error; err != nil {
+		return in, err
+	}
+	for _, f := range creatorFavs {
+		if in.CreatorFavorites[f.CreatorID] == nil {
+			in.CreatorFavorites[f.CreatorID] = make(map[int]bool)
+		}
+		in.CreatorFavorites[f.CreatorID][f.VenueID] = true
+	}
+
+	var venueFavs []struct {
+		VenueID int `gorm:"column:venue_user_id"`
+		EventID int `gorm:"column:event_id"`
+	}
+	if err := db.Raw(`SELECT venue_user_id, event_id FROM venue_favorite_events`).Scan(&venueFavs).Error; err != nil {
+		return in, err
+	}
+	for _, f := range venueFavs {
+		if in.VenueFavorites[f.VenueID] == nil {
+			in.VenueFavorites[f.VenueID] = make(map[int]bool)
+		}
+		in.VenueFavorites[f.VenueID][f.EventID] = true
+	}
+
+	// Любая заявка по мероприятию между сторонами (в том числе отклонённая)
+	// исключает пару из выдачи
+	var applied []struct {
+		EventID int `gorm:"column:event_id"`
+		VenueID int `gorm:"column:venue_id"`
+	}
+	if err := db.Raw(`
+		SELECT DISTINCT event_id,
+		       CASE WHEN sender_type = 'venue' THEN sender_id ELSE receiver_id END AS venue_id
+		FROM applications
+	`).Scan(&applied).Error; err != nil {
+		return in, err
+	}
+	for _, a := range applied {
+		in.Applied[[2]int{a.EventID, a.VenueID}] = true
+	}
+
+	return in, nil
+}
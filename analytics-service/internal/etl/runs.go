@@ -0,0 +1,262 @@
+package etl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"analytics-service/internal/clickhouse"
+
+	"github.com/google/uuid"
+)
+
+const (
+	KindFull        = "full"
+	KindIncremental = "incremental"
+
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrRunInProgress = errors.New("ETL_RUN_IN_PROGRESS")
+	ErrRunNotFound   = errors.New("ETL_RUN_NOT_FOUND")
+	ErrInvalidKind   = errors.New("INVALID_ETL_KIND")
+)
+
+// TableResult — итог загрузки одной витрины
+type TableResult struct {
+	Table      string `json:"table"`
+	Rows       int    `json:"rows"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Run — запуск ETL. Хранится в ClickHouse (etl_runs) и обновляется
+// после каждой витрины, так что по GET /etl/runs/:id виден прогресс.
+type Run struct {
+	ID         string        `json:"id"`
+	Kind       string        `json:"kind"`
+	Trigger    string        `json:"trigger"`
+	Status     string        `json:"status"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	DurationMs int64         `json:"duration_ms"`
+	Tables     []TableResult `json:"tables"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Runner запускает ETL асинхронно и не допускает параллельных запусков:
+// перестройки делят теневые таблицы и водяные знаки.
+type Runner struct {
+	builders *Builders
+	ch       *clickhouse.Client
+	lock     sync.Mutex
+}
+
+func NewRunner(builders *Builders, ch *clickhouse.Client) *Runner {
+	return &Runner{builders: builders, ch: ch}
+}
+
+// Start запускает ETL в фоне и сразу возвращает запись о запуске.
+// Если другой запуск ещё идёт, возвращает ErrRunInProgress.
+func (r *Runner) Start(kind, trigger string) (*Run, error) {
+	var steps []step
+	switch kind {
+	case KindFull:
+		steps = r.builders.fullSteps()
+	case KindIncremental:
+		steps = r.builders.incrementalSteps()
+	default:
+		return nil, ErrInvalidKind
+	}
+
+	if !r.lock.TryLock() {
+		return nil, ErrRunInProgress
+	}
+
+	run := &Run{
+		ID:        uuid.NewString(),
+		Kind:      kind,
+		Trigger:   trigger,
+		Status:    StatusRunning,
+		StartedAt: time.Now().UTC(),
+		Tables:    []TableResult{},
+	}
+	if err := r.save(run); err != nil {
+		r.lock.Unlock()
+		return nil, err
+	}
+
+	started := *run
+	go func() {
+		defer r.lock.Unlock()
+		r.execute(run, steps)
+	}()
+	return &started, nil
+}
+
+func (r *Runner) execute(run *Run, steps []step) {
+	log.Printf("[etl] run %s (%s, %s) started", run.ID, run.Kind, run.Trigger)
+	etlRunning.Set(1)
+	defer etlRunning.Set(0)
+
+	failed := 0
+	for _, s := range steps {
+		res := runStep(s)
+		if res.Error != "" {
+			failed++
+		}
+		run.Tables = append(run.Tables, res)
+		if err := r.save(run); err != nil {
+			log.Printf("[etl] run %s: save progress: %v", run.ID, err)
+		}
+	}
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Status = StatusSucceeded
+	if failed > 0 {
+		run.Status = StatusFailed
+		run.Error = fmt.Sprintf("%d of %d tables failed", failed, len(steps))
+	}
+	if err := r.save(run); err != nil {
+		log.Printf("[etl] run %s: save result: %v", run.ID, err)
+	}
+
+	observeRun(run)
+	log.Printf("[etl] run %s %s in %dms", run.ID, run.Status, run.DurationMs)
+}
+
+// runStep выполняет загрузку витрины; ошибка одной витрины не
+// останавливает остальные
+func runStep(s step) TableResult {
+	log.Printf("[etl] starting %s", s.table)
+	start := time.Now()
+	rows, err := s.fn()
+	res := TableResult{Table: s.table, Rows: rows, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		res.Error = err.Error()
+		log.Printf("[etl] %s failed: %v", s.table, err)
+	} else {
+		log.Printf("[etl] %s done", s.table)
+	}
+	observeTable(res)
+	return res
+}
+
+func (r *Runner) save(run *Run) error {
+	tables, err := json.Marshal(run.Tables)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	batch, err := r.ch.Conn().PrepareBatch(ctx, "INSERT INTO etl_runs")
+	if err != nil {
+		return fmt.Errorf("etl_runs prepare batch: %w", err)
+	}
+	if err := batch.Append(
+		run.ID, run.Kind, run.Trigger, run.Status,
+		run.StartedAt, run.FinishedAt, uint64(run.DurationMs),
+		string(tables), run.Error, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("etl_runs append: %w", err)
+	}
+	return batch.Send()
+}
+
+// FailInterrupted помечает упавшими запуски, оставшиеся в running после
+// перезапуска сервиса. Вызывается при старте, до первого запуска.
+func (r *Runner) FailInterrupted(ctx context.Context) error {
+	rows, err := r.ch.Conn().Query(ctx,
+		`SELECT `+runColumns+` FROM etl_runs FINAL WHERE status = ?`, StatusRunning)
+	if err != nil {
+		return fmt.Errorf("etl_runs interrupted: %w", err)
+	}
+	var runs []*Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		runs = append(runs, run)
+	}
+	rows.Close()
+
+	for _, run := range runs {
+		run.Status = StatusFailed
+		run.Error = "interrupted by service restart"
+		if err := r.save(run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const runColumns = `run_id, kind, trigger, status, started_at, finished_at, duration_ms, tables, error`
+
+// List возвращает последние запуски, новые первыми
+func (r *Runner) List(ctx context.Context, limit int) ([]Run, error) {
+	rows, err := r.ch.Conn().Query(ctx,
+		`SELECT `+runColumns+` FROM etl_runs FINAL ORDER BY started_at DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("etl_runs list: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []Run{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *Runner) Get(ctx context.Context, id string) (*Run, error) {
+	rows, err := r.ch.Conn().Query(ctx,
+		`SELECT `+runColumns+` FROM etl_runs FINAL WHERE run_id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("etl_runs get: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRunNotFound
+	}
+	return scanRun(rows)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRun(rows rowScanner) (*Run, error) {
+	var run Run
+	var duration uint64
+	var tables string
+	if err := rows.Scan(
+		&run.ID, &run.Kind, &run.Trigger, &run.Status,
+		&run.StartedAt, &run.FinishedAt, &duration, &tables, &run.Error,
+	); err != nil {
+		return nil, fmt.Errorf("etl_runs scan: %w", err)
+	}
+	run.DurationMs = int64(duration)
+	if err := json.Unmarshal([]byte(tables), &run.Tables); err != nil {
+		return nil, fmt.Errorf("etl_runs decode tables: %w", err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,42 @@
+package etl
+
+import (
+	"analytics-service/internal/clickhouse"
+	"analytics-service/internal/scd"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// applySCD2 загружает текущие версии из table, по scd.Plan закрывает
+// изменившиеся и открывает новые. Возвращает число новых версий.
+func applySCD2[T any, P interface {
+	*T
+	scd.Record
+}](ctx context.Context, ch *clickhouse.Client, table string, incoming []T) (int, error) {
+	var current []T
+	if err := ch.Conn().Select(ctx, &current, "SELECT * FROM "+table+" FINAL WHERE is_current = 1"); err != nil {
+		return 0, fmt.Errorf("%s load current: %w", table, err)
+	}
+
+	writes, opened := scd.Plan[T, P](current, incoming, time.Now().UTC())
+	if opened == 0 {
+		return 0, nil
+	}
+
+	batch, err := ch.Conn().PrepareBatch(ctx, "INSERT INTO "+table)
+	if err != nil {
+		return 0, fmt.Errorf("%s prepare batch: %w", table, err)
+	}
+	for _, row := range writes {
+		if err := batch.AppendStruct(row); err != nil {
+			return 0, fmt.Errorf("%s append version: %w", table, err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return 0, fmt.Errorf("%s send: %w", table, err)
+	}
+	log.Printf("[%s] opened %d new versions", table, opened)
+	return opened, nil
+}
@@ -0,0 +1,350 @@
+package recommend
+
+import (
+	"sort"
+)
+
+// Коды объяснений; подписи для клиента — в labels
+const (
+	ReasonSharedCategory  = "shared_category"
+	ReasonSameCity        = "same_city"
+	ReasonCapacityFit     = "capacity_fit"
+	ReasonCreatorFavorite = "creator_favorite"
+	ReasonVenueFavorite   = "venue_favorite"
+	ReasonWorkedTogether  = "worked_together"
+	ReasonSimilarAccepted = "similar_venues_accepted"
+)
+
+// weights — вклад сигналов в итоговый скор, в сумме 1
+var weights = map[string]float64{
+	ReasonSharedCategory:  0.30,
+	ReasonSameCity:        0.15,
+	ReasonCapacityFit:     0.10,
+	ReasonCreatorFavorite: 0.10,
+	ReasonVenueFavorite:   0.10,
+	ReasonWorkedTogether:  0.10,
+	ReasonSimilarAccepted: 0.15,
+}
+
+var labels = map[string]string{
+	ReasonSharedCategory:  "Shared category",
+	ReasonSameCity:        "Same city",
+	ReasonCapacityFit:     "Capacity fits your previous venues",
+	ReasonCreatorFavorite: "In your favorites",
+	ReasonVenueFavorite:   "Venue saved this event",
+	ReasonWorkedTogether:  "You have worked together before",
+	ReasonSimilarAccepted: "Similar venues accepted events like this",
+}
+
+const (
+	// minScore отсекает пары, где совпал только один слабый сигнал
+	minScore = 0.15
+	// similarAcceptedCap — столько похожих площадок дают полный вклад сигнала
+	similarAcceptedCap = 3
+)
+
+type Event struct {
+	ID         int
+	CreatorID  int
+	Categories []int
+}
+
+type Venue struct {
+	UserID     int
+	CityID     int
+	Capacity   int
+	Categories []int
+}
+
+// Collaboration — неотменённая коллаборация; Categories — категории её мероприятия
+type Collaboration struct {
+	EventID    int
+	CreatorID  int
+	VenueID    int
+	Completed  bool
+	Categories []int
+}
+
+// Input — срез данных Postgres, на котором считаются рекомендации
+type Input struct {
+	// Events — только активные незавершённые мероприятия
+	Events         []Event
+	Venues         []Venue
+	Collaborations []Collaboration
+	// CreatorFavorites[creatorID][venueID], VenueFavorites[venueID][eventID]
+	CreatorFavorites map[int]map[int]bool
+	VenueFavorites   map[int]map[int]bool
+	// Applied — пары {eventID, venueID}, по которым уже была заявка
+	Applied map[[2]int]bool
+}
+
+// Match — скор пары мероприятие–площадка. EventRank — место площадки
+// в выдаче для мероприятия, VenueRank — место мероприятия в выдаче
+// для площадки; 0 — пара не попала в топ с этой стороны.
+type Match struct {
+	EventID   int
+	CreatorID int
+	VenueID   int
+	Score     float64
+	Reasons   []string
+	EventRank int
+	VenueRank int
+}
+
+// Compute считает скоры всех пар и оставляет topN лучших с каждой стороны
+func Compute(in Input, topN int) []Match {
+	venues := make(map[int]*Venue, len(in.Venues))
+	for i := range in.Venues {
+		venues[in.Venues[i].UserID] = &in.Venues[i]
+	}
+	profiles := creatorProfiles(in.Collaborations, in.CreatorFavorites, venues)
+	simAccepted := similarAccepted(in.Venues, in.Collaborations)
+
+	worked := make(map[[2]int]bool)
+	for _, c := range in.Collaborations {
+		if c.Completed {
+			worked[[2]int{c.CreatorID, c.VenueID}] = true
+		}
+	}
+
+	var matches []Match
+	for _, e := range in.Events {
+		eventCats := toSet(e.Categories)
+		profile := profiles[e.CreatorID]
+		for i := range in.Venues {
+			v := &in.Venues[i]
+			if in.Applied[[2]int{e.ID, v.UserID}] {
+				continue
+			}
+
+			contrib := make(map[string]float64)
+			if len(eventCats) > 0 {
+				shared := 0
+				for _, c := range v.Categories {
+					if eventCats[c] {
+						shared++
+					}
+				}
+				if shared > 0 {
+					contrib[ReasonSharedCategory] = weights[ReasonSharedCategory] * float64(shared) / float64(len(eventCats))
+				}
+			}
+			if profile.homeCity != 0 && v.CityID == profile.homeCity {
+				contrib[ReasonSameCity] = weights[ReasonSameCity]
+			}
+			if profile.medianCapacity > 0 && v.Capacity > 0 &&
+				v.Capacity*2 >= profile.medianCapacity && v.Capacity <= profile.medianCapacity*2 {
+				contrib[ReasonCapacityFit] = weights[ReasonCapacityFit]
+			}
+			if in.CreatorFavorites[e.CreatorID][v.UserID] {
+				contrib[ReasonCreatorFavorite] = weights[ReasonCreatorFavorite]
+			}
+			if in.VenueFavorites[v.UserID][e.ID] {
+				contrib[ReasonVenueFavorite] = weights[ReasonVenueFavorite]
+			}
+			if worked[[2]int{e.CreatorID, v.UserID}] {
+				contrib[ReasonWorkedTogether] = weights[ReasonWorkedTogether]
+			}
+			best := 0
+			for c := range eventCats {
+				if n := simAccepted[v.UserID][c]; n > best {
+					best = n
+				}
+			}
+			if best > 0 {
+				contrib[ReasonSimilarAccepted] = weights[ReasonSimilarAccepted] * float64(min(best, similarAcceptedCap)) / similarAcceptedCap
+			}
+
+			score := 0.0
+			for _, w := range contrib {
+				score += w
+			}
+			if score < minScore {
+				continue
+			}
+			matches = append(matches, Match{
+				EventID:   e.ID,
+				CreatorID: e.CreatorID,
+				VenueID:   v.UserID,
+				Score:     score,
+				Reasons:   orderedReasons(contrib),
+			})
+		}
+	}
+
+	rank(matches, topN)
+	kept := matches[:0]
+	for _, m := range matches {
+		if m.EventRank > 0 || m.VenueRank > 0 {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+type creatorProfile struct {
+	homeCity       int
+	medianCapacity int
+}
+
+// creatorProfiles: у creator'а нет города и вместимости в профиле, поэтому
+// «свой» город — самый частый город площадок из коллабораций и избранного,
+// а ориентир по вместимости — медиана площадок, с которыми он работал
+func creatorProfiles(collabs []Collaboration, favorites map[int]map[int]bool, venues map[int]*Venue) map[int]creatorProfile {
+	cityVotes := make(map[int]map[int]int)
+	capacities := make(map[int][]int)
+	vote := func(creatorID, venueID int) {
+		v, ok := venues[venueID]
+		if !ok || v.CityID == 0 {
+			return
+		}
+		if cityVotes[creatorID] == nil {
+			cityVotes[creatorID] = make(map[int]int)
+		}
+		cityVotes[creatorID][v.CityID]++
+	}
+	for _, c := range collabs {
+		vote(c.CreatorID, c.VenueID)
+		if v, ok := venues[c.VenueID]; ok && v.Capacity > 0 {
+			capacities[c.CreatorID] = append(capacities[c.CreatorID], v.Capacity)
+		}
+	}
+	for creatorID, favs := range favorites {
+		for venueID := range favs {
+			vote(creatorID, venueID)
+		}
+	}
+
+	profiles := make(map[int]creatorProfile, len(cityVotes))
+	for creatorID, votes := range cityVotes {
+		p := profiles[creatorID]
+		best := 0
+		for city, n := range votes {
+			if n > best || (n == best && city < p.homeCity) {
+				p.homeCity, best = city, n
+			}
+		}
+		profiles[creatorID] = p
+	}
+	for creatorID, caps := range capacities {
+		sort.Ints(caps)
+		p := profiles[creatorID]
+		p.medianCapacity = caps[len(caps)/2]
+		profiles[creatorID] = p
+	}
+	return profiles
+}
+
+// similarAccepted[venueID][categoryID] — сколько похожих площадок (тот же
+// город и хотя бы одна общая категория) уже брали мероприятия этой категории
+func similarAccepted(venues []Venue, collabs []Collaboration) map[int]map[int]int {
+	acceptedCats := make(map[int]map[int]bool)
+	for _, c := range collabs {
+		if acceptedCats[c.VenueID] == nil {
+			acceptedCats[c.VenueID] = make(map[int]bool)
+		}
+		for _, cat := range c.Categories {
+			acceptedCats[c.VenueID][cat] = true
+		}
+	}
+
+	byCity := make(map[int][]*Venue)
+	for i := range venues {
+		if venues[i].CityID != 0 {
+			byCity[venues[i].CityID] = append(byCity[venues[i].CityID], &venues[i])
+		}
+	}
+
+	result := make(map[int]map[int]int)
+	for i := range venues {
+		v := &venues[i]
+		cats := toSet(v.Categories)
+		counts := make(map[int]int)
+		for _, u := range byCity[v.CityID] {
+			if u.UserID == v.UserID || len(acceptedCats[u.UserID]) == 0 || !overlaps(cats, u.Categories) {
+				continue
+			}
+			for cat := range acceptedCats[u.UserID] {
+				counts[cat]++
+			}
+		}
+		result[v.UserID] = counts
+	}
+	return result
+}
+
+// rank проставляет места с обеих сторон: по убыванию скора, при равенстве — по id
+func rank(matches []Match, topN int) {
+	idx := make([]int, len(matches))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	byScore := func(key func(m *Match) (int, int)) {
+		sort.Slice(idx, func(a, b int) bool {
+			ma, mb := &matches[idx[a]], &matches[idx[b]]
+			ga, ta := key(ma)
+			gb, tb := key(mb)
+			if ga != gb {
+				return ga < gb
+			}
+			if ma.Score != mb.Score {
+				return ma.Score > mb.Score
+			}
+			return ta < tb
+		})
+	}
+
+	byScore(func(m *Match) (int, int) { return m.EventID, m.VenueID })
+	group, n := -1, 0
+	for _, i := range idx {
+		if matches[i].EventID != group {
+			group, n = matches[i].EventID, 0
+		}
+		if n++; n <= topN {
+			matches[i].EventRank = n
+		}
+	}
+
+	byScore(func(m *Match) (int, int) { return m.VenueID, m.EventID })
+	group, n = -1, 0
+	for _, i := range idx {
+		if matches[i].VenueID != group {
+			group, n = matches[i].VenueID, 0
+		}
+		if n++; n <= topN {
+			matches[i].VenueRank = n
+		}
+	}
+}
+
+func orderedReasons(contrib map[string]float64) []string {
+	reasons := make([]string, 0, len(contrib))
+	for r := range contrib {
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if contrib[reasons[i]] != contrib[reasons[j]] {
+			return contrib[reasons[i]] > contrib[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	return reasons
+}
+
+func toSet(ids []int) map[int]bool {
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func overlaps(set map[int]bool, ids []int) bool {
+	for _, id := range ids {
+		if set[id] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package recommend
+
+import (
+	"analytics-service/internal/clickhouse"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrEventNotFound = errors.New("EVENT_NOT_FOUND")
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+)
+
+type Reason struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+}
+
+type VenueRecommendation struct {
+	VenueID  uint32   `json:"venue_id"`
+	Name     string   `json:"name"`
+	City     string   `json:"city"`
+	Capacity uint32   `json:"capacity"`
+	Score    float32  `json:"score"`
+	Reasons  []Reason `json:"reasons"`
+}
+
+type EventRecommendation struct {
+	EventID   uint32   `json:"event_id"`
+	CreatorID uint32   `json:"creator_id"`
+	Title     string   `json:"title"`
+	Score     float32  `json:"score"`
+	Reasons   []Reason `json:"reasons"`
+}
+
+// Store читает предрассчитанные ETL рекомендации из rec_matches
+type Store struct {
+	ch *clickhouse.Client
+}
+
+func NewStore(ch *clickhouse.Client) *Store {
+	return &Store{ch: ch}
+}
+
+// VenuesForEvent — площадки для мероприятия creator'а. Чужое или
+// неизвестное мероприятие — ErrEventNotFound.
+func (s *Store) VenuesForEvent(ctx context.Context, creatorID, eventID, limit int) ([]VenueRecommendation, time.Time, error) {
+	var owner uint32
+	err := s.ch.Conn().QueryRow(ctx,
+		`SELECT creator_id FROM dim_event FINAL WHERE event_id = ?`, uint32(eventID),
+	).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && owner != uint32(creatorID)) {
+		return nil, time.Time{}, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("event owner query: %w", err)
+	}
+
+	rows, err := s.ch.Conn().Query(ctx, `
+		SELECT r.venue_id, u.name, u.city, u.capacity, r.score, r.reasons, r.computed_at
+		FROM rec_matches AS r
+		LEFT JOIN (
+		    SELECT user_id, name, city, capacity FROM dim_user FINAL
+		    WHERE user_id IN (SELECT venue_id FROM rec_matches WHERE event_id = ?)
+		) AS u ON u.user_id = r.venue_id
+		WHERE r.event_id = ? AND r.event_rank > 0
+		ORDER BY r.event_rank
+		LIMIT ?`,
+		uint32(eventID), uint32(eventID), clampLimit(limit))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("venues for event query: %w", err)
+	}
+	defer rows.Close()
+
+	result := []VenueRecommendation{}
+	var computedAt time.Time
+	for rows.Next() {
+		var rec VenueRecommendation
+		var reasons []string
+		if err := rows.Scan(&rec.VenueID, &rec.Name, &rec.City, &rec.Capacity, &rec.Score, &reasons, &computedAt); err != nil {
+			return nil, time.Time{}, fmt.Errorf("venues for event scan: %w", err)
+		}
+		rec.Reasons = explain(reasons)
+		result = append(result, rec)
+	}
+	return result, computedAt, rows.Err()
+}
+
+// EventsForVenue — мероприятия для площадки. Мероприятия, снятые с каталога
+// после расчёта, отфильтровываются по dim_event.
+func (s *Store) EventsForVenue(ctx context.Context, venueID, limit int) ([]EventRecommendation, time.Time, error) {
+	rows, err := s.ch.Conn().Query(ctx, `
+		SELECT r.event_id, r.creator_id, e.title, r.score, r.reasons, r.computed_at
+		FROM rec_matches AS r
+		INNER JOIN (
+		    SELECT event_id, title FROM dim_event FINAL
+		    WHERE is_active = 1 AND is_completed = 0
+		      AND event_id IN (SELECT event_id FROM rec_matches WHERE venue_id = ?)
+		) AS e ON e.event_id = r.event_id
+		WHERE r.venue_id = ? AND r.venue_rank > 0
+		ORDER BY r.venue_rank
+		LIMIT ?`,
+		uint32(venueID), uint32(venueID), clampLimit(limit))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("events for venue query: %w", err)
+	}
+	defer rows.Close()
+
+	result := []EventRecommendation{}
+	var computedAt time.Time
+	for rows.Next() {
+		var rec EventRecommendation
+		var reasons []string
+		if err := rows.Scan(&rec.EventID, &rec.CreatorID, &rec.Title, &rec.Score, &reasons, &computedAt); err != nil {
+			return nil, time.Time{}, fmt.Errorf("events for venue scan: %w", err)
+		}
+		rec.Reasons = explain(reasons)
+		result = append(result, rec)
+	}
+	return result, computedAt, rows.Err()
+}
+
+func explain(codes []string) []Reason {
+	reasons := make([]Reason, 0, len(codes))
+	for _, c := range codes {
+		reasons = append(reasons, Reason{Code: c, Label: labels[c]})
+	}
+	return reasons
+}
+
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return min(limit, maxLimit)
+}
@@ -0,0 +1,61 @@
+package reports
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLinkInvalid = errors.New("LINK_INVALID")
+	ErrLinkExpired = errors.New("LINK_EXPIRED")
+)
+
+// Signer выдаёт ссылки на скачивание выгрузок с подписью HMAC-SHA256
+// и сроком действия. Ссылки открываются без авторизации (в том числе
+// из письма), поэтому подпись покрывает и ключ файла, и срок.
+type Signer struct {
+	secret  []byte
+	ttl     time.Duration
+	baseURL string
+}
+
+// NewSigner: baseURL — внешний адрес analytics-service за gateway,
+// например https://example.com/api/analytics
+func NewSigner(secret string, ttl time.Duration, baseURL string) *Signer {
+	return &Signer{secret: []byte(secret), ttl: ttl, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// URL возвращает подписанную ссылку на файл, действующую ttl от now
+func (s *Signer) URL(key string, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("sig", s.sign(key, expires))
+	return s.baseURL + "/report-files/" + key + "?" + q.Encode()
+}
+
+// Verify проверяет подпись и срок ссылки
+func (s *Signer) Verify(key, expires, sig string, now time.Time) error {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(sig), []byte(s.sign(key, expires))) {
+		return ErrLinkInvalid
+	}
+	if now.Unix() > exp {
+		return ErrLinkExpired
+	}
+	return nil
+}
+
+func (s *Signer) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write([]byte(expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
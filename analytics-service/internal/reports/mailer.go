@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Notifier рассылает получателям отчёта ссылки на готовые файлы
+type Notifier interface {
+	Notify(ctx context.Context, report *Report, run *Run) error
+}
+
+// SMTPNotifier отправляет письмо через SMTP. Без SMTP_HOST уведомления
+// отключены: отчёты формируются, ссылки доступны через API.
+type SMTPNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPNotifier(host, port, username, password, from string) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPNotifier{addr: net.JoinHostPort(host, port), auth: auth, from: from}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, report *Report, run *Run) error {
+	if len(report.Recipients) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Отчёт «%s» сформирован %s UTC.\r\n\r\n", report.Name, run.StartedAt.UTC().Format("2006-01-02 15:04"))
+	for _, f := range run.Files {
+		fmt.Fprintf(&body, "%s: %s\r\n", strings.ToUpper(f.Format), f.URL)
+	}
+	body.WriteString("\r\nСсылки действуют ограниченное время.\r\n")
+
+	msg := "From: " + n.from + "\r\n" +
+		"To: " + strings.Join(report.Recipients, ", ") + "\r\n" +
+		"Subject: " + mime.BEncoding.Encode("UTF-8", "Отчёт: "+report.Name) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"Content-Transfer-Encoding: 8bit\r\n\r\n" +
+		body.String()
+
+	return smtp.SendMail(n.addr, n.auth, n.from, report.Recipients, []byte(msg))
+}
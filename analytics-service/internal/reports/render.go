@@ -0,0 +1,96 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const sheetName = "Report"
+
+var contentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Render формирует файл отчёта в указанном формате
+func Render(format string, t Table) ([]byte, error) {
+	switch format {
+	case FormatCSV:
+		return renderCSV(t)
+	case FormatXLSX:
+		return renderXLSX(t)
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// renderCSV пишет UTF-8 с BOM, чтобы Excel корректно открывал кириллицу
+func renderCSV(t Table) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\uFEFF")
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, v := range row {
+			record[i] = cellString(v)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func renderXLSX(t Table) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c
+	}
+	if err := f.SetSheetRow(sheetName, "A1", &header); err != nil {
+		return nil, err
+	}
+	for i, row := range t.Rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(sheetName, cell, &row); err != nil {
+			return nil, fmt.Errorf("xlsx row %d: %w", i+2, err)
+		}
+	}
+
+	// Жирная закреплённая шапка и автофильтр по всем колонкам
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	lastCol, _ := excelize.ColumnNumberToName(len(t.Columns))
+	if err := f.SetCellStyle(sheetName, "A1", lastCol+"1", bold); err != nil {
+		return nil, err
+	}
+	if err := f.SetPanes(sheetName, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return nil, err
+	}
+	lastCell, _ := excelize.CoordinatesToCellName(len(t.Columns), len(t.Rows)+1)
+	if err := f.AutoFilter(sheetName, "A1:"+lastCell, nil); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,160 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+const (
+	maxPeriodDays = 3 * 365
+	maxRecipients = 20
+)
+
+var (
+	ErrReportNotFound   = errors.New("REPORT_NOT_FOUND")
+	ErrRunInProgress    = errors.New("REPORT_RUN_IN_PROGRESS")
+	ErrInvalidKind      = errors.New("INVALID_REPORT_KIND")
+	ErrInvalidSchedule  = errors.New("INVALID_SCHEDULE")
+	ErrInvalidFormat    = errors.New("INVALID_FORMAT")
+	ErrInvalidRecipient = errors.New("INVALID_RECIPIENT")
+	ErrValidation       = errors.New("VALIDATION_ERROR")
+)
+
+// Report — сохранённый отчёт: витрина (kind), скользящее окно в днях,
+// расписание в формате cron и получатели ссылок на выгрузку
+type Report struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	Kind        string     `gorm:"not null" json:"kind"`
+	PeriodDays  int        `gorm:"not null" json:"period_days"`
+	Granularity string     `gorm:"not null" json:"granularity"`
+	TopLimit    int        `gorm:"column:top_limit;not null" json:"limit"`
+	Formats     []string   `gorm:"serializer:json;type:jsonb" json:"formats"`
+	Schedule    string     `gorm:"not null" json:"schedule"`
+	Recipients  []string   `gorm:"serializer:json;type:jsonb" json:"recipients"`
+	Enabled     bool       `json:"enabled"`
+	CreatedBy   int        `gorm:"not null" json:"created_by"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Report) TableName() string { return "analytics_reports" }
+
+// File — выгрузка одного формата; URL заполняется только в ответах API
+type File struct {
+	Format    string `json:"format"`
+	ObjectKey string `json:"object_key"`
+	Size      int64  `json:"size"`
+	URL       string `json:"url,omitempty"`
+}
+
+type Run struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ReportID   int        `gorm:"not null" json:"report_id"`
+	Trigger    string     `gorm:"not null" json:"trigger"`
+	Status     string     `gorm:"not null" json:"status"`
+	Rows       int        `json:"rows"`
+	Files      []File     `gorm:"serializer:json;type:jsonb" json:"files"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"autoCreateTime" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func (Run) TableName() string { return "analytics_report_runs" }
+
+// Input — тело POST/PUT /reports. Незаданные поля получают значения по умолчанию.
+type Input struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	PeriodDays  int      `json:"period_days"`
+	Granularity string   `json:"granularity"`
+	Limit       int      `json:"limit"`
+	Formats     []string `json:"formats"`
+	Schedule    string   `json:"schedule"`
+	Recipients  []string `json:"recipients"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// Apply проверяет ввод и переносит его в отчёт
+func (in Input) Apply(r *Report) error {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > 200 {
+		return fmt.Errorf("%w: name is required and must be at most 200 characters", ErrValidation)
+	}
+	if _, ok := kinds[in.Kind]; !ok {
+		return ErrInvalidKind
+	}
+
+	if in.PeriodDays == 0 {
+		in.PeriodDays = 30
+	}
+	if in.PeriodDays < 1 || in.PeriodDays > maxPeriodDays {
+		return fmt.Errorf("%w: period_days must be between 1 and %d", ErrValidation, maxPeriodDays)
+	}
+	if in.Granularity == "" {
+		in.Granularity = "day"
+	}
+	if in.Granularity != "day" && in.Granularity != "week" && in.Granularity != "month" {
+		return fmt.Errorf("%w: granularity must be one of day, week, month", ErrValidation)
+	}
+
+	if len(in.Formats) == 0 {
+		in.Formats = []string{FormatCSV}
+	}
+	formats := make([]string, 0, len(in.Formats))
+	seen := make(map[string]bool)
+	for _, f := range in.Formats {
+		f = strings.ToLower(f)
+		if f != FormatCSV && f != FormatXLSX {
+			return ErrInvalidFormat
+		}
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+
+	if _, err := cron.ParseStandard(in.Schedule); err != nil {
+		return ErrInvalidSchedule
+	}
+
+	if len(in.Recipients) > maxRecipients {
+		return fmt.Errorf("%w: at most %d recipients", ErrInvalidRecipient, maxRecipients)
+	}
+	recipients := make([]string, 0, len(in.Recipients))
+	for _, addr := range in.Recipients {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidRecipient, addr)
+		}
+		recipients = append(recipients, parsed.Address)
+	}
+
+	r.Name = name
+	r.Kind = in.Kind
+	r.PeriodDays = in.PeriodDays
+	r.Granularity = in.Granularity
+	r.TopLimit = in.Limit
+	r.Formats = formats
+	r.Schedule = in.Schedule
+	r.Recipients = recipients
+	r.Enabled = in.Enabled == nil || *in.Enabled
+	return nil
+}
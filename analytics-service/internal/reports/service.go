@@ -0,0 +1,317 @@
+package reports
+
+import (
+	"analytics-service/internal/stats"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	runTimeout      = 2 * time.Minute
+	defaultRunLimit = 20
+	maxRunLimit     = 100
+)
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Service управляет сохранёнными отчётами: хранит их в Postgres,
+// ставит в расписание cron и формирует выгрузки в хранилище.
+type Service struct {
+	db       *gorm.DB
+	store    *stats.Store
+	storage  Storage
+	signer   *Signer
+	notifier Notifier
+
+	cron    *cron.Cron
+	mu      sync.Mutex
+	entries map[int]cron.EntryID
+	running map[int]bool
+}
+
+// NewService: notifier может быть nil — тогда письма не отправляются
+func NewService(db *gorm.DB, store *stats.Store, storage Storage, signer *Signer, notifier Notifier) *Service {
+	return &Service{
+		db:       db,
+		store:    store,
+		storage:  storage,
+		signer:   signer,
+		notifier: notifier,
+		cron:     cron.New(),
+		entries:  make(map[int]cron.EntryID),
+		running:  make(map[int]bool),
+	}
+}
+
+// Start ставит в расписание все включённые отчёты и запускает планировщик
+func (s *Service) Start() error {
+	var reports []Report
+	if err := s.db.Where("enabled = ?", true).Find(&reports).Error; err != nil {
+		return fmt.Errorf("load reports: %w", err)
+	}
+	for i := range reports {
+		s.schedule(&reports[i])
+	}
+	s.cron.Start()
+	log.Printf("[reports] scheduled %d reports", len(reports))
+	return nil
+}
+
+// Stop останавливает планировщик; уже идущие выгрузки дорабатывают
+func (s *Service) Stop() {
+	s.cron.Stop()
+}
+
+func (s *Service) List() ([]Report, error) {
+	var reports []Report
+	err := s.db.Order("id").Find(&reports).Error
+	return reports, err
+}
+
+func (s *Service) Get(id int) (*Report, error) {
+	var report Report
+	err := s.db.First(&report, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReportNotFound
+	}
+	return &report, err
+}
+
+func (s *Service) Create(in Input, createdBy int) (*Report, error) {
+	report := &Report{CreatedBy: createdBy}
+	if err := in.Apply(report); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(report).Error; err != nil {
+		return nil, err
+	}
+	s.schedule(report)
+	return report, nil
+}
+
+func (s *Service) Update(id int, in Input) (*Report, error) {
+	report, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := in.Apply(report); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(report).Error; err != nil {
+		return nil, err
+	}
+	s.schedule(report)
+	return report, nil
+}
+
+// Delete удаляет отчёт и историю запусков; уже выгруженные файлы
+// остаются в хранилище, ссылки на них действуют до истечения срока
+func (s *Service) Delete(id int) error {
+	result := s.db.Delete(&Report{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReportNotFound
+	}
+	s.unschedule(id)
+	return nil
+}
+
+// ListRuns возвращает последние запуски отчёта с подписанными ссылками
+func (s *Service) ListRuns(reportID, limit int) ([]Run, error) {
+	if _, err := s.Get(reportID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRunLimit
+	}
+	if limit > maxRunLimit {
+		limit = maxRunLimit
+	}
+
+	var runs []Run
+	if err := s.db.Where("report_id = ?", reportID).Order("started_at DESC, id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range runs {
+		s.sign(&runs[i], now)
+	}
+	return runs, nil
+}
+
+// Run запускает формирование отчёта в фоне и сразу возвращает запись запуска.
+// Одновременно формируется не более одной выгрузки каждого отчёта.
+func (s *Service) Run(reportID int, trigger string) (*Run, error) {
+	report, err := s.Get(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.running[reportID] {
+		s.mu.Unlock()
+		return nil, ErrRunInProgress
+	}
+	s.running[reportID] = true
+	s.mu.Unlock()
+
+	run := &Run{ReportID: reportID, Trigger: trigger, Status: RunStatusRunning, Files: []File{}}
+	if err := s.db.Create(run).Error; err != nil {
+		s.release(reportID)
+		return nil, err
+	}
+
+	go func() {
+		defer s.release(reportID)
+		s.execute(report, run)
+	}()
+	return run, nil
+}
+
+func (s *Service) release(reportID int) {
+	s.mu.Lock()
+	delete(s.running, reportID)
+	s.mu.Unlock()
+}
+
+func (s *Service) execute(report *Report, run *Run) {
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := s.generate(ctx, report, run)
+	finished := time.Now()
+	run.FinishedAt = &finished
+	if err != nil {
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+		log.Printf("[reports] report %d run %d failed: %v", report.ID, run.ID, err)
+	} else {
+		run.Status = RunStatusSucceeded
+		log.Printf("[reports] report %d run %d: %d rows in %s", report.ID, run.ID, run.Rows, time.Since(started).Round(time.Millisecond))
+	}
+
+	if err := s.db.Save(run).Error; err != nil {
+		log.Printf("[reports] save run %d: %v", run.ID, err)
+	}
+	if err := s.db.Model(&Report{}).Where("id = ?", report.ID).UpdateColumn("last_run_at", run.StartedAt).Error; err != nil {
+		log.Printf("[reports] update report %d last_run_at: %v", report.ID, err)
+	}
+
+	if run.Status == RunStatusSucceeded && s.notifier != nil && len(report.Recipients) > 0 {
+		notified := *run
+		notified.Files = append([]File(nil), run.Files...)
+		s.sign(&notified, finished)
+		if err := s.notifier.Notify(ctx, report, &notified); err != nil {
+			log.Printf("[reports] notify recipients of report %d: %v", report.ID, err)
+		}
+	}
+}
+
+// generate считает отчёт за окно из period_days полных дней, заканчивающееся
+// вчера, и кладёт файлы всех форматов в хранилище
+func (s *Service) generate(ctx context.Context, report *Report, run *Run) error {
+	query, ok := kinds[report.Kind]
+	if !ok {
+		return ErrInvalidKind
+	}
+
+	to := time.Now().UTC().AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -report.PeriodDays+1)
+	p, err := stats.ParseParams(from.Format("2006-01-02"), to.Format("2006-01-02"), report.Granularity, report.TopLimit)
+	if err != nil {
+		return err
+	}
+
+	table, err := query(ctx, s.store, p)
+	if err != nil {
+		return err
+	}
+	run.Rows = len(table.Rows)
+
+	base := fmt.Sprintf("%d/%d/%s_%s_%s", report.ID, run.ID, slug(report.Name),
+		p.From.Format("20060102"), p.To.Format("20060102"))
+	for _, format := range report.Formats {
+		data, err := Render(format, table)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", format, err)
+		}
+		key := base + "." + format
+		if err := s.storage.Put(ctx, key, data, contentTypes[format]); err != nil {
+			return fmt.Errorf("upload %s: %w", format, err)
+		}
+		run.Files = append(run.Files, File{Format: format, ObjectKey: key, Size: int64(len(data))})
+	}
+	return nil
+}
+
+func (s *Service) sign(run *Run, now time.Time) {
+	for i := range run.Files {
+		run.Files[i].URL = s.signer.URL(run.Files[i].ObjectKey, now)
+	}
+}
+
+// schedule (пере)ставит отчёт в расписание; выключенный отчёт снимается
+func (s *Service) schedule(report *Report) {
+	s.unschedule(report.ID)
+	if !report.Enabled {
+		return
+	}
+
+	reportID := report.ID
+	id, err := s.cron.AddFunc(report.Schedule, func() {
+		if _, err := s.Run(reportID, "cron"); err != nil {
+			log.Printf("[reports] scheduled run of report %d skipped: %v", reportID, err)
+		}
+	})
+	if err != nil {
+		// Расписание проверяется при сохранении, сюда попадают только старые записи
+		log.Printf("[reports] report %d has invalid schedule %q: %v", reportID, report.Schedule, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.entries[reportID] = id
+	s.mu.Unlock()
+}
+
+func (s *Service) unschedule(reportID int) {
+	s.mu.Lock()
+	id, ok := s.entries[reportID]
+	delete(s.entries, reportID)
+	s.mu.Unlock()
+	if ok {
+		s.cron.Remove(id)
+	}
+}
+
+// slug — безопасная для имени файла часть названия отчёта
+func slug(name string) string {
+	s := strings.Trim(unsafeNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if s == "" {
+		return "report"
+	}
+	if len(s) > 50 {
+		s = strings.TrimRight(s[:50], "-")
+	}
+	return s
+}
+
+// OpenFile проверяет подписанную ссылку и открывает файл выгрузки
+func (s *Service) OpenFile(ctx context.Context, key, expires, sig string) (*Object, error) {
+	if err := s.signer.Verify(key, expires, sig, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.storage.Get(ctx, key)
+}
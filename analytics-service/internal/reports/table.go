@@ -0,0 +1,121 @@
+package reports
+
+import (
+	"analytics-service/internal/stats"
+	"context"
+	"strconv"
+)
+
+// Table — результат отчёта в табличном виде для CSV/XLSX
+type Table struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+type queryFunc func(ctx context.Context, store *stats.Store, p stats.Params) (Table, error)
+
+// kinds — витрины, на которых можно строить отчёты; ключ совпадает
+// с путём соответствующей ручки /stats/*
+var kinds = map[string]queryFunc{
+	"funnel":          funnelTable,
+	"registrations":   registrationsTable,
+	"top-categories":  topCategoriesTable,
+	"top-cities":      topCitiesTable,
+	"event-lifecycle": lifecycleTable,
+}
+
+func funnelTable(ctx context.Context, store *stats.Store, p stats.Params) (Table, error) {
+	points, err := store.Funnel(ctx, p)
+	if err != nil {
+		return Table{}, err
+	}
+	t := Table{Columns: []string{"period", "applications", "accepted", "completed", "acceptance_rate", "completion_rate"}}
+	for _, pt := range points {
+		t.Rows = append(t.Rows, []interface{}{pt.Period, pt.Applications, pt.Accepted, pt.Completed, pt.AcceptanceRate, pt.CompletionRate})
+	}
+	return t, nil
+}
+
+func registrationsTable(ctx context.Context, store *stats.Store, p stats.Params) (Table, error) {
+	points, err := store.Registrations(ctx, p)
+	if err != nil {
+		return Table{}, err
+	}
+	t := Table{Columns: []string{"period", "creators", "venues", "total"}}
+	for _, pt := range points {
+		t.Rows = append(t.Rows, []interface{}{pt.Period, pt.Creators, pt.Venues, pt.Total})
+	}
+	return t, nil
+}
+
+func topCategoriesTable(ctx context.Context, store *stats.Store, p stats.Params) (Table, error) {
+	periods, err := store.TopCategories(ctx, p)
+	if err != nil {
+		return Table{}, err
+	}
+	t := Table{Columns: []string{"period", "rank", "category_id", "category", "applications", "accepted"}}
+	for _, period := range periods {
+		for i, it := range period.Items {
+			t.Rows = append(t.Rows, []interface{}{period.Period, i + 1, it.CategoryID, it.Name, it.Applications, it.Accepted})
+		}
+	}
+	return t, nil
+}
+
+func topCitiesTable(ctx context.Context, store *stats.Store, p stats.Params) (Table, error) {
+	periods, err := store.TopCities(ctx, p)
+	if err != nil {
+		return Table{}, err
+	}
+	t := Table{Columns: []string{"period", "rank", "city", "applications", "accepted"}}
+	for _, period := range periods {
+		for i, it := range period.Items {
+			t.Rows = append(t.Rows, []interface{}{period.Period, i + 1, it.City, it.Applications, it.Accepted})
+		}
+	}
+	return t, nil
+}
+
+func lifecycleTable(ctx context.Context, store *stats.Store, p stats.Params) (Table, error) {
+	points, err := store.EventLifecycle(ctx, p)
+	if err != nil {
+		return Table{}, err
+	}
+	t := Table{Columns: []string{
+		"period", "published", "with_applications", "matched", "completed", "match_rate",
+		"median_time_to_first_application_seconds", "median_time_to_match_seconds",
+	}}
+	for _, pt := range points {
+		t.Rows = append(t.Rows, []interface{}{
+			pt.Period, pt.Published, pt.WithApplications, pt.Matched, pt.Completed, pt.MatchRate,
+			optional(pt.MedianTimeToFirstApplicationSec), optional(pt.MedianTimeToMatchSec),
+		})
+	}
+	return t, nil
+}
+
+// optional — пустая ячейка вместо отсутствующей медианы
+func optional(v *float64) interface{} {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// cellString — текстовое представление ячейки для CSV
+func cellString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(x)
+	case uint32:
+		return strconv.FormatUint(uint64(x), 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	default:
+		return ""
+	}
+}
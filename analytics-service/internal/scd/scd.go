@@ -0,0 +1,73 @@
+package scd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// OpenValidTo — valid_to открытой (текущей) версии
+var OpenValidTo = time.Date(2106, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Record — версия измерения в таблице *_history (SCD type 2).
+// Ключ таблицы — (id, valid_from), поэтому закрытие версии — это
+// перезапись той же строки с valid_to и большим version.
+type Record interface {
+	SCDKey() uint32
+	SCDHash() uint64
+	SCDValidFrom() time.Time
+	SCDChangedAt() time.Time
+	StartAt(t time.Time)
+	CloseAt(validTo, version time.Time)
+}
+
+// Plan сравнивает пришедшие состояния с текущими версиями и для
+// изменившихся закрывает старую версию и открывает новую. Возвращает
+// строки для записи (закрытые и новые версии по порядку) и число новых
+// версий. Неизменившиеся строки (в том числе повторно выгруженные из-за
+// перекрытия водяного знака) пропускаются.
+//
+// Первая версия сущности начинается с её создания, чтобы к ней
+// присоединялись и факты, появившиеся до первого запуска ETL;
+// последующие — с момента изменения (updated_at источника).
+func Plan[T any, P interface {
+	*T
+	Record
+}](current, incoming []T, version time.Time) (writes []P, opened int) {
+	byKey := make(map[uint32]P, len(current))
+	for i := range current {
+		cur := P(&current[i])
+		byKey[cur.SCDKey()] = cur
+	}
+
+	for i := range incoming {
+		in := P(&incoming[i])
+		cur, ok := byKey[in.SCDKey()]
+		if ok && cur.SCDHash() == in.SCDHash() {
+			continue
+		}
+		if ok {
+			// valid_from новой версии строго позже старой, иначе интервалы пересекутся
+			from := in.SCDChangedAt()
+			if !from.After(cur.SCDValidFrom()) {
+				from = cur.SCDValidFrom().Add(time.Second)
+			}
+			in.StartAt(from)
+			cur.CloseAt(in.SCDValidFrom(), version)
+			writes = append(writes, cur)
+		}
+		writes = append(writes, in)
+		byKey[in.SCDKey()] = in
+		opened++
+	}
+	return writes, opened
+}
+
+// AttrsHash — отпечаток отслеживаемых атрибутов версии
+func AttrsHash(attrs ...interface{}) uint64 {
+	h := fnv.New64a()
+	for _, a := range attrs {
+		fmt.Fprintf(h, "%v\x00", a)
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,126 @@
+package scd
+
+import (
+	"slices"
+	"time"
+)
+
+// User — отслеживаемое состояние пользователя из источника
+type User struct {
+	UserID        int
+	Role          string
+	Name          string
+	City          string
+	Capacity      int
+	ProfileFilled int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// UserVersion — версия пользователя в dim_user_history. Описание
+// не отслеживается: его правки не влияют на аналитику и плодили бы версии.
+type UserVersion struct {
+	UserID        uint32    `ch:"user_id"`
+	Role          string    `ch:"role"`
+	Name          string    `ch:"name"`
+	City          string    `ch:"city"`
+	Capacity      uint32    `ch:"capacity"`
+	ProfileFilled uint8     `ch:"profile_filled"`
+	AttrsHash     uint64    `ch:"attrs_hash"`
+	ValidFrom     time.Time `ch:"valid_from"`
+	ValidTo       time.Time `ch:"valid_to"`
+	IsCurrent     uint8     `ch:"is_current"`
+	Version       time.Time `ch:"version"`
+
+	changedAt time.Time
+}
+
+func (r *UserVersion) SCDKey() uint32          { return r.UserID }
+func (r *UserVersion) SCDHash() uint64         { return r.AttrsHash }
+func (r *UserVersion) SCDValidFrom() time.Time { return r.ValidFrom }
+func (r *UserVersion) SCDChangedAt() time.Time { return r.changedAt }
+func (r *UserVersion) StartAt(t time.Time)     { r.ValidFrom = t }
+func (r *UserVersion) CloseAt(validTo, version time.Time) {
+	r.ValidTo, r.IsCurrent, r.Version = validTo, 0, version
+}
+
+func NewUserVersion(u User) UserVersion {
+	return UserVersion{
+		UserID:        uint32(u.UserID),
+		Role:          u.Role,
+		Name:          u.Name,
+		City:          u.City,
+		Capacity:      uint32(u.Capacity),
+		ProfileFilled: uint8(u.ProfileFilled),
+		AttrsHash:     AttrsHash(u.Role, u.Name, u.City, u.Capacity, u.ProfileFilled),
+		ValidFrom:     u.CreatedAt.UTC().Truncate(time.Second),
+		ValidTo:       OpenValidTo,
+		IsCurrent:     1,
+		Version:       time.Now().UTC(),
+		changedAt:     u.UpdatedAt.UTC().Truncate(time.Second),
+	}
+}
+
+// Event — отслеживаемое состояние мероприятия из источника
+type Event struct {
+	EventID     int
+	CreatorID   int
+	Title       string
+	IsActive    bool
+	IsCompleted bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// EventVersion — версия мероприятия в dim_event_history
+type EventVersion struct {
+	EventID     uint32    `ch:"event_id"`
+	CreatorID   uint32    `ch:"creator_id"`
+	Title       string    `ch:"title"`
+	CategoryIDs []uint32  `ch:"category_ids"`
+	IsActive    uint8     `ch:"is_active"`
+	IsCompleted uint8     `ch:"is_completed"`
+	AttrsHash   uint64    `ch:"attrs_hash"`
+	ValidFrom   time.Time `ch:"valid_from"`
+	ValidTo     time.Time `ch:"valid_to"`
+	IsCurrent   uint8     `ch:"is_current"`
+	Version     time.Time `ch:"version"`
+
+	changedAt time.Time
+}
+
+func (r *EventVersion) SCDKey() uint32          { return r.EventID }
+func (r *EventVersion) SCDHash() uint64         { return r.AttrsHash }
+func (r *EventVersion) SCDValidFrom() time.Time { return r.ValidFrom }
+func (r *EventVersion) SCDChangedAt() time.Time { return r.changedAt }
+func (r *EventVersion) StartAt(t time.Time)     { r.ValidFrom = t }
+func (r *EventVersion) CloseAt(validTo, version time.Time) {
+	r.ValidTo, r.IsCurrent, r.Version = validTo, 0, version
+}
+
+func NewEventVersion(e Event, categoryIDs []uint32) EventVersion {
+	// Хеш зависит от порядка элементов: один и тот же набор категорий
+	// не должен давать новую версию
+	categoryIDs = slices.Sorted(slices.Values(categoryIDs))
+	return EventVersion{
+		EventID:     uint32(e.EventID),
+		CreatorID:   uint32(e.CreatorID),
+		Title:       e.Title,
+		CategoryIDs: categoryIDs,
+		IsActive:    flag(e.IsActive),
+		IsCompleted: flag(e.IsCompleted),
+		AttrsHash:   AttrsHash(e.CreatorID, e.Title, categoryIDs, e.IsActive, e.IsCompleted),
+		ValidFrom:   e.CreatedAt.UTC().Truncate(time.Second),
+		ValidTo:     OpenValidTo,
+		IsCurrent:   1,
+		Version:     time.Now().UTC(),
+		changedAt:   e.UpdatedAt.UTC().Truncate(time.Second),
+	}
+}
+
+func flag(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
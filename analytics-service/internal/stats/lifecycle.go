@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LifecyclePoint — жизненный цикл мероприятий, впервые опубликованных в периоде.
+// Медианы — в секундах от публикации; nil, если в периоде нет ни одного случая.
+type LifecyclePoint struct {
+	Period                          string   `json:"period"`
+	Published                       uint64   `json:"published"`
+	WithApplications                uint64   `json:"with_applications"`
+	Matched                         uint64   `json:"matched"`
+	Completed                       uint64   `json:"completed"`
+	MatchRate                       float64  `json:"match_rate"`
+	MedianTimeToFirstApplicationSec *float64 `json:"median_time_to_first_application_seconds"`
+	MedianTimeToMatchSec            *float64 `json:"median_time_to_match_seconds"`
+}
+
+// EventLifecycle строится по fact_event_status_changes: публикация — первый
+// переход в active, матч — первое снятие с каталога по принятой заявке.
+// Первая заявка берётся из fact_applications.
+func (s *Store) EventLifecycle(ctx context.Context, p Params) ([]LifecyclePoint, error) {
+	query := fmt.Sprintf(`
+		SELECT %s(toDate(l.published_at)) AS period,
+		       count() AS published,
+		       countIf(a.event_id != 0) AS with_applications,
+		       countIf(l.matched) AS matched,
+		       countIf(l.completed) AS completed,
+		       quantileIf(0.5)(toFloat64(dateDiff('second', l.published_at, a.first_application_at)), a.event_id != 0) AS ttfa,
+		       quantileIf(0.5)(toFloat64(dateDiff('second', l.published_at, l.matched_at)), l.matched) AS ttm
+		FROM (
+		    SELECT event_id,
+		           minIf(changed_at, to_status = 'active') AS published_at,
+		           minIf(changed_at, reason = 'application_accepted') AS matched_at,
+		           countIf(reason = 'application_accepted') > 0 AS matched,
+		           countIf(to_status = 'completed') > 0 AS completed
+		    FROM fact_event_status_changes FINAL
+		    GROUP BY event_id
+		    HAVING countIf(to_status = 'active') > 0
+		       AND toDate(published_at) BETWEEN toDate(?) AND toDate(?)
+		) AS l
+		LEFT JOIN (
+		    SELECT event_id, min(created_at) AS first_application_at
+		    FROM fact_applications FINAL
+		    GROUP BY event_id
+		) AS a ON a.event_id = l.event_id
+		GROUP BY period
+		ORDER BY period`, p.bucket())
+
+	rows, err := s.ch.Conn().Query(ctx, query, p.fromStr(), p.toStr())
+	if err != nil {
+		return nil, fmt.Errorf("event lifecycle query: %w", err)
+	}
+	defer rows.Close()
+
+	points := []LifecyclePoint{}
+	for rows.Next() {
+		var period time.Time
+		var ttfa, ttm float64
+		var pt LifecyclePoint
+		if err := rows.Scan(&period, &pt.Published, &pt.WithApplications, &pt.Matched, &pt.Completed, &ttfa, &ttm); err != nil {
+			return nil, fmt.Errorf("event lifecycle scan: %w", err)
+		}
+		pt.Period = period.Format(dateLayout)
+		pt.MatchRate = ratio(pt.Matched, pt.Published)
+		if pt.WithApplications > 0 {
+			pt.MedianTimeToFirstApplicationSec = &ttfa
+		}
+		if pt.Matched > 0 {
+			pt.MedianTimeToMatchSec = &ttm
+		}
+		points = append(points, pt)
+	}
+	return points, rows.Err()
+}
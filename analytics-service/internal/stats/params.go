@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+const (
+	defaultRangeDays = 30
+	maxRangeDays     = 3 * 366
+	defaultTopLimit  = 10
+	maxTopLimit      = 50
+)
+
+var (
+	ErrInvalidDate        = errors.New("INVALID_DATE")
+	ErrInvalidRange       = errors.New("INVALID_RANGE")
+	ErrInvalidGranularity = errors.New("INVALID_GRANULARITY")
+)
+
+// granularities — функция ClickHouse, приводящая Date к началу периода
+var granularities = map[string]string{
+	"day":   "toDate",
+	"week":  "toMonday",
+	"month": "toStartOfMonth",
+}
+
+// Params — общий диапазон дат (включительно) и шаг агрегации
+type Params struct {
+	From        time.Time
+	To          time.Time
+	Granularity string
+	Limit       int
+}
+
+// ParseParams разбирает from/to (YYYY-MM-DD), granularity и limit.
+// По умолчанию — последние 30 дней по дням.
+func ParseParams(from, to, granularity string, limit int) (Params, error) {
+	p := Params{Granularity: granularity, Limit: limit}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	p.To = today
+	if to != "" {
+		t, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return p, fmt.Errorf("%w: to", ErrInvalidDate)
+		}
+		p.To = t
+	}
+	p.From = p.To.AddDate(0, 0, -defaultRangeDays+1)
+	if from != "" {
+		t, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return p, fmt.Errorf("%w: from", ErrInvalidDate)
+		}
+		p.From = t
+	}
+	if p.From.After(p.To) || p.To.Sub(p.From) > maxRangeDays*24*time.Hour {
+		return p, ErrInvalidRange
+	}
+
+	if p.Granularity == "" {
+		p.Granularity = "day"
+	}
+	if _, ok := granularities[p.Granularity]; !ok {
+		return p, ErrInvalidGranularity
+	}
+
+	if p.Limit <= 0 {
+		p.Limit = defaultTopLimit
+	}
+	if p.Limit > maxTopLimit {
+		p.Limit = maxTopLimit
+	}
+	return p, nil
+}
+
+func (p Params) bucket() string {
+	return granularities[p.Granularity]
+}
+
+func (p Params) fromStr() string { return p.From.Format(dateLayout) }
+func (p Params) toStr() string   { return p.To.Format(dateLayout) }
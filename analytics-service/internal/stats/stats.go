@@ -0,0 +1,272 @@
+package stats
+
+import (
+	"analytics-service/internal/clickhouse"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store читает агрегаты из витрин ClickHouse. Postgres нужен только
+// для названий категорий, которых нет в витринах.
+type Store struct {
+	db *gorm.DB
+	ch *clickhouse.Client
+}
+
+func NewStore(db *gorm.DB, ch *clickhouse.Client) *Store {
+	return &Store{db: db, ch: ch}
+}
+
+type FunnelPoint struct {
+	Period         string  `json:"period"`
+	Applications   uint64  `json:"applications"`
+	Accepted       uint64  `json:"accepted"`
+	Completed      uint64  `json:"completed"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+type RegistrationPoint struct {
+	Period   string `json:"period"`
+	Creators uint64 `json:"creators"`
+	Venues   uint64 `json:"venues"`
+	Total    uint64 `json:"total"`
+}
+
+type CategoryStat struct {
+	CategoryID   uint32 `json:"category_id"`
+	Name         string `json:"name"`
+	Applications uint64 `json:"applications"`
+	Accepted     uint64 `json:"accepted"`
+}
+
+type CityStat struct {
+	City         string `json:"city"`
+	Applications uint64 `json:"applications"`
+	Accepted     uint64 `json:"accepted"`
+}
+
+// TopPeriod — топ за один период
+type TopPeriod[T any] struct {
+	Period string `json:"period"`
+	Items  []T    `json:"items"`
+}
+
+// Funnel: заявки, созданные в периоде → принятые → завершённые коллаборации
+func (s *Store) Funnel(ctx context.Context, p Params) ([]FunnelPoint, error) {
+	query := fmt.Sprintf(`
+		SELECT %s(a.date) AS period,
+		       count() AS applications,
+		       countIf(a.status = 'accepted') AS accepted,
+		       countIf(c.status = 'completed') AS completed
+		FROM (
+		    SELECT application_id, date, status
+		    FROM fact_applications FINAL
+		    WHERE date BETWEEN toDate(?) AND toDate(?)
+		) AS a
+		LEFT JOIN (
+		    SELECT application_id, status FROM fact_collaborations FINAL
+		) AS c ON c.application_id = a.application_id
+		GROUP BY period
+		ORDER BY period`, p.bucket())
+
+	rows, err := s.ch.Conn().Query(ctx, query, p.fromStr(), p.toStr())
+	if err != nil {
+		return nil, fmt.Errorf("funnel query: %w", err)
+	}
+	defer rows.Close()
+
+	points := []FunnelPoint{}
+	for rows.Next() {
+		var period time.Time
+		var pt FunnelPoint
+		if err := rows.Scan(&period, &pt.Applications, &pt.Accepted, &pt.Completed); err != nil {
+			return nil, fmt.Errorf("funnel scan: %w", err)
+		}
+		pt.Period = period.Format(dateLayout)
+		pt.AcceptanceRate = ratio(pt.Accepted, pt.Applications)
+		pt.CompletionRate = ratio(pt.Completed, pt.Accepted)
+		points = append(points, pt)
+	}
+	return points, rows.Err()
+}
+
+// Registrations считает регистрации по календарю dim_date, поэтому
+// периоды без регистраций тоже попадают в ответ с нулями
+func (s *Store) Registrations(ctx context.Context, p Params) ([]RegistrationPoint, error) {
+	query := fmt.Sprintf(`
+		SELECT %s(d.date) AS period,
+		       countIf(u.role = 'creator') AS creators,
+		       countIf(u.role = 'venue') AS venues
+		FROM dim_date AS d
+		LEFT JOIN (
+		    SELECT user_id, role, registration_date FROM dim_user FINAL
+		) AS u ON u.registration_date = d.date
+		WHERE d.date BETWEEN toDate(?) AND toDate(?)
+		GROUP BY period
+		ORDER BY period`, p.bucket())
+
+	rows, err := s.ch.Conn().Query(ctx, query, p.fromStr(), p.toStr())
+	if err != nil {
+		return nil, fmt.Errorf("registrations query: %w", err)
+	}
+	defer rows.Close()
+
+	points := []RegistrationPoint{}
+	for rows.Next() {
+		var period time.Time
+		var pt RegistrationPoint
+		if err := rows.Scan(&period, &pt.Creators, &pt.Venues); err != nil {
+			return nil, fmt.Errorf("registrations scan: %w", err)
+		}
+		pt.Period = period.Format(dateLayout)
+		pt.Total = pt.Creators + pt.Venues
+		points = append(points, pt)
+	}
+	return points, rows.Err()
+}
+
+// TopCategories — категории мероприятий по числу заявок в каждом периоде.
+// Заявка относится к категориям той версии мероприятия, что действовала
+// в момент её создания (ASOF JOIN к dim_event_history).
+func (s *Store) TopCategories(ctx context.Context, p Params) ([]TopPeriod[CategoryStat], error) {
+	query := fmt.Sprintf(`
+		SELECT %s(a.date) AS period,
+		       arrayJoin(e.category_ids) AS category_id,
+		       count() AS applications,
+		       countIf(a.status = 'accepted') AS accepted
+		FROM (
+		    SELECT event_id, date, status, created_at
+		    FROM fact_applications FINAL
+		    WHERE date BETWEEN toDate(?) AND toDate(?)
+		) AS a
+		ASOF INNER JOIN (
+		    SELECT event_id, category_ids, valid_from FROM dim_event_history FINAL
+		) AS e ON e.event_id = a.event_id AND a.created_at >= e.valid_from
+		GROUP BY period, category_id
+		ORDER BY period, applications DESC, category_id
+		LIMIT ? BY period`, p.bucket())
+
+	rows, err := s.ch.Conn().Query(ctx, query, p.fromStr(), p.toStr(), p.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("top categories query: %w", err)
+	}
+	defer rows.Close()
+
+	var result []TopPeriod[CategoryStat]
+	var ids []uint32
+	for rows.Next() {
+		var period time.Time
+		var st CategoryStat
+		if err := rows.Scan(&period, &st.CategoryID, &st.Applications, &st.Accepted); err != nil {
+			return nil, fmt.Errorf("top categories scan: %w", err)
+		}
+		result = appendTop(result, period.Format(dateLayout), st)
+		ids = append(ids, st.CategoryID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	names, err := s.categoryNames(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		for j := range result[i].Items {
+			result[i].Items[j].Name = names[result[i].Items[j].CategoryID]
+		}
+	}
+	return nonNil(result), nil
+}
+
+// TopCities — города площадок, участвующих в заявках (отправителем или получателем).
+// Город берётся из версии профиля площадки на момент создания заявки,
+// поэтому переезд площадки не переписывает прошлую статистику.
+func (s *Store) TopCities(ctx context.Context, p Params) ([]TopPeriod[CityStat], error) {
+	query := fmt.Sprintf(`
+		SELECT %s(a.date) AS period,
+		       u.city,
+		       count() AS applications,
+		       countIf(a.status = 'accepted') AS accepted
+		FROM (
+		    SELECT date, status, created_at,
+		           if(sender_type = 'venue', sender_id, receiver_id) AS venue_id
+		    FROM fact_applications FINAL
+		    WHERE date BETWEEN toDate(?) AND toDate(?)
+		) AS a
+		ASOF INNER JOIN (
+		    SELECT user_id, city, valid_from FROM dim_user_history FINAL
+		    WHERE role = 'venue'
+		) AS u ON u.user_id = a.venue_id AND a.created_at >= u.valid_from
+		WHERE u.city != ''
+		GROUP BY period, u.city
+		ORDER BY period, applications DESC, u.city
+		LIMIT ? BY period`, p.bucket())
+
+	rows, err := s.ch.Conn().Query(ctx, query, p.fromStr(), p.toStr(), p.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("top cities query: %w", err)
+	}
+	defer rows.Close()
+
+	var result []TopPeriod[CityStat]
+	for rows.Next() {
+		var period time.Time
+		var st CityStat
+		if err := rows.Scan(&period, &st.City, &st.Applications, &st.Accepted); err != nil {
+			return nil, fmt.Errorf("top cities scan: %w", err)
+		}
+		result = appendTop(result, period.Format(dateLayout), st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return nonNil(result), nil
+}
+
+func (s *Store) categoryNames(ids []uint32) (map[uint32]string, error) {
+	names := make(map[uint32]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	var rows []struct {
+		ID   uint32 `gorm:"column:id"`
+		Name string `gorm:"column:name"`
+	}
+	if err := s.db.Raw(`SELECT id, name FROM categories WHERE id IN ?`, ids).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("category names: %w", err)
+	}
+	for _, r := range rows {
+		names[r.ID] = r.Name
+	}
+	return names, nil
+}
+
+// appendTop добавляет строку в последний период или открывает новый;
+// строки приходят отсортированными по периоду
+func appendTop[T any](result []TopPeriod[T], period string, item T) []TopPeriod[T] {
+	if n := len(result); n > 0 && result[n-1].Period == period {
+		result[n-1].Items = append(result[n-1].Items, item)
+		return result
+	}
+	return append(result, TopPeriod[T]{Period: period, Items: []T{item}})
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func ratio(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UserStats — личная статистика creator'а или площадки за период
+type UserStats struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+
+	Sent     map[string]uint64 `json:"applications_sent"`
+	Received map[string]uint64 `json:"applications_received"`
+
+	// AcceptanceRate — доля отправленных заявок, которые приняли
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	// MedianResponseSeconds — медиана времени ответа на входящие заявки;
+	// nil, если на заявки за период ещё не отвечали
+	MedianResponseSeconds *float64 `json:"median_response_seconds"`
+
+	CompletedCollaborations []CountPoint `json:"completed_collaborations"`
+	FavoritesReceived       uint64       `json:"favorites_received"`
+}
+
+type CountPoint struct {
+	Period string `json:"period"`
+	Count  uint64 `json:"count"`
+}
+
+func (s *Store) UserStats(ctx context.Context, userID int, role string, p Params) (*UserStats, error) {
+	st := &UserStats{
+		UserID:   userID,
+		Role:     role,
+		Sent:     map[string]uint64{},
+		Received: map[string]uint64{},
+	}
+
+	if err := s.applicationsByStatus(ctx, st, p); err != nil {
+		return nil, err
+	}
+	if err := s.medianResponse(ctx, st, p); err != nil {
+		return nil, err
+	}
+
+	completed, err := s.completedCollaborations(ctx, userID, p)
+	if err != nil {
+		return nil, err
+	}
+	st.CompletedCollaborations = completed
+
+	if err := s.ch.Conn().QueryRow(ctx, `
+		SELECT count() FROM fact_favorites
+		WHERE target_user_id = ?
+		  AND date BETWEEN toDate(?) AND toDate(?)`,
+		uint32(userID), p.fromStr(), p.toStr(),
+	).Scan(&st.FavoritesReceived); err != nil {
+		return nil, fmt.Errorf("favorites received: %w", err)
+	}
+
+	return st, nil
+}
+
+func (s *Store) applicationsByStatus(ctx context.Context, st *UserStats, p Params) error {
+	uid := uint32(st.UserID)
+	rows, err := s.ch.Conn().Query(ctx, `
+		SELECT sender_id = ? AS sent, status, count() AS cnt
+		FROM fact_applications FINAL
+		WHERE (sender_id = ? OR receiver_id = ?)
+		  AND date BETWEEN toDate(?) AND toDate(?)
+		GROUP BY sent, status`,
+		uid, uid, uid, p.fromStr(), p.toStr())
+	if err != nil {
+		return fmt.Errorf("applications by status: %w", err)
+	}
+	defer rows.Close()
+
+	var sentTotal uint64
+	for rows.Next() {
+		var sent uint8
+		var status string
+		var cnt uint64
+		if err := rows.Scan(&sent, &status, &cnt); err != nil {
+			return fmt.Errorf("applications by status scan: %w", err)
+		}
+		if sent == 1 {
+			st.Sent[status] = cnt
+			sentTotal += cnt
+		} else {
+			st.Received[status] = cnt
+		}
+	}
+	st.AcceptanceRate = ratio(st.Sent["accepted"], sentTotal)
+	return rows.Err()
+}
+
+// medianResponse: время от создания входящей заявки до ответа. Пока
+// заявка не в pending, updated_at — момент принятия или отклонения.
+func (s *Store) medianResponse(ctx context.Context, st *UserStats, p Params) error {
+	var answered uint64
+	var median float64
+	err := s.ch.Conn().QueryRow(ctx, `
+		SELECT count(), quantile(0.5)(dateDiff('second', created_at, updated_at))
+		FROM fact_applications FINAL
+		WHERE receiver_id = ? AND status IN ('accepted', 'rejected')
+		  AND date BETWEEN toDate(?) AND toDate(?)`,
+		uint32(st.UserID), p.fromStr(), p.toStr(),
+	).Scan(&answered, &median)
+	if err != nil {
+		return fmt.Errorf("median response: %w", err)
+	}
+	if answered > 0 {
+		st.MedianResponseSeconds = &median
+	}
+	return nil
+}
+
+// completedCollaborations — завершённые коллаборации по дате завершения,
+// периоды без завершений заполняются нулями через dim_date
+func (s *Store) completedCollaborations(ctx context.Context, userID int, p Params) ([]CountPoint, error) {
+	uid := uint32(userID)
+	query := fmt.Sprintf(`
+		SELECT %s(d.date) AS period,
+		       countIf(c.collaboration_id != 0) AS completed
+		FROM dim_date AS d
+		LEFT JOIN (
+		    SELECT collaboration_id, toDate(updated_at) AS completed_date
+		    FROM fact_collaborations FINAL
+		    WHERE status = 'completed' AND (creator_id = ? OR venue_id = ?)
+		) AS c ON c.completed_date = d.date
+		WHERE d.date BETWEEN toDate(?) AND toDate(?)
+		GROUP BY period
+		ORDER BY period`, p.bucket())
+
+	rows, err := s.ch.Conn().Query(ctx, query, uid, uid, p.fromStr(), p.toStr())
+	if err != nil {
+		return nil, fmt.Errorf("completed collaborations: %w", err)
+	}
+	defer rows.Close()
+
+	points := []CountPoint{}
+	for rows.Next() {
+		var period time.Time
+		var pt CountPoint
+		if err := rows.Scan(&period, &pt.Count); err != nil {
+			return nil, fmt.Errorf("completed collaborations scan: %w", err)
+		}
+		pt.Period = period.Format(dateLayout)
+		points = append(points, pt)
+	}
+	return points, rows.Err()
+}
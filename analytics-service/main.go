@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,10 +12,15 @@ import (
 	"syscall"
 	"time"
 
+	"analytics-service/internal/api"
 	"analytics-service/internal/clickhouse"
 	"analytics-service/internal/config"
 	"analytics-service/internal/etl"
+	"analytics-service/internal/recommend"
+	"analytics-service/internal/reports"
+	"analytics-service/internal/stats"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/robfig/cron/v3"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -57,26 +64,71 @@ func main() {
 	log.Println("ClickHouse schemas ready")
 
 	builders := etl.NewBuilders(db, chClient)
+	runner := etl.NewRunner(builders, chClient)
+	if err := runner.FailInterrupted(context.Background()); err != nil {
+		log.Printf("failed to close interrupted ETL runs: %v", err)
+	}
 
 	// Полная перестройка при старте (в фоне, чтобы не задерживать /health)
-	go func() {
-		log.Println("running initial full ETL...")
-		builders.RunFull()
-		log.Println("initial ETL complete")
-	}()
+	if _, err := runner.Start(etl.KindFull, "startup"); err != nil {
+		log.Printf("initial ETL not started: %v", err)
+	}
 
-	// Ежедневный крон в 03:00 UTC
+	// Инкрементальная догрузка по водяным знакам каждые 15 минут и полная
+	// перестройка в 03:00 UTC — она убирает из витрин удалённые в Postgres строки.
+	// Если предыдущий запуск ещё идёт, очередной пропускается.
 	c := cron.New()
+	if _, err := c.AddFunc("*/15 * * * *", func() {
+		if _, err := runner.Start(etl.KindIncremental, "cron"); err != nil {
+			log.Printf("incremental ETL skipped: %v", err)
+		}
+	}); err != nil {
+		log.Fatalf("failed to add cron job: %v", err)
+	}
 	if _, err := c.AddFunc("0 3 * * *", func() {
-		log.Println("running daily ETL...")
-		builders.RunDaily()
-		log.Println("daily ETL complete")
+		if _, err := runner.Start(etl.KindFull, "cron"); err != nil {
+			log.Printf("nightly full ETL skipped: %v", err)
+		}
 	}); err != nil {
 		log.Fatalf("failed to add cron job: %v", err)
 	}
 	c.Start()
 	defer c.Stop()
 
+	store := stats.NewStore(db, chClient)
+
+	// Отчёты: выгрузки в MinIO, ссылки подписываются REPORT_LINK_SECRET
+	reportStorage, err := reports.NewMinioStorage(cfg.MinioEndpoint, cfg.MinioAccessKey, cfg.MinioSecretKey, cfg.MinioUseSSL, cfg.ReportsBucket)
+	if err != nil {
+		log.Fatalf("failed to init report storage: %v", err)
+	}
+	bucketCtx, bucketCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := reportStorage.EnsureBucket(bucketCtx); err != nil {
+		log.Printf("failed to ensure reports bucket %s: %v", cfg.ReportsBucket, err)
+	}
+	bucketCancel()
+
+	linkSecret := cfg.ReportLinkSecret
+	if linkSecret == "" {
+		// Ссылки перестанут открываться после рестарта — для прода секрет обязателен
+		buf := make([]byte, 32)
+		rand.Read(buf)
+		linkSecret = hex.EncodeToString(buf)
+		log.Println("REPORT_LINK_SECRET is not set, using a random secret")
+	}
+
+	var notifier reports.Notifier
+	if cfg.SMTPHost != "" {
+		notifier = reports.NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+
+	reportService := reports.NewService(db, store, reportStorage,
+		reports.NewSigner(linkSecret, cfg.ReportLinkTTL, cfg.ReportsPublicURL), notifier)
+	if err := reportService.Start(); err != nil {
+		log.Printf("failed to schedule reports: %v", err)
+	}
+	defer reportService.Stop()
+
 	// HTTP-сервер
 	mux := http.NewServeMux()
 
@@ -86,23 +138,25 @@ func main() {
 		fmt.Fprint(w, `{"status":"ok"}`)
 	})
 
-	mux.HandleFunc("/run-etl", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
-			return
-		}
-		log.Println("/run-etl triggered manually")
-		builders.RunFull()
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprint(w, `{"status":"ok","message":"ETL completed"}`)
-	})
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	// Запуски ETL: POST /run-etl, GET /etl/runs, GET /etl/runs/{id}
+	api.NewETLHandler(runner).Register(mux)
+
+	// Read-only API витрин для админки (через gateway: /api/analytics/stats/*)
+	api.NewHandler(store).Register(mux)
+
+	// Рекомендации creator'ам и площадкам (через gateway: /api/analytics/me/recommendations/*)
+	api.NewRecommendationsHandler(recommend.NewStore(chClient)).Register(mux)
+
+	// Сохранённые отчёты (/reports/*) и скачивание выгрузок (/report-files/*)
+	api.NewReportsHandler(reportService).Register(mux)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Port),
 		Handler:      mux,
 		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 5 * time.Second,
+		WriteTimeout: 30 * time.Second, // выгрузки отчётов отдаются потоком
 	}
 
 	go func() {
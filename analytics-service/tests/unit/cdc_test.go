@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"analytics-service/internal/etl"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ─── Загрузка по водяному знаку ───────────────────────────────────────────────
+
+// watermarkRows отвечает на запрос водяного знака таблицы
+func watermarkRows(wm time.Time) func(string) [][]any {
+	return func(query string) [][]any {
+		if strings.Contains(query, "etl_watermarks") {
+			return [][]any{{wm}}
+		}
+		return nil
+	}
+}
+
+type loadCall struct {
+	target string
+	since  time.Time
+}
+
+func fakeLoad(calls *[]loadCall, rows int, maxUpdated time.Time, err error) etl.LoadFunc {
+	return func(ctx context.Context, target string, since time.Time) (int, time.Time, error) {
+		*calls = append(*calls, loadCall{target: target, since: since})
+		return rows, maxUpdated, err
+	}
+}
+
+func TestLoadIncremental_StartsBeforeWatermark(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+	wm := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	conn.rows = watermarkRows(wm)
+
+	var calls []loadCall
+	newest := wm.Add(10 * time.Minute)
+	loaded, err := etl.LoadIncremental(context.Background(), ch, "dim_user", fakeLoad(&calls, 3, newest, nil))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if loaded != 3 {
+		t.Errorf("expected 3 rows, got %d", loaded)
+	}
+	// Перекрытие в 5 минут подбирает транзакции, закоммиченные не по порядку updated_at
+	if len(calls) != 1 || calls[0].target != "dim_user" || !calls[0].since.Equal(wm.Add(-5*time.Minute)) {
+		t.Errorf("expected load into dim_user since watermark minus overlap, got %+v", calls)
+	}
+
+	saved := conn.rowsOf("etl_watermarks")
+	if len(saved) != 1 || saved[0][0] != "dim_user" || !saved[0][1].(time.Time).Equal(newest) {
+		t.Errorf("expected watermark to move to %v, got %v", newest, saved)
+	}
+}
+
+func TestLoadIncremental_FirstRunLoadsEverything(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+
+	var calls []loadCall
+	newest := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := etl.LoadIncremental(context.Background(), ch, "dim_event", fakeLoad(&calls, 5, newest, nil)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(calls) != 1 || !calls[0].since.IsZero() {
+		t.Errorf("expected load from the beginning, got %+v", calls)
+	}
+	if len(conn.rowsOf("etl_watermarks")) != 1 {
+		t.Error("expected the first watermark to be saved")
+	}
+}
+
+func TestLoadIncremental_WatermarkNeverMovesBack(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+	wm := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	conn.rows = watermarkRows(wm)
+
+	// В перекрытие попали только уже загруженные строки
+	var calls []loadCall
+	loaded, err := etl.LoadIncremental(context.Background(), ch, "dim_user", fakeLoad(&calls, 2, wm.Add(-time.Minute), nil))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if loaded != 2 {
+		t.Errorf("expected 2 reloaded rows, got %d", loaded)
+	}
+	if saved := conn.rowsOf("etl_watermarks"); len(saved) != 0 {
+		t.Errorf("expected watermark to stay, got %v", saved)
+	}
+}
+
+func TestLoadIncremental_LoadErrorKeepsWatermark(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+
+	var calls []loadCall
+	loadErr := errors.New("postgres is down")
+	if _, err := etl.LoadIncremental(context.Background(), ch, "dim_user", fakeLoad(&calls, 0, time.Now(), loadErr)); !errors.Is(err, loadErr) {
+		t.Fatalf("expected load error, got %v", err)
+	}
+	if saved := conn.rowsOf("etl_watermarks"); len(saved) != 0 {
+		t.Errorf("expected no watermark after a failed load, got %v", saved)
+	}
+}
+
+// ─── Полная перестройка ───────────────────────────────────────────────────────
+
+func TestLoadFull_SwapsShadowTable(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+	conn.rows = watermarkRows(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	var calls []loadCall
+	newest := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := etl.LoadFull(context.Background(), ch, "fact_applications", fakeLoad(&calls, 10, newest, nil)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Полная перестройка грузит все строки в тень, не глядя на водяной знак
+	if len(calls) != 1 || calls[0].target != "fact_applications_shadow" || !calls[0].since.IsZero() {
+		t.Errorf("expected full load into the shadow table, got %+v", calls)
+	}
+	want := []string{
+		"DROP TABLE IF EXISTS fact_applications_shadow",
+		"CREATE TABLE fact_applications_shadow AS fact_applications",
+		"EXCHANGE TABLES fact_applications AND fact_applications_shadow",
+		"DROP TABLE IF EXISTS fact_applications_shadow",
+	}
+	if got := conn.execLog(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands:\n%s", strings.Join(got, "\n"))
+	}
+	// Водяной знак перезаписывается тем, что реально загружено
+	saved := conn.rowsOf("etl_watermarks")
+	if len(saved) != 1 || !saved[0][1].(time.Time).Equal(newest) {
+		t.Errorf("expected watermark %v, got %v", newest, saved)
+	}
+}
+
+func TestLoadFull_FailedLoadDoesNotSwap(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+
+	var calls []loadCall
+	if _, err := etl.LoadFull(context.Background(), ch, "dim_user", fakeLoad(&calls, 0, time.Time{}, errors.New("boom"))); err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, cmd := range conn.execLog() {
+		if strings.HasPrefix(cmd, "EXCHANGE") {
+			t.Errorf("expected main table to stay untouched, got %q", cmd)
+		}
+	}
+}
+
+func TestLoadFull_EmptySourceSetsNoWatermark(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+
+	var calls []loadCall
+	if _, err := etl.LoadFull(context.Background(), ch, "fact_favorites", fakeLoad(&calls, 0, time.Time{}, nil)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if saved := conn.rowsOf("etl_watermarks"); len(saved) != 0 {
+		t.Errorf("expected no watermark for an empty source, got %v", saved)
+	}
+}
@@ -0,0 +1,156 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"analytics-service/internal/clickhouse"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// fakeConn — соединение ClickHouse в памяти: запоминает команды и вставленные
+// батчи, а на запросы отвечает строками из rows. Нереализованные методы
+// driver.Conn паникуют.
+type fakeConn struct {
+	driver.Conn
+
+	mu       sync.Mutex
+	execs    []string
+	queries  []fakeQuery
+	inserted map[string][][]any // таблица -> строки отправленных батчей
+
+	// rows возвращает строки ответа на запрос; nil — пустой ответ
+	rows func(query string) [][]any
+	// beforeQuery вызывается перед каждым запросом, например чтобы его задержать
+	beforeQuery func(query string)
+}
+
+// fakeQuery — запрос на чтение вместе с аргументами
+type fakeQuery struct {
+	sql  string
+	args []any
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{inserted: make(map[string][][]any)}
+}
+
+func newFakeClickHouse() (*fakeConn, *clickhouse.Client) {
+	conn := newFakeConn()
+	return conn, clickhouse.NewClientFromConn(conn)
+}
+
+func (c *fakeConn) respond(query string, args []any) [][]any {
+	c.mu.Lock()
+	c.queries = append(c.queries, fakeQuery{sql: query, args: args})
+	c.mu.Unlock()
+	if c.beforeQuery != nil {
+		c.beforeQuery(query)
+	}
+	if c.rows == nil {
+		return nil
+	}
+	return c.rows(query)
+}
+
+func (c *fakeConn) Exec(ctx context.Context, query string, args ...any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.execs = append(c.execs, query)
+	return nil
+}
+
+func (c *fakeConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	return &fakeRows{rows: c.respond(query, args), pos: -1}, nil
+}
+
+func (c *fakeConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	return &fakeRow{rows: c.respond(query, args)}
+}
+
+func (c *fakeConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	return &fakeBatch{conn: c, table: strings.TrimSpace(strings.TrimPrefix(query, "INSERT INTO "))}, nil
+}
+
+// rowsOf возвращает копию строк, отправленных в таблицу
+func (c *fakeConn) rowsOf(table string) [][]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]any(nil), c.inserted[table]...)
+}
+
+func (c *fakeConn) queryLog() []fakeQuery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]fakeQuery(nil), c.queries...)
+}
+
+func (c *fakeConn) execLog() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.execs...)
+}
+
+type fakeBatch struct {
+	driver.Batch
+	conn  *fakeConn
+	table string
+	rows  [][]any
+}
+
+func (b *fakeBatch) Append(v ...any) error {
+	b.rows = append(b.rows, v)
+	return nil
+}
+
+func (b *fakeBatch) Send() error {
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+	b.conn.inserted[b.table] = append(b.conn.inserted[b.table], b.rows...)
+	return nil
+}
+
+func (b *fakeBatch) Abort() error { return nil }
+
+type fakeRows struct {
+	driver.Rows
+	rows [][]any
+	pos  int
+}
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error { return scanInto(r.rows[r.pos], dest) }
+func (r *fakeRows) Close() error           { return nil }
+func (r *fakeRows) Err() error             { return nil }
+
+type fakeRow struct {
+	driver.Row
+	rows [][]any
+}
+
+func (r *fakeRow) Err() error { return nil }
+
+func (r *fakeRow) Scan(dest ...any) error {
+	if len(r.rows) == 0 {
+		return fmt.Errorf("no rows")
+	}
+	return scanInto(r.rows[0], dest)
+}
+
+func scanInto(row []any, dest []any) error {
+	if len(row) != len(dest) {
+		return fmt.Errorf("scan: %d values into %d destinations", len(row), len(dest))
+	}
+	for i, v := range row {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"analytics-service/internal/stats"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ─── Жизненный цикл мероприятий ───────────────────────────────────────────────
+
+func lifecycleStore(rows [][]any) (*fakeConn, *stats.Store) {
+	conn, ch := newFakeClickHouse()
+	conn.rows = func(string) [][]any { return rows }
+	return conn, stats.NewStore(nil, ch)
+}
+
+func TestEventLifecycle_BuildsPoints(t *testing.T) {
+	conn, store := lifecycleStore([][]any{
+		// period, published, with_applications, matched, completed, ttfa, ttm
+		{time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), uint64(4), uint64(3), uint64(1), uint64(1), 3600.0, 86400.0},
+		{time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), uint64(2), uint64(0), uint64(0), uint64(0), 0.0, 0.0},
+	})
+	p, err := stats.ParseParams("2025-03-01", "2025-03-16", "week", 0)
+	if err != nil {
+		t.Fatalf("expected valid params, got %v", err)
+	}
+
+	points, err := store.EventLifecycle(context.Background(), p)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(points))
+	}
+
+	first := points[0]
+	if first.Period != "2025-03-03" || first.Published != 4 || first.WithApplications != 3 || first.Completed != 1 {
+		t.Errorf("unexpected first point: %+v", first)
+	}
+	if first.MatchRate != 0.25 {
+		t.Errorf("expected match rate 0.25, got %v", first.MatchRate)
+	}
+	if first.MedianTimeToFirstApplicationSec == nil || *first.MedianTimeToFirstApplicationSec != 3600 {
+		t.Errorf("expected median time to first application 3600, got %v", first.MedianTimeToFirstApplicationSec)
+	}
+	if first.MedianTimeToMatchSec == nil || *first.MedianTimeToMatchSec != 86400 {
+		t.Errorf("expected median time to match 86400, got %v", first.MedianTimeToMatchSec)
+	}
+
+	// Без заявок и матчей медиан нет: нулевая квантиль ClickHouse — не «0 секунд»
+	second := points[1]
+	if second.MatchRate != 0 || second.MedianTimeToFirstApplicationSec != nil || second.MedianTimeToMatchSec != nil {
+		t.Errorf("expected no medians for a period without applications, got %+v", second)
+	}
+
+	queries := conn.queryLog()
+	if len(queries) != 1 {
+		t.Fatalf("expected one query, got %d", len(queries))
+	}
+	q := queries[0]
+	if !strings.Contains(q.sql, "toMonday(toDate(l.published_at))") {
+		t.Errorf("expected weekly buckets, got query:\n%s", q.sql)
+	}
+	if len(q.args) != 2 || q.args[0] != "2025-03-01" || q.args[1] != "2025-03-16" {
+		t.Errorf("expected the date range as arguments, got %v", q.args)
+	}
+}
+
+func TestEventLifecycle_EmptyRange(t *testing.T) {
+	_, store := lifecycleStore(nil)
+	p, err := stats.ParseParams("2025-03-01", "2025-03-31", "month", 0)
+	if err != nil {
+		t.Fatalf("expected valid params, got %v", err)
+	}
+
+	points, err := store.EventLifecycle(context.Background(), p)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Пустой массив, а не null в JSON
+	if points == nil || len(points) != 0 {
+		t.Errorf("expected an empty slice, got %#v", points)
+	}
+}
@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"analytics-service/internal/reports"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ─── Signer ───────────────────────────────────────────────────────────────────
+
+func parseLink(t *testing.T, link string) (key, expires, sig string) {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("invalid link %q: %v", link, err)
+	}
+	key = strings.TrimPrefix(u.Path, "/api/analytics/report-files/")
+	return key, u.Query().Get("expires"), u.Query().Get("sig")
+}
+
+func TestSigner_URLVerifies(t *testing.T) {
+	signer := reports.NewSigner("secret", time.Hour, "https://example.com/api/analytics/")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	link := signer.URL("reports/1/funnel.csv", now)
+	if !strings.HasPrefix(link, "https://example.com/api/analytics/report-files/reports/1/funnel.csv?") {
+		t.Fatalf("unexpected link %q", link)
+	}
+
+	key, expires, sig := parseLink(t, link)
+	if err := signer.Verify(key, expires, sig, now.Add(59*time.Minute)); err != nil {
+		t.Errorf("expected link to be valid, got %v", err)
+	}
+}
+
+func TestSigner_Expired(t *testing.T) {
+	signer := reports.NewSigner("secret", time.Hour, "https://example.com/api/analytics")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key, expires, sig := parseLink(t, signer.URL("reports/1/funnel.csv", now))
+
+	err := signer.Verify(key, expires, sig, now.Add(time.Hour+time.Second))
+	if !errors.Is(err, reports.ErrLinkExpired) {
+		t.Errorf("expected ErrLinkExpired, got %v", err)
+	}
+}
+
+func TestSigner_RejectsTampering(t *testing.T) {
+	signer := reports.NewSigner("secret", time.Hour, "https://example.com/api/analytics")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	key, expires, sig := parseLink(t, signer.URL("reports/1/funnel.csv", now))
+
+	cases := map[string]struct {
+		signer            *reports.Signer
+		key, expires, sig string
+	}{
+		"other key":       {signer, "reports/2/funnel.csv", expires, sig},
+		"extended expiry": {signer, key, "9999999999", sig},
+		"broken expiry":   {signer, key, "soon", sig},
+		"bad signature":   {signer, key, expires, strings.Repeat("0", len(sig))},
+		"other secret":    {reports.NewSigner("other", time.Hour, ""), key, expires, sig},
+	}
+	for name, tc := range cases {
+		if err := tc.signer.Verify(tc.key, tc.expires, tc.sig, now); !errors.Is(err, reports.ErrLinkInvalid) {
+			t.Errorf("%s: expected ErrLinkInvalid, got %v", name, err)
+		}
+	}
+}
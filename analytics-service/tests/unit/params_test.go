@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"analytics-service/internal/stats"
+	"errors"
+	"testing"
+	"time"
+)
+
+// ─── Stats params ─────────────────────────────────────────────────────────────
+
+func TestParseParams_Defaults(t *testing.T) {
+	p, err := stats.ParseParams("", "", "", 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if days := p.To.Sub(p.From) / (24 * time.Hour); days != 29 {
+		t.Errorf("expected a 30-day inclusive range, got %d days between bounds", days)
+	}
+	if p.Granularity != "day" || p.Limit != 10 {
+		t.Errorf("unexpected defaults: %+v", p)
+	}
+}
+
+func TestParseParams_ExplicitRange(t *testing.T) {
+	p, err := stats.ParseParams("2026-01-01", "2026-03-31", "month", 500)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !p.From.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !p.To.Equal(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected range: %v – %v", p.From, p.To)
+	}
+	if p.Limit != 50 {
+		t.Errorf("expected limit to be capped at 50, got %d", p.Limit)
+	}
+}
+
+func TestParseParams_Errors(t *testing.T) {
+	cases := map[string]struct {
+		from, to, granularity string
+		want                  error
+	}{
+		"bad from":        {"01.01.2026", "", "", stats.ErrInvalidDate},
+		"bad to":          {"", "tomorrow", "", stats.ErrInvalidDate},
+		"reversed range":  {"2026-02-01", "2026-01-01", "", stats.ErrInvalidRange},
+		"range too long":  {"2020-01-01", "2026-01-01", "", stats.ErrInvalidRange},
+		"bad granularity": {"", "", "hour", stats.ErrInvalidGranularity},
+	}
+	for name, tc := range cases {
+		if _, err := stats.ParseParams(tc.from, tc.to, tc.granularity, 0); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+}
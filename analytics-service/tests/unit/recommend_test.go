@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"analytics-service/internal/recommend"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// ─── Recommendations ──────────────────────────────────────────────────────────
+
+func recommendInput() recommend.Input {
+	return recommend.Input{
+		Events: []recommend.Event{
+			{ID: 1, CreatorID: 10, Categories: []int{1, 2}},
+			{ID: 2, CreatorID: 10, Categories: []int{3}},
+			{ID: 3, CreatorID: 20, Categories: []int{1, 4}},
+		},
+		Venues: []recommend.Venue{
+			{UserID: 100, CityID: 1, Capacity: 100, Categories: []int{1, 2}},
+			{UserID: 101, CityID: 1, Categories: []int{1}},
+			{UserID: 102, CityID: 2, Categories: []int{5}},
+		},
+		Collaborations: []recommend.Collaboration{
+			{EventID: 50, CreatorID: 10, VenueID: 100, Completed: true, Categories: []int{1}},
+		},
+		Applied: map[[2]int]bool{{2, 101}: true},
+	}
+}
+
+func findMatch(matches []recommend.Match, eventID, venueID int) *recommend.Match {
+	for i := range matches {
+		if matches[i].EventID == eventID && matches[i].VenueID == venueID {
+			return &matches[i]
+		}
+	}
+	return nil
+}
+
+func TestCompute_ScoresAndReasons(t *testing.T) {
+	matches := recommend.Compute(recommendInput(), 10)
+
+	m := findMatch(matches, 1, 100)
+	if m == nil {
+		t.Fatalf("expected event 1 / venue 100 to match, got %+v", matches)
+	}
+	// Общие категории, город и вместимость прошлых площадок, прошлая коллаборация
+	if math.Abs(m.Score-0.65) > 1e-9 {
+		t.Errorf("expected score 0.65, got %v", m.Score)
+	}
+	want := []string{
+		recommend.ReasonSharedCategory,
+		recommend.ReasonSameCity,
+		recommend.ReasonCapacityFit,
+		recommend.ReasonWorkedTogether,
+	}
+	if !reflect.DeepEqual(m.Reasons, want) {
+		t.Errorf("expected reasons %v, got %v", want, m.Reasons)
+	}
+
+	// Похожая площадка того же города уже брала мероприятия категории 1
+	if m := findMatch(matches, 1, 101); m == nil || !contains(m.Reasons, recommend.ReasonSimilarAccepted) {
+		t.Errorf("expected similar_venues_accepted for venue 101, got %+v", m)
+	}
+}
+
+func TestCompute_SkipsAppliedAndWeakPairs(t *testing.T) {
+	matches := recommend.Compute(recommendInput(), 10)
+
+	if m := findMatch(matches, 2, 101); m != nil {
+		t.Errorf("expected pair with an application to be skipped, got %+v", m)
+	}
+	for _, m := range matches {
+		if m.VenueID == 102 {
+			t.Errorf("expected venue without shared signals to be skipped, got %+v", m)
+		}
+	}
+}
+
+func TestCompute_RanksBothSides(t *testing.T) {
+	matches := recommend.Compute(recommendInput(), 1)
+
+	// Для мероприятия 1 лучшая площадка — 100, а 101 остается в выдаче,
+	// потому что для самой площадки 101 это лучшее мероприятие
+	if m := findMatch(matches, 1, 100); m == nil || m.EventRank != 1 || m.VenueRank != 1 {
+		t.Errorf("expected 1/100 to be first on both sides, got %+v", m)
+	}
+	if m := findMatch(matches, 1, 101); m == nil || m.EventRank != 0 || m.VenueRank != 1 {
+		t.Errorf("expected 1/101 to be ranked only for the venue, got %+v", m)
+	}
+	// Для мероприятия 3 выше площадка 101, а у площадки 100 есть мероприятия лучше
+	if m := findMatch(matches, 3, 101); m == nil || m.EventRank != 1 {
+		t.Errorf("expected 3/101 to be first for the event, got %+v", m)
+	}
+	if m := findMatch(matches, 3, 100); m != nil {
+		t.Errorf("expected 3/100 to be outside both top lists, got %+v", m)
+	}
+}
+
+func contains(items []string, item string) bool {
+	for _, s := range items {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"analytics-service/internal/reports"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// ─── Report input ─────────────────────────────────────────────────────────────
+
+func TestReportInput_Defaults(t *testing.T) {
+	var r reports.Report
+	err := reports.Input{Name: "  Weekly funnel ", Kind: "funnel", Schedule: "0 9 * * 1"}.Apply(&r)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if r.Name != "Weekly funnel" || r.PeriodDays != 30 || r.Granularity != "day" || !r.Enabled {
+		t.Errorf("unexpected defaults: %+v", r)
+	}
+	if !reflect.DeepEqual(r.Formats, []string{reports.FormatCSV}) {
+		t.Errorf("expected csv by default, got %v", r.Formats)
+	}
+}
+
+func TestReportInput_NormalizesFormatsAndRecipients(t *testing.T) {
+	var r reports.Report
+	disabled := false
+	err := reports.Input{
+		Name:       "Cities",
+		Kind:       "top-cities",
+		Formats:    []string{"XLSX", "csv", "xlsx"},
+		Schedule:   "@daily",
+		Recipients: []string{"Analyst <analyst@example.com>", "ops@example.com"},
+		Enabled:    &disabled,
+	}.Apply(&r)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(r.Formats, []string{reports.FormatXLSX, reports.FormatCSV}) {
+		t.Errorf("expected deduplicated formats, got %v", r.Formats)
+	}
+	if !reflect.DeepEqual(r.Recipients, []string{"analyst@example.com", "ops@example.com"}) {
+		t.Errorf("expected bare addresses, got %v", r.Recipients)
+	}
+	if r.Enabled {
+		t.Error("expected report to stay disabled")
+	}
+}
+
+func TestReportInput_Validation(t *testing.T) {
+	valid := func() reports.Input {
+		return reports.Input{Name: "Funnel", Kind: "funnel", Schedule: "0 9 * * *"}
+	}
+	cases := map[string]struct {
+		modify func(in *reports.Input)
+		want   error
+	}{
+		"empty name":      {func(in *reports.Input) { in.Name = "  " }, reports.ErrValidation},
+		"unknown kind":    {func(in *reports.Input) { in.Kind = "revenue" }, reports.ErrInvalidKind},
+		"period too long": {func(in *reports.Input) { in.PeriodDays = 5000 }, reports.ErrValidation},
+		"negative period": {func(in *reports.Input) { in.PeriodDays = -1 }, reports.ErrValidation},
+		"bad granularity": {func(in *reports.Input) { in.Granularity = "hour" }, reports.ErrValidation},
+		"unknown format":  {func(in *reports.Input) { in.Formats = []string{"pdf"} }, reports.ErrInvalidFormat},
+		"bad cron":        {func(in *reports.Input) { in.Schedule = "every monday" }, reports.ErrInvalidSchedule},
+		"empty schedule":  {func(in *reports.Input) { in.Schedule = "" }, reports.ErrInvalidSchedule},
+		"bad recipient":   {func(in *reports.Input) { in.Recipients = []string{"not-an-email"} }, reports.ErrInvalidRecipient},
+		"too many recipients": {func(in *reports.Input) {
+			in.Recipients = make([]string, 21)
+			for i := range in.Recipients {
+				in.Recipients[i] = "user@example.com"
+			}
+		}, reports.ErrInvalidRecipient},
+	}
+	for name, tc := range cases {
+		in := valid()
+		tc.modify(&in)
+		var r reports.Report
+		if err := in.Apply(&r); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+}
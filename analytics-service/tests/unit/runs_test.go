@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"analytics-service/internal/etl"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ─── Runner ───────────────────────────────────────────────────────────────────
+
+// unreachableDB — Postgres, к которому нельзя подключиться: загрузки из него
+// завершаются ошибкой, не затрагивая остальные шаги запуска
+func unreachableDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN: "host=127.0.0.1 port=1 user=etl dbname=etl sslmode=disable connect_timeout=1",
+	}), &gorm.Config{DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	return db
+}
+
+// waitRun ждет, пока запуск id будет сохранен в etl_runs завершенным
+func waitRun(t *testing.T, conn *fakeConn, id string) etl.Run {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, row := range conn.rowsOf("etl_runs") {
+			if row[0] != id || row[3] == etl.StatusRunning {
+				continue
+			}
+			run := etl.Run{ID: id, Status: row[3].(string), Error: row[8].(string)}
+			if err := json.Unmarshal([]byte(row[7].(string)), &run.Tables); err != nil {
+				t.Fatalf("failed to decode tables: %v", err)
+			}
+			return run
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("run %s did not finish in time", id)
+	return etl.Run{}
+}
+
+func TestRunner_RejectsConcurrentRuns(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+	// dim_date уже заполнена; первый шаг запуска ждет, пока тест его отпустит
+	conn.rows = func(query string) [][]any {
+		if strings.Contains(query, "dim_date") {
+			return [][]any{{uint64(1)}}
+		}
+		return nil
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	conn.beforeQuery = func(query string) {
+		if strings.Contains(query, "dim_date") {
+			once.Do(func() { close(started) })
+			<-release
+		}
+	}
+	runner := etl.NewRunner(etl.NewBuilders(unreachableDB(t), ch), ch)
+
+	run, err := runner.Start(etl.KindFull, "manual")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if run.Status != etl.StatusRunning || run.Kind != etl.KindFull || run.Trigger != "manual" {
+		t.Errorf("unexpected run: %+v", run)
+	}
+
+	<-started
+	if _, err := runner.Start(etl.KindIncremental, "schedule"); !errors.Is(err, etl.ErrRunInProgress) {
+		t.Errorf("expected ErrRunInProgress while a run is going, got %v", err)
+	}
+	close(release)
+
+	// Ошибка одной витрины не останавливает остальные
+	finished := waitRun(t, conn, run.ID)
+	if finished.Status != etl.StatusFailed || len(finished.Tables) != 8 {
+		t.Fatalf("expected a failed run over 8 tables, got %+v", finished)
+	}
+	if finished.Tables[0].Table != "dim_date" || finished.Tables[0].Error != "" {
+		t.Errorf("expected dim_date to succeed, got %+v", finished.Tables[0])
+	}
+	if finished.Error != "7 of 8 tables failed" {
+		t.Errorf("unexpected run error %q", finished.Error)
+	}
+
+	// После завершения можно запускать снова
+	next, err := runner.Start(etl.KindIncremental, "schedule")
+	if err != nil {
+		t.Fatalf("expected a new run after the previous one finished, got %v", err)
+	}
+	waitRun(t, conn, next.ID)
+}
+
+func TestRunner_InvalidKind(t *testing.T) {
+	conn, ch := newFakeClickHouse()
+	runner := etl.NewRunner(etl.NewBuilders(nil, ch), ch)
+
+	if _, err := runner.Start("weekly", "manual"); !errors.Is(err, etl.ErrInvalidKind) {
+		t.Errorf("expected ErrInvalidKind, got %v", err)
+	}
+	if rows := conn.rowsOf("etl_runs"); len(rows) != 0 {
+		t.Errorf("expected no run to be recorded, got %v", rows)
+	}
+}
@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"analytics-service/internal/scd"
+	"testing"
+	"time"
+)
+
+// ─── SCD2: версии измерений ───────────────────────────────────────────────────
+
+var (
+	scdCreated = time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	scdVersion = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func venueState(city string, updated time.Time) scd.User {
+	return scd.User{
+		UserID: 7, Role: "venue", Name: "Loft", City: city,
+		Capacity: 120, ProfileFilled: 1,
+		CreatedAt: scdCreated, UpdatedAt: updated,
+	}
+}
+
+func TestSCDPlan_FirstVersionStartsAtCreation(t *testing.T) {
+	incoming := []scd.UserVersion{scd.NewUserVersion(venueState("Moscow", scdCreated.Add(48*time.Hour)))}
+
+	writes, opened := scd.Plan(nil, incoming, scdVersion)
+	if opened != 1 || len(writes) != 1 {
+		t.Fatalf("expected one new version, got %d (%d writes)", opened, len(writes))
+	}
+	v := writes[0]
+	// К первой версии присоединяются и факты, появившиеся до первого запуска ETL
+	if !v.ValidFrom.Equal(scdCreated) || !v.ValidTo.Equal(scd.OpenValidTo) || v.IsCurrent != 1 {
+		t.Errorf("expected open version from creation, got %v..%v current=%d", v.ValidFrom, v.ValidTo, v.IsCurrent)
+	}
+}
+
+func TestSCDPlan_SkipsUnchangedRows(t *testing.T) {
+	current := []scd.UserVersion{scd.NewUserVersion(venueState("Moscow", scdCreated))}
+	// Та же строка повторно пришла из перекрытия водяного знака
+	incoming := []scd.UserVersion{scd.NewUserVersion(venueState("Moscow", scdCreated.Add(time.Hour)))}
+
+	if writes, opened := scd.Plan(current, incoming, scdVersion); opened != 0 || len(writes) != 0 {
+		t.Errorf("expected no writes for an unchanged row, got %d (%d writes)", opened, len(writes))
+	}
+}
+
+func TestSCDPlan_ClosesOldAndOpensNewVersion(t *testing.T) {
+	changed := time.Date(2025, 2, 20, 15, 30, 0, 0, time.UTC)
+	current := []scd.UserVersion{scd.NewUserVersion(venueState("Moscow", scdCreated))}
+	incoming := []scd.UserVersion{scd.NewUserVersion(venueState("Kazan", changed))}
+
+	writes, opened := scd.Plan(current, incoming, scdVersion)
+	if opened != 1 || len(writes) != 2 {
+		t.Fatalf("expected closed and opened versions, got %d (%d writes)", opened, len(writes))
+	}
+
+	closed, next := writes[0], writes[1]
+	if closed.City != "Moscow" || closed.IsCurrent != 0 || !closed.ValidTo.Equal(changed) || !closed.Version.Equal(scdVersion) {
+		t.Errorf("expected old version closed at %v, got %+v", changed, *closed)
+	}
+	// Закрытие — перезапись строки с тем же ключом (id, valid_from)
+	if !closed.ValidFrom.Equal(scdCreated) {
+		t.Errorf("expected closed version to keep valid_from %v, got %v", scdCreated, closed.ValidFrom)
+	}
+	if next.City != "Kazan" || next.IsCurrent != 1 || !next.ValidFrom.Equal(changed) || !next.ValidTo.Equal(scd.OpenValidTo) {
+		t.Errorf("expected new open version from %v, got %+v", changed, *next)
+	}
+}
+
+func TestSCDPlan_ValidFromMovesPastOldVersion(t *testing.T) {
+	// updated_at источника не позже начала текущей версии: интервалы не должны пересечься
+	current := []scd.UserVersion{scd.NewUserVersion(venueState("Moscow", scdCreated))}
+	incoming := []scd.UserVersion{scd.NewUserVersion(venueState("Kazan", scdCreated))}
+
+	writes, _ := scd.Plan(current, incoming, scdVersion)
+	if len(writes) != 2 {
+		t.Fatalf("expected two writes, got %d", len(writes))
+	}
+	want := scdCreated.Add(time.Second)
+	if !writes[1].ValidFrom.Equal(want) || !writes[0].ValidTo.Equal(want) {
+		t.Errorf("expected versions to meet at %v, got %v and %v", want, writes[0].ValidTo, writes[1].ValidFrom)
+	}
+}
+
+func TestSCDPlan_RepeatedChangesInOneBatch(t *testing.T) {
+	first := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	incoming := []scd.UserVersion{
+		scd.NewUserVersion(venueState("Moscow", scdCreated)),
+		scd.NewUserVersion(venueState("Kazan", first)),
+	}
+
+	writes, opened := scd.Plan(nil, incoming, scdVersion)
+	if opened != 2 || len(writes) != 3 {
+		t.Fatalf("expected two versions and one closed, got %d (%d writes)", opened, len(writes))
+	}
+	if writes[1].City != "Moscow" || writes[1].IsCurrent != 0 || !writes[1].ValidTo.Equal(first) {
+		t.Errorf("expected first version closed by the second, got %+v", *writes[1])
+	}
+}
+
+func TestNewUserVersion_TracksOnlyAnalyticAttributes(t *testing.T) {
+	base := scd.NewUserVersion(venueState("Moscow", scdCreated))
+	renamed := venueState("Moscow", scdCreated)
+	renamed.Name = "Loft 2"
+
+	if scd.NewUserVersion(renamed).AttrsHash == base.AttrsHash {
+		t.Error("expected a name change to change the hash")
+	}
+	if later := scd.NewUserVersion(venueState("Moscow", scdCreated.Add(time.Hour))); later.AttrsHash != base.AttrsHash {
+		t.Error("expected updated_at alone not to change the hash")
+	}
+}
+
+func TestNewEventVersion_CategoryOrderDoesNotMatter(t *testing.T) {
+	event := scd.Event{EventID: 3, CreatorID: 7, Title: "Jazz night", IsActive: true, CreatedAt: scdCreated, UpdatedAt: scdCreated}
+
+	a := scd.NewEventVersion(event, []uint32{5, 2, 9})
+	b := scd.NewEventVersion(event, []uint32{9, 5, 2})
+	if a.AttrsHash != b.AttrsHash {
+		t.Error("expected the same category set to give the same hash")
+	}
+	if got := a.CategoryIDs; len(got) != 3 || got[0] != 2 || got[1] != 5 || got[2] != 9 {
+		t.Errorf("expected sorted categories, got %v", got)
+	}
+	if c := scd.NewEventVersion(event, []uint32{2, 5}); c.AttrsHash == a.AttrsHash {
+		t.Error("expected a removed category to change the hash")
+	}
+}
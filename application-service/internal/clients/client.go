@@ -0,0 +1,17 @@
+package clients
+
+import "shared/svcclient"
+
+// Повторы, таймауты и circuit breaker общие для всех сервисов (shared/svcclient)
+type Options = svcclient.Options
+
+var (
+	// ErrNotFound — ресурс отсутствует в сервисе-источнике (HTTP 404)
+	ErrNotFound = svcclient.ErrNotFound
+	// ErrCircuitOpen — сервис недавно отказывал, запрос не выполнялся
+	ErrCircuitOpen = svcclient.ErrCircuitOpen
+)
+
+func DefaultOptions() Options {
+	return svcclient.DefaultOptions()
+}
@@ -0,0 +1,36 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"shared/svcclient"
+)
+
+// Event — поля мероприятия, нужные application-service
+type Event struct {
+	ID          int  `json:"id"`
+	CreatorID   int  `json:"creator_id"`
+	IsActive    bool `json:"is_active"`
+	IsCompleted bool `json:"is_completed"`
+}
+
+type EventClient interface {
+	// GetEvent возвращает ErrNotFound, если мероприятия нет
+	GetEvent(ctx context.Context, id int) (*Event, error)
+}
+
+type HTTPEventClient struct {
+	c *svcclient.Client
+}
+
+func NewEventClient(baseURL string, opts Options) *HTTPEventClient {
+	return &HTTPEventClient{c: svcclient.New("event-service", baseURL, opts)}
+}
+
+func (e *HTTPEventClient) GetEvent(ctx context.Context, id int) (*Event, error) {
+	var event Event
+	if err := e.c.GetJSON(ctx, fmt.Sprintf("/events/%d", id), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,44 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shared/svcclient"
+)
+
+type UserClient interface {
+	// HasRole проверяет, что у пользователя есть профиль роли creator или venue
+	HasRole(ctx context.Context, userID int, role string) (bool, error)
+}
+
+type HTTPUserClient struct {
+	c *svcclient.Client
+}
+
+func NewUserClient(baseURL string, opts Options) *HTTPUserClient {
+	return &HTTPUserClient{c: svcclient.New("user-service", baseURL, opts)}
+}
+
+func (u *HTTPUserClient) HasRole(ctx context.Context, userID int, role string) (bool, error) {
+	var path string
+	switch role {
+	case "creator":
+		path = fmt.Sprintf("/users/creators/%d", userID)
+	case "venue":
+		path = fmt.Sprintf("/users/venues/%d", userID)
+	default:
+		return false, nil
+	}
+
+	// Тело профиля не нужно — достаточно того, что он существует
+	var profile struct{}
+	err := u.c.GetJSON(ctx, path, &profile)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
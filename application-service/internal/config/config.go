@@ -12,6 +12,10 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	ServerPort string
+	RedisURL   string
+
+	EventServiceURL string
+	UserServiceURL  string
 }
 
 func LoadConfig() *Config {
@@ -22,6 +26,10 @@ func LoadConfig() *Config {
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "sovmestno"),
 		ServerPort: getEnv("SERVER_PORT", "8083"),
+		RedisURL:   getEnv("REDIS_URL", "redis:6379"),
+
+		EventServiceURL: getEnv("EVENT_SERVICE_URL", "http://event-service:8082"),
+		UserServiceURL:  getEnv("USER_SERVICE_URL", "http://user-service:8081"),
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"application-service/internal/service"
 	"errors"
 	"net/http"
+	"shared/pagination"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -28,7 +29,10 @@ func NewApplicationHandler(applicationService *service.ApplicationService) *Appl
 // @Success 201 {object} models.Application
 // @Failure 400 {object} apperror.ErrorResponse
 // @Failure 401 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
 // @Failure 409 {object} apperror.ErrorResponse
+// @Failure 422 {object} apperror.ErrorResponse
+// @Failure 503 {object} apperror.ErrorResponse
 // @Security BearerAuth
 // @Router /applications [post]
 func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
@@ -60,6 +64,10 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, apperror.One("CANNOT_APPLY_TO_SELF", "You cannot send an application to yourself"))
 			return
 		}
+		if errors.Is(err, service.ErrInvalidReceiverType) {
+			c.JSON(http.StatusBadRequest, apperror.One("INVALID_RECEIVER_TYPE", "Creators apply to venues and venues invite creators"))
+			return
+		}
 		if errors.Is(err, service.ErrDuplicatePendingApplication) {
 			c.JSON(http.StatusConflict, apperror.One("DUPLICATE_APPLICATION", "A pending application already exists for this event"))
 			return
@@ -68,6 +76,26 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 			c.JSON(http.StatusConflict, apperror.One("MIRROR_APPLICATION_EXISTS", "An incoming application already exists for this event, check your applications"))
 			return
 		}
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("EVENT_NOT_FOUND", "Event not found"))
+			return
+		}
+		if errors.Is(err, service.ErrEventNotActive) {
+			c.JSON(http.StatusUnprocessableEntity, apperror.One("EVENT_NOT_ACTIVE", "Event is not open for applications"))
+			return
+		}
+		if errors.Is(err, service.ErrEventOwnershipMismatch) {
+			c.JSON(http.StatusUnprocessableEntity, apperror.One("EVENT_OWNERSHIP_MISMATCH", "Event does not belong to the creator in this application"))
+			return
+		}
+		if errors.Is(err, service.ErrReceiverRoleMismatch) {
+			c.JSON(http.StatusUnprocessableEntity, apperror.One("RECEIVER_ROLE_MISMATCH", "Receiver does not have the given role"))
+			return
+		}
+		if errors.Is(err, service.ErrDependencyUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, apperror.One("DEPENDENCY_UNAVAILABLE", "Cannot validate application right now, try again later"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to create application"))
 		return
 	}
@@ -121,9 +149,11 @@ func (h *ApplicationHandler) GetApplication(c *gin.Context) {
 // @Produce json
 // @Param role query string false "User's role in application (sender, receiver, any)" default(any)
 // @Param status query string false "Application status (pending, accepted, rejected)"
-// @Param limit query int false "Limit" default(10)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {array} models.Application
+// @Param limit query int false "Limit (max 100)" default(10)
+// @Param cursor query string false "Cursor of the next page (next_cursor from the previous response)"
+// @Param include_total query bool false "Include total count"
+// @Success 200 {object} pagination.Page[models.Application]
+// @Failure 400 {object} apperror.ErrorResponse
 // @Failure 401 {object} apperror.ErrorResponse
 // @Failure 500 {object} apperror.ErrorResponse
 // @Security BearerAuth
@@ -137,10 +167,13 @@ func (h *ApplicationHandler) ListApplications(c *gin.Context) {
 
 	role := c.DefaultQuery("role", "any")
 	status := c.Query("status")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	apps, err := h.applicationService.ListApplications(userID.(int), role, status, limit, offset)
+	apps, err := h.applicationService.ListApplications(userID.(int), role, status, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch applications"))
 		return
@@ -5,6 +5,7 @@ import (
 	"application-service/internal/service"
 	"errors"
 	"net/http"
+	"shared/pagination"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -61,9 +62,11 @@ func (h *CollaborationHandler) GetCollaboration(c *gin.Context) {
 // @Tags collaborations
 // @Produce json
 // @Param status query string false "Collaboration status (pending, completed, cancelled)"
-// @Param limit query int false "Limit" default(10)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {array} models.Collaboration
+// @Param limit query int false "Limit (max 100)" default(10)
+// @Param cursor query string false "Cursor of the next page (next_cursor from the previous response)"
+// @Param include_total query bool false "Include total count"
+// @Success 200 {object} pagination.Page[models.Collaboration]
+// @Failure 400 {object} apperror.ErrorResponse
 // @Failure 401 {object} apperror.ErrorResponse
 // @Failure 500 {object} apperror.ErrorResponse
 // @Security BearerAuth
@@ -76,10 +79,13 @@ func (h *CollaborationHandler) ListCollaborations(c *gin.Context) {
 	}
 
 	status := c.Query("status")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	collabs, err := h.applicationService.ListCollaborations(userID.(int), status, limit, offset)
+	collabs, err := h.applicationService.ListCollaborations(userID.(int), status, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch collaborations"))
 		return
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"application-service/internal/apperror"
+	"application-service/internal/models"
+	"application-service/internal/service"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RatingSummariesResponse struct {
+	Ratings []models.RatingSummary `json:"ratings"`
+}
+
+// GetRatingSummaries возвращает оценки пользователей для других сервисов
+// @Summary Rating summaries for other services
+// @Description Internal endpoint: aggregate ratings by visible reviews for users from user_ids, in request order. Users without reviews get a zero rating. Not reachable through the gateway.
+// @Tags internal
+// @Produce json
+// @Param user_ids query string true "Comma-separated user IDs"
+// @Success 200 {object} RatingSummariesResponse
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 500 {object} apperror.ErrorResponse
+// @Router /internal/ratings [get]
+func (h *ReviewHandler) GetRatingSummaries(c *gin.Context) {
+	var userIDs []int
+	for _, raw := range strings.Split(c.Query("user_ids"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, apperror.One("INVALID_USER_ID", "Invalid user ID: "+raw))
+			return
+		}
+		userIDs = append(userIDs, id)
+	}
+	if len(userIDs) == 0 || len(userIDs) > service.MaxRatingSummaries {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_PARAM", fmt.Sprintf("user_ids must list from 1 to %d user IDs", service.MaxRatingSummaries)))
+		return
+	}
+
+	ratings, err := h.reviewService.GetRatingSummaries(userIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch ratings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, RatingSummariesResponse{Ratings: ratings})
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"application-service/internal/apperror"
+	"application-service/internal/service"
+	"errors"
+	"net/http"
+	"shared/pagination"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MessageHandler struct {
+	messageService *service.MessageService
+}
+
+func NewMessageHandler(messageService *service.MessageService) *MessageHandler {
+	return &MessageHandler{messageService: messageService}
+}
+
+func (h *MessageHandler) respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrApplicationNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("APPLICATION_NOT_FOUND", "Application not found"))
+	case errors.Is(err, service.ErrCollaborationNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("COLLABORATION_NOT_FOUND", "Collaboration not found"))
+	case errors.Is(err, service.ErrConversationNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("CONVERSATION_NOT_FOUND", "Conversation not found"))
+	case errors.Is(err, service.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You are not a participant of this conversation"))
+	default:
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", fallback))
+	}
+}
+
+// OpenApplicationConversation открывает переписку по заявке
+// @Summary Open application conversation
+// @Description Get or create the conversation thread for an application. Only the sender and receiver have access.
+// @Tags messages
+// @Produce json
+// @Param id path int true "Application ID"
+// @Success 200 {object} models.Conversation
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /applications/{id}/conversation [post]
+func (h *MessageHandler) OpenApplicationConversation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid application ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	conv, err := h.messageService.OpenApplicationConversation(id, userID.(int))
+	if err != nil {
+		h.respondError(c, err, "Failed to open conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, conv)
+}
+
+// OpenCollaborationConversation открывает переписку по коллаборации
+// @Summary Open collaboration conversation
+// @Description Get or create the conversation thread for a collaboration (shared with its source application)
+// @Tags messages
+// @Produce json
+// @Param id path int true "Collaboration ID"
+// @Success 200 {object} models.Conversation
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /collaborations/{id}/conversation [post]
+func (h *MessageHandler) OpenCollaborationConversation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid collaboration ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	conv, err := h.messageService.OpenCollaborationConversation(id, userID.(int))
+	if err != nil {
+		h.respondError(c, err, "Failed to open conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, conv)
+}
+
+// ListConversations возвращает переписки текущего пользователя
+// @Summary List conversations
+// @Description Conversations of the current user, newest first, with last message and unread counter
+// @Tags messages
+// @Produce json
+// @Param limit query int false "Limit (max 100)" default(20)
+// @Param cursor query string false "Cursor of the next page (next_cursor from the previous response)"
+// @Param include_total query bool false "Include total count"
+// @Success 200 {object} pagination.Page[service.ConversationSummary]
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 500 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations [get]
+func (h *MessageHandler) ListConversations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultConversationsLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
+
+	convs, err := h.messageService.ListConversations(userID.(int), page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch conversations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, convs)
+}
+
+// GetUnreadCount возвращает общее число непрочитанных сообщений
+// @Summary Unread messages counter
+// @Tags messages
+// @Produce json
+// @Success 200 {object} service.UnreadCountResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 500 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/unread-count [get]
+func (h *MessageHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	resp, err := h.messageService.CountUnread(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to count unread messages"))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetConversation получает переписку по ID
+// @Summary Get conversation by ID
+// @Tags messages
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Success 200 {object} models.Conversation
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id} [get]
+func (h *MessageHandler) GetConversation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid conversation ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	conv, err := h.messageService.GetConversation(id, userID.(int))
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, conv)
+}
+
+// ListMessages возвращает сообщения переписки
+// @Summary List messages
+// @Description Messages from newest to oldest. Pass next_before_id from the previous page as before_id to load older messages.
+// @Tags messages
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param before_id query int false "Return messages with ID less than this"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} service.MessagesPage
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/messages [get]
+func (h *MessageHandler) ListMessages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid conversation ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	beforeID, _ := strconv.Atoi(c.DefaultQuery("before_id", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	page, err := h.messageService.ListMessages(id, userID.(int), beforeID, limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// SendMessage отправляет сообщение в переписку
+// @Summary Send message
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param message body service.SendMessageRequest true "Message"
+// @Success 201 {object} models.Message
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/messages [post]
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid conversation ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req service.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	msg, err := h.messageService.SendMessage(id, userID.(int), &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, msg)
+}
+
+// MarkRead отмечает входящие сообщения прочитанными
+// @Summary Mark conversation as read
+// @Description Set read_at on all incoming unread messages of the conversation
+// @Tags messages
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Success 200 {object} service.MarkReadResponse
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/read [patch]
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid conversation ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	resp, err := h.messageService.MarkRead(id, userID.(int))
+	if err != nil {
+		h.respondError(c, err, "Failed to mark messages as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"application-service/internal/apperror"
+	"application-service/internal/service"
+	"errors"
+	"net/http"
+	"shared/pagination"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// CreateReview оставляет отзыв по завершённой коллаборации
+// @Summary Review collaboration partner
+// @Description Leave a 1–5 rating and a text review for the other side of a completed collaboration. One review per participant.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Collaboration ID"
+// @Param review body service.CreateReviewRequest true "Review data"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Failure 409 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /collaborations/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid collaboration ID"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req service.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	review, err := h.reviewService.CreateReview(id, userID.(int), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrCollaborationNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("COLLABORATION_NOT_FOUND", "Collaboration not found"))
+			return
+		}
+		if errors.Is(err, service.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You are not involved in this collaboration"))
+			return
+		}
+		if errors.Is(err, service.ErrCollaborationNotCompleted) {
+			c.JSON(http.StatusConflict, apperror.One("COLLABORATION_NOT_COMPLETED", "Reviews can only be left for completed collaborations"))
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateReview) {
+			c.JSON(http.StatusConflict, apperror.One("DUPLICATE_REVIEW", "You have already reviewed this collaboration"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to create review"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// ListUserReviews возвращает публичные отзывы о пользователе
+// @Summary List reviews about a user
+// @Description Public list of visible reviews about a creator or venue with the aggregate rating
+// @Tags reviews
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Param limit query int false "Limit (max 100)" default(10)
+// @Param cursor query string false "Cursor of the next page (next_cursor from the previous response)"
+// @Param include_total query bool false "Include total count"
+// @Success 200 {object} service.UserReviewsResponse
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 500 {object} apperror.ErrorResponse
+// @Router /public/users/{user_id}/reviews [get]
+func (h *ReviewHandler) ListUserReviews(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_USER_ID", "user_id must be a positive integer"))
+		return
+	}
+
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultReviewsLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
+
+	resp, err := h.reviewService.ListUserReviews(userID, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch reviews"))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ModerateReview скрывает или восстанавливает отзыв
+// @Summary Moderate review
+// @Description Hide or restore a review (admin only). Hidden reviews are excluded from profiles and ratings.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param moderation body service.ModerateReviewRequest true "Moderation decision"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/{id}/moderation [patch]
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid review ID"))
+		return
+	}
+
+	var req service.ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("REVIEW_NOT_FOUND", "Review not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to moderate review"))
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Conversation — переписка двух участников заявки. Один тред на заявку,
+// после принятия заявки тот же тред продолжает работать для коллаборации.
+type Conversation struct {
+	ID              int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ApplicationID   int        `gorm:"not null" json:"application_id"`
+	CollaborationID *int       `json:"collaboration_id,omitempty"`
+	EventID         int        `gorm:"not null" json:"event_id"`
+	SenderID        int        `gorm:"not null" json:"sender_id"`
+	ReceiverID      int        `gorm:"not null" json:"receiver_id"`
+	LastMessageAt   *time.Time `json:"last_message_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Conversation) TableName() string { return "conversations" }
+
+// HasParticipant сообщает, является ли пользователь участником переписки
+func (c *Conversation) HasParticipant(userID int) bool {
+	return c.SenderID == userID || c.ReceiverID == userID
+}
+
+type Message struct {
+	ID             int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ConversationID int        `gorm:"not null" json:"conversation_id"`
+	SenderID       int        `gorm:"not null" json:"sender_id"`
+	Body           string     `gorm:"not null" json:"body"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Message) TableName() string { return "messages" }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Review struct {
+	ID               int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CollaborationID  int       `gorm:"not null" json:"collaboration_id"`
+	EventID          int       `gorm:"not null" json:"event_id"`
+	AuthorID         int       `gorm:"not null" json:"author_id"`
+	AuthorType       string    `gorm:"not null" json:"author_type"` // creator, venue
+	TargetID         int       `gorm:"not null" json:"target_id"`
+	TargetType       string    `gorm:"not null" json:"target_type"` // creator, venue
+	Rating           int       `gorm:"not null" json:"rating"`      // 1..5
+	Text             string    `json:"text,omitempty"`
+	IsHidden         bool      `gorm:"default:false" json:"is_hidden"`
+	ModerationReason string    `json:"moderation_reason,omitempty"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Review) TableName() string { return "reviews" }
+
+// RatingSummary — агрегированная оценка пользователя по видимым отзывам
+type RatingSummary struct {
+	UserID  int     `json:"user_id"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
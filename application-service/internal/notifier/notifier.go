@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Типы событий realtime-канала. Gateway отдаёт их клиенту как SSE event name.
+const (
+	EventApplicationCreated         = "application.created"
+	EventApplicationStatusChanged   = "application.status_changed"
+	EventCollaborationStatusChanged = "collaboration.status_changed"
+	EventMessageCreated             = "message.created"
+	EventMessagesRead               = "message.read"
+	EventNotification               = "notification"
+)
+
+// channelPrefix должен совпадать с realtime.ChannelPrefix в gateway
+const channelPrefix = "realtime:user:"
+
+const publishTimeout = 2 * time.Second
+
+// Notifier доставляет события конкретному пользователю. Доставка best-effort:
+// ошибка публикации не должна откатывать уже закоммиченную операцию.
+type Notifier interface {
+	Notify(userID int, eventType string, data interface{})
+}
+
+type Envelope struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Notification — полезная нагрузка события EventNotification
+type Notification struct {
+	Kind     string `json:"kind"`
+	EntityID int    `json:"entity_id"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Noop — notifier для окружений без Redis и для тестов
+type Noop struct{}
+
+func (Noop) Notify(int, string, interface{}) {}
+
+type RedisNotifier struct {
+	rdb *redis.Client
+}
+
+func NewRedisNotifier(rdb *redis.Client) *RedisNotifier {
+	return &RedisNotifier{rdb: rdb}
+}
+
+func (n *RedisNotifier) Notify(userID int, eventType string, data interface{}) {
+	payload, err := json.Marshal(Envelope{Type: eventType, Data: data, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		log.Printf("notifier: failed to marshal %s: %v", eventType, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	channel := fmt.Sprintf("%s%d", channelPrefix, userID)
+	if err := n.rdb.Publish(ctx, channel, payload).Err(); err != nil {
+		log.Printf("notifier: failed to publish %s to user %d: %v", eventType, userID, err)
+	}
+}
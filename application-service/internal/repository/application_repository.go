@@ -2,9 +2,11 @@ package repository
 
 import (
 	"application-service/internal/models"
+	"application-service/internal/outbox"
 	"errors"
+	"shared/eventbus"
+	"shared/pagination"
 	"strings"
-	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,15 +14,6 @@ import (
 var ErrDuplicatePendingApplication = errors.New("pending application already exists for this event")
 var ErrMirrorApplicationExists = errors.New("incoming application already exists for this event, check your applications")
 
-type Event struct {
-	ID          int       `gorm:"primaryKey"`
-	IsActive    bool      `gorm:"column:is_active"`
-	IsCompleted bool      `gorm:"column:is_completed"`
-	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
-}
-
-func (Event) TableName() string { return "events" }
-
 type ApplicationRepository struct {
 	db *gorm.DB
 }
@@ -55,10 +48,10 @@ func (r *ApplicationRepository) GetApplicationByID(id int) (*models.Application,
 	return &app, nil
 }
 
-func (r *ApplicationRepository) ListApplications(userID int, role string, status string, limit, offset int) ([]models.Application, error) {
+func (r *ApplicationRepository) ListApplications(userID int, role string, status string, page pagination.Params) ([]models.Application, *int64, error) {
 	var applications []models.Application
 
-	query := r.db.Order("updated_at DESC").Limit(limit).Offset(offset)
+	query := r.db.Model(&models.Application{})
 
 	switch role {
 	case "sender":
@@ -73,8 +66,13 @@ func (r *ApplicationRepository) ListApplications(userID int, role string, status
 		query = query.Where("status = ?", status)
 	}
 
-	err := query.Find(&applications).Error
-	return applications, err
+	total, err := pagination.Count(query, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = pagination.Apply(query, "applications", page).Find(&applications).Error
+	return applications, total, err
 }
 
 func (r *ApplicationRepository) UpdateApplication(app *models.Application) error {
@@ -85,26 +83,39 @@ func (r *ApplicationRepository) DeleteApplication(id int) error {
 	return r.db.Delete(&models.Application{}, id).Error
 }
 
-// AcceptApplicationTx атомарно принимает заявку, снимает событие с каталога и создаёт коллаборацию.
+// AcceptApplicationTx атомарно принимает заявку, создаёт коллаборацию и пишет
+// ApplicationAccepted в outbox. Снятие мероприятия с каталога делает event-service.
 func (r *ApplicationRepository) AcceptApplicationTx(app *models.Application, collab *models.Collaboration) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Save(app).Error; err != nil {
 			return err
 		}
-		if err := tx.Model(&Event{}).Where("id = ?", app.EventID).Update("is_active", false).Error; err != nil {
+		if err := tx.Create(collab).Error; err != nil {
 			return err
 		}
-		return tx.Create(collab).Error
+		return outbox.Enqueue(tx, eventbus.ApplicationAccepted, app.ID, eventbus.ApplicationAcceptedPayload{
+			ApplicationID:   app.ID,
+			CollaborationID: collab.ID,
+			EventID:         collab.EventID,
+			CreatorUserID:   collab.CreatorUserID,
+			VenueUserID:     collab.VenueUserID,
+		})
 	})
 }
 
-// CompleteCollaborationTx атомарно завершает коллаборацию и помечает событие как проведённое.
-func (r *ApplicationRepository) CompleteCollaborationTx(collaborationID int, eventID int) error {
+// CompleteCollaborationTx атомарно завершает коллаборацию и пишет CollaborationCompleted
+// в outbox. Отметку мероприятия проведённым делает event-service.
+func (r *ApplicationRepository) CompleteCollaborationTx(collab *models.Collaboration) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&models.Collaboration{}).Where("id = ?", collaborationID).Update("status", "completed").Error; err != nil {
+		if err := tx.Model(&models.Collaboration{}).Where("id = ?", collab.ID).Update("status", "completed").Error; err != nil {
 			return err
 		}
-		return tx.Model(&Event{}).Where("id = ?", eventID).Update("is_completed", true).Error
+		return outbox.Enqueue(tx, eventbus.CollaborationCompleted, collab.ID, eventbus.CollaborationCompletedPayload{
+			CollaborationID: collab.ID,
+			EventID:         collab.EventID,
+			CreatorUserID:   collab.CreatorUserID,
+			VenueUserID:     collab.VenueUserID,
+		})
 	})
 }
 
@@ -132,14 +143,20 @@ func (r *ApplicationRepository) ListCollaborationPartners(userID int) ([]int, er
 	return partnerIDs, err
 }
 
-func (r *ApplicationRepository) ListCollaborations(userID int, status string, limit, offset int) ([]models.Collaboration, error) {
+func (r *ApplicationRepository) ListCollaborations(userID int, status string, page pagination.Params) ([]models.Collaboration, *int64, error) {
 	var collabs []models.Collaboration
-	query := r.db.Where("creator_user_id = ? OR venue_user_id = ?", userID, userID)
+	query := r.db.Model(&models.Collaboration{}).Where("creator_user_id = ? OR venue_user_id = ?", userID, userID)
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
-	err := query.Order("updated_at DESC").Limit(limit).Offset(offset).Find(&collabs).Error
-	return collabs, err
+
+	total, err := pagination.Count(query, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = pagination.Apply(query, "collaborations", page).Find(&collabs).Error
+	return collabs, total, err
 }
 
 func (r *ApplicationRepository) GetCompletedEventIDsByUserID(userID int) ([]int, error) {
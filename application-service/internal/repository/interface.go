@@ -1,6 +1,10 @@
 package repository
 
-import "application-service/internal/models"
+import (
+	"application-service/internal/models"
+	"shared/pagination"
+	"time"
+)
 
 //go:generate mockgen -source=interface.go -destination=mocks/mock_repository.go -package=mocks
 
@@ -8,14 +12,37 @@ type ApplicationRepositoryInterface interface {
 	HasMirrorPendingApplication(senderID, receiverID, eventID int) (bool, error)
 	CreateApplication(app *models.Application) error
 	GetApplicationByID(id int) (*models.Application, error)
-	ListApplications(userID int, role string, status string, limit, offset int) ([]models.Application, error)
+	ListApplications(userID int, role string, status string, page pagination.Params) ([]models.Application, *int64, error)
 	UpdateApplication(app *models.Application) error
 	DeleteApplication(id int) error
 	AcceptApplicationTx(app *models.Application, collab *models.Collaboration) error
-	CompleteCollaborationTx(collaborationID int, eventID int) error
+	CompleteCollaborationTx(collab *models.Collaboration) error
 	CancelCollaborationTx(collaborationID int) error
 	GetCollaborationByID(id int) (*models.Collaboration, error)
 	ListCollaborationPartners(userID int) ([]int, error)
-	ListCollaborations(userID int, status string, limit, offset int) ([]models.Collaboration, error)
+	ListCollaborations(userID int, status string, page pagination.Params) ([]models.Collaboration, *int64, error)
 	GetCompletedEventIDsByUserID(userID int) ([]int, error)
 }
+
+type ReviewRepositoryInterface interface {
+	CreateReview(review *models.Review) error
+	GetReviewByID(id int) (*models.Review, error)
+	UpdateReview(review *models.Review) error
+	ListReviewsByTarget(targetID int, includeHidden bool, page pagination.Params) ([]models.Review, *int64, error)
+	GetRatingSummary(targetID int) (*models.RatingSummary, error)
+	GetRatingSummaries(targetIDs []int) ([]models.RatingSummary, error)
+}
+
+type MessageRepositoryInterface interface {
+	CreateConversation(conv *models.Conversation) error
+	GetConversationByID(id int) (*models.Conversation, error)
+	GetConversationByApplicationID(applicationID int) (*models.Conversation, error)
+	UpdateConversation(conv *models.Conversation) error
+	ListConversations(userID int, page pagination.Params) ([]models.Conversation, *int64, error)
+	CreateMessage(msg *models.Message) error
+	ListMessages(conversationID int, beforeID int, limit int) ([]models.Message, error)
+	GetLastMessages(conversationIDs []int) (map[int]models.Message, error)
+	MarkMessagesRead(conversationID int, readerID int, readAt time.Time) (int64, error)
+	CountUnreadByConversation(userID int, conversationIDs []int) (map[int]int64, error)
+	CountUnread(userID int) (int64, error)
+}
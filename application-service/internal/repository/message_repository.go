@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"application-service/internal/models"
+	"errors"
+	"shared/pagination"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrConversationExists = errors.New("conversation for this application already exists")
+
+type MessageRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageRepository(db *gorm.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+func (r *MessageRepository) CreateConversation(conv *models.Conversation) error {
+	err := r.db.Create(conv).Error
+	if err != nil && strings.Contains(err.Error(), "uq_conversations_application") {
+		return ErrConversationExists
+	}
+	return err
+}
+
+func (r *MessageRepository) GetConversationByID(id int) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := r.db.First(&conv, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+func (r *MessageRepository) GetConversationByApplicationID(applicationID int) (*models.Conversation, error) {
+	var conv models.Conversation
+	err := r.db.Where("application_id = ?", applicationID).First(&conv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+func (r *MessageRepository) UpdateConversation(conv *models.Conversation) error {
+	return r.db.Save(conv).Error
+}
+
+// ListConversations возвращает страницу переписок пользователя, сначала новые
+func (r *MessageRepository) ListConversations(userID int, page pagination.Params) ([]models.Conversation, *int64, error) {
+	var convs []models.Conversation
+	query := r.db.Model(&models.Conversation{}).Where("sender_id = ? OR receiver_id = ?", userID, userID)
+
+	total, err := pagination.Count(query, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = pagination.Apply(query, "conversations", page).Find(&convs).Error
+	return convs, total, err
+}
+
+// CreateMessage атомарно сохраняет сообщение и сдвигает last_message_at переписки.
+func (r *MessageRepository) CreateMessage(msg *models.Message) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(msg).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Conversation{}).
+			Where("id = ?", msg.ConversationID).
+			Update("last_message_at", msg.CreatedAt).Error
+	})
+}
+
+// ListMessages возвращает сообщения переписки от новых к старым.
+// beforeID > 0 — keyset-пагинация: только сообщения с id < beforeID.
+func (r *MessageRepository) ListMessages(conversationID int, beforeID int, limit int) ([]models.Message, error) {
+	var msgs []models.Message
+	query := r.db.Where("conversation_id = ?", conversationID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&msgs).Error
+	return msgs, err
+}
+
+func (r *MessageRepository) GetLastMessages(conversationIDs []int) (map[int]models.Message, error) {
+	result := make(map[int]models.Message, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+	var msgs []models.Message
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (conversation_id) *
+		FROM messages
+		WHERE conversation_id IN ?
+		ORDER BY conversation_id, id DESC
+	`, conversationIDs).Scan(&msgs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		result[m.ConversationID] = m
+	}
+	return result, nil
+}
+
+// MarkMessagesRead проставляет read_at всем непрочитанным входящим сообщениям переписки.
+func (r *MessageRepository) MarkMessagesRead(conversationID int, readerID int, readAt time.Time) (int64, error) {
+	result := r.db.Model(&models.Message{}).
+		Where("conversation_id = ? AND sender_id <> ? AND read_at IS NULL", conversationID, readerID).
+		Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+// CountUnreadByConversation возвращает число непрочитанных входящих сообщений по каждой переписке.
+func (r *MessageRepository) CountUnreadByConversation(userID int, conversationIDs []int) (map[int]int64, error) {
+	result := make(map[int]int64, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		ConversationID int
+		Count          int64
+	}
+	err := r.db.Model(&models.Message{}).
+		Select("conversation_id, COUNT(*) AS count").
+		Where("conversation_id IN ? AND sender_id <> ? AND read_at IS NULL", conversationIDs, userID).
+		Group("conversation_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ConversationID] = row.Count
+	}
+	return result, nil
+}
+
+// CountUnread возвращает общее число непрочитанных входящих сообщений пользователя.
+func (r *MessageRepository) CountUnread(userID int) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Message{}).
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
+		Where("(conversations.sender_id = ? OR conversations.receiver_id = ?) AND messages.sender_id <> ? AND messages.read_at IS NULL",
+			userID, userID, userID).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"application-service/internal/models"
+	"errors"
+	"math"
+	"shared/pagination"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var ErrDuplicateReview = errors.New("review for this collaboration already exists")
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func (r *ReviewRepository) CreateReview(review *models.Review) error {
+	err := r.db.Create(review).Error
+	if err != nil && strings.Contains(err.Error(), "uq_reviews_collaboration_author") {
+		return ErrDuplicateReview
+	}
+	return err
+}
+
+func (r *ReviewRepository) GetReviewByID(id int) (*models.Review, error) {
+	var review models.Review
+	err := r.db.First(&review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepository) UpdateReview(review *models.Review) error {
+	return r.db.Save(review).Error
+}
+
+func (r *ReviewRepository) ListReviewsByTarget(targetID int, includeHidden bool, page pagination.Params) ([]models.Review, *int64, error) {
+	var reviews []models.Review
+	query := r.db.Model(&models.Review{}).Where("target_id = ?", targetID)
+	if !includeHidden {
+		query = query.Where("is_hidden = false")
+	}
+
+	total, err := pagination.Count(query, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = pagination.Apply(query, "reviews", page).Find(&reviews).Error
+	return reviews, total, err
+}
+
+func (r *ReviewRepository) GetRatingSummary(targetID int) (*models.RatingSummary, error) {
+	var row struct {
+		Average float64
+		Count   int
+	}
+	err := r.db.Model(&models.Review{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("target_id = ? AND is_hidden = false", targetID).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &models.RatingSummary{
+		UserID:  targetID,
+		Average: math.Round(row.Average*100) / 100,
+		Count:   row.Count,
+	}, nil
+}
+
+// GetRatingSummaries возвращает оценки пользователей из targetIDs одним запросом.
+// Пользователи без видимых отзывов в результат не попадают.
+func (r *ReviewRepository) GetRatingSummaries(targetIDs []int) ([]models.RatingSummary, error) {
+	var rows []models.RatingSummary
+	err := r.db.Model(&models.Review{}).
+		Select("target_id AS user_id, AVG(rating) AS average, COUNT(*) AS count").
+		Where("target_id IN ? AND is_hidden = false", targetIDs).
+		Group("target_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Average = math.Round(rows[i].Average*100) / 100
+	}
+	return rows, nil
+}
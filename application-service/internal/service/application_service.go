@@ -1,17 +1,28 @@
 package service
 
 import (
+	"application-service/internal/clients"
 	"application-service/internal/models"
+	"application-service/internal/notifier"
 	"application-service/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"shared/pagination"
 )
 
+// DefaultListLimit — размер страницы заявок и коллабораций по умолчанию
+const DefaultListLimit = 10
 
 type ApplicationService struct {
-	repo repository.ApplicationRepositoryInterface
+	repo     repository.ApplicationRepositoryInterface
+	events   clients.EventClient
+	users    clients.UserClient
+	notifier notifier.Notifier
 }
 
-func NewApplicationService(repo repository.ApplicationRepositoryInterface) *ApplicationService {
-	return &ApplicationService{repo: repo}
+func NewApplicationService(repo repository.ApplicationRepositoryInterface, events clients.EventClient, users clients.UserClient, n notifier.Notifier) *ApplicationService {
+	return &ApplicationService{repo: repo, events: events, users: users, notifier: n}
 }
 
 type CreateApplicationRequest struct {
@@ -26,6 +37,10 @@ func (s *ApplicationService) CreateApplication(req *CreateApplicationRequest, se
 		return nil, ErrCannotApplyToSelf
 	}
 
+	if err := s.validateParticipants(req, senderID, senderType); err != nil {
+		return nil, err
+	}
+
 	mirror, err := s.repo.HasMirrorPendingApplication(senderID, req.ReceiverID, req.EventID)
 	if err != nil {
 		return nil, err
@@ -48,9 +63,64 @@ func (s *ApplicationService) CreateApplication(req *CreateApplicationRequest, se
 		return nil, err
 	}
 
+	s.notifier.Notify(app.ReceiverID, notifier.EventApplicationCreated, app)
+
 	return app, nil
 }
 
+// counterpartRole — роль второй стороны заявки: creator пишет площадкам,
+// площадка приглашает creator'ов
+func counterpartRole(role string) string {
+	switch role {
+	case "creator":
+		return "venue"
+	case "venue":
+		return "creator"
+	}
+	return ""
+}
+
+// validateParticipants сверяет заявку с event-service и user-service:
+// получатель — противоположная отправителю роль, мероприятие существует
+// и открыто, принадлежит creator'у из заявки, а у получателя действительно
+// есть профиль указанной роли.
+func (s *ApplicationService) validateParticipants(req *CreateApplicationRequest, senderID int, senderType string) error {
+	if req.ReceiverType != counterpartRole(senderType) {
+		return ErrInvalidReceiverType
+	}
+
+	ctx := context.Background()
+
+	event, err := s.events.GetEvent(ctx, req.EventID)
+	if errors.Is(err, clients.ErrNotFound) {
+		return ErrEventNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDependencyUnavailable, err)
+	}
+	if !event.IsActive || event.IsCompleted {
+		return ErrEventNotActive
+	}
+
+	// Мероприятие в заявке — всегда мероприятие creator'а-участника
+	creatorID := req.ReceiverID
+	if senderType == "creator" {
+		creatorID = senderID
+	}
+	if event.CreatorID != creatorID {
+		return ErrEventOwnershipMismatch
+	}
+
+	ok, err := s.users.HasRole(ctx, req.ReceiverID, req.ReceiverType)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDependencyUnavailable, err)
+	}
+	if !ok {
+		return ErrReceiverRoleMismatch
+	}
+	return nil
+}
+
 func (s *ApplicationService) GetApplicationByID(id int, userID int) (*models.Application, error) {
 	app, err := s.repo.GetApplicationByID(id)
 	if err != nil {
@@ -64,15 +134,15 @@ func (s *ApplicationService) GetApplicationByID(id int, userID int) (*models.App
 	return app, nil
 }
 
-func (s *ApplicationService) ListApplications(userID int, role string, status string, limit, offset int) ([]models.Application, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
+func (s *ApplicationService) ListApplications(userID int, role string, status string, page pagination.Params) (pagination.Page[models.Application], error) {
+	page = page.Normalize(DefaultListLimit)
+	apps, total, err := s.repo.ListApplications(userID, role, status, page)
+	if err != nil {
+		return pagination.Page[models.Application]{}, err
 	}
-
-	return s.repo.ListApplications(userID, role, status, limit, offset)
+	return pagination.NewPage(apps, total, page, func(a *models.Application) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: a.CreatedAt, ID: a.ID}
+	}), nil
 }
 
 func (s *ApplicationService) AcceptApplication(id int, userID int) (*models.Application, error) {
@@ -117,6 +187,8 @@ func (s *ApplicationService) AcceptApplication(id int, userID int) (*models.Appl
 		return nil, err
 	}
 
+	s.notifier.Notify(app.SenderID, notifier.EventApplicationStatusChanged, app)
+
 	return app, nil
 }
 
@@ -139,6 +211,8 @@ func (s *ApplicationService) RejectApplication(id int, userID int) (*models.Appl
 		return nil, err
 	}
 
+	s.notifier.Notify(app.SenderID, notifier.EventApplicationStatusChanged, app)
+
 	return app, nil
 }
 
@@ -173,11 +247,13 @@ func (s *ApplicationService) CompleteCollaboration(id int, userID int, userRole
 		return nil, ErrCollaborationAlreadyProcessed
 	}
 
-	if err := s.repo.CompleteCollaborationTx(collab.ID, collab.EventID); err != nil {
+	if err := s.repo.CompleteCollaborationTx(collab); err != nil {
 		return nil, err
 	}
 
 	collab.Status = "completed"
+	s.notifier.Notify(collab.VenueUserID, notifier.EventCollaborationStatusChanged, collab)
+
 	return collab, nil
 }
 
@@ -199,22 +275,29 @@ func (s *ApplicationService) CancelCollaboration(id int, userID int, userRole st
 		return ErrCollaborationAlreadyProcessed
 	}
 
-	return s.repo.CancelCollaborationTx(collab.ID)
+	if err := s.repo.CancelCollaborationTx(collab.ID); err != nil {
+		return err
+	}
+
+	collab.Status = "cancelled"
+	s.notifier.Notify(collab.VenueUserID, notifier.EventCollaborationStatusChanged, collab)
+
+	return nil
 }
 
 func (s *ApplicationService) ListCollaborationPartners(userID int) ([]int, error) {
 	return s.repo.ListCollaborationPartners(userID)
 }
 
-func (s *ApplicationService) ListCollaborations(userID int, status string, limit, offset int) ([]models.Collaboration, error) {
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
+func (s *ApplicationService) ListCollaborations(userID int, status string, page pagination.Params) (pagination.Page[models.Collaboration], error) {
+	page = page.Normalize(DefaultListLimit)
+	collabs, total, err := s.repo.ListCollaborations(userID, status, page)
+	if err != nil {
+		return pagination.Page[models.Collaboration]{}, err
 	}
-
-	return s.repo.ListCollaborations(userID, status, limit, offset)
+	return pagination.NewPage(collabs, total, page, func(c *models.Collaboration) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	}), nil
 }
 
 func (s *ApplicationService) GetCompletedEventIDsByUserID(userID int) ([]int, error) {
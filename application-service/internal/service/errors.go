@@ -14,4 +14,14 @@ var (
 	ErrApplicationAlreadyProcessed   = errors.New("APPLICATION_ALREADY_PROCESSED")
 	ErrCollaborationNotFound         = errors.New("COLLABORATION_NOT_FOUND")
 	ErrCollaborationAlreadyProcessed = errors.New("COLLABORATION_ALREADY_PROCESSED")
+	ErrCollaborationNotCompleted     = errors.New("COLLABORATION_NOT_COMPLETED")
+	ErrDuplicateReview               = repository.ErrDuplicateReview
+	ErrReviewNotFound                = errors.New("REVIEW_NOT_FOUND")
+	ErrConversationNotFound          = errors.New("CONVERSATION_NOT_FOUND")
+	ErrEventNotFound                 = errors.New("EVENT_NOT_FOUND")
+	ErrEventNotActive                = errors.New("EVENT_NOT_ACTIVE")
+	ErrEventOwnershipMismatch        = errors.New("EVENT_OWNERSHIP_MISMATCH")
+	ErrReceiverRoleMismatch          = errors.New("RECEIVER_ROLE_MISMATCH")
+	ErrInvalidReceiverType           = errors.New("INVALID_RECEIVER_TYPE")
+	ErrDependencyUnavailable         = errors.New("DEPENDENCY_UNAVAILABLE")
 )
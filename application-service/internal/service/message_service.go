@@ -0,0 +1,260 @@
+package service
+
+import (
+	"application-service/internal/models"
+	"application-service/internal/notifier"
+	"application-service/internal/repository"
+	"errors"
+	"shared/pagination"
+	"time"
+)
+
+// DefaultConversationsLimit — размер страницы переписок по умолчанию
+const DefaultConversationsLimit = 20
+
+type MessageService struct {
+	msgRepo  repository.MessageRepositoryInterface
+	appRepo  repository.ApplicationRepositoryInterface
+	notifier notifier.Notifier
+}
+
+func NewMessageService(msgRepo repository.MessageRepositoryInterface, appRepo repository.ApplicationRepositoryInterface, n notifier.Notifier) *MessageService {
+	return &MessageService{msgRepo: msgRepo, appRepo: appRepo, notifier: n}
+}
+
+// MessagesReadEvent — полезная нагрузка события EventMessagesRead
+type MessagesReadEvent struct {
+	ConversationID int       `json:"conversation_id"`
+	ReaderID       int       `json:"reader_id"`
+	ReadAt         time.Time `json:"read_at"`
+}
+
+func counterpart(conv *models.Conversation, userID int) int {
+	if conv.SenderID == userID {
+		return conv.ReceiverID
+	}
+	return conv.SenderID
+}
+
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+type ConversationSummary struct {
+	models.Conversation
+	LastMessage *models.Message `json:"last_message,omitempty"`
+	UnreadCount int64           `json:"unread_count"`
+}
+
+type MessagesPage struct {
+	Messages     []models.Message `json:"messages"`
+	NextBeforeID *int             `json:"next_before_id,omitempty"`
+	Limit        int              `json:"limit"`
+}
+
+type MarkReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+type UnreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// OpenApplicationConversation возвращает переписку по заявке, создавая её при первом обращении.
+func (s *MessageService) OpenApplicationConversation(applicationID int, userID int) (*models.Conversation, error) {
+	app, err := s.appRepo.GetApplicationByID(applicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+	if app.SenderID != userID && app.ReceiverID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	return s.getOrCreateConversation(app, nil)
+}
+
+// OpenCollaborationConversation возвращает переписку коллаборации — это тот же тред,
+// что и у исходной заявки, с проставленным collaboration_id.
+func (s *MessageService) OpenCollaborationConversation(collaborationID int, userID int) (*models.Conversation, error) {
+	collab, err := s.appRepo.GetCollaborationByID(collaborationID)
+	if err != nil {
+		return nil, ErrCollaborationNotFound
+	}
+	if collab.CreatorUserID != userID && collab.VenueUserID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	app, err := s.appRepo.GetApplicationByID(collab.ApplicationID)
+	if err != nil {
+		return nil, ErrApplicationNotFound
+	}
+
+	return s.getOrCreateConversation(app, &collab.ID)
+}
+
+func (s *MessageService) getOrCreateConversation(app *models.Application, collaborationID *int) (*models.Conversation, error) {
+	conv, err := s.msgRepo.GetConversationByApplicationID(app.ID)
+	if err != nil {
+		conv = &models.Conversation{
+			ApplicationID:   app.ID,
+			CollaborationID: collaborationID,
+			EventID:         app.EventID,
+			SenderID:        app.SenderID,
+			ReceiverID:      app.ReceiverID,
+		}
+		err = s.msgRepo.CreateConversation(conv)
+		if errors.Is(err, repository.ErrConversationExists) {
+			// Параллельный запрос успел создать тред — берём его
+			conv, err = s.msgRepo.GetConversationByApplicationID(app.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if collaborationID != nil && conv.CollaborationID == nil {
+		conv.CollaborationID = collaborationID
+		if err := s.msgRepo.UpdateConversation(conv); err != nil {
+			return nil, err
+		}
+	}
+
+	return conv, nil
+}
+
+func (s *MessageService) GetConversation(id int, userID int) (*models.Conversation, error) {
+	conv, err := s.msgRepo.GetConversationByID(id)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	if !conv.HasParticipant(userID) {
+		return nil, ErrAccessDenied
+	}
+	return conv, nil
+}
+
+// ListConversations возвращает страницу переписок пользователя с последним сообщением
+// и счётчиком непрочитанных.
+func (s *MessageService) ListConversations(userID int, page pagination.Params) (pagination.Page[ConversationSummary], error) {
+	page = page.Normalize(DefaultConversationsLimit)
+	rows, total, err := s.msgRepo.ListConversations(userID, page)
+	if err != nil {
+		return pagination.Page[ConversationSummary]{}, err
+	}
+	convPage := pagination.NewPage(rows, total, page, func(c *models.Conversation) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+	convs := convPage.Items
+
+	ids := make([]int, len(convs))
+	for i := range convs {
+		ids[i] = convs[i].ID
+	}
+
+	lastMessages, err := s.msgRepo.GetLastMessages(ids)
+	if err != nil {
+		return pagination.Page[ConversationSummary]{}, err
+	}
+	unread, err := s.msgRepo.CountUnreadByConversation(userID, ids)
+	if err != nil {
+		return pagination.Page[ConversationSummary]{}, err
+	}
+
+	result := make([]ConversationSummary, len(convs))
+	for i := range convs {
+		result[i] = ConversationSummary{Conversation: convs[i], UnreadCount: unread[convs[i].ID]}
+		if msg, ok := lastMessages[convs[i].ID]; ok {
+			result[i].LastMessage = &msg
+		}
+	}
+	return pagination.Page[ConversationSummary]{
+		Items:      result,
+		NextCursor: convPage.NextCursor,
+		Total:      convPage.Total,
+		Limit:      convPage.Limit,
+	}, nil
+}
+
+// ListMessages возвращает страницу сообщений от новых к старым. Для следующей
+// страницы клиент передаёт next_before_id как before_id.
+func (s *MessageService) ListMessages(conversationID int, userID int, beforeID int, limit int) (*MessagesPage, error) {
+	if _, err := s.GetConversation(conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Берём на одно сообщение больше, чтобы понять, есть ли следующая страница
+	msgs, err := s.msgRepo.ListMessages(conversationID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagesPage{Messages: msgs, Limit: limit}
+	if len(msgs) > limit {
+		page.Messages = msgs[:limit]
+		next := page.Messages[limit-1].ID
+		page.NextBeforeID = &next
+	}
+	if page.Messages == nil {
+		page.Messages = []models.Message{}
+	}
+	return page, nil
+}
+
+func (s *MessageService) SendMessage(conversationID int, userID int, req *SendMessageRequest) (*models.Message, error) {
+	conv, err := s.GetConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &models.Message{
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Body:           req.Body,
+	}
+	if err := s.msgRepo.CreateMessage(msg); err != nil {
+		return nil, err
+	}
+
+	s.notifier.Notify(counterpart(conv, userID), notifier.EventMessageCreated, msg)
+
+	return msg, nil
+}
+
+// MarkRead отмечает все входящие сообщения переписки прочитанными (read receipts).
+func (s *MessageService) MarkRead(conversationID int, userID int) (*MarkReadResponse, error) {
+	conv, err := s.GetConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	readAt := time.Now()
+	updated, err := s.msgRepo.MarkMessagesRead(conversationID, userID, readAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated > 0 {
+		s.notifier.Notify(counterpart(conv, userID), notifier.EventMessagesRead, MessagesReadEvent{
+			ConversationID: conversationID,
+			ReaderID:       userID,
+			ReadAt:         readAt,
+		})
+	}
+
+	return &MarkReadResponse{Updated: updated}, nil
+}
+
+func (s *MessageService) CountUnread(userID int) (*UnreadCountResponse, error) {
+	count, err := s.msgRepo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &UnreadCountResponse{Unread: count}, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"application-service/internal/models"
+	"application-service/internal/notifier"
+	"application-service/internal/repository"
+	"shared/pagination"
+)
+
+// DefaultReviewsLimit — размер страницы отзывов по умолчанию
+const DefaultReviewsLimit = 10
+
+type ReviewService struct {
+	reviewRepo repository.ReviewRepositoryInterface
+	appRepo    repository.ApplicationRepositoryInterface
+	notifier   notifier.Notifier
+}
+
+func NewReviewService(reviewRepo repository.ReviewRepositoryInterface, appRepo repository.ApplicationRepositoryInterface, n notifier.Notifier) *ReviewService {
+	return &ReviewService{reviewRepo: reviewRepo, appRepo: appRepo, notifier: n}
+}
+
+type CreateReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text" binding:"omitempty,max=2000"`
+}
+
+type ModerateReviewRequest struct {
+	IsHidden bool   `json:"is_hidden"`
+	Reason   string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// UserReviewsResponse — страница отзывов о пользователе вместе с его оценкой
+type UserReviewsResponse struct {
+	pagination.Page[models.Review]
+	Summary *models.RatingSummary `json:"summary"`
+}
+
+// CreateReview оставляет отзыв о второй стороне завершённой коллаборации.
+// Каждый участник может оставить не больше одного отзыва на коллаборацию.
+func (s *ReviewService) CreateReview(collaborationID int, authorID int, req *CreateReviewRequest) (*models.Review, error) {
+	collab, err := s.appRepo.GetCollaborationByID(collaborationID)
+	if err != nil {
+		return nil, ErrCollaborationNotFound
+	}
+
+	review := &models.Review{
+		CollaborationID: collab.ID,
+		EventID:         collab.EventID,
+		AuthorID:        authorID,
+		Rating:          req.Rating,
+		Text:            req.Text,
+	}
+
+	switch authorID {
+	case collab.CreatorUserID:
+		review.AuthorType = "creator"
+		review.TargetID = collab.VenueUserID
+		review.TargetType = "venue"
+	case collab.VenueUserID:
+		review.AuthorType = "venue"
+		review.TargetID = collab.CreatorUserID
+		review.TargetType = "creator"
+	default:
+		return nil, ErrAccessDenied
+	}
+
+	if collab.Status != "completed" {
+		return nil, ErrCollaborationNotCompleted
+	}
+
+	if err := s.reviewRepo.CreateReview(review); err != nil {
+		return nil, err
+	}
+
+	s.notifier.Notify(review.TargetID, notifier.EventNotification, notifier.Notification{
+		Kind:     "review_received",
+		EntityID: review.ID,
+	})
+
+	return review, nil
+}
+
+// ListUserReviews возвращает страницу видимых отзывов о пользователе и его агрегированную оценку.
+func (s *ReviewService) ListUserReviews(targetID int, page pagination.Params) (*UserReviewsResponse, error) {
+	page = page.Normalize(DefaultReviewsLimit)
+	summary, err := s.reviewRepo.GetRatingSummary(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, total, err := s.reviewRepo.ListReviewsByTarget(targetID, false, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserReviewsResponse{
+		Page: pagination.NewPage(reviews, total, page, func(r *models.Review) pagination.Cursor {
+			return pagination.Cursor{CreatedAt: r.CreatedAt, ID: r.ID}
+		}),
+		Summary: summary,
+	}, nil
+}
+
+// MaxRatingSummaries — сколько пользователей можно запросить в GetRatingSummaries за раз
+const MaxRatingSummaries = 100
+
+// GetRatingSummaries возвращает оценки пользователей в порядке userIDs (без повторов).
+// Для пользователей без видимых отзывов возвращается нулевая оценка.
+func (s *ReviewService) GetRatingSummaries(userIDs []int) ([]models.RatingSummary, error) {
+	rows, err := s.reviewRepo.GetRatingSummaries(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[int]models.RatingSummary, len(rows))
+	for _, row := range rows {
+		byUser[row.UserID] = row
+	}
+
+	summaries := make([]models.RatingSummary, 0, len(userIDs))
+	seen := make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		summary, ok := byUser[id]
+		if !ok {
+			summary = models.RatingSummary{UserID: id}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// ModerateReview скрывает или возвращает отзыв (только admin). Скрытые отзывы
+// не показываются в профилях и не учитываются в агрегированной оценке.
+func (s *ReviewService) ModerateReview(id int, req *ModerateReviewRequest) (*models.Review, error) {
+	review, err := s.reviewRepo.GetReviewByID(id)
+	if err != nil {
+		return nil, ErrReviewNotFound
+	}
+
+	review.IsHidden = req.IsHidden
+	if req.IsHidden {
+		review.ModerationReason = req.Reason
+	} else {
+		review.ModerationReason = ""
+	}
+
+	if err := s.reviewRepo.UpdateReview(review); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"application-service/internal/clients"
 	"application-service/internal/config"
 	"application-service/internal/handlers"
 	"application-service/internal/middleware"
+	"application-service/internal/notifier"
+	"application-service/internal/outbox"
 	"application-service/internal/repository"
 	"application-service/internal/service"
+	"context"
 	"log"
+	"os"
+	"shared/eventbus"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/driver/postgres"
@@ -34,11 +41,35 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// Redis: pub/sub для realtime-событий и Streams для доменных событий.
+	// Если он недоступен, сервис работает: realtime-публикации логируются
+	// как ошибки, а доменные события копятся в outbox до восстановления
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.RedisURL})
+	defer redisClient.Close()
+	eventNotifier := notifier.NewRedisNotifier(redisClient)
+
+	hostname, _ := os.Hostname()
+	eventBus := eventbus.NewRedisStreams(redisClient, eventbus.DefaultStream, hostname)
+	relay := outbox.NewRelay(db, eventBus)
+	go relay.Run(context.Background())
+
 	applicationRepo := repository.NewApplicationRepository(db)
-	applicationService := service.NewApplicationService(applicationRepo)
+	clientOpts := clients.DefaultOptions()
+	eventClient := clients.NewEventClient(cfg.EventServiceURL, clientOpts)
+	userClient := clients.NewUserClient(cfg.UserServiceURL, clientOpts)
+
+	applicationService := service.NewApplicationService(applicationRepo, eventClient, userClient, eventNotifier)
 	applicationHandler := handlers.NewApplicationHandler(applicationService)
 	collaborationHandler := handlers.NewCollaborationHandler(applicationService)
 
+	reviewRepo := repository.NewReviewRepository(db)
+	reviewService := service.NewReviewService(reviewRepo, applicationRepo, eventNotifier)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+
+	messageRepo := repository.NewMessageRepository(db)
+	messageService := service.NewMessageService(messageRepo, applicationRepo, eventNotifier)
+	messageHandler := handlers.NewMessageHandler(messageService)
+
 	r := gin.Default()
 
 	r.Use(middleware.PrometheusMiddleware("application-service"))
@@ -60,6 +91,7 @@ func main() {
 		applications.PATCH("/:id/accept", applicationHandler.AcceptApplication)
 		applications.PATCH("/:id/reject", applicationHandler.RejectApplication)
 		applications.DELETE("/:id", applicationHandler.DeleteApplication)
+		applications.POST("/:id/conversation", messageHandler.OpenApplicationConversation)
 	}
 
 	collaborations := r.Group("/collaborations")
@@ -71,6 +103,39 @@ func main() {
 		collaborations.GET("/:id", collaborationHandler.GetCollaboration)
 		collaborations.PATCH("/:id/complete", collaborationHandler.CompleteCollaboration)
 		collaborations.PATCH("/:id/cancel", collaborationHandler.CancelCollaboration)
+		collaborations.POST("/:id/reviews", reviewHandler.CreateReview)
+		collaborations.POST("/:id/conversation", messageHandler.OpenCollaborationConversation)
+	}
+
+	conversations := r.Group("/conversations")
+	conversations.Use(middleware.ExtractUserContext())
+	{
+		conversations.GET("", messageHandler.ListConversations)
+		conversations.GET("/unread-count", messageHandler.GetUnreadCount)
+		conversations.GET("/:id", messageHandler.GetConversation)
+		conversations.GET("/:id/messages", messageHandler.ListMessages)
+		conversations.POST("/:id/messages", messageHandler.SendMessage)
+		conversations.PATCH("/:id/read", messageHandler.MarkRead)
+	}
+
+	reviews := r.Group("/reviews")
+	// Роль admin проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	reviews.Use(middleware.ExtractUserContext())
+	{
+		reviews.PATCH("/:id/moderation", reviewHandler.ModerateReview)
+	}
+
+	// Публичные эндпоинты (без авторизации)
+	public := r.Group("/public")
+	{
+		public.GET("/users/:user_id/reviews", reviewHandler.ListUserReviews)
+	}
+
+	// Внутренние ручки для других сервисов. Gateway их не пропускает: в политике
+	// доступа нет правила для /api/application/internal/**, а по умолчанию запрос запрещен.
+	internalAPI := r.Group("/internal")
+	{
+		internalAPI.GET("/ratings", reviewHandler.GetRatingSummaries)
 	}
 
 	log.Printf("Application Service starting on port %s", cfg.ServerPort)
@@ -4,10 +4,14 @@ package integration
 
 import (
 	"application-service/internal/models"
+	"application-service/internal/outbox"
 	"application-service/internal/repository"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"shared/eventbus"
 	"testing"
 	"time"
 
@@ -98,12 +102,23 @@ func migrateTestDB(db *gorm.DB) error {
 			created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
 		);
+
+		CREATE TABLE IF NOT EXISTS application_outbox (
+			id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			event_type   VARCHAR(100) NOT NULL,
+			aggregate_id INT NOT NULL,
+			payload      JSONB NOT NULL,
+			created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			published_at TIMESTAMPTZ,
+			attempts     INT NOT NULL DEFAULT 0,
+			last_error   TEXT NOT NULL DEFAULT ''
+		);
 	`).Error
 }
 
 func resetDB(t *testing.T) {
 	t.Helper()
-	if err := testDB.Exec("TRUNCATE applications, collaborations, events RESTART IDENTITY CASCADE").Error; err != nil {
+	if err := testDB.Exec("TRUNCATE applications, collaborations, events, application_outbox RESTART IDENTITY CASCADE").Error; err != nil {
 		t.Fatalf("failed to reset db: %v", err)
 	}
 }
@@ -225,11 +240,11 @@ func TestIntegration_AcceptApplicationTx(t *testing.T) {
 		t.Fatalf("accept tx failed: %v", err)
 	}
 
-	// Проверяем что event снят с каталога
-	var isActive bool
-	testDB.Raw("SELECT is_active FROM events WHERE id = 1").Scan(&isActive)
-	if isActive {
-		t.Error("expected event.is_active = false after accept")
+	// Событие ApplicationAccepted записано в outbox той же транзакцией
+	var eventTypes []string
+	testDB.Raw("SELECT event_type FROM application_outbox ORDER BY id").Scan(&eventTypes)
+	if len(eventTypes) != 1 || eventTypes[0] != eventbus.ApplicationAccepted {
+		t.Errorf("expected one ApplicationAccepted in outbox, got %v", eventTypes)
 	}
 
 	// Проверяем что коллаборация создана
@@ -238,7 +253,7 @@ func TestIntegration_AcceptApplicationTx(t *testing.T) {
 	}
 }
 
-// ─── CompleteCollaborationTx: event.is_completed ──────────────────────────────
+// ─── CompleteCollaborationTx: CollaborationCompleted в outbox ─────────────────
 
 func TestIntegration_CompleteCollaborationTx(t *testing.T) {
 	resetDB(t)
@@ -259,14 +274,14 @@ func TestIntegration_CompleteCollaborationTx(t *testing.T) {
 	}
 	repo.AcceptApplicationTx(app, collab)
 
-	if err := repo.CompleteCollaborationTx(collab.ID, 1); err != nil {
+	if err := repo.CompleteCollaborationTx(collab); err != nil {
 		t.Fatalf("complete tx failed: %v", err)
 	}
 
-	var isCompleted bool
-	testDB.Raw("SELECT is_completed FROM events WHERE id = 1").Scan(&isCompleted)
-	if !isCompleted {
-		t.Error("expected event.is_completed = true after complete")
+	var eventTypes []string
+	testDB.Raw("SELECT event_type FROM application_outbox ORDER BY id").Scan(&eventTypes)
+	if len(eventTypes) != 2 || eventTypes[1] != eventbus.CollaborationCompleted {
+		t.Errorf("expected CollaborationCompleted in outbox, got %v", eventTypes)
 	}
 
 	var collabStatus string
@@ -276,6 +291,94 @@ func TestIntegration_CompleteCollaborationTx(t *testing.T) {
 	}
 }
 
+// ─── Outbox relay ─────────────────────────────────────────────────────────────
+
+func TestIntegration_OutboxRelay(t *testing.T) {
+	resetDB(t)
+	seedEvent(t, 1, 1)
+	repo := repository.NewApplicationRepository(testDB)
+
+	app := &models.Application{
+		SenderID: 1, SenderType: "creator",
+		ReceiverID: 2, ReceiverType: "venue",
+		EventID: 1, Status: "pending",
+	}
+	repo.CreateApplication(app)
+	app.Status = "accepted"
+	repo.AcceptApplicationTx(app, &models.Collaboration{
+		ApplicationID: app.ID, EventID: 1,
+		CreatorUserID: 1, VenueUserID: 2, Status: "pending",
+	})
+
+	bus := eventbus.NewMemory()
+	var received []eventbus.Event
+	bus.Handle("test", func(ctx context.Context, e eventbus.Event) error {
+		received = append(received, e)
+		return nil
+	})
+
+	relay := outbox.NewRelay(testDB, bus)
+	n, err := relay.ProcessBatch(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 published, got %d (err %v)", n, err)
+	}
+	if len(received) != 1 || received[0].Type != eventbus.ApplicationAccepted || received[0].AggregateID != app.ID {
+		t.Fatalf("unexpected events: %+v", received)
+	}
+
+	var payload eventbus.ApplicationAcceptedPayload
+	json.Unmarshal(received[0].Payload, &payload)
+	if payload.EventID != 1 || payload.VenueUserID != 2 {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+
+	// Повторный прогон ничего не публикует
+	if n, _ := relay.ProcessBatch(context.Background()); n != 0 {
+		t.Errorf("expected nothing to relay, got %d", n)
+	}
+}
+
+func TestIntegration_OutboxRelay_PublishFailure(t *testing.T) {
+	resetDB(t)
+	testDB.Exec(`INSERT INTO application_outbox (event_type, aggregate_id, payload) VALUES ('ApplicationAccepted', 1, '{}')`)
+
+	relay := outbox.NewRelay(testDB, failingPublisher{})
+	if _, err := relay.ProcessBatch(context.Background()); err == nil {
+		t.Fatal("expected publish error")
+	}
+
+	var attempts int
+	testDB.Raw("SELECT attempts FROM application_outbox WHERE published_at IS NULL").Scan(&attempts)
+	if attempts != 1 {
+		t.Errorf("expected attempts = 1 for unpublished message, got %d", attempts)
+	}
+}
+
+func TestIntegration_OutboxRelay_SingleActiveRelay(t *testing.T) {
+	resetDB(t)
+	testDB.Exec(`INSERT INTO application_outbox (event_type, aggregate_id, payload) VALUES ('ApplicationAccepted', 1, '{}')`)
+
+	// Другая реплика держит блокировку outbox — эта пропускает тик
+	other := testDB.Begin()
+	other.Exec("SELECT pg_advisory_xact_lock(hashtext('application_outbox'))")
+
+	relay := outbox.NewRelay(testDB, eventbus.NewMemory())
+	if n, err := relay.ProcessBatch(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected relay to skip while locked, got %d (err %v)", n, err)
+	}
+
+	other.Rollback()
+	if n, err := relay.ProcessBatch(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 published after unlock, got %d (err %v)", n, err)
+	}
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, eventbus.Event) error {
+	return errors.New("broker unavailable")
+}
+
 // ─── GetCompletedEventIDsByUserID ─────────────────────────────────────────────
 
 func TestIntegration_GetCompletedEventIDsByUserID(t *testing.T) {
@@ -3,14 +3,17 @@ package unit
 import (
 	"application-service/internal/service"
 	"errors"
+	"shared/pagination"
 	"testing"
+	"time"
 )
 
 // ─── CreateApplication ────────────────────────────────────────────────────────
 
 func TestCreateApplication_Success(t *testing.T) {
+	events, users := newSeededClients()
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, events, users, newMockNotifier())
 
 	app, err := svc.CreateApplication(
 		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
@@ -28,8 +31,9 @@ func TestCreateApplication_Success(t *testing.T) {
 }
 
 func TestCreateApplication_CannotApplyToSelf(t *testing.T) {
+	events, users := newSeededClients()
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, events, users, newMockNotifier())
 
 	_, err := svc.CreateApplication(
 		&service.CreateApplicationRequest{ReceiverID: 1, ReceiverType: "creator", EventID: 10},
@@ -41,10 +45,11 @@ func TestCreateApplication_CannotApplyToSelf(t *testing.T) {
 }
 
 func TestCreateApplication_MirrorExists(t *testing.T) {
+	events, users := newSeededClients()
 	repo := newMockRepo()
 	// venue уже отправил заявку creator'у на этот event
 	repo.applications[1] = newApp(1, 2, 1, 10, "venue", "creator", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, events, users, newMockNotifier())
 
 	_, err := svc.CreateApplication(
 		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
@@ -56,9 +61,10 @@ func TestCreateApplication_MirrorExists(t *testing.T) {
 }
 
 func TestCreateApplication_RepoError(t *testing.T) {
+	events, users := newSeededClients()
 	repo := newMockRepo()
 	repo.errCreate = errors.New("db error")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, events, users, newMockNotifier())
 
 	_, err := svc.CreateApplication(
 		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
@@ -69,12 +75,137 @@ func TestCreateApplication_RepoError(t *testing.T) {
 	}
 }
 
+// ─── CreateApplication: проверки через event-service и user-service ─────────
+
+func TestCreateApplication_EventNotFound(t *testing.T) {
+	events, users := newSeededClients()
+	svc := service.NewApplicationService(newMockRepo(), events, users, newMockNotifier())
+
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 99},
+		1, "creator",
+	)
+	if !errors.Is(err, service.ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestCreateApplication_EventNotActive(t *testing.T) {
+	events, users := newSeededClients()
+	events.events[10].IsActive = false
+	svc := service.NewApplicationService(newMockRepo(), events, users, newMockNotifier())
+
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
+		1, "creator",
+	)
+	if !errors.Is(err, service.ErrEventNotActive) {
+		t.Errorf("expected ErrEventNotActive, got %v", err)
+	}
+}
+
+func TestCreateApplication_CreatorSendsForeignEvent(t *testing.T) {
+	events, users := newSeededClients()
+	users.roles[3] = "creator"
+	svc := service.NewApplicationService(newMockRepo(), events, users, newMockNotifier())
+
+	// creator 3 подаёт заявку с мероприятием creator'а 1
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
+		3, "creator",
+	)
+	if !errors.Is(err, service.ErrEventOwnershipMismatch) {
+		t.Errorf("expected ErrEventOwnershipMismatch, got %v", err)
+	}
+}
+
+func TestCreateApplication_VenueInvitesEventOwner(t *testing.T) {
+	events, users := newSeededClients()
+	svc := service.NewApplicationService(newMockRepo(), events, users, newMockNotifier())
+
+	if _, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 1, ReceiverType: "creator", EventID: 10},
+		2, "venue",
+	); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	users.roles[3] = "creator"
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 3, ReceiverType: "creator", EventID: 10},
+		2, "venue",
+	)
+	if !errors.Is(err, service.ErrEventOwnershipMismatch) {
+		t.Errorf("expected ErrEventOwnershipMismatch for non-owner receiver, got %v", err)
+	}
+}
+
+func TestCreateApplication_ReceiverRoleMismatch(t *testing.T) {
+	events, users := newSeededClients()
+	users.roles[2] = "creator"
+	svc := service.NewApplicationService(newMockRepo(), events, users, newMockNotifier())
+
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
+		1, "creator",
+	)
+	if !errors.Is(err, service.ErrReceiverRoleMismatch) {
+		t.Errorf("expected ErrReceiverRoleMismatch, got %v", err)
+	}
+}
+
+func TestCreateApplication_ReceiverTypeMustBeCounterpart(t *testing.T) {
+	events, users := newSeededClients()
+	users.roles[3] = "creator"
+	users.roles[4] = "venue"
+	repo := newMockRepo()
+	svc := service.NewApplicationService(repo, events, users, newMockNotifier())
+
+	// creator 1 — владелец мероприятия, поэтому проверка владельца проходит
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 3, ReceiverType: "creator", EventID: 10},
+		1, "creator",
+	)
+	if !errors.Is(err, service.ErrInvalidReceiverType) {
+		t.Errorf("expected ErrInvalidReceiverType for creator to creator, got %v", err)
+	}
+
+	_, err = svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 4, ReceiverType: "venue", EventID: 10},
+		2, "venue",
+	)
+	if !errors.Is(err, service.ErrInvalidReceiverType) {
+		t.Errorf("expected ErrInvalidReceiverType for venue to venue, got %v", err)
+	}
+	if len(repo.applications) != 0 {
+		t.Error("expected no application to be stored")
+	}
+}
+
+func TestCreateApplication_DependencyUnavailable(t *testing.T) {
+	events, users := newSeededClients()
+	events.err = errors.New("connection refused")
+	repo := newMockRepo()
+	svc := service.NewApplicationService(repo, events, users, newMockNotifier())
+
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
+		1, "creator",
+	)
+	if !errors.Is(err, service.ErrDependencyUnavailable) {
+		t.Errorf("expected ErrDependencyUnavailable, got %v", err)
+	}
+	if len(repo.applications) != 0 {
+		t.Error("expected no application to be stored")
+	}
+}
+
 // ─── GetApplicationByID ───────────────────────────────────────────────────────
 
 func TestGetApplicationByID_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	app, err := svc.GetApplicationByID(1, 1)
 	if err != nil {
@@ -88,7 +219,7 @@ func TestGetApplicationByID_Success(t *testing.T) {
 func TestGetApplicationByID_AccessDenied(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.GetApplicationByID(1, 99)
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -98,7 +229,7 @@ func TestGetApplicationByID_AccessDenied(t *testing.T) {
 
 func TestGetApplicationByID_NotFound(t *testing.T) {
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.GetApplicationByID(999, 1)
 	if err == nil {
@@ -111,7 +242,7 @@ func TestGetApplicationByID_NotFound(t *testing.T) {
 func TestAcceptApplication_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	app, err := svc.AcceptApplication(1, 2)
 	if err != nil {
@@ -128,7 +259,7 @@ func TestAcceptApplication_Success(t *testing.T) {
 func TestAcceptApplication_AccessDenied(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.AcceptApplication(1, 99)
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -139,7 +270,7 @@ func TestAcceptApplication_AccessDenied(t *testing.T) {
 func TestAcceptApplication_AlreadyProcessed(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "accepted")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.AcceptApplication(1, 2)
 	if !errors.Is(err, service.ErrApplicationAlreadyProcessed) {
@@ -151,7 +282,7 @@ func TestAcceptApplication_CollaborationRoles(t *testing.T) {
 	// Venue отправил заявку creator'у — venue = sender
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 2, 1, 10, "venue", "creator", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.AcceptApplication(1, 1) // creator принимает
 	if err != nil {
@@ -168,7 +299,7 @@ func TestAcceptApplication_CollaborationRoles(t *testing.T) {
 func TestRejectApplication_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	app, err := svc.RejectApplication(1, 2)
 	if err != nil {
@@ -182,7 +313,7 @@ func TestRejectApplication_Success(t *testing.T) {
 func TestRejectApplication_AccessDenied(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.RejectApplication(1, 1) // sender пытается отклонить свою заявку
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -193,7 +324,7 @@ func TestRejectApplication_AccessDenied(t *testing.T) {
 func TestRejectApplication_AlreadyProcessed(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "rejected")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.RejectApplication(1, 2)
 	if !errors.Is(err, service.ErrApplicationAlreadyProcessed) {
@@ -206,7 +337,7 @@ func TestRejectApplication_AlreadyProcessed(t *testing.T) {
 func TestDeleteApplication_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	err := svc.DeleteApplication(1, 1)
 	if err != nil {
@@ -220,7 +351,7 @@ func TestDeleteApplication_Success(t *testing.T) {
 func TestDeleteApplication_OnlySenderCanDelete(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	err := svc.DeleteApplication(1, 2) // receiver пытается удалить
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -231,7 +362,7 @@ func TestDeleteApplication_OnlySenderCanDelete(t *testing.T) {
 func TestDeleteApplication_OnlyPendingCanBeDeleted(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "accepted")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	err := svc.DeleteApplication(1, 1)
 	if !errors.Is(err, service.ErrApplicationAlreadyProcessed) {
@@ -244,7 +375,7 @@ func TestDeleteApplication_OnlyPendingCanBeDeleted(t *testing.T) {
 func TestCompleteCollaboration_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	collab, err := svc.CompleteCollaboration(1, 1, "creator")
 	if err != nil {
@@ -258,7 +389,7 @@ func TestCompleteCollaboration_Success(t *testing.T) {
 func TestCompleteCollaboration_OnlyCreatorRole(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.CompleteCollaboration(1, 2, "venue")
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -269,7 +400,7 @@ func TestCompleteCollaboration_OnlyCreatorRole(t *testing.T) {
 func TestCompleteCollaboration_WrongCreator(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.CompleteCollaboration(1, 99, "creator")
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -280,7 +411,7 @@ func TestCompleteCollaboration_WrongCreator(t *testing.T) {
 func TestCompleteCollaboration_AlreadyProcessed(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.CompleteCollaboration(1, 1, "creator")
 	if !errors.Is(err, service.ErrCollaborationAlreadyProcessed) {
@@ -293,7 +424,7 @@ func TestCompleteCollaboration_AlreadyProcessed(t *testing.T) {
 func TestCancelCollaboration_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	err := svc.CancelCollaboration(1, 1, "creator")
 	if err != nil {
@@ -304,7 +435,7 @@ func TestCancelCollaboration_Success(t *testing.T) {
 func TestCancelCollaboration_OnlyCreatorRole(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	err := svc.CancelCollaboration(1, 2, "venue")
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -315,7 +446,7 @@ func TestCancelCollaboration_OnlyCreatorRole(t *testing.T) {
 func TestCancelCollaboration_AlreadyProcessed(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "cancelled")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	err := svc.CancelCollaboration(1, 1, "creator")
 	if !errors.Is(err, service.ErrCollaborationAlreadyProcessed) {
@@ -328,7 +459,7 @@ func TestCancelCollaboration_AlreadyProcessed(t *testing.T) {
 func TestGetCollaborationByID_Success(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	collab, err := svc.GetCollaborationByID(1, 1)
 	if err != nil {
@@ -342,7 +473,7 @@ func TestGetCollaborationByID_Success(t *testing.T) {
 func TestGetCollaborationByID_AccessDenied(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	_, err := svc.GetCollaborationByID(1, 99)
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -357,7 +488,7 @@ func TestGetCompletedEventIDsByUserID_AsCreator(t *testing.T) {
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
 	repo.collaborations[2] = newCollab(2, 2, 20, 3, 1, "completed") // user=1 как venue
 	repo.collaborations[3] = newCollab(3, 3, 30, 1, 2, "pending")   // не completed
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	ids, err := svc.GetCompletedEventIDsByUserID(1)
 	if err != nil {
@@ -370,7 +501,7 @@ func TestGetCompletedEventIDsByUserID_AsCreator(t *testing.T) {
 
 func TestGetCompletedEventIDsByUserID_Empty(t *testing.T) {
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	ids, err := svc.GetCompletedEventIDsByUserID(1)
 	if err != nil {
@@ -385,34 +516,71 @@ func TestGetCompletedEventIDsByUserID_Empty(t *testing.T) {
 
 func TestListCollaborations_LimitCapped(t *testing.T) {
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
-	_, err := svc.ListCollaborations(1, "", 0, 0)
+	page, err := svc.ListCollaborations(1, "", pagination.Params{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if page.Limit != 10 {
+		t.Errorf("expected default limit 10, got %d", page.Limit)
+	}
 
-	_, err = svc.ListCollaborations(1, "", 200, 0)
+	page, err = svc.ListCollaborations(1, "", pagination.Params{Limit: 200})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if page.Limit != 100 {
+		t.Errorf("expected limit capped at 100, got %d", page.Limit)
+	}
 }
 
 func TestListCollaborations_StatusFilter(t *testing.T) {
 	repo := newMockRepo()
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
 	repo.collaborations[2] = newCollab(2, 2, 20, 1, 3, "completed")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
-	results, err := svc.ListCollaborations(1, "completed", 10, 0)
+	results, err := svc.ListCollaborations(1, "completed", pagination.Params{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 1 {
-		t.Errorf("expected 1 completed collaboration, got %d", len(results))
+	if len(results.Items) != 1 {
+		t.Fatalf("expected 1 completed collaboration, got %d", len(results.Items))
 	}
-	if results[0].Status != "completed" {
-		t.Errorf("expected status completed, got %s", results[0].Status)
+	if results.Items[0].Status != "completed" {
+		t.Errorf("expected status completed, got %s", results.Items[0].Status)
+	}
+}
+
+func TestListCollaborations_NextCursor(t *testing.T) {
+	repo := newMockRepo()
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for id := 1; id <= 3; id++ {
+		c := newCollab(id, id, id*10, 1, 2, "pending")
+		c.CreatedAt = created.Add(time.Duration(id) * time.Hour)
+		repo.collaborations[id] = c
+	}
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
+
+	first, err := svc.ListCollaborations(1, "", pagination.Params{Limit: 2, WithTotal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != 3 || first.NextCursor == nil {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	if first.Total == nil || *first.Total != 3 {
+		t.Errorf("expected total 3, got %v", first.Total)
+	}
+
+	after, _ := pagination.Decode(*first.NextCursor)
+	second, err := svc.ListCollaborations(1, "", pagination.Params{Limit: 2, After: after})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != 1 || second.NextCursor != nil {
+		t.Errorf("unexpected last page: %+v", second)
 	}
 }
 
@@ -424,14 +592,14 @@ func TestListApplications_AsSender(t *testing.T) {
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
 	repo.applications[2] = newApp(2, 1, 3, 20, "creator", "venue", "accepted")
 	repo.applications[3] = newApp(3, 5, 1, 30, "venue", "creator", "pending") // receiver
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
-	results, err := svc.ListApplications(1, "creator", "", 10, 0)
+	results, err := svc.ListApplications(1, "creator", "", pagination.Params{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 3 {
-		t.Errorf("expected 3 applications (sender+receiver), got %d", len(results))
+	if len(results.Items) != 3 {
+		t.Errorf("expected 3 applications (sender+receiver), got %d", len(results.Items))
 	}
 }
 
@@ -439,30 +607,36 @@ func TestListApplications_StatusFilter(t *testing.T) {
 	repo := newMockRepo()
 	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
 	repo.applications[2] = newApp(2, 1, 3, 20, "creator", "venue", "accepted")
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
-	results, err := svc.ListApplications(1, "creator", "pending", 10, 0)
+	results, err := svc.ListApplications(1, "creator", "pending", pagination.Params{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(results) != 1 {
-		t.Errorf("expected 1 pending application, got %d", len(results))
+	if len(results.Items) != 1 {
+		t.Errorf("expected 1 pending application, got %d", len(results.Items))
 	}
 }
 
 func TestListApplications_LimitCapped(t *testing.T) {
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
-	_, err := svc.ListApplications(1, "creator", "", 0, 0)
+	page, err := svc.ListApplications(1, "creator", "", pagination.Params{})
 	if err != nil {
 		t.Fatalf("unexpected error for limit=0: %v", err)
 	}
+	if page.Limit != 10 {
+		t.Errorf("expected default limit 10, got %d", page.Limit)
+	}
 
-	_, err = svc.ListApplications(1, "creator", "", 500, 0)
+	page, err = svc.ListApplications(1, "creator", "", pagination.Params{Limit: 500})
 	if err != nil {
 		t.Fatalf("unexpected error for limit=500: %v", err)
 	}
+	if page.Limit != 100 {
+		t.Errorf("expected limit capped at 100, got %d", page.Limit)
+	}
 }
 
 // ─── ListCollaborationPartners ────────────────────────────────────────────────
@@ -473,7 +647,7 @@ func TestListCollaborationPartners_Success(t *testing.T) {
 	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
 	repo.collaborations[2] = newCollab(2, 2, 20, 3, 1, "completed") // user=1 is venue
 	repo.collaborations[3] = newCollab(3, 3, 30, 1, 4, "pending")   // pending — not a partner
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	partners, err := svc.ListCollaborationPartners(1)
 	if err != nil {
@@ -486,7 +660,7 @@ func TestListCollaborationPartners_Success(t *testing.T) {
 
 func TestListCollaborationPartners_Empty(t *testing.T) {
 	repo := newMockRepo()
-	svc := service.NewApplicationService(repo)
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), newMockNotifier())
 
 	partners, err := svc.ListCollaborationPartners(1)
 	if err != nil {
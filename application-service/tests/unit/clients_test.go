@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"application-service/internal/clients"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"shared/svcclient"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ─── Breaker ──────────────────────────────────────────────────────────────────
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b := svcclient.NewBreaker(2, time.Hour)
+
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("expected breaker to stay closed after one failure")
+	}
+	b.Failure()
+	if b.Allow() {
+		t.Error("expected breaker to open after threshold")
+	}
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b := svcclient.NewBreaker(1, 10*time.Millisecond)
+	b.Failure()
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.Allow() {
+		t.Fatal("expected probe request after cooldown")
+	}
+	if b.Allow() {
+		t.Error("expected only one probe while half-open")
+	}
+	b.Success()
+	if !b.Allow() {
+		t.Error("expected breaker to close after successful probe")
+	}
+}
+
+// ─── HTTP clients ─────────────────────────────────────────────────────────────
+
+func testOptions() clients.Options {
+	return clients.Options{
+		Timeout:          time.Second,
+		Retries:          2,
+		Backoff:          time.Millisecond,
+		FailureThreshold: 3,
+		Cooldown:         time.Hour,
+	}
+}
+
+func TestEventClient_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") == "" || r.Header.Get("X-User-Role") == "" {
+			t.Error("expected internal user context headers")
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"id":10,"creator_id":1,"is_active":true}`))
+	}))
+	defer srv.Close()
+
+	event, err := clients.NewEventClient(srv.URL, testOptions()).GetEvent(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if event.CreatorID != 1 || !event.IsActive {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestEventClient_NotFoundIsNotRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	_, err := clients.NewEventClient(srv.URL, testOptions()).GetEvent(context.Background(), 10)
+	if !errors.Is(err, clients.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestEventClient_CircuitOpens(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := clients.NewEventClient(srv.URL, testOptions())
+	client.GetEvent(context.Background(), 10) // 3 попытки — порог достигнут
+
+	_, err := client.GetEvent(context.Background(), 10)
+	if !errors.Is(err, clients.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls before circuit opened, got %d", calls)
+	}
+}
+
+func TestUserClient_HasRole(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/venues/2" {
+			w.Write([]byte(`{"user_id":2}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := clients.NewUserClient(srv.URL, testOptions())
+	if ok, err := client.HasRole(context.Background(), 2, "venue"); err != nil || !ok {
+		t.Errorf("expected venue role, got %v (err %v)", ok, err)
+	}
+	if ok, err := client.HasRole(context.Background(), 2, "creator"); err != nil || ok {
+		t.Errorf("expected no creator role, got %v (err %v)", ok, err)
+	}
+}
@@ -0,0 +1,223 @@
+package unit
+
+import (
+	"application-service/internal/service"
+	"errors"
+	"shared/pagination"
+	"testing"
+	"time"
+)
+
+// ─── OpenConversation ─────────────────────────────────────────────────────────
+
+func TestOpenApplicationConversation_CreatesOnce(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+
+	first, err := svc.OpenApplicationConversation(1, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := svc.OpenApplicationConversation(1, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.ID != second.ID {
+		t.Errorf("expected the same conversation, got %d and %d", first.ID, second.ID)
+	}
+	if first.SenderID != 1 || first.ReceiverID != 2 || first.EventID != 10 {
+		t.Errorf("unexpected conversation fields: %+v", first)
+	}
+}
+
+func TestOpenApplicationConversation_AccessDenied(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+
+	_, err := svc.OpenApplicationConversation(1, 99)
+	if !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied, got %v", err)
+	}
+}
+
+func TestOpenApplicationConversation_NotFound(t *testing.T) {
+	svc := service.NewMessageService(newMockMessageRepo(), newMockRepo(), newMockNotifier())
+
+	_, err := svc.OpenApplicationConversation(42, 1)
+	if !errors.Is(err, service.ErrApplicationNotFound) {
+		t.Errorf("expected ErrApplicationNotFound, got %v", err)
+	}
+}
+
+func TestOpenCollaborationConversation_ReusesApplicationThread(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "accepted")
+	repo.collaborations[5] = newCollab(5, 1, 10, 1, 2, "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+
+	appConv, _ := svc.OpenApplicationConversation(1, 1)
+	collabConv, err := svc.OpenCollaborationConversation(5, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if collabConv.ID != appConv.ID {
+		t.Errorf("expected collaboration to reuse application thread")
+	}
+	if collabConv.CollaborationID == nil || *collabConv.CollaborationID != 5 {
+		t.Errorf("expected collaboration_id 5, got %v", collabConv.CollaborationID)
+	}
+}
+
+// ─── Messages ─────────────────────────────────────────────────────────────────
+
+func TestSendMessage_NonParticipant(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+	conv, _ := svc.OpenApplicationConversation(1, 1)
+
+	_, err := svc.SendMessage(conv.ID, 99, &service.SendMessageRequest{Body: "hi"})
+	if !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied, got %v", err)
+	}
+}
+
+func TestSendMessage_ConversationNotFound(t *testing.T) {
+	svc := service.NewMessageService(newMockMessageRepo(), newMockRepo(), newMockNotifier())
+
+	_, err := svc.SendMessage(42, 1, &service.SendMessageRequest{Body: "hi"})
+	if !errors.Is(err, service.ErrConversationNotFound) {
+		t.Errorf("expected ErrConversationNotFound, got %v", err)
+	}
+}
+
+func TestListMessages_Pagination(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+	conv, _ := svc.OpenApplicationConversation(1, 1)
+
+	for i := 0; i < 5; i++ {
+		if _, err := svc.SendMessage(conv.ID, 1, &service.SendMessageRequest{Body: "msg"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	page, err := svc.ListMessages(conv.ID, 2, 0, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page.Messages) != 3 || page.Messages[0].ID != 5 {
+		t.Fatalf("unexpected first page: %+v", page.Messages)
+	}
+	if page.NextBeforeID == nil || *page.NextBeforeID != 3 {
+		t.Fatalf("expected next_before_id 3, got %v", page.NextBeforeID)
+	}
+
+	page, err = svc.ListMessages(conv.ID, 2, *page.NextBeforeID, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page.Messages) != 2 || page.NextBeforeID != nil {
+		t.Errorf("unexpected last page: %+v", page)
+	}
+}
+
+// ─── Read receipts ────────────────────────────────────────────────────────────
+
+func TestMarkRead_OnlyIncomingMessages(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+	conv, _ := svc.OpenApplicationConversation(1, 1)
+
+	svc.SendMessage(conv.ID, 1, &service.SendMessageRequest{Body: "привет"})
+	svc.SendMessage(conv.ID, 1, &service.SendMessageRequest{Body: "есть вопрос"})
+	svc.SendMessage(conv.ID, 2, &service.SendMessageRequest{Body: "слушаю"})
+
+	unread, _ := svc.CountUnread(2)
+	if unread.Unread != 2 {
+		t.Errorf("expected 2 unread for receiver, got %d", unread.Unread)
+	}
+
+	resp, err := svc.MarkRead(conv.ID, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Updated != 2 {
+		t.Errorf("expected 2 messages marked read, got %d", resp.Updated)
+	}
+
+	unread, _ = svc.CountUnread(2)
+	if unread.Unread != 0 {
+		t.Errorf("expected 0 unread after read, got %d", unread.Unread)
+	}
+	unread, _ = svc.CountUnread(1)
+	if unread.Unread != 1 {
+		t.Errorf("expected sender's incoming message to stay unread, got %d", unread.Unread)
+	}
+}
+
+func TestListConversations_UnreadAndLastMessage(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	svc := service.NewMessageService(newMockMessageRepo(), repo, newMockNotifier())
+	conv, _ := svc.OpenApplicationConversation(1, 1)
+
+	svc.SendMessage(conv.ID, 1, &service.SendMessageRequest{Body: "первое"})
+	svc.SendMessage(conv.ID, 1, &service.SendMessageRequest{Body: "второе"})
+
+	page, err := svc.ListConversations(2, pagination.Params{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	convs := page.Items
+	if len(convs) != 1 {
+		t.Fatalf("expected 1 conversation, got %d", len(convs))
+	}
+	if convs[0].UnreadCount != 2 {
+		t.Errorf("expected unread_count 2, got %d", convs[0].UnreadCount)
+	}
+	if convs[0].LastMessage == nil || convs[0].LastMessage.Body != "второе" {
+		t.Errorf("unexpected last message: %+v", convs[0].LastMessage)
+	}
+}
+
+func TestListConversations_NextCursor(t *testing.T) {
+	repo := newMockRepo()
+	msgRepo := newMockMessageRepo()
+	svc := service.NewMessageService(msgRepo, repo, newMockNotifier())
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for id := 1; id <= 3; id++ {
+		repo.applications[id] = newApp(id, 1, id+1, id*10, "creator", "venue", "pending")
+		conv, err := svc.OpenApplicationConversation(id, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		msgRepo.conversations[conv.ID].CreatedAt = created.Add(time.Duration(id) * time.Hour)
+	}
+	svc.SendMessage(1, 2, &service.SendMessageRequest{Body: "ответ"})
+
+	first, err := svc.ListConversations(1, pagination.Params{Limit: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != 3 || first.NextCursor == nil {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+
+	after, _ := pagination.Decode(*first.NextCursor)
+	second, err := svc.ListConversations(1, pagination.Params{Limit: 2, After: after})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != 1 || second.NextCursor != nil {
+		t.Fatalf("unexpected last page: %+v", second)
+	}
+	// Последнее сообщение и непрочитанные считаются только для отданной страницы
+	if second.Items[0].UnreadCount != 1 || second.Items[0].LastMessage == nil {
+		t.Errorf("expected unread reply in the last conversation, got %+v", second.Items[0])
+	}
+}
@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"application-service/internal/clients"
+	"context"
+)
+
+// mockEventClient отдаёт мероприятия из памяти
+type mockEventClient struct {
+	events map[int]*clients.Event
+	err    error
+}
+
+func newMockEventClient() *mockEventClient {
+	return &mockEventClient{events: make(map[int]*clients.Event)}
+}
+
+func (m *mockEventClient) GetEvent(ctx context.Context, id int) (*clients.Event, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	e, ok := m.events[id]
+	if !ok {
+		return nil, clients.ErrNotFound
+	}
+	cp := *e
+	return &cp, nil
+}
+
+// mockUserClient хранит роль каждого пользователя
+type mockUserClient struct {
+	roles map[int]string
+	err   error
+}
+
+func newMockUserClient() *mockUserClient {
+	return &mockUserClient{roles: make(map[int]string)}
+}
+
+func (m *mockUserClient) HasRole(ctx context.Context, userID int, role string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	return m.roles[userID] == role, nil
+}
+
+// newSeededClients: creator 1 владеет активным мероприятием 10, пользователь 2 — venue
+func newSeededClients() (*mockEventClient, *mockUserClient) {
+	events := newMockEventClient()
+	events.events[10] = &clients.Event{ID: 10, CreatorID: 1, IsActive: true}
+	users := newMockUserClient()
+	users.roles[1] = "creator"
+	users.roles[2] = "venue"
+	return events, users
+}
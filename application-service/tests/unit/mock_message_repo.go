@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"application-service/internal/models"
+	"application-service/internal/repository"
+	"shared/pagination"
+	"sort"
+	"time"
+)
+
+// mockMessageRepo — ручной мок репозитория переписок для unit-тестов
+type mockMessageRepo struct {
+	conversations map[int]*models.Conversation
+	messages      map[int]*models.Message
+	nextConvID    int
+	nextMsgID     int
+}
+
+func newMockMessageRepo() *mockMessageRepo {
+	return &mockMessageRepo{
+		conversations: make(map[int]*models.Conversation),
+		messages:      make(map[int]*models.Message),
+		nextConvID:    1,
+		nextMsgID:     1,
+	}
+}
+
+func (m *mockMessageRepo) CreateConversation(conv *models.Conversation) error {
+	for _, c := range m.conversations {
+		if c.ApplicationID == conv.ApplicationID {
+			return repository.ErrConversationExists
+		}
+	}
+	conv.ID = m.nextConvID
+	m.nextConvID++
+	copy := *conv
+	m.conversations[conv.ID] = &copy
+	return nil
+}
+
+func (m *mockMessageRepo) GetConversationByID(id int) (*models.Conversation, error) {
+	c, ok := m.conversations[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	copy := *c
+	return &copy, nil
+}
+
+func (m *mockMessageRepo) GetConversationByApplicationID(applicationID int) (*models.Conversation, error) {
+	for _, c := range m.conversations {
+		if c.ApplicationID == applicationID {
+			copy := *c
+			return &copy, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (m *mockMessageRepo) UpdateConversation(conv *models.Conversation) error {
+	copy := *conv
+	m.conversations[conv.ID] = &copy
+	return nil
+}
+
+func (m *mockMessageRepo) ListConversations(userID int, page pagination.Params) ([]models.Conversation, *int64, error) {
+	var result []models.Conversation
+	for _, c := range m.conversations {
+		if c.HasParticipant(userID) {
+			result = append(result, *c)
+		}
+	}
+	items, total := paginate(result, page, func(c *models.Conversation) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+	return items, total, nil
+}
+
+func (m *mockMessageRepo) CreateMessage(msg *models.Message) error {
+	msg.ID = m.nextMsgID
+	m.nextMsgID++
+	msg.CreatedAt = time.Now()
+	copy := *msg
+	m.messages[msg.ID] = &copy
+	return nil
+}
+
+func (m *mockMessageRepo) ListMessages(conversationID int, beforeID int, limit int) ([]models.Message, error) {
+	var result []models.Message
+	for _, msg := range m.messages {
+		if msg.ConversationID == conversationID && (beforeID <= 0 || msg.ID < beforeID) {
+			result = append(result, *msg)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID > result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *mockMessageRepo) GetLastMessages(conversationIDs []int) (map[int]models.Message, error) {
+	result := make(map[int]models.Message)
+	for _, id := range conversationIDs {
+		for _, msg := range m.messages {
+			if msg.ConversationID == id && msg.ID > result[id].ID {
+				result[id] = *msg
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *mockMessageRepo) MarkMessagesRead(conversationID int, readerID int, readAt time.Time) (int64, error) {
+	var updated int64
+	for _, msg := range m.messages {
+		if msg.ConversationID == conversationID && msg.SenderID != readerID && msg.ReadAt == nil {
+			t := readAt
+			msg.ReadAt = &t
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (m *mockMessageRepo) CountUnreadByConversation(userID int, conversationIDs []int) (map[int]int64, error) {
+	result := make(map[int]int64)
+	for _, id := range conversationIDs {
+		for _, msg := range m.messages {
+			if msg.ConversationID == id && msg.SenderID != userID && msg.ReadAt == nil {
+				result[id]++
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *mockMessageRepo) CountUnread(userID int) (int64, error) {
+	var count int64
+	for _, msg := range m.messages {
+		conv := m.conversations[msg.ConversationID]
+		if conv != nil && conv.HasParticipant(userID) && msg.SenderID != userID && msg.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
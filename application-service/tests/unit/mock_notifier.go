@@ -0,0 +1,21 @@
+package unit
+
+// sentEvent — событие, отправленное через mockNotifier
+type sentEvent struct {
+	userID    int
+	eventType string
+	data      interface{}
+}
+
+// mockNotifier запоминает отправленные realtime-события
+type mockNotifier struct {
+	events []sentEvent
+}
+
+func newMockNotifier() *mockNotifier {
+	return &mockNotifier{}
+}
+
+func (m *mockNotifier) Notify(userID int, eventType string, data interface{}) {
+	m.events = append(m.events, sentEvent{userID: userID, eventType: eventType, data: data})
+}
@@ -3,6 +3,8 @@ package unit
 import (
 	"application-service/internal/models"
 	"errors"
+	"shared/pagination"
+	"sort"
 )
 
 var errNotFound = errors.New("not found")
@@ -68,7 +70,7 @@ func (m *mockRepo) GetApplicationByID(id int) (*models.Application, error) {
 	return &copy, nil
 }
 
-func (m *mockRepo) ListApplications(userID int, role string, status string, limit, offset int) ([]models.Application, error) {
+func (m *mockRepo) ListApplications(userID int, role string, status string, page pagination.Params) ([]models.Application, *int64, error) {
 	var result []models.Application
 	for _, app := range m.applications {
 		if app.SenderID == userID || app.ReceiverID == userID {
@@ -77,7 +79,10 @@ func (m *mockRepo) ListApplications(userID int, role string, status string, limi
 			}
 		}
 	}
-	return result, nil
+	items, total := paginate(result, page, func(a *models.Application) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: a.CreatedAt, ID: a.ID}
+	})
+	return items, total, nil
 }
 
 func (m *mockRepo) UpdateApplication(app *models.Application) error {
@@ -101,11 +106,11 @@ func (m *mockRepo) AcceptApplicationTx(app *models.Application, collab *models.C
 	return nil
 }
 
-func (m *mockRepo) CompleteCollaborationTx(collaborationID int, eventID int) error {
+func (m *mockRepo) CompleteCollaborationTx(collab *models.Collaboration) error {
 	if m.errCompleteTx != nil {
 		return m.errCompleteTx
 	}
-	if c, ok := m.collaborations[collaborationID]; ok {
+	if c, ok := m.collaborations[collab.ID]; ok {
 		c.Status = "completed"
 	}
 	return nil
@@ -151,7 +156,7 @@ func (m *mockRepo) ListCollaborationPartners(userID int) ([]int, error) {
 	return result, nil
 }
 
-func (m *mockRepo) ListCollaborations(userID int, status string, limit, offset int) ([]models.Collaboration, error) {
+func (m *mockRepo) ListCollaborations(userID int, status string, page pagination.Params) ([]models.Collaboration, *int64, error) {
 	var result []models.Collaboration
 	for _, c := range m.collaborations {
 		if c.CreatorUserID == userID || c.VenueUserID == userID {
@@ -160,7 +165,10 @@ func (m *mockRepo) ListCollaborations(userID int, status string, limit, offset i
 			}
 		}
 	}
-	return result, nil
+	items, total := paginate(result, page, func(c *models.Collaboration) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+	return items, total, nil
 }
 
 func (m *mockRepo) GetCompletedEventIDsByUserID(userID int) ([]int, error) {
@@ -200,3 +208,29 @@ func newCollab(id, appID, eventID, creatorID, venueID int, status string) *model
 		Status:        status,
 	}
 }
+
+// paginate повторяет pagination.Apply и pagination.Count для данных в памяти:
+// порядок (created_at DESC, id DESC), элементы после курсора, limit+1
+func paginate[T any](items []T, page pagination.Params, cursorOf func(*T) pagination.Cursor) ([]T, *int64) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := cursorOf(&items[i]), cursorOf(&items[j])
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	var result []T
+	for i := range items {
+		c := cursorOf(&items[i])
+		if page.Includes(c.CreatedAt, c.ID) && len(result) <= page.Limit {
+			result = append(result, items[i])
+		}
+	}
+
+	if !page.WithTotal {
+		return result, nil
+	}
+	total := int64(len(items))
+	return result, &total
+}
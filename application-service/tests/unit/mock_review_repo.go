@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"application-service/internal/models"
+	"application-service/internal/repository"
+	"math"
+	"shared/pagination"
+)
+
+// mockReviewRepo — ручной мок репозитория отзывов для unit-тестов
+type mockReviewRepo struct {
+	reviews map[int]*models.Review
+	nextID  int
+}
+
+func newMockReviewRepo() *mockReviewRepo {
+	return &mockReviewRepo{
+		reviews: make(map[int]*models.Review),
+		nextID:  1,
+	}
+}
+
+func (m *mockReviewRepo) CreateReview(review *models.Review) error {
+	for _, r := range m.reviews {
+		if r.CollaborationID == review.CollaborationID && r.AuthorID == review.AuthorID {
+			return repository.ErrDuplicateReview
+		}
+	}
+	review.ID = m.nextID
+	m.nextID++
+	copy := *review
+	m.reviews[review.ID] = &copy
+	return nil
+}
+
+func (m *mockReviewRepo) GetReviewByID(id int) (*models.Review, error) {
+	r, ok := m.reviews[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	copy := *r
+	return &copy, nil
+}
+
+func (m *mockReviewRepo) UpdateReview(review *models.Review) error {
+	copy := *review
+	m.reviews[review.ID] = &copy
+	return nil
+}
+
+func (m *mockReviewRepo) ListReviewsByTarget(targetID int, includeHidden bool, page pagination.Params) ([]models.Review, *int64, error) {
+	var result []models.Review
+	for _, r := range m.reviews {
+		if r.TargetID == targetID && (includeHidden || !r.IsHidden) {
+			result = append(result, *r)
+		}
+	}
+	items, total := paginate(result, page, func(r *models.Review) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: r.CreatedAt, ID: r.ID}
+	})
+	return items, total, nil
+}
+
+func (m *mockReviewRepo) GetRatingSummary(targetID int) (*models.RatingSummary, error) {
+	sum, count := 0, 0
+	for _, r := range m.reviews {
+		if r.TargetID == targetID && !r.IsHidden {
+			sum += r.Rating
+			count++
+		}
+	}
+	summary := &models.RatingSummary{UserID: targetID, Count: count}
+	if count > 0 {
+		summary.Average = math.Round(float64(sum)/float64(count)*100) / 100
+	}
+	return summary, nil
+}
+
+func (m *mockReviewRepo) GetRatingSummaries(targetIDs []int) ([]models.RatingSummary, error) {
+	var result []models.RatingSummary
+	for _, id := range targetIDs {
+		if summary, _ := m.GetRatingSummary(id); summary.Count > 0 {
+			result = append(result, *summary)
+		}
+	}
+	return result, nil
+}
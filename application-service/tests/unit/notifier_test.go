@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"application-service/internal/notifier"
+	"application-service/internal/service"
+	"testing"
+)
+
+// ─── Realtime notifications ───────────────────────────────────────────────────
+
+func TestCreateApplication_NotifiesReceiver(t *testing.T) {
+	events, users := newSeededClients()
+	n := newMockNotifier()
+	svc := service.NewApplicationService(newMockRepo(), events, users, n)
+
+	_, err := svc.CreateApplication(
+		&service.CreateApplicationRequest{ReceiverID: 2, ReceiverType: "venue", EventID: 10},
+		1, "creator",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(n.events) != 1 || n.events[0].userID != 2 || n.events[0].eventType != notifier.EventApplicationCreated {
+		t.Errorf("unexpected events: %+v", n.events)
+	}
+}
+
+func TestAcceptApplication_NotifiesSender(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	n := newMockNotifier()
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), n)
+
+	if _, err := svc.AcceptApplication(1, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(n.events) != 1 || n.events[0].userID != 1 || n.events[0].eventType != notifier.EventApplicationStatusChanged {
+		t.Errorf("unexpected events: %+v", n.events)
+	}
+}
+
+func TestAcceptApplication_NoNotificationOnFailure(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	repo.errAcceptTx = errNotFound
+	n := newMockNotifier()
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), n)
+
+	if _, err := svc.AcceptApplication(1, 2); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(n.events) != 0 {
+		t.Errorf("expected no events, got %+v", n.events)
+	}
+}
+
+func TestCancelCollaboration_NotifiesVenue(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
+	n := newMockNotifier()
+	svc := service.NewApplicationService(repo, newMockEventClient(), newMockUserClient(), n)
+
+	if err := svc.CancelCollaboration(1, 1, "creator"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(n.events) != 1 || n.events[0].userID != 2 || n.events[0].eventType != notifier.EventCollaborationStatusChanged {
+		t.Errorf("unexpected events: %+v", n.events)
+	}
+}
+
+func TestSendMessage_NotifiesCounterpart(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	n := newMockNotifier()
+	svc := service.NewMessageService(newMockMessageRepo(), repo, n)
+	conv, _ := svc.OpenApplicationConversation(1, 1)
+
+	svc.SendMessage(conv.ID, 2, &service.SendMessageRequest{Body: "добрый день"})
+
+	if len(n.events) != 1 || n.events[0].userID != 1 || n.events[0].eventType != notifier.EventMessageCreated {
+		t.Errorf("unexpected events: %+v", n.events)
+	}
+}
+
+func TestMarkRead_NotifiesOnlyWhenSomethingRead(t *testing.T) {
+	repo := newMockRepo()
+	repo.applications[1] = newApp(1, 1, 2, 10, "creator", "venue", "pending")
+	n := newMockNotifier()
+	svc := service.NewMessageService(newMockMessageRepo(), repo, n)
+	conv, _ := svc.OpenApplicationConversation(1, 1)
+
+	svc.MarkRead(conv.ID, 2)
+	if len(n.events) != 0 {
+		t.Fatalf("expected no events for empty read, got %+v", n.events)
+	}
+
+	svc.SendMessage(conv.ID, 1, &service.SendMessageRequest{Body: "привет"})
+	svc.MarkRead(conv.ID, 2)
+
+	last := n.events[len(n.events)-1]
+	if last.userID != 1 || last.eventType != notifier.EventMessagesRead {
+		t.Errorf("unexpected read receipt event: %+v", last)
+	}
+}
+
+func TestCreateReview_NotifiesTarget(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	n := newMockNotifier()
+	svc := service.NewReviewService(newMockReviewRepo(), repo, n)
+
+	svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 5})
+
+	if len(n.events) != 1 || n.events[0].userID != 2 || n.events[0].eventType != notifier.EventNotification {
+		t.Errorf("unexpected events: %+v", n.events)
+	}
+}
@@ -0,0 +1,234 @@
+package unit
+
+import (
+	"application-service/internal/models"
+	"application-service/internal/service"
+	"errors"
+	"shared/pagination"
+	"testing"
+	"time"
+)
+
+// ─── CreateReview ─────────────────────────────────────────────────────────────
+
+func TestCreateReview_CreatorReviewsVenue(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	svc := service.NewReviewService(newMockReviewRepo(), repo, newMockNotifier())
+
+	review, err := svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 5, Text: "Отличная площадка"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if review.AuthorType != "creator" || review.TargetID != 2 || review.TargetType != "venue" {
+		t.Errorf("unexpected review sides: %+v", review)
+	}
+	if review.EventID != 10 {
+		t.Errorf("expected event_id 10, got %d", review.EventID)
+	}
+}
+
+func TestCreateReview_VenueReviewsCreator(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	svc := service.NewReviewService(newMockReviewRepo(), repo, newMockNotifier())
+
+	review, err := svc.CreateReview(1, 2, &service.CreateReviewRequest{Rating: 4})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if review.AuthorType != "venue" || review.TargetID != 1 || review.TargetType != "creator" {
+		t.Errorf("unexpected review sides: %+v", review)
+	}
+}
+
+func TestCreateReview_NotParticipant(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	svc := service.NewReviewService(newMockReviewRepo(), repo, newMockNotifier())
+
+	_, err := svc.CreateReview(1, 99, &service.CreateReviewRequest{Rating: 3})
+	if !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied, got %v", err)
+	}
+}
+
+func TestCreateReview_NotCompleted(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "pending")
+	svc := service.NewReviewService(newMockReviewRepo(), repo, newMockNotifier())
+
+	_, err := svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 3})
+	if !errors.Is(err, service.ErrCollaborationNotCompleted) {
+		t.Errorf("expected ErrCollaborationNotCompleted, got %v", err)
+	}
+}
+
+func TestCreateReview_CollaborationNotFound(t *testing.T) {
+	svc := service.NewReviewService(newMockReviewRepo(), newMockRepo(), newMockNotifier())
+
+	_, err := svc.CreateReview(42, 1, &service.CreateReviewRequest{Rating: 3})
+	if !errors.Is(err, service.ErrCollaborationNotFound) {
+		t.Errorf("expected ErrCollaborationNotFound, got %v", err)
+	}
+}
+
+func TestCreateReview_Duplicate(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	svc := service.NewReviewService(newMockReviewRepo(), repo, newMockNotifier())
+
+	if _, err := svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 5}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err := svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 1})
+	if !errors.Is(err, service.ErrDuplicateReview) {
+		t.Errorf("expected ErrDuplicateReview, got %v", err)
+	}
+}
+
+// ─── ListUserReviews ──────────────────────────────────────────────────────────
+
+func TestListUserReviews_SummaryExcludesHidden(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	repo.collaborations[2] = newCollab(2, 2, 11, 3, 2, "completed")
+	repo.collaborations[3] = newCollab(3, 3, 12, 4, 2, "completed")
+	reviewRepo := newMockReviewRepo()
+	svc := service.NewReviewService(reviewRepo, repo, newMockNotifier())
+
+	svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 5})
+	svc.CreateReview(2, 3, &service.CreateReviewRequest{Rating: 4})
+	hidden, _ := svc.CreateReview(3, 4, &service.CreateReviewRequest{Rating: 1})
+	if _, err := svc.ModerateReview(hidden.ID, &service.ModerateReviewRequest{IsHidden: true, Reason: "spam"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	resp, err := svc.ListUserReviews(2, pagination.Params{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.Items) != 2 {
+		t.Errorf("expected 2 visible reviews, got %d", len(resp.Items))
+	}
+	if resp.Summary.Count != 2 || resp.Summary.Average != 4.5 {
+		t.Errorf("unexpected summary: %+v", resp.Summary)
+	}
+	if resp.Limit != 10 {
+		t.Errorf("expected default limit 10, got %d", resp.Limit)
+	}
+}
+
+func TestListUserReviews_Empty(t *testing.T) {
+	svc := service.NewReviewService(newMockReviewRepo(), newMockRepo(), newMockNotifier())
+
+	resp, err := svc.ListUserReviews(2, pagination.Params{Limit: 500})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Items == nil || len(resp.Items) != 0 {
+		t.Errorf("expected empty non-nil slice, got %v", resp.Items)
+	}
+	if resp.Limit != 100 {
+		t.Errorf("expected limit capped at 100, got %d", resp.Limit)
+	}
+}
+
+func TestListUserReviews_NextCursor(t *testing.T) {
+	repo := newMockRepo()
+	reviewRepo := newMockReviewRepo()
+	svc := service.NewReviewService(reviewRepo, repo, newMockNotifier())
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for id := 1; id <= 3; id++ {
+		repo.collaborations[id] = newCollab(id, id, id*10, id+10, 2, "completed")
+		review, err := svc.CreateReview(id, id+10, &service.CreateReviewRequest{Rating: 5})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		reviewRepo.reviews[review.ID].CreatedAt = created.Add(time.Duration(id) * time.Hour)
+	}
+
+	first, err := svc.ListUserReviews(2, pagination.Params{Limit: 2, WithTotal: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != 3 || first.NextCursor == nil {
+		t.Fatalf("unexpected first page: %+v", first.Page)
+	}
+	if first.Total == nil || *first.Total != 3 || first.Summary.Count != 3 {
+		t.Errorf("expected total and summary over all 3 reviews, got %v and %+v", first.Total, first.Summary)
+	}
+
+	after, _ := pagination.Decode(*first.NextCursor)
+	second, err := svc.ListUserReviews(2, pagination.Params{Limit: 2, After: after})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != 1 || second.NextCursor != nil {
+		t.Errorf("unexpected last page: %+v", second.Page)
+	}
+}
+
+// ─── GetRatingSummaries ───────────────────────────────────────────────────────
+
+func TestGetRatingSummaries_ZeroForUsersWithoutReviews(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	repo.collaborations[2] = newCollab(2, 2, 11, 3, 2, "completed")
+	repo.collaborations[3] = newCollab(3, 3, 12, 4, 5, "completed")
+	reviewRepo := newMockReviewRepo()
+	svc := service.NewReviewService(reviewRepo, repo, newMockNotifier())
+
+	svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 5})
+	svc.CreateReview(2, 3, &service.CreateReviewRequest{Rating: 2})
+	hidden, _ := svc.CreateReview(3, 4, &service.CreateReviewRequest{Rating: 1})
+	if _, err := svc.ModerateReview(hidden.ID, &service.ModerateReviewRequest{IsHidden: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	summaries, err := svc.GetRatingSummaries([]int{7, 2, 5, 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []models.RatingSummary{
+		{UserID: 7},
+		{UserID: 2, Average: 3.5, Count: 2},
+		{UserID: 5},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("expected %d summaries, got %+v", len(want), summaries)
+	}
+	for i := range want {
+		if summaries[i] != want[i] {
+			t.Errorf("summary %d: expected %+v, got %+v", i, want[i], summaries[i])
+		}
+	}
+}
+
+// ─── ModerateReview ───────────────────────────────────────────────────────────
+
+func TestModerateReview_RestoreClearsReason(t *testing.T) {
+	repo := newMockRepo()
+	repo.collaborations[1] = newCollab(1, 1, 10, 1, 2, "completed")
+	svc := service.NewReviewService(newMockReviewRepo(), repo, newMockNotifier())
+
+	review, _ := svc.CreateReview(1, 1, &service.CreateReviewRequest{Rating: 2})
+	svc.ModerateReview(review.ID, &service.ModerateReviewRequest{IsHidden: true, Reason: "оскорбления"})
+
+	restored, err := svc.ModerateReview(review.ID, &service.ModerateReviewRequest{IsHidden: false, Reason: "ignored"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if restored.IsHidden || restored.ModerationReason != "" {
+		t.Errorf("expected visible review without reason, got %+v", restored)
+	}
+}
+
+func TestModerateReview_NotFound(t *testing.T) {
+	svc := service.NewReviewService(newMockReviewRepo(), newMockRepo(), newMockNotifier())
+
+	_, err := svc.ModerateReview(42, &service.ModerateReviewRequest{IsHidden: true})
+	if !errors.Is(err, service.ErrReviewNotFound) {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+}
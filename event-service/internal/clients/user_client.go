@@ -0,0 +1,44 @@
+package clients
+
+import (
+	"context"
+	"net/url"
+	"shared/svcclient"
+	"strings"
+)
+
+// Image — сведения об изображении из user-service, нужные для ссылок мероприятия
+type Image struct {
+	ID          string `json:"id"`
+	OwnerUserID *int   `json:"owner_user_id"`
+	FileName    string `json:"file_name"`
+	FileType    string `json:"file_type"`
+}
+
+type UserClient interface {
+	// GetImages возвращает найденные изображения из ids; отклоненные при
+	// проверке и отсутствующие в ответ не попадают
+	GetImages(ctx context.Context, ids []string) ([]Image, error)
+}
+
+type HTTPUserClient struct {
+	c *svcclient.Client
+}
+
+func NewUserClient(baseURL string, opts svcclient.Options) *HTTPUserClient {
+	return &HTTPUserClient{c: svcclient.New("user-service", baseURL, opts)}
+}
+
+func (u *HTTPUserClient) GetImages(ctx context.Context, ids []string) ([]Image, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var resp struct {
+		Images []Image `json:"images"`
+	}
+	path := "/internal/images?ids=" + url.QueryEscape(strings.Join(ids, ","))
+	if err := u.c.GetJSON(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Images, nil
+}
@@ -6,6 +6,9 @@ type Config struct {
 	Port        string
 	GinMode     string
 	DatabaseDSN string
+	RedisURL    string
+
+	UserServiceURL string
 }
 
 func Load() *Config {
@@ -13,6 +16,9 @@ func Load() *Config {
 		Port:        getEnv("PORT", "8082"),
 		GinMode:     getEnv("GIN_MODE", "release"),
 		DatabaseDSN: getEnv("DB_DSN", ""),
+		RedisURL:    getEnv("REDIS_URL", "redis:6379"),
+
+		UserServiceURL: getEnv("USER_SERVICE_URL", "http://user-service:8081"),
 	}
 }
 
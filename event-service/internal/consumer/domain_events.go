@@ -0,0 +1,49 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"event-service/internal/repository"
+	"log"
+	"shared/eventbus"
+)
+
+// Group — consumer group event-service в шине доменных событий
+const Group = "event-service"
+
+// DomainEventConsumer применяет к мероприятиям события других сервисов.
+// Обработка идемпотентна: повторная доставка приводит к тому же состоянию.
+type DomainEventConsumer struct {
+	repo repository.EventRepositoryInterface
+}
+
+func NewDomainEventConsumer(repo repository.EventRepositoryInterface) *DomainEventConsumer {
+	return &DomainEventConsumer{repo: repo}
+}
+
+func (c *DomainEventConsumer) Handle(ctx context.Context, event eventbus.Event) error {
+	switch event.Type {
+	case eventbus.ApplicationAccepted:
+		var p eventbus.ApplicationAcceptedPayload
+		if err := json.Unmarshal(event.Payload, &p); err != nil {
+			return skipMalformed(event, err)
+		}
+		// Мероприятие нашло площадку — убираем его из каталога
+		return c.repo.DeactivateEvent(p.EventID)
+
+	case eventbus.CollaborationCompleted:
+		var p eventbus.CollaborationCompletedPayload
+		if err := json.Unmarshal(event.Payload, &p); err != nil {
+			return skipMalformed(event, err)
+		}
+		return c.repo.MarkEventCompleted(p.EventID)
+	}
+	return nil
+}
+
+// skipMalformed логирует событие с битым payload и подтверждает его:
+// повторная доставка его не исправит
+func skipMalformed(event eventbus.Event, err error) error {
+	log.Printf("consumer: skipping malformed %s (%s): %v", event.Type, event.ID, err)
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"event-service/internal/apperror"
 	"event-service/internal/service"
 	"net/http"
+	"shared/pagination"
 	"strconv"
 	"strings"
 
@@ -29,7 +30,10 @@ func NewEventHandler(eventService *service.EventService) *EventHandler {
 // @Success 201 {object} models.Event
 // @Failure 400 {object} apperror.ErrorResponse
 // @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 422 {object} apperror.ErrorResponse
 // @Failure 500 {object} apperror.ErrorResponse
+// @Failure 503 {object} apperror.ErrorResponse
 // @Security BearerAuth
 // @Router /events [post]
 func (h *EventHandler) CreateEvent(c *gin.Context) {
@@ -51,6 +55,9 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 
 	event, err := h.eventService.CreateEvent(&req, creatorID.(int))
 	if err != nil {
+		if respondImageReferenceError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to create event"))
 		return
 	}
@@ -67,6 +74,7 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 // @Success 200 {object} models.Event
 // @Failure 400 {object} apperror.ErrorResponse
 // @Failure 404 {object} apperror.ErrorResponse
+// @Failure 500 {object} apperror.ErrorResponse
 // @Security BearerAuth
 // @Router /events/{id} [get]
 func (h *EventHandler) GetEvent(c *gin.Context) {
@@ -78,7 +86,11 @@ func (h *EventHandler) GetEvent(c *gin.Context) {
 
 	event, err := h.eventService.GetEventByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("EVENT_NOT_FOUND", "Event not found"))
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("EVENT_NOT_FOUND", "Event not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get event"))
 		return
 	}
 
@@ -95,8 +107,10 @@ func (h *EventHandler) GetEvent(c *gin.Context) {
 // @Param is_active query bool false "Filter by is_active (default: true)"
 // @Param is_completed query bool false "Filter by is_completed"
 // @Param limit query int false "Количество элементов (по умолчанию 20, максимум 100)"
-// @Param offset query int false "Смещение (по умолчанию 0)"
-// @Success 200 {object} map[string]interface{}
+// @Param cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param include_total query bool false "Посчитать общее количество (total)"
+// @Success 200 {object} pagination.Page[models.Event]
+// @Failure 400 {object} apperror.ErrorResponse
 // @Failure 500 {object} apperror.ErrorResponse
 // @Security BearerAuth
 // @Router /events [get]
@@ -133,20 +147,19 @@ func (h *EventHandler) ListEvents(c *gin.Context) {
 		isActive = &val
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultEventsLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	events, err := h.eventService.ListEvents(creatorID, categoryID, isActive, isCompleted, limit, offset)
+	events, err := h.eventService.ListEvents(creatorID, categoryID, isActive, isCompleted, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch events"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"events": events,
-		"limit":  limit,
-		"offset": offset,
-	})
+	c.JSON(http.StatusOK, events)
 }
 
 // GetEventsByIDs получает мероприятия по списку ID
@@ -210,6 +223,8 @@ func splitAndTrim(s string) []string {
 // @Failure 401 {object} apperror.ErrorResponse
 // @Failure 403 {object} apperror.ErrorResponse
 // @Failure 404 {object} apperror.ErrorResponse
+// @Failure 422 {object} apperror.ErrorResponse
+// @Failure 503 {object} apperror.ErrorResponse
 // @Security BearerAuth
 // @Router /events/{id} [put]
 func (h *EventHandler) UpdateEvent(c *gin.Context) {
@@ -237,6 +252,9 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 
 	event, err := h.eventService.UpdateEvent(id, &req, creatorID.(int))
 	if err != nil {
+		if respondImageReferenceError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrAccessDenied) {
 			c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You are not the creator of this event"))
 			return
@@ -333,3 +351,20 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// respondImageReferenceError отвечает на ссылку мероприятия на чужое или
+// несуществующее изображение и на недоступность user-service, который их проверяет;
+// false — ошибка другого рода
+func respondImageReferenceError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrImageNotOwned):
+		c.JSON(http.StatusForbidden, apperror.One("IMAGE_NOT_OWNED", "You can only use images you uploaded"))
+	case errors.Is(err, service.ErrImageNotFound):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("IMAGE_NOT_FOUND", "Referenced image not found"))
+	case errors.Is(err, service.ErrDependencyUnavailable):
+		c.JSON(http.StatusServiceUnavailable, apperror.One("DEPENDENCY_UNAVAILABLE", "Cannot check images right now, try again later"))
+	default:
+		return false
+	}
+	return true
+}
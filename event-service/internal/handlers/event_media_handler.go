@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"event-service/internal/apperror"
+	"event-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddEventMedia прикрепляет файлы к мероприятию
+// @Summary Add event media
+// @Description Attach uploaded files to an event (creator only): gallery photos, a poster and rider/tech spec PDFs. All items are added or none. Returns all event media in order.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param media body service.AddEventMediaRequest true "Media items"
+// @Success 201 {array} models.EventMedia
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Failure 409 {object} apperror.ErrorResponse
+// @Failure 422 {object} apperror.ErrorResponse
+// @Failure 503 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /events/{id}/media [post]
+func (h *EventHandler) AddEventMedia(c *gin.Context) {
+	eventID, creatorID, ok := mediaRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req service.AddEventMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	media, err := h.eventService.AddEventMedia(eventID, creatorID, req.Items)
+	if err != nil {
+		if respondMediaError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to add event media"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// ReorderEventMedia задает порядок медиа мероприятия
+// @Summary Reorder event media
+// @Description Set the order of event media (creator only). media_ids must list every media item of the event exactly once.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param order body service.ReorderEventMediaRequest true "Media IDs in the new order"
+// @Success 200 {array} models.EventMedia
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Failure 422 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /events/{id}/media/order [put]
+func (h *EventHandler) ReorderEventMedia(c *gin.Context) {
+	eventID, creatorID, ok := mediaRequestContext(c)
+	if !ok {
+		return
+	}
+
+	var req service.ReorderEventMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	media, err := h.eventService.ReorderEventMedia(eventID, creatorID, req.MediaIDs)
+	if err != nil {
+		if respondMediaError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to reorder event media"))
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// UpdateEventMedia меняет подпись медиа
+// @Summary Update event media
+// @Description Update the caption of an event media item (creator only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param media_id path int true "Media ID"
+// @Param media body service.UpdateEventMediaRequest true "Media data"
+// @Success 200 {object} models.EventMedia
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /events/{id}/media/{media_id} [patch]
+func (h *EventHandler) UpdateEventMedia(c *gin.Context) {
+	eventID, creatorID, ok := mediaRequestContext(c)
+	if !ok {
+		return
+	}
+	mediaID, err := strconv.Atoi(c.Param("media_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid media ID"))
+		return
+	}
+
+	var req service.UpdateEventMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	media, err := h.eventService.UpdateEventMedia(eventID, mediaID, creatorID, &req)
+	if err != nil {
+		if respondMediaError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to update event media"))
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// DeleteEventMedia открепляет файл от мероприятия
+// @Summary Delete event media
+// @Description Remove a media item from an event (creator only). Remaining items are shifted to close the gap.
+// @Tags events
+// @Param id path int true "Event ID"
+// @Param media_id path int true "Media ID"
+// @Success 204
+// @Failure 400 {object} apperror.ErrorResponse
+// @Failure 401 {object} apperror.ErrorResponse
+// @Failure 403 {object} apperror.ErrorResponse
+// @Failure 404 {object} apperror.ErrorResponse
+// @Security BearerAuth
+// @Router /events/{id}/media/{media_id} [delete]
+func (h *EventHandler) DeleteEventMedia(c *gin.Context) {
+	eventID, creatorID, ok := mediaRequestContext(c)
+	if !ok {
+		return
+	}
+	mediaID, err := strconv.Atoi(c.Param("media_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid media ID"))
+		return
+	}
+
+	if err := h.eventService.DeleteEventMedia(eventID, mediaID, creatorID); err != nil {
+		if respondMediaError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to delete event media"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// mediaRequestContext разбирает ID мероприятия и пользователя; при ошибке ответ уже отправлен
+func mediaRequestContext(c *gin.Context) (eventID, creatorID int, ok bool) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid event ID"))
+		return 0, 0, false
+	}
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return 0, 0, false
+	}
+	return eventID, userID.(int), true
+}
+
+// respondMediaError отвечает на ошибки работы с медиа мероприятия; false — ошибка другого рода
+func respondMediaError(c *gin.Context, err error) bool {
+	if respondImageReferenceError(c, err) {
+		return true
+	}
+	switch {
+	case errors.Is(err, service.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("EVENT_NOT_FOUND", "Event not found"))
+	case errors.Is(err, service.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You are not the creator of this event"))
+	case errors.Is(err, service.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("MEDIA_NOT_FOUND", "Event media not found"))
+	case errors.Is(err, service.ErrInvalidMediaFile):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("INVALID_MEDIA_FILE", "Photos and posters must be images, riders must be PDF documents"))
+	case errors.Is(err, service.ErrDuplicateMedia):
+		c.JSON(http.StatusConflict, apperror.One("DUPLICATE_MEDIA", "File is already attached to the event"))
+	case errors.Is(err, service.ErrPosterExists):
+		c.JSON(http.StatusConflict, apperror.One("POSTER_EXISTS", "Event can have only one poster"))
+	case errors.Is(err, service.ErrMediaLimitExceeded):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("MEDIA_LIMIT_EXCEEDED", "Too many media items for the event"))
+	case errors.Is(err, service.ErrInvalidMediaOrder):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("INVALID_MEDIA_ORDER", "media_ids must list every media item of the event exactly once"))
+	default:
+		return false
+	}
+	return true
+}
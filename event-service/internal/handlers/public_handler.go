@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"event-service/internal/apperror"
+	"event-service/internal/service"
 	"net/http"
+	"shared/pagination"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -15,9 +18,11 @@ import (
 // @Produce      json
 // @Param        creator_id  query int  false "Фильтр по creator_id"
 // @Param        category_id query int  false "Фильтр по категории"
-// @Param        limit       query int  false "Количество элементов (по умолчанию 20)"
-// @Param        offset      query int  false "Смещение (по умолчанию 0)"
-// @Success      200 {object} map[string]interface{}
+// @Param        limit         query int    false "Количество элементов (по умолчанию 20, максимум 100)"
+// @Param        cursor        query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param        include_total query bool   false "Посчитать общее количество (total)"
+// @Success      200 {object} pagination.Page[models.Event]
+// @Failure      400 {object} apperror.ErrorResponse
 // @Failure      500 {object} apperror.ErrorResponse
 // @Router       /public/events [get]
 func (h *EventHandler) ListPublicEvents(c *gin.Context) {
@@ -37,23 +42,22 @@ func (h *EventHandler) ListPublicEvents(c *gin.Context) {
 		}
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultEventsLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
 	// Принудительно только активные мероприятия
 	isActive := true
 
-	events, err := h.eventService.ListEvents(creatorID, categoryID, &isActive, nil, limit, offset)
+	events, err := h.eventService.ListEvents(creatorID, categoryID, &isActive, nil, page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch events"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"events": events,
-		"limit":  limit,
-		"offset": offset,
-	})
+	c.JSON(http.StatusOK, events)
 }
 
 // GetPublicEvent godoc
@@ -65,6 +69,7 @@ func (h *EventHandler) ListPublicEvents(c *gin.Context) {
 // @Success      200 {object} models.Event
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
 // @Router       /public/events/{id} [get]
 func (h *EventHandler) GetPublicEvent(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -75,7 +80,11 @@ func (h *EventHandler) GetPublicEvent(c *gin.Context) {
 
 	event, err := h.eventService.GetEventByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("EVENT_NOT_FOUND", "Event not found"))
+		if errors.Is(err, service.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("EVENT_NOT_FOUND", "Event not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get event"))
 		return
 	}
 
@@ -36,25 +36,3 @@ func ExtractUserContext() gin.HandlerFunc {
 		c.Next()
 	}
 }
-
-func RequireRole(allowedRoles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Role not found in context"))
-			c.Abort()
-			return
-		}
-
-		roleStr := role.(string)
-		for _, allowedRole := range allowedRoles {
-			if roleStr == allowedRole {
-				c.Next()
-				return
-			}
-		}
-
-		c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "Insufficient permissions"))
-		c.Abort()
-	}
-}
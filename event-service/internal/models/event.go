@@ -13,10 +13,52 @@ type Event struct {
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 	Categories   []int     `gorm:"-" json:"category_ids,omitempty"`
+	// Media заполняется только при получении одного мероприятия
+	Media []EventMedia `gorm:"-" json:"media,omitempty"`
 }
 
 func (Event) TableName() string { return "events" }
 
+// Статусы мероприятия в жизненном цикле каталога
+const (
+	EventStatusActive    = "active"
+	EventStatusInactive  = "inactive"
+	EventStatusCompleted = "completed"
+)
+
+// Причины смены статуса, пишутся в event_status_history.reason
+const (
+	StatusReasonCreated                = "created"
+	StatusReasonPublished              = "published"
+	StatusReasonApplicationAccepted    = "application_accepted"
+	StatusReasonCollaborationCompleted = "collaboration_completed"
+)
+
+// Status сводит флаги is_active/is_completed к одному статусу
+func (e *Event) Status() string {
+	switch {
+	case e.IsCompleted:
+		return EventStatusCompleted
+	case e.IsActive:
+		return EventStatusActive
+	default:
+		return EventStatusInactive
+	}
+}
+
+// EventStatusChange — запись истории статусов мероприятия.
+// FromStatus пуст у первой записи (создание мероприятия).
+type EventStatusChange struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID    int       `gorm:"not null" json:"event_id"`
+	FromStatus *string   `json:"from_status"`
+	ToStatus   string    `gorm:"not null" json:"to_status"`
+	Reason     string    `gorm:"not null" json:"reason"`
+	ChangedAt  time.Time `gorm:"autoCreateTime" json:"changed_at"`
+}
+
+func (EventStatusChange) TableName() string { return "event_status_history" }
+
 type EventCategory struct {
 	EventID    int `gorm:"primaryKey" json:"event_id"`
 	CategoryID int `gorm:"primaryKey" json:"category_id"`
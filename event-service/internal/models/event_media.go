@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Типы медиа мероприятия
+const (
+	MediaTypePhoto  = "photo"  // фото галереи
+	MediaTypePoster = "poster" // постер, у мероприятия один
+	MediaTypeRider  = "rider"  // райдер или техпаспорт, PDF
+)
+
+// EventMedia - файл мероприятия, загруженный в user-service.
+// Position задает общий порядок медиа мероприятия, позиции идут без пропусков.
+type EventMedia struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID   int       `gorm:"not null" json:"event_id"`
+	ImageID   string    `gorm:"type:uuid;not null" json:"image_id"`
+	Type      string    `gorm:"not null" json:"type"` // photo, poster, rider
+	Position  int       `gorm:"not null" json:"position"`
+	Caption   string    `json:"caption,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	// Копируются из user-service при добавлении медиа
+	FileName string `json:"file_name,omitempty"`
+	FileType string `json:"file_type,omitempty"`
+}
+
+func (EventMedia) TableName() string { return "event_media" }
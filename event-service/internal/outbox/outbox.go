@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"shared/eventbus"
+	sharedoutbox "shared/outbox"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// TableName — outbox этого сервиса; у каждого сервиса своя таблица
+	TableName = "event_outbox"
+	// Source попадает в eventbus.Event.Source
+	Source = "event-service"
+)
+
+var box = sharedoutbox.New(TableName, Source)
+
+// Enqueue сохраняет доменное событие в outbox сервиса в транзакции tx
+func Enqueue(tx *gorm.DB, eventType string, aggregateID int, payload interface{}) error {
+	return box.Enqueue(tx, eventType, aggregateID, payload)
+}
+
+// NewRelay создает relay, публикующий outbox сервиса в шину событий
+func NewRelay(db *gorm.DB, publisher eventbus.Publisher) *sharedoutbox.Relay {
+	return box.NewRelay(db, publisher)
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"errors"
+	"event-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMediaLimit — после добавления у мероприятия стало бы больше медиа, чем разрешено
+	ErrMediaLimit = errors.New("event media limit reached")
+	// ErrMediaDuplicate — изображение уже прикреплено к мероприятию
+	ErrMediaDuplicate = errors.New("image already attached to event")
+	// ErrPosterExists — у мероприятия уже есть постер
+	ErrPosterExists = errors.New("event already has a poster")
+	// ErrMediaMismatch — переданные ID медиа не совпадают с медиа мероприятия
+	ErrMediaMismatch = errors.New("media do not match the event")
+)
+
+func eventMediaQuery(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+func (r *EventRepository) GetEventMedia(eventID int) ([]models.EventMedia, error) {
+	media := []models.EventMedia{}
+	err := eventMediaQuery(r.db).Where("event_id = ?", eventID).Find(&media).Error
+	return media, err
+}
+
+func (r *EventRepository) GetEventMediaByID(eventID, mediaID int) (*models.EventMedia, error) {
+	var media models.EventMedia
+	err := eventMediaQuery(r.db).Where("event_id = ? AND id = ?", eventID, mediaID).Take(&media).Error
+	return &media, err
+}
+
+// lockEventMedia блокирует строку мероприятия до конца транзакции: изменения медиа
+// одного мероприятия выполняются по очереди, и лимит с позициями не разъезжаются
+func lockEventMedia(tx *gorm.DB, eventID int) error {
+	var id int
+	err := tx.Raw("SELECT id FROM events WHERE id = ? FOR UPDATE", eventID).Scan(&id).Error
+	if err == nil && id == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+// AddEventMedia добавляет медиа в конец списка (все или ни одного)
+func (r *EventRepository) AddEventMedia(eventID int, media []models.EventMedia, maxMedia int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockEventMedia(tx, eventID); err != nil {
+			return err
+		}
+
+		var current []models.EventMedia
+		if err := tx.Where("event_id = ?", eventID).Find(&current).Error; err != nil {
+			return err
+		}
+		if len(current)+len(media) > maxMedia {
+			return ErrMediaLimit
+		}
+		attached := make(map[string]bool, len(current))
+		hasPoster := false
+		for _, m := range current {
+			attached[m.ImageID] = true
+			hasPoster = hasPoster || m.Type == models.MediaTypePoster
+		}
+		for _, m := range media {
+			if attached[m.ImageID] {
+				return ErrMediaDuplicate
+			}
+			if m.Type == models.MediaTypePoster && hasPoster {
+				return ErrPosterExists
+			}
+		}
+
+		// Позиции идут без пропусков, поэтому следующая равна числу медиа
+		for i := range media {
+			media[i].EventID = eventID
+			media[i].Position = len(current) + i
+		}
+		return tx.Create(&media).Error
+	})
+}
+
+func (r *EventRepository) UpdateEventMediaCaption(eventID, mediaID int, caption string) error {
+	result := r.db.Model(&models.EventMedia{}).
+		Where("event_id = ? AND id = ?", eventID, mediaID).
+		Update("caption", caption)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReorderEventMedia расставляет медиа в порядке mediaIDs. Список должен содержать
+// все медиа мероприятия ровно по одному разу.
+func (r *EventRepository) ReorderEventMedia(eventID int, mediaIDs []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockEventMedia(tx, eventID); err != nil {
+			return err
+		}
+		var current []int
+		if err := tx.Model(&models.EventMedia{}).Where("event_id = ?", eventID).Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		if !sameIDs(current, mediaIDs) {
+			return ErrMediaMismatch
+		}
+		// Уникальность (event_id, position) проверяется при коммите, промежуточные дубли допустимы
+		for position, id := range mediaIDs {
+			if err := tx.Model(&models.EventMedia{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteEventMedia удаляет медиа и сдвигает оставшиеся, чтобы позиции снова шли без пропусков
+func (r *EventRepository) DeleteEventMedia(eventID, mediaID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockEventMedia(tx, eventID); err != nil {
+			return err
+		}
+		result := tx.Where("event_id = ? AND id = ?", eventID, mediaID).Delete(&models.EventMedia{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Exec(`
+			UPDATE event_media m SET position = o.rn - 1
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS rn
+				FROM event_media WHERE event_id = ?
+			) o
+			WHERE m.id = o.id AND m.position <> o.rn - 1`, eventID).Error
+	})
+}
+
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[int]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
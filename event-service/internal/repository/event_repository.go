@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"errors"
 	"event-service/internal/models"
+	"event-service/internal/outbox"
 	"fmt"
+	"shared/eventbus"
+	"shared/pagination"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository struct {
@@ -15,8 +20,18 @@ func NewEventRepository(db *gorm.DB) *EventRepository {
 	return &EventRepository{db: db}
 }
 
+// CreateEvent создаёт мероприятие и открывает его историю статусов
 func (r *EventRepository) CreateEvent(event *models.Event) error {
-	return r.db.Create(event).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		// is_active/is_completed могли прийти из DEFAULT — перечитываем
+		if err := tx.Select("is_active", "is_completed").First(event, event.ID).Error; err != nil {
+			return err
+		}
+		return recordStatusChange(tx, event.ID, nil, event.Status(), models.StatusReasonCreated)
+	})
 }
 
 func (r *EventRepository) GetEventByID(id int) (*models.Event, error) {
@@ -25,9 +40,11 @@ func (r *EventRepository) GetEventByID(id int) (*models.Event, error) {
 	return &event, err
 }
 
-func (r *EventRepository) ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, limit, offset int) ([]models.Event, error) {
+// ListEvents возвращает страницу мероприятий (на одно больше page.Limit — см. pagination.Apply)
+// и общее количество по фильтрам, если оно запрошено
+func (r *EventRepository) ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, page pagination.Params) ([]models.Event, *int64, error) {
 	var events []models.Event
-	query := r.db.Order("created_at DESC")
+	query := r.db.Model(&models.Event{})
 
 	if creatorID != nil {
 		query = query.Where("creator_id = ?", *creatorID)
@@ -46,21 +63,107 @@ func (r *EventRepository) ListEvents(creatorID *int, categoryID *int, isActive *
 		query = query.Where("is_completed = ?", *isCompleted)
 	}
 
-	err := query.Limit(limit).Offset(offset).Find(&events).Error
-	return events, err
+	total, err := pagination.Count(query, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = pagination.Apply(query, "events", page).Find(&events).Error
+	return events, total, err
 }
 
+// PublishEvent открывает мероприятие в каталоге и в той же транзакции
+// записывает EventPublished в outbox и переход статуса в историю.
 func (r *EventRepository) PublishEvent(id int, creatorID int) error {
-	result := r.db.Model(&models.Event{}).
-		Where("id = ? AND creator_id = ?", id, creatorID).
-		Update("is_active", true)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("event not found or access denied")
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND creator_id = ?", id, creatorID).
+			First(&event).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("event not found or access denied")
+		}
+		if err != nil {
+			return err
+		}
+
+		if !event.IsActive {
+			from := event.Status()
+			event.IsActive = true
+			if err := tx.Model(&event).Update("is_active", true).Error; err != nil {
+				return err
+			}
+			if err := recordStatusChange(tx, id, &from, event.Status(), models.StatusReasonPublished); err != nil {
+				return err
+			}
+		}
+		return outbox.Enqueue(tx, eventbus.EventPublished, id, eventbus.EventPublishedPayload{
+			EventID:   id,
+			CreatorID: creatorID,
+		})
+	})
+}
+
+// DeactivateEvent снимает мероприятие с каталога (по ApplicationAccepted).
+// Повторный вызов ничего не меняет, отсутствующее мероприятие не ошибка.
+func (r *EventRepository) DeactivateEvent(id int) error {
+	return r.changeStatus(id, models.StatusReasonApplicationAccepted, func(e *models.Event) (string, bool) {
+		if !e.IsActive {
+			return "", false
+		}
+		e.IsActive = false
+		return "is_active", false
+	})
+}
+
+// MarkEventCompleted отмечает мероприятие проведённым (по CollaborationCompleted)
+func (r *EventRepository) MarkEventCompleted(id int) error {
+	return r.changeStatus(id, models.StatusReasonCollaborationCompleted, func(e *models.Event) (string, bool) {
+		if e.IsCompleted {
+			return "", false
+		}
+		e.IsCompleted = true
+		return "is_completed", true
+	})
+}
+
+// changeStatus блокирует строку мероприятия, применяет к ней mutate и,
+// если флаг изменился, сохраняет его и пишет переход в историю.
+// mutate возвращает изменённую колонку и её новое значение; пустая
+// колонка означает, что мероприятие уже в нужном состоянии.
+func (r *EventRepository) changeStatus(id int, reason string, mutate func(e *models.Event) (string, bool)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		from := event.Status()
+		column, value := mutate(&event)
+		if column == "" {
+			return nil
+		}
+		if err := tx.Model(&event).Update(column, value).Error; err != nil {
+			return err
+		}
+		if to := event.Status(); to != from {
+			return recordStatusChange(tx, id, &from, to, reason)
+		}
+		return nil
+	})
+}
+
+func recordStatusChange(tx *gorm.DB, eventID int, from *string, to, reason string) error {
+	return tx.Create(&models.EventStatusChange{
+		EventID:    eventID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+	}).Error
 }
 
 func (r *EventRepository) GetEventsByIDs(ids []int) ([]models.Event, error) {
@@ -73,15 +176,21 @@ func (r *EventRepository) UpdateEvent(event *models.Event) error {
 	return r.db.Save(event).Error
 }
 
+// DeleteEvent удаляет мероприятие с категориями и пишет EventDeleted в outbox:
+// по нему user-service сбрасывает кэш похожих креаторов
 func (r *EventRepository) DeleteEvent(id int) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("event_id = ?", id).Delete(&models.EventCategory{}).Error; err != nil {
 			return err
 		}
-		return tx.Delete(&models.Event{}, id).Error
+		if err := tx.Delete(&models.Event{}, id).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, eventbus.EventDeleted, id, eventbus.EventDeletedPayload{EventID: id})
 	})
 }
 
+// AddEventCategories заменяет категории мероприятия и пишет EventCategoriesChanged в outbox
 func (r *EventRepository) AddEventCategories(eventID int, categoryIDs []int) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("event_id = ?", eventID).Delete(&models.EventCategory{}).Error; err != nil {
@@ -96,35 +205,57 @@ func (r *EventRepository) AddEventCategories(eventID int, categoryIDs []int) err
 				return err
 			}
 		}
-		return nil
+		return outbox.Enqueue(tx, eventbus.EventCategoriesChanged, eventID, eventbus.EventCategoriesChangedPayload{
+			EventID:     eventID,
+			CategoryIDs: categoryIDs,
+		})
 	})
 }
 
 // Favorites operations
 
+// AddVenueFavoriteEvent добавляет мероприятие в избранное площадки; если запись
+// появилась, в той же транзакции пишет VenueFavoriteChanged в outbox
 func (r *EventRepository) AddVenueFavoriteEvent(venueUserID, eventID int) (bool, error) {
-	fav := &models.VenueFavoriteEvent{
-		VenueUserID: venueUserID,
-		EventID:     eventID,
-	}
-	result := r.db.Where(fav).FirstOrCreate(fav)
-	if result.Error != nil {
-		return false, result.Error
-	}
-	alreadyExisted := result.RowsAffected == 0
-	return alreadyExisted, nil
+	alreadyExisted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		fav := &models.VenueFavoriteEvent{
+			VenueUserID: venueUserID,
+			EventID:     eventID,
+		}
+		result := tx.Where(fav).FirstOrCreate(fav)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			alreadyExisted = true
+			return nil
+		}
+		return enqueueFavoriteChanged(tx, venueUserID, eventID, true)
+	})
+	return alreadyExisted, err
 }
 
 func (r *EventRepository) RemoveVenueFavoriteEvent(venueUserID, eventID int) error {
-	result := r.db.Where("venue_user_id = ? AND event_id = ?", venueUserID, eventID).
-		Delete(&models.VenueFavoriteEvent{})
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("venue_user_id = ? AND event_id = ?", venueUserID, eventID).
+			Delete(&models.VenueFavoriteEvent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return enqueueFavoriteChanged(tx, venueUserID, eventID, false)
+	})
+}
+
+func enqueueFavoriteChanged(tx *gorm.DB, venueUserID, eventID int, favorited bool) error {
+	return outbox.Enqueue(tx, eventbus.VenueFavoriteChanged, eventID, eventbus.VenueFavoriteChangedPayload{
+		EventID:     eventID,
+		VenueUserID: venueUserID,
+		Favorited:   favorited,
+	})
 }
 
 func (r *EventRepository) ListVenueFavoriteEvents(venueUserID int) ([]models.Event, error) {
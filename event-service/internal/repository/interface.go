@@ -1,20 +1,31 @@
 package repository
 
-import "event-service/internal/models"
+import (
+	"event-service/internal/models"
+	"shared/pagination"
+)
 
 type EventRepositoryInterface interface {
 	CreateEvent(event *models.Event) error
 	GetEventByID(id int) (*models.Event, error)
 	GetEventsByIDs(ids []int) ([]models.Event, error)
-	ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, limit, offset int) ([]models.Event, error)
+	ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, page pagination.Params) ([]models.Event, *int64, error)
 	UpdateEvent(event *models.Event) error
 	DeleteEvent(id int) error
 	PublishEvent(id int, creatorID int) error
+	DeactivateEvent(id int) error
+	MarkEventCompleted(id int) error
 	AddEventCategories(eventID int, categoryIDs []int) error
 	GetEventCategories(eventID int) ([]int, error)
 	AddVenueFavoriteEvent(venueUserID, eventID int) (bool, error)
 	RemoveVenueFavoriteEvent(venueUserID, eventID int) error
 	ListVenueFavoriteEvents(venueUserID int) ([]models.Event, error)
+	GetEventMedia(eventID int) ([]models.EventMedia, error)
+	GetEventMediaByID(eventID, mediaID int) (*models.EventMedia, error)
+	AddEventMedia(eventID int, media []models.EventMedia, maxMedia int) error
+	UpdateEventMediaCaption(eventID, mediaID int, caption string) error
+	ReorderEventMedia(eventID int, mediaIDs []int) error
+	DeleteEventMedia(eventID, mediaID int) error
 }
 
 type CategoryRepositoryInterface interface {
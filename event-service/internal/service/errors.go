@@ -7,4 +7,15 @@ var (
 	ErrAccessDenied     = errors.New("ACCESS_DENIED")
 	ErrAlreadyFavorited = errors.New("ALREADY_FAVORITED")
 	ErrFavoriteNotFound = errors.New("FAVORITE_NOT_FOUND")
+	ErrImageNotFound    = errors.New("IMAGE_NOT_FOUND")
+	ErrImageNotOwned    = errors.New("IMAGE_NOT_OWNED")
+
+	ErrDependencyUnavailable = errors.New("DEPENDENCY_UNAVAILABLE")
+
+	ErrMediaNotFound      = errors.New("MEDIA_NOT_FOUND")
+	ErrInvalidMediaFile   = errors.New("INVALID_MEDIA_FILE")
+	ErrDuplicateMedia     = errors.New("DUPLICATE_MEDIA")
+	ErrPosterExists       = errors.New("POSTER_EXISTS")
+	ErrMediaLimitExceeded = errors.New("MEDIA_LIMIT_EXCEEDED")
+	ErrInvalidMediaOrder  = errors.New("INVALID_MEDIA_ORDER")
 )
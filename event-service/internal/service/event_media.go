@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"event-service/internal/clients"
+	"event-service/internal/models"
+	"event-service/internal/repository"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MaxEventMedia — сколько медиа (фото, постер, документы) может быть у мероприятия
+const MaxEventMedia = 30
+
+// EventMediaInput - файл, прикрепляемый к мероприятию. Файл загружается в user-service:
+// фото и постер — как изображение (event-photo, event-poster), райдер — как PDF (event-document).
+type EventMediaInput struct {
+	ImageID string `json:"image_id" binding:"required,uuid"`
+	Type    string `json:"type" binding:"required,oneof=photo poster rider"`
+	Caption string `json:"caption" binding:"omitempty,max=500"`
+}
+
+type AddEventMediaRequest struct {
+	Items []EventMediaInput `json:"items" binding:"required,min=1,max=30,dive"`
+}
+
+type ReorderEventMediaRequest struct {
+	MediaIDs []int `json:"media_ids" binding:"required"`
+}
+
+type UpdateEventMediaRequest struct {
+	Caption *string `json:"caption" binding:"omitempty,max=500"`
+}
+
+// AddEventMedia прикрепляет файлы к мероприятию в конец списка (все или ни одного)
+// и возвращает все медиа мероприятия по порядку
+func (s *EventService) AddEventMedia(eventID, creatorID int, items []EventMediaInput) ([]models.EventMedia, error) {
+	if err := s.ensureEventOwned(eventID, creatorID); err != nil {
+		return nil, err
+	}
+	images, err := s.checkMediaInputs(creatorID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Имя и тип файла сохраняются вместе с медиа: изображение не меняется после
+	// загрузки, поэтому при чтении медиа в user-service ходить не нужно
+	media := make([]models.EventMedia, len(items))
+	for i, item := range items {
+		image := images[item.ImageID]
+		media[i] = models.EventMedia{
+			ImageID:  item.ImageID,
+			Type:     item.Type,
+			Caption:  item.Caption,
+			FileName: image.FileName,
+			FileType: image.FileType,
+		}
+	}
+	if err := s.repo.AddEventMedia(eventID, media, MaxEventMedia); err != nil {
+		return nil, mapMediaError(err)
+	}
+	return s.repo.GetEventMedia(eventID)
+}
+
+func (s *EventService) UpdateEventMedia(eventID, mediaID, creatorID int, req *UpdateEventMediaRequest) (*models.EventMedia, error) {
+	if err := s.ensureEventOwned(eventID, creatorID); err != nil {
+		return nil, err
+	}
+	if req.Caption != nil {
+		if err := s.repo.UpdateEventMediaCaption(eventID, mediaID, *req.Caption); err != nil {
+			return nil, mapMediaError(err)
+		}
+	}
+	media, err := s.repo.GetEventMediaByID(eventID, mediaID)
+	if err != nil {
+		return nil, mapMediaError(err)
+	}
+	return media, nil
+}
+
+// ReorderEventMedia задает порядок медиа; mediaIDs должен перечислять все медиа мероприятия
+func (s *EventService) ReorderEventMedia(eventID, creatorID int, mediaIDs []int) ([]models.EventMedia, error) {
+	if err := s.ensureEventOwned(eventID, creatorID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReorderEventMedia(eventID, mediaIDs); err != nil {
+		return nil, mapMediaError(err)
+	}
+	return s.repo.GetEventMedia(eventID)
+}
+
+func (s *EventService) DeleteEventMedia(eventID, mediaID, creatorID int) error {
+	if err := s.ensureEventOwned(eventID, creatorID); err != nil {
+		return err
+	}
+	return mapMediaError(s.repo.DeleteEventMedia(eventID, mediaID))
+}
+
+func (s *EventService) ensureEventOwned(eventID, creatorID int) error {
+	event, err := s.repo.GetEventByID(eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		return err
+	}
+	if event.CreatorID != creatorID {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// checkMediaInputs проверяет, что файлы не повторяются, загружены создателем и
+// подходят по типу: фото и постер — изображения, райдер — PDF. Возвращает
+// изображения из user-service по ID.
+func (s *EventService) checkMediaInputs(creatorID int, items []EventMediaInput) (map[string]clients.Image, error) {
+	ids := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	posters := 0
+	for _, item := range items {
+		if seen[item.ImageID] {
+			return nil, ErrDuplicateMedia
+		}
+		seen[item.ImageID] = true
+		ids = append(ids, item.ImageID)
+		if item.Type == models.MediaTypePoster {
+			posters++
+		}
+	}
+	if posters > 1 {
+		return nil, ErrPosterExists
+	}
+
+	images, err := s.getImages(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]clients.Image, len(images))
+	for _, image := range images {
+		byID[image.ID] = image
+	}
+
+	for _, item := range items {
+		image, ok := byID[item.ImageID]
+		if !ok {
+			return nil, ErrImageNotFound
+		}
+		if image.OwnerUserID == nil || *image.OwnerUserID != creatorID {
+			return nil, ErrImageNotOwned
+		}
+		if !mediaFileAllowed(item.Type, image.FileType) {
+			return nil, ErrInvalidMediaFile
+		}
+	}
+	return byID, nil
+}
+
+func mediaFileAllowed(mediaType, fileType string) bool {
+	if mediaType == models.MediaTypeRider {
+		return fileType == "application/pdf"
+	}
+	return strings.HasPrefix(fileType, "image/")
+}
+
+func mapMediaError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrMediaNotFound
+	case errors.Is(err, repository.ErrMediaLimit):
+		return ErrMediaLimitExceeded
+	case errors.Is(err, repository.ErrMediaDuplicate):
+		return ErrDuplicateMedia
+	case errors.Is(err, repository.ErrPosterExists):
+		return ErrPosterExists
+	case errors.Is(err, repository.ErrMediaMismatch):
+		return ErrInvalidMediaOrder
+	}
+	return err
+}
@@ -1,19 +1,34 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"event-service/internal/clients"
 	"event-service/internal/models"
 	"event-service/internal/repository"
+	"fmt"
+	"shared/pagination"
+
+	"gorm.io/gorm"
 )
 
+// DefaultEventsLimit — размер страницы списка мероприятий по умолчанию
+const DefaultEventsLimit = 20
+
 type EventService struct {
-	repo repository.EventRepositoryInterface
+	repo  repository.EventRepositoryInterface
+	users clients.UserClient
 }
 
-func NewEventService(repo repository.EventRepositoryInterface) *EventService {
-	return &EventService{repo: repo}
+func NewEventService(repo repository.EventRepositoryInterface, users clients.UserClient) *EventService {
+	return &EventService{repo: repo, users: users}
 }
 
 func (s *EventService) CreateEvent(req *CreateEventRequest, creatorID int) (*models.Event, error) {
+	if err := s.ensureImageOwned(creatorID, nil, req.CoverPhotoID); err != nil {
+		return nil, err
+	}
+
 	event := &models.Event{
 		CreatorID:    creatorID,
 		Title:        req.Title,
@@ -38,6 +53,9 @@ func (s *EventService) CreateEvent(req *CreateEventRequest, creatorID int) (*mod
 func (s *EventService) GetEventByID(id int) (*models.Event, error) {
 	event, err := s.repo.GetEventByID(id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
 		return nil, err
 	}
 
@@ -47,27 +65,64 @@ func (s *EventService) GetEventByID(id int) (*models.Event, error) {
 	}
 	event.Categories = categoryIDs
 
+	media, err := s.repo.GetEventMedia(id)
+	if err != nil {
+		return nil, err
+	}
+	event.Media = media
+
 	return event, nil
 }
 
-func (s *EventService) ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, limit, offset int) ([]models.Event, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
-	events, err := s.repo.ListEvents(creatorID, categoryID, isActive, isCompleted, limit, offset)
+func (s *EventService) ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, page pagination.Params) (pagination.Page[models.Event], error) {
+	page = page.Normalize(DefaultEventsLimit)
+	events, total, err := s.repo.ListEvents(creatorID, categoryID, isActive, isCompleted, page)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Event]{}, err
 	}
 
-	for i := range events {
-		categoryIDs, err := s.repo.GetEventCategories(events[i].ID)
+	result := pagination.NewPage(events, total, page, eventCursor)
+	for i := range result.Items {
+		categoryIDs, err := s.repo.GetEventCategories(result.Items[i].ID)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.Event]{}, err
 		}
-		events[i].Categories = categoryIDs
+		result.Items[i].Categories = categoryIDs
 	}
 
-	return events, nil
+	return result, nil
+}
+
+// ensureImageOwned проверяет, что мероприятие ссылается на изображение, загруженное
+// его создателем. Уже сохраненное значение current не перепроверяется.
+func (s *EventService) ensureImageOwned(creatorID int, current, requested *string) error {
+	if requested == nil || (current != nil && *current == *requested) {
+		return nil
+	}
+	images, err := s.getImages([]string{*requested})
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return ErrImageNotFound
+	}
+	if images[0].OwnerUserID == nil || *images[0].OwnerUserID != creatorID {
+		return ErrImageNotOwned
+	}
+	return nil
+}
+
+// getImages запрашивает изображения у user-service, который ведет таблицу images
+func (s *EventService) getImages(ids []string) ([]clients.Image, error) {
+	images, err := s.users.GetImages(context.Background(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDependencyUnavailable, err)
+	}
+	return images, nil
+}
+
+func eventCursor(e *models.Event) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: e.CreatedAt, ID: e.ID}
 }
 
 func (s *EventService) GetEventsByIDs(ids []int) ([]models.Event, error) {
@@ -107,6 +162,9 @@ func (s *EventService) UpdateEvent(id int, req *UpdateEventRequest, creatorID in
 		event.Description = *req.Description
 	}
 	if req.CoverPhotoID != nil {
+		if err := s.ensureImageOwned(creatorID, event.CoverPhotoID, req.CoverPhotoID); err != nil {
+			return nil, err
+		}
 		event.CoverPhotoID = req.CoverPhotoID
 	}
 
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
+	"event-service/internal/clients"
 	"event-service/internal/config"
+	"event-service/internal/consumer"
 	"event-service/internal/handlers"
 	"event-service/internal/middleware"
+	"event-service/internal/outbox"
 	"event-service/internal/repository"
 	"event-service/internal/service"
 	"log"
+	"os"
+	"shared/eventbus"
+	"shared/svcclient"
 
 	_ "event-service/docs" // Swagger docs
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/driver/postgres"
@@ -40,7 +48,27 @@ func main() {
 	eventRepo := repository.NewEventRepository(db)
 	categoryRepo := repository.NewCategoryRepository(db)
 
-	eventService := service.NewEventService(eventRepo)
+	// Доменные события: свои публикуем через outbox, чужие (заявки,
+	// коллаборации) применяем к мероприятиям через consumer group
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.RedisURL})
+	defer redisClient.Close()
+	hostname, _ := os.Hostname()
+	eventBus := eventbus.NewRedisStreams(redisClient, eventbus.DefaultStream, hostname)
+
+	go outbox.NewRelay(db, eventBus).Run(context.Background())
+
+	domainConsumer := consumer.NewDomainEventConsumer(eventRepo)
+	go func() {
+		if err := eventBus.Subscribe(context.Background(), consumer.Group, domainConsumer.Handle); err != nil {
+			log.Printf("Domain event consumer stopped: %v", err)
+		}
+	}()
+
+	// Изображения мероприятий ведет user-service: владельца и тип файла
+	// спрашиваем у него, а не читаем его таблицы
+	userClient := clients.NewUserClient(cfg.UserServiceURL, svcclient.DefaultOptions())
+
+	eventService := service.NewEventService(eventRepo, userClient)
 	categoryService := service.NewCategoryService(categoryRepo)
 	favoritesService := service.NewFavoritesService(eventRepo)
 
@@ -69,12 +97,17 @@ func main() {
 	}
 
 	eventsCreator := r.Group("/events")
-	eventsCreator.Use(middleware.ExtractUserContext(), middleware.RequireRole("creator"))
+	// Роль creator проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	eventsCreator.Use(middleware.ExtractUserContext())
 	{
 		eventsCreator.POST("", eventHandler.CreateEvent)
 		eventsCreator.PUT("/:id", eventHandler.UpdateEvent)
 		eventsCreator.PATCH("/:id/publish", eventHandler.PublishEvent)
 		eventsCreator.DELETE("/:id", eventHandler.DeleteEvent)
+		eventsCreator.POST("/:id/media", eventHandler.AddEventMedia)
+		eventsCreator.PUT("/:id/media/order", eventHandler.ReorderEventMedia)
+		eventsCreator.PATCH("/:id/media/:media_id", eventHandler.UpdateEventMedia)
+		eventsCreator.DELETE("/:id/media/:media_id", eventHandler.DeleteEventMedia)
 	}
 
 	// Public routes (без авторизации, только is_active=true)
@@ -86,7 +119,8 @@ func main() {
 
 	// Favorites routes (venue → events)
 	eventsFavorites := r.Group("/events/favorites")
-	eventsFavorites.Use(middleware.ExtractUserContext(), middleware.RequireRole("venue"))
+	// Роль venue проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	eventsFavorites.Use(middleware.ExtractUserContext())
 	{
 		eventsFavorites.GET("", favoritesHandler.ListFavoriteEvents)
 		eventsFavorites.PUT("/:id", favoritesHandler.AddFavoriteEvent)
@@ -98,7 +132,8 @@ func main() {
 	r.GET("/categories/:id", categoryHandler.GetCategory)
 
 	categoriesAdmin := r.Group("/categories")
-	categoriesAdmin.Use(middleware.ExtractUserContext(), middleware.RequireRole("admin"))
+	// Роль admin проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	categoriesAdmin.Use(middleware.ExtractUserContext())
 	{
 		categoriesAdmin.POST("", categoryHandler.CreateCategory)
 		categoriesAdmin.PUT("/:id", categoryHandler.UpdateCategory)
@@ -4,10 +4,17 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"event-service/internal/consumer"
 	"event-service/internal/models"
 	"event-service/internal/repository"
+	"event-service/internal/service"
 	"fmt"
 	"os"
+	"shared/eventbus"
+	"shared/pagination"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,6 +75,14 @@ func migrateTestDB(db *gorm.DB) error {
 			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 		);
 
+		CREATE TABLE IF NOT EXISTS images (
+			id            UUID PRIMARY KEY,
+			status        VARCHAR(20) NOT NULL DEFAULT 'ready',
+			owner_user_id INT,
+			file_name     VARCHAR(255) NOT NULL DEFAULT '',
+			file_type     VARCHAR(100)
+		);
+
 		CREATE TABLE IF NOT EXISTS events (
 			id            SERIAL PRIMARY KEY,
 			creator_id    INT NOT NULL,
@@ -92,12 +107,47 @@ func migrateTestDB(db *gorm.DB) error {
 			created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			PRIMARY KEY (venue_user_id, event_id)
 		);
+
+		CREATE TABLE IF NOT EXISTS event_media (
+			id         SERIAL PRIMARY KEY,
+			event_id   INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			image_id   UUID NOT NULL REFERENCES images(id),
+			type       VARCHAR(20) NOT NULL,
+			position   INT NOT NULL,
+			caption    VARCHAR(500),
+			file_name  VARCHAR(255) NOT NULL DEFAULT '',
+			file_type  VARCHAR(100),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			UNIQUE (event_id, image_id),
+			CONSTRAINT uq_event_media_position UNIQUE (event_id, position) DEFERRABLE INITIALLY DEFERRED
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_event_media_poster ON event_media (event_id) WHERE type = 'poster';
+
+		CREATE TABLE IF NOT EXISTS event_outbox (
+			id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			event_type   VARCHAR(100) NOT NULL,
+			aggregate_id INT NOT NULL,
+			payload      JSONB NOT NULL,
+			created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			published_at TIMESTAMPTZ,
+			attempts     INT NOT NULL DEFAULT 0,
+			last_error   TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE TABLE IF NOT EXISTS event_status_history (
+			id          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+			event_id    INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			from_status VARCHAR(20),
+			to_status   VARCHAR(20) NOT NULL,
+			reason      VARCHAR(50) NOT NULL,
+			changed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
 	`).Error
 }
 
 func resetDB(t *testing.T) {
 	t.Helper()
-	if err := testDB.Exec("TRUNCATE venue_favorite_events, event_categories, events, categories RESTART IDENTITY CASCADE").Error; err != nil {
+	if err := testDB.Exec("TRUNCATE event_media, images, venue_favorite_events, event_categories, event_status_history, events, categories, event_outbox RESTART IDENTITY CASCADE").Error; err != nil {
 		t.Fatalf("failed to reset db: %v", err)
 	}
 }
@@ -220,6 +270,12 @@ func TestIntegration_PublishEvent(t *testing.T) {
 	if !fetched.IsActive {
 		t.Error("expected is_active=true after publish")
 	}
+
+	var eventTypes []string
+	testDB.Raw("SELECT event_type FROM event_outbox WHERE aggregate_id = ?", event.ID).Scan(&eventTypes)
+	if len(eventTypes) != 1 || eventTypes[0] != eventbus.EventPublished {
+		t.Errorf("expected EventPublished in outbox, got %v", eventTypes)
+	}
 }
 
 func TestIntegration_PublishEvent_WrongCreator(t *testing.T) {
@@ -233,6 +289,156 @@ func TestIntegration_PublishEvent_WrongCreator(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for wrong creator, got nil")
 	}
+
+	var count int64
+	testDB.Raw("SELECT COUNT(*) FROM event_outbox").Scan(&count)
+	if count != 0 {
+		t.Errorf("expected no outbox rows after failed publish, got %d", count)
+	}
+}
+
+// ─── Outbox: изменения, от которых зависят похожие креаторы ─────────────────
+
+func TestIntegration_Outbox_CategoriesFavoritesDelete(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+	catRepo := repository.NewCategoryRepository(testDB)
+
+	cat := &models.Category{Name: "Music"}
+	catRepo.CreateCategory(cat)
+	event := &models.Event{CreatorID: 1, Title: "Event"}
+	repo.CreateEvent(event)
+
+	if err := repo.AddEventCategories(event.ID, []int{cat.ID}); err != nil {
+		t.Fatalf("add categories failed: %v", err)
+	}
+	repo.AddVenueFavoriteEvent(2, event.ID)
+	// Повторное добавление ничего не меняет и события не пишет
+	repo.AddVenueFavoriteEvent(2, event.ID)
+	if err := repo.RemoveVenueFavoriteEvent(2, event.ID); err != nil {
+		t.Fatalf("remove favorite failed: %v", err)
+	}
+	if err := repo.DeleteEvent(event.ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	var eventTypes []string
+	testDB.Raw("SELECT event_type FROM event_outbox WHERE aggregate_id = ? ORDER BY id", event.ID).Scan(&eventTypes)
+	expected := []string{
+		eventbus.EventCategoriesChanged,
+		eventbus.VenueFavoriteChanged,
+		eventbus.VenueFavoriteChanged,
+		eventbus.EventDeleted,
+	}
+	if strings.Join(eventTypes, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected outbox %v, got %v", expected, eventTypes)
+	}
+}
+
+// ─── Domain events ────────────────────────────────────────────────────────────
+
+func TestIntegration_DomainEventConsumer(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+
+	event := &models.Event{CreatorID: 1, Title: "Event"}
+	repo.CreateEvent(event)
+
+	bus := eventbus.NewMemory()
+	bus.Handle(consumer.Group, consumer.NewDomainEventConsumer(repo).Handle)
+
+	accepted, _ := json.Marshal(eventbus.ApplicationAcceptedPayload{EventID: event.ID})
+	completed, _ := json.Marshal(eventbus.CollaborationCompletedPayload{EventID: event.ID})
+	ctx := context.Background()
+	if err := bus.Publish(ctx, eventbus.Event{Type: eventbus.ApplicationAccepted, Payload: accepted}); err != nil {
+		t.Fatalf("handle accepted failed: %v", err)
+	}
+	if err := bus.Publish(ctx, eventbus.Event{Type: eventbus.CollaborationCompleted, Payload: completed}); err != nil {
+		t.Fatalf("handle completed failed: %v", err)
+	}
+
+	fetched, _ := repo.GetEventByID(event.ID)
+	if fetched.IsActive || !fetched.IsCompleted {
+		t.Errorf("expected inactive completed event, got is_active=%v is_completed=%v", fetched.IsActive, fetched.IsCompleted)
+	}
+}
+
+// ─── Status history ───────────────────────────────────────────────────────────
+
+func statusHistory(t *testing.T, eventID int) []models.EventStatusChange {
+	t.Helper()
+	var changes []models.EventStatusChange
+	if err := testDB.Where("event_id = ?", eventID).Order("id").Find(&changes).Error; err != nil {
+		t.Fatalf("load status history: %v", err)
+	}
+	return changes
+}
+
+func TestIntegration_StatusHistory_Lifecycle(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+
+	event := &models.Event{CreatorID: 1, Title: "Event"}
+	repo.CreateEvent(event)
+	repo.DeactivateEvent(event.ID)
+	repo.PublishEvent(event.ID, 1)
+	repo.DeactivateEvent(event.ID)
+	repo.MarkEventCompleted(event.ID)
+
+	want := []struct{ from, to, reason string }{
+		{"", models.EventStatusActive, models.StatusReasonCreated},
+		{models.EventStatusActive, models.EventStatusInactive, models.StatusReasonApplicationAccepted},
+		{models.EventStatusInactive, models.EventStatusActive, models.StatusReasonPublished},
+		{models.EventStatusActive, models.EventStatusInactive, models.StatusReasonApplicationAccepted},
+		{models.EventStatusInactive, models.EventStatusCompleted, models.StatusReasonCollaborationCompleted},
+	}
+	changes := statusHistory(t, event.ID)
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+	for i, w := range want {
+		c := changes[i]
+		from := ""
+		if c.FromStatus != nil {
+			from = *c.FromStatus
+		}
+		if from != w.from || c.ToStatus != w.to || c.Reason != w.reason {
+			t.Errorf("change %d: expected %s→%s (%s), got %s→%s (%s)", i, w.from, w.to, w.reason, from, c.ToStatus, c.Reason)
+		}
+	}
+}
+
+func TestIntegration_StatusHistory_NoOpTransitionsNotRecorded(t *testing.T) {
+	// Повторная доставка доменных событий и публикация активного
+	// мероприятия не создают лишних записей
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+
+	event := &models.Event{CreatorID: 1, Title: "Event"}
+	repo.CreateEvent(event)
+	repo.PublishEvent(event.ID, 1)
+	repo.DeactivateEvent(event.ID)
+	repo.DeactivateEvent(event.ID)
+	repo.MarkEventCompleted(event.ID)
+	repo.MarkEventCompleted(event.ID)
+
+	if changes := statusHistory(t, event.ID); len(changes) != 3 {
+		t.Errorf("expected 3 changes, got %d: %+v", len(changes), changes)
+	}
+}
+
+func TestIntegration_StatusHistory_MissingEvent(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+
+	if err := repo.DeactivateEvent(999); err != nil {
+		t.Errorf("expected no error for missing event, got %v", err)
+	}
+	var count int64
+	testDB.Model(&models.EventStatusChange{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no history rows, got %d", count)
+	}
 }
 
 // ─── Favorites ────────────────────────────────────────────────────────────────
@@ -309,3 +515,113 @@ func TestIntegration_GetEventsByIDs(t *testing.T) {
 		t.Errorf("expected 2 events, got %d", len(events))
 	}
 }
+
+// ─── ListEvents: keyset pagination ────────────────────────────────────────────
+
+func TestIntegration_ListEvents_CursorPagination(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+	svc := service.NewEventService(repo, nil)
+
+	for i := 0; i < 5; i++ {
+		repo.CreateEvent(&models.Event{CreatorID: 1, Title: fmt.Sprintf("E%d", i)})
+	}
+	// Два события с одинаковым created_at: порядок между ними задает id
+	testDB.Exec("UPDATE events SET created_at = (SELECT MAX(created_at) FROM events) WHERE id IN (4, 5)")
+
+	var seen []int
+	page := pagination.Params{Limit: 2, WithTotal: true}
+	for {
+		result, err := svc.ListEvents(nil, nil, nil, nil, page)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.WithTotal && (result.Total == nil || *result.Total != 5) {
+			t.Fatalf("expected total 5, got %v", result.Total)
+		}
+		for _, e := range result.Items {
+			seen = append(seen, e.ID)
+		}
+		if result.NextCursor == nil {
+			break
+		}
+		if page.After, err = pagination.Decode(*result.NextCursor); err != nil {
+			t.Fatalf("unexpected cursor error: %v", err)
+		}
+		// Новое событие между запросами не должно порождать дублей на следующих страницах
+		repo.CreateEvent(&models.Event{CreatorID: 2, Title: "Late"})
+		page.WithTotal = false
+	}
+
+	want := []int{5, 4, 3, 2, 1}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, seen)
+	}
+}
+
+func TestIntegration_EventMedia(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewEventRepository(testDB)
+	event := &models.Event{CreatorID: 7, Title: "Gig"}
+	if err := repo.CreateEvent(event); err != nil {
+		t.Fatalf("create event failed: %v", err)
+	}
+	testDB.Exec(`INSERT INTO images (id, status, owner_user_id, file_name, file_type) VALUES
+		('2e7d2a1b-0000-4000-8000-000000000001', 'ready', 7, 'poster.jpg', 'image/jpeg'),
+		('2e7d2a1b-0000-4000-8000-000000000002', 'ready', 7, 'stage.png', 'image/png'),
+		('2e7d2a1b-0000-4000-8000-000000000003', 'quarantined', 7, 'rider.pdf', 'application/pdf'),
+		('2e7d2a1b-0000-4000-8000-000000000004', 'rejected', 7, 'bad.pdf', 'application/pdf')`)
+
+	err := repo.AddEventMedia(event.ID, []models.EventMedia{
+		{ImageID: "2e7d2a1b-0000-4000-8000-000000000001", Type: models.MediaTypePoster, FileName: "poster.jpg", FileType: "image/jpeg"},
+		{ImageID: "2e7d2a1b-0000-4000-8000-000000000002", Type: models.MediaTypePhoto, Caption: "Stage", FileName: "stage.png", FileType: "image/png"},
+		{ImageID: "2e7d2a1b-0000-4000-8000-000000000003", Type: models.MediaTypeRider, FileName: "rider.pdf", FileType: "application/pdf"},
+	}, 3)
+	if err != nil {
+		t.Fatalf("add media failed: %v", err)
+	}
+
+	err = repo.AddEventMedia(event.ID, []models.EventMedia{{ImageID: "2e7d2a1b-0000-4000-8000-000000000002", Type: models.MediaTypePhoto}}, 10)
+	if !errors.Is(err, repository.ErrMediaDuplicate) {
+		t.Errorf("expected ErrMediaDuplicate, got %v", err)
+	}
+	if err := repo.AddEventMedia(event.ID, []models.EventMedia{{ImageID: "2e7d2a1b-0000-4000-8000-000000000004", Type: models.MediaTypeRider}}, 3); !errors.Is(err, repository.ErrMediaLimit) {
+		t.Errorf("expected ErrMediaLimit, got %v", err)
+	}
+
+	media, err := repo.GetEventMedia(event.ID)
+	if err != nil || len(media) != 3 {
+		t.Fatalf("expected 3 media, got %+v, %v", media, err)
+	}
+	if media[1].Caption != "Stage" || media[1].FileName != "stage.png" || media[2].FileType != "application/pdf" {
+		t.Errorf("expected stored file details, got %+v", media)
+	}
+
+	if err := repo.ReorderEventMedia(event.ID, []int{media[2].ID, media[0].ID}); !errors.Is(err, repository.ErrMediaMismatch) {
+		t.Errorf("expected ErrMediaMismatch, got %v", err)
+	}
+	if err := repo.ReorderEventMedia(event.ID, []int{media[2].ID, media[0].ID, media[1].ID}); err != nil {
+		t.Fatalf("reorder failed: %v", err)
+	}
+
+	if err := repo.DeleteEventMedia(event.ID, media[0].ID); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := repo.DeleteEventMedia(event.ID, media[0].ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	media, _ = repo.GetEventMedia(event.ID)
+	if len(media) != 2 || media[0].Type != models.MediaTypeRider || media[1].Position != 1 {
+		t.Errorf("expected rider first and compacted positions, got %+v", media)
+	}
+
+	if err := repo.DeleteEvent(event.ID); err != nil {
+		t.Fatalf("delete event failed: %v", err)
+	}
+	var left int64
+	testDB.Model(&models.EventMedia{}).Count(&left)
+	if left != 0 {
+		t.Errorf("expected media to be removed with the event, got %d", left)
+	}
+}
@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"event-service/internal/clients"
+	"net/http"
+	"net/http/httptest"
+	"shared/svcclient"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ─── UserClient ───────────────────────────────────────────────────────────────
+
+func testClientOptions() svcclient.Options {
+	return svcclient.Options{
+		Timeout:          time.Second,
+		Retries:          2,
+		Backoff:          time.Millisecond,
+		FailureThreshold: 3,
+		Cooldown:         time.Hour,
+	}
+}
+
+func TestUserClient_GetImages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/images" || r.URL.Query().Get("ids") != "a,b" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"images":[{"id":"a","owner_user_id":7,"file_name":"rider.pdf","file_type":"application/pdf"}]}`))
+	}))
+	defer srv.Close()
+
+	images, err := clients.NewUserClient(srv.URL, testClientOptions()).GetImages(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(images) != 1 || images[0].OwnerUserID == nil || *images[0].OwnerUserID != 7 || images[0].FileType != "application/pdf" {
+		t.Errorf("unexpected images: %+v", images)
+	}
+}
+
+func TestUserClient_RetriesAndOpensCircuit(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := clients.NewUserClient(srv.URL, testClientOptions())
+	if _, err := client.GetImages(context.Background(), []string{"a"}); err == nil {
+		t.Fatal("expected an error after retries")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+
+	_, err := client.GetImages(context.Background(), []string{"a"})
+	if !errors.Is(err, svcclient.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+}
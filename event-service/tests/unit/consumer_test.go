@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"event-service/internal/consumer"
+	"shared/eventbus"
+	"testing"
+)
+
+// ─── DomainEventConsumer ─────────────────────────────────────────────────────
+
+func publishDomainEvent(t *testing.T, bus *eventbus.Memory, eventType string, payload interface{}) {
+	t.Helper()
+	data, _ := json.Marshal(payload)
+	if err := bus.Publish(context.Background(), eventbus.Event{Type: eventType, Payload: data}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestDomainEventConsumer_ApplicationAcceptedDeactivates(t *testing.T) {
+	repo := newMockEventRepo()
+	repo.events[1] = newEvent(1, 1, "Event", true, false)
+	bus := eventbus.NewMemory()
+	bus.Handle(consumer.Group, consumer.NewDomainEventConsumer(repo).Handle)
+
+	publishDomainEvent(t, bus, eventbus.ApplicationAccepted, eventbus.ApplicationAcceptedPayload{EventID: 1})
+	// Повторная доставка не меняет результат
+	publishDomainEvent(t, bus, eventbus.ApplicationAccepted, eventbus.ApplicationAcceptedPayload{EventID: 1})
+
+	if repo.events[1].IsActive {
+		t.Error("expected event to be deactivated")
+	}
+}
+
+func TestDomainEventConsumer_CollaborationCompletedMarksEvent(t *testing.T) {
+	repo := newMockEventRepo()
+	repo.events[1] = newEvent(1, 1, "Event", false, false)
+	bus := eventbus.NewMemory()
+	bus.Handle(consumer.Group, consumer.NewDomainEventConsumer(repo).Handle)
+
+	publishDomainEvent(t, bus, eventbus.CollaborationCompleted, eventbus.CollaborationCompletedPayload{EventID: 1})
+
+	if !repo.events[1].IsCompleted {
+		t.Error("expected event to be completed")
+	}
+}
+
+func TestDomainEventConsumer_IgnoresUnknownAndMalformed(t *testing.T) {
+	repo := newMockEventRepo()
+	repo.events[1] = newEvent(1, 1, "Event", true, false)
+	c := consumer.NewDomainEventConsumer(repo)
+
+	if err := c.Handle(context.Background(), eventbus.Event{Type: eventbus.EventPublished, Payload: []byte(`{}`)}); err != nil {
+		t.Errorf("expected unknown event to be ignored, got %v", err)
+	}
+	if err := c.Handle(context.Background(), eventbus.Event{Type: eventbus.ApplicationAccepted, Payload: []byte(`not json`)}); err != nil {
+		t.Errorf("expected malformed event to be skipped, got %v", err)
+	}
+	if !repo.events[1].IsActive {
+		t.Error("expected event to stay active")
+	}
+}
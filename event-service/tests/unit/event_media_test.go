@@ -0,0 +1,171 @@
+package unit
+
+import (
+	"errors"
+	"event-service/internal/models"
+	"event-service/internal/service"
+	"testing"
+)
+
+func newMediaFixture() (*mockEventRepo, *service.EventService) {
+	repo := newMockEventRepo()
+	repo.events[1] = newEvent(1, 1, "Event", true, false)
+	users := newMockUserClient()
+	users.owners["photo-1"] = 1
+	users.owners["photo-2"] = 1
+	users.owners["poster"] = 1
+	users.owners["rider"] = 1
+	users.types["rider"] = "application/pdf"
+	users.owners["foreign"] = 2
+	return repo, service.NewEventService(repo, users)
+}
+
+func TestAddEventMedia_ReturnedWithEvent(t *testing.T) {
+	_, svc := newMediaFixture()
+
+	media, err := svc.AddEventMedia(1, 1, []service.EventMediaInput{
+		{ImageID: "poster", Type: models.MediaTypePoster},
+		{ImageID: "photo-1", Type: models.MediaTypePhoto, Caption: "Soundcheck"},
+		{ImageID: "rider", Type: models.MediaTypeRider, Caption: "Tech rider"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(media) != 3 || media[1].Position != 1 || media[2].Type != models.MediaTypeRider {
+		t.Fatalf("unexpected media: %+v", media)
+	}
+	if media[2].FileName != "rider.file" || media[2].FileType != "application/pdf" {
+		t.Errorf("expected file details from user-service to be stored, got %+v", media[2])
+	}
+
+	event, err := svc.GetEventByID(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(event.Media) != 3 || event.Media[0].ImageID != "poster" {
+		t.Errorf("expected media on event, got %+v", event.Media)
+	}
+}
+
+func TestAddEventMedia_OnlyEventCreator(t *testing.T) {
+	repo, svc := newMediaFixture()
+
+	_, err := svc.AddEventMedia(1, 2, []service.EventMediaInput{{ImageID: "foreign", Type: models.MediaTypePhoto}})
+	if !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied, got %v", err)
+	}
+	if len(repo.media[1]) != 0 {
+		t.Errorf("expected no media to be added, got %+v", repo.media[1])
+	}
+}
+
+func TestAddEventMedia_ChecksFiles(t *testing.T) {
+	cases := map[string]struct {
+		items []service.EventMediaInput
+		want  error
+	}{
+		"foreign image": {
+			items: []service.EventMediaInput{{ImageID: "foreign", Type: models.MediaTypePhoto}},
+			want:  service.ErrImageNotOwned,
+		},
+		"missing image": {
+			items: []service.EventMediaInput{{ImageID: "missing", Type: models.MediaTypePhoto}},
+			want:  service.ErrImageNotFound,
+		},
+		"rider is not pdf": {
+			items: []service.EventMediaInput{{ImageID: "photo-1", Type: models.MediaTypeRider}},
+			want:  service.ErrInvalidMediaFile,
+		},
+		"pdf as photo": {
+			items: []service.EventMediaInput{{ImageID: "rider", Type: models.MediaTypePhoto}},
+			want:  service.ErrInvalidMediaFile,
+		},
+		"same file twice": {
+			items: []service.EventMediaInput{
+				{ImageID: "photo-1", Type: models.MediaTypePhoto},
+				{ImageID: "photo-1", Type: models.MediaTypePhoto},
+			},
+			want: service.ErrDuplicateMedia,
+		},
+		"two posters": {
+			items: []service.EventMediaInput{
+				{ImageID: "photo-1", Type: models.MediaTypePoster},
+				{ImageID: "poster", Type: models.MediaTypePoster},
+			},
+			want: service.ErrPosterExists,
+		},
+	}
+	for name, tc := range cases {
+		repo, svc := newMediaFixture()
+		if _, err := svc.AddEventMedia(1, 1, tc.items); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, err)
+		}
+		if len(repo.media[1]) != 0 {
+			t.Errorf("%s: expected nothing to be added, got %+v", name, repo.media[1])
+		}
+	}
+}
+
+func TestAddEventMedia_UserServiceUnavailable(t *testing.T) {
+	repo := newMockEventRepo()
+	repo.events[1] = newEvent(1, 1, "Event", true, false)
+	users := newMockUserClient()
+	users.err = errors.New("connection refused")
+	svc := service.NewEventService(repo, users)
+
+	_, err := svc.AddEventMedia(1, 1, []service.EventMediaInput{{ImageID: "photo-1", Type: models.MediaTypePhoto}})
+	if !errors.Is(err, service.ErrDependencyUnavailable) {
+		t.Errorf("expected ErrDependencyUnavailable, got %v", err)
+	}
+	if len(repo.media[1]) != 0 {
+		t.Errorf("expected nothing to be added, got %+v", repo.media[1])
+	}
+}
+
+func TestAddEventMedia_SecondPosterRejected(t *testing.T) {
+	_, svc := newMediaFixture()
+	if _, err := svc.AddEventMedia(1, 1, []service.EventMediaInput{{ImageID: "poster", Type: models.MediaTypePoster}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err := svc.AddEventMedia(1, 1, []service.EventMediaInput{{ImageID: "photo-1", Type: models.MediaTypePoster}})
+	if !errors.Is(err, service.ErrPosterExists) {
+		t.Errorf("expected ErrPosterExists, got %v", err)
+	}
+}
+
+func TestReorderAndDeleteEventMedia(t *testing.T) {
+	_, svc := newMediaFixture()
+	media, err := svc.AddEventMedia(1, 1, []service.EventMediaInput{
+		{ImageID: "photo-1", Type: models.MediaTypePhoto},
+		{ImageID: "photo-2", Type: models.MediaTypePhoto},
+		{ImageID: "rider", Type: models.MediaTypeRider},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := svc.ReorderEventMedia(1, 1, []int{media[0].ID, media[1].ID}); !errors.Is(err, service.ErrInvalidMediaOrder) {
+		t.Errorf("expected ErrInvalidMediaOrder for partial order, got %v", err)
+	}
+
+	reordered, err := svc.ReorderEventMedia(1, 1, []int{media[2].ID, media[0].ID, media[1].ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reordered[0].ImageID != "rider" || reordered[2].ImageID != "photo-2" {
+		t.Errorf("unexpected order: %+v", reordered)
+	}
+
+	if err := svc.DeleteEventMedia(1, media[0].ID, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := svc.DeleteEventMedia(1, media[0].ID, 1); !errors.Is(err, service.ErrMediaNotFound) {
+		t.Errorf("expected ErrMediaNotFound, got %v", err)
+	}
+
+	event, _ := svc.GetEventByID(1)
+	if len(event.Media) != 2 || event.Media[1].ImageID != "photo-2" || event.Media[1].Position != 1 {
+		t.Errorf("expected positions to be compacted, got %+v", event.Media)
+	}
+}
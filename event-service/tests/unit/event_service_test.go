@@ -4,14 +4,16 @@ import (
 	"errors"
 	"event-service/internal/models"
 	"event-service/internal/service"
+	"shared/pagination"
 	"testing"
+	"time"
 )
 
 // ─── CreateEvent ──────────────────────────────────────────────────────────────
 
 func TestCreateEvent_Success(t *testing.T) {
 	repo := newMockEventRepo()
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	event, err := svc.CreateEvent(&service.CreateEventRequest{Title: "My Event"}, 1)
 	if err != nil {
@@ -27,7 +29,7 @@ func TestCreateEvent_Success(t *testing.T) {
 
 func TestCreateEvent_WithCategories(t *testing.T) {
 	repo := newMockEventRepo()
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	event, err := svc.CreateEvent(
 		&service.CreateEventRequest{Title: "Event", CategoryIDs: []int{1, 2, 3}},
@@ -44,7 +46,7 @@ func TestCreateEvent_WithCategories(t *testing.T) {
 func TestCreateEvent_RepoError(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.errCreate = errors.New("db error")
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	_, err := svc.CreateEvent(&service.CreateEventRequest{Title: "Event"}, 1)
 	if err == nil {
@@ -52,12 +54,40 @@ func TestCreateEvent_RepoError(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_CoverImageOwnership(t *testing.T) {
+	repo := newMockEventRepo()
+	users := newMockUserClient()
+	users.owners["own"] = 1
+	users.owners["foreign"] = 2
+	svc := service.NewEventService(repo, users)
+
+	own := "own"
+	if _, err := svc.CreateEvent(&service.CreateEventRequest{Title: "Event", CoverPhotoID: &own}, 1); err != nil {
+		t.Fatalf("expected own cover to be accepted, got %v", err)
+	}
+
+	foreign := "foreign"
+	if _, err := svc.CreateEvent(&service.CreateEventRequest{Title: "Event", CoverPhotoID: &foreign}, 1); !errors.Is(err, service.ErrImageNotOwned) {
+		t.Errorf("expected ErrImageNotOwned, got %v", err)
+	}
+
+	missing := "missing"
+	if _, err := svc.CreateEvent(&service.CreateEventRequest{Title: "Event", CoverPhotoID: &missing}, 1); !errors.Is(err, service.ErrImageNotFound) {
+		t.Errorf("expected ErrImageNotFound, got %v", err)
+	}
+
+	users.err = errors.New("connection refused")
+	if _, err := svc.CreateEvent(&service.CreateEventRequest{Title: "Event", CoverPhotoID: &own}, 1); !errors.Is(err, service.ErrDependencyUnavailable) {
+		t.Errorf("expected ErrDependencyUnavailable, got %v", err)
+	}
+}
+
 // ─── GetEventByID ─────────────────────────────────────────────────────────────
 
 func TestGetEventByID_Success(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Test", true, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	event, err := svc.GetEventByID(1)
 	if err != nil {
@@ -70,11 +100,22 @@ func TestGetEventByID_Success(t *testing.T) {
 
 func TestGetEventByID_NotFound(t *testing.T) {
 	repo := newMockEventRepo()
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	_, err := svc.GetEventByID(999)
-	if err == nil {
-		t.Fatal("expected error, got nil")
+	if !errors.Is(err, service.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestGetEventByID_DatabaseErrorIsNotNotFound(t *testing.T) {
+	repo := newMockEventRepo()
+	repo.errGetByID = errors.New("connection refused")
+	svc := service.NewEventService(repo, newMockUserClient())
+
+	_, err := svc.GetEventByID(1)
+	if err == nil || errors.Is(err, service.ErrEventNotFound) {
+		t.Fatalf("expected database error to pass through, got %v", err)
 	}
 }
 
@@ -82,7 +123,7 @@ func TestGetEventByID_NotFound(t *testing.T) {
 
 func TestGetEventsByIDs_Empty(t *testing.T) {
 	repo := newMockEventRepo()
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	events, err := svc.GetEventsByIDs([]int{})
 	if err != nil {
@@ -97,7 +138,7 @@ func TestGetEventsByIDs_Success(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Event 1", true, false)
 	repo.events[2] = newEvent(2, 1, "Event 2", true, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	events, err := svc.GetEventsByIDs([]int{1, 2})
 	if err != nil {
@@ -113,7 +154,7 @@ func TestGetEventsByIDs_Success(t *testing.T) {
 func TestUpdateEvent_Success(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Old Title", true, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	newTitle := "New Title"
 	event, err := svc.UpdateEvent(1, &service.UpdateEventRequest{Title: &newTitle}, 1)
@@ -128,7 +169,7 @@ func TestUpdateEvent_Success(t *testing.T) {
 func TestUpdateEvent_AccessDenied(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Title", true, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	title := "Hack"
 	_, err := svc.UpdateEvent(1, &service.UpdateEventRequest{Title: &title}, 99)
@@ -139,7 +180,7 @@ func TestUpdateEvent_AccessDenied(t *testing.T) {
 
 func TestUpdateEvent_NotFound(t *testing.T) {
 	repo := newMockEventRepo()
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	title := "Title"
 	_, err := svc.UpdateEvent(999, &service.UpdateEventRequest{Title: &title}, 1)
@@ -148,12 +189,29 @@ func TestUpdateEvent_NotFound(t *testing.T) {
 	}
 }
 
+func TestUpdateEvent_ForeignCoverImage(t *testing.T) {
+	repo := newMockEventRepo()
+	repo.events[1] = newEvent(1, 1, "Title", true, false)
+	users := newMockUserClient()
+	users.owners["foreign"] = 2
+	svc := service.NewEventService(repo, users)
+
+	cover := "foreign"
+	_, err := svc.UpdateEvent(1, &service.UpdateEventRequest{CoverPhotoID: &cover}, 1)
+	if !errors.Is(err, service.ErrImageNotOwned) {
+		t.Errorf("expected ErrImageNotOwned, got %v", err)
+	}
+	if repo.events[1].CoverPhotoID != nil {
+		t.Error("expected cover to stay unchanged")
+	}
+}
+
 // ─── DeleteEvent ─────────────────────────────────────────────────────────────
 
 func TestDeleteEvent_Success(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Title", true, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	if err := svc.DeleteEvent(1, 1); err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -166,7 +224,7 @@ func TestDeleteEvent_Success(t *testing.T) {
 func TestDeleteEvent_AccessDenied(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Title", true, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	err := svc.DeleteEvent(1, 99)
 	if !errors.Is(err, service.ErrAccessDenied) {
@@ -178,19 +236,75 @@ func TestDeleteEvent_AccessDenied(t *testing.T) {
 
 func TestListEvents_LimitNormalized(t *testing.T) {
 	repo := newMockEventRepo()
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	// limit=0 → нормализуется в 20
-	_, err := svc.ListEvents(nil, nil, nil, nil, 0, 0)
+	page, err := svc.ListEvents(nil, nil, nil, nil, pagination.Params{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if page.Limit != 20 {
+		t.Errorf("expected limit 20, got %d", page.Limit)
+	}
 
-	// limit=200 → нормализуется в 20
-	_, err = svc.ListEvents(nil, nil, nil, nil, 200, 0)
+	// limit=200 → ограничивается 100
+	page, err = svc.ListEvents(nil, nil, nil, nil, pagination.Params{Limit: 200})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if page.Limit != 100 {
+		t.Errorf("expected limit 100, got %d", page.Limit)
+	}
+}
+
+func TestListEvents_CursorWalksAllPages(t *testing.T) {
+	repo := newMockEventRepo()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id := 1; id <= 5; id++ {
+		e := newEvent(id, 1, "Event", true, false)
+		// события 3–5 созданы одновременно — порядок между ними задает id
+		e.CreatedAt = base.Add(time.Duration(min(id, 3)) * time.Minute)
+		repo.events[id] = e
+	}
+	svc := service.NewEventService(repo, newMockUserClient())
+
+	var seen []int
+	params := pagination.Params{Limit: 2, WithTotal: true}
+	for {
+		page, err := svc.ListEvents(nil, nil, nil, nil, params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if page.Total == nil || *page.Total != 5 {
+			t.Fatalf("expected total 5, got %v", page.Total)
+		}
+		for _, e := range page.Items {
+			seen = append(seen, e.ID)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		params.After, err = pagination.Decode(*page.NextCursor)
+		if err != nil {
+			t.Fatalf("unexpected cursor error: %v", err)
+		}
+	}
+
+	want := []int{5, 4, 3, 2, 1}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, seen)
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	if _, err := pagination.Decode("not-a-cursor"); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
 }
 
 // ─── FavoritesService ────────────────────────────────────────────────────────
@@ -259,7 +373,7 @@ func TestRemoveFavoriteEvent_NotFound(t *testing.T) {
 func TestPublishEvent_Success(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Draft Event", false, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	if err := svc.PublishEvent(1, 1); err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -269,7 +383,7 @@ func TestPublishEvent_Success(t *testing.T) {
 func TestPublishEvent_WrongCreator(t *testing.T) {
 	repo := newMockEventRepo()
 	repo.events[1] = newEvent(1, 1, "Draft Event", false, false)
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	err := svc.PublishEvent(1, 99)
 	if err == nil {
@@ -313,9 +427,9 @@ func TestListFavoriteEvents_Empty(t *testing.T) {
 func TestListEvents_FilterByCreator(t *testing.T) {
 	repo := newMockEventRepo()
 	creatorID := 42
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
-	_, err := svc.ListEvents(&creatorID, nil, nil, nil, 10, 0)
+	_, err := svc.ListEvents(&creatorID, nil, nil, nil, pagination.Params{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -324,10 +438,10 @@ func TestListEvents_FilterByCreator(t *testing.T) {
 func TestListEvents_FilterByIsActive(t *testing.T) {
 	repo := newMockEventRepo()
 	isActive := true
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
 	// categoryID=nil, isActive=true
-	_, err := svc.ListEvents(nil, nil, &isActive, nil, 10, 0)
+	_, err := svc.ListEvents(nil, nil, &isActive, nil, pagination.Params{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -336,9 +450,9 @@ func TestListEvents_FilterByIsActive(t *testing.T) {
 func TestListEvents_FilterByIsCompleted(t *testing.T) {
 	repo := newMockEventRepo()
 	isCompleted := false
-	svc := service.NewEventService(repo)
+	svc := service.NewEventService(repo, newMockUserClient())
 
-	_, err := svc.ListEvents(nil, nil, nil, &isCompleted, 10, 0)
+	_, err := svc.ListEvents(nil, nil, nil, &isCompleted, pagination.Params{Limit: 10})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
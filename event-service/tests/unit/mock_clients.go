@@ -0,0 +1,39 @@
+package unit
+
+import (
+	"context"
+	"event-service/internal/clients"
+)
+
+// mockUserClient отдает изображения, как их вернул бы user-service
+type mockUserClient struct {
+	owners map[string]int    // imageID -> owner_user_id
+	types  map[string]string // imageID -> file_type, по умолчанию image/jpeg
+	err    error
+}
+
+func newMockUserClient() *mockUserClient {
+	return &mockUserClient{
+		owners: make(map[string]int),
+		types:  make(map[string]string),
+	}
+}
+
+func (m *mockUserClient) GetImages(ctx context.Context, ids []string) ([]clients.Image, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var images []clients.Image
+	for _, id := range ids {
+		owner, ok := m.owners[id]
+		if !ok {
+			continue
+		}
+		fileType := m.types[id]
+		if fileType == "" {
+			fileType = "image/jpeg"
+		}
+		images = append(images, clients.Image{ID: id, OwnerUserID: &owner, FileName: id + ".file", FileType: fileType})
+	}
+	return images, nil
+}
@@ -3,16 +3,22 @@ package unit
 import (
 	"errors"
 	"event-service/internal/models"
+	"event-service/internal/repository"
+	"shared/pagination"
+	"sort"
 
 	"gorm.io/gorm"
 )
 
-var errNotFound = errors.New("not found")
+// errNotFound — то же, что возвращает GORM для отсутствующей записи
+var errNotFound = gorm.ErrRecordNotFound
 
 type mockEventRepo struct {
 	events         map[int]*models.Event
 	categories     map[int][]int // eventID -> []categoryID
 	favorites      map[int][]int // venueUserID -> []eventID
+	media          map[int][]models.EventMedia
+	nextMediaID    int
 	nextID         int
 	errCreate      error
 	errGetByID     error
@@ -28,6 +34,8 @@ func newMockEventRepo() *mockEventRepo {
 		events:     make(map[int]*models.Event),
 		categories: make(map[int][]int),
 		favorites:  make(map[int][]int),
+		media:       make(map[int][]models.EventMedia),
+		nextMediaID: 1,
 		nextID:     1,
 	}
 }
@@ -65,7 +73,7 @@ func (m *mockEventRepo) GetEventsByIDs(ids []int) ([]models.Event, error) {
 	return result, nil
 }
 
-func (m *mockEventRepo) ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, limit, offset int) ([]models.Event, error) {
+func (m *mockEventRepo) ListEvents(creatorID *int, categoryID *int, isActive *bool, isCompleted *bool, page pagination.Params) ([]models.Event, *int64, error) {
 	var result []models.Event
 	for _, e := range m.events {
 		if creatorID != nil && e.CreatorID != *creatorID {
@@ -79,7 +87,24 @@ func (m *mockEventRepo) ListEvents(creatorID *int, categoryID *int, isActive *bo
 		}
 		result = append(result, *e)
 	}
-	return result, nil
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+
+	total := int64(len(result))
+	var pageItems []models.Event
+	for _, e := range result {
+		if page.Includes(e.CreatedAt, e.ID) && len(pageItems) <= page.Limit {
+			pageItems = append(pageItems, e)
+		}
+	}
+	if !page.WithTotal {
+		return pageItems, nil, nil
+	}
+	return pageItems, &total, nil
 }
 
 func (m *mockEventRepo) UpdateEvent(event *models.Event) error {
@@ -112,6 +137,20 @@ func (m *mockEventRepo) PublishEvent(id int, creatorID int) error {
 	return nil
 }
 
+func (m *mockEventRepo) DeactivateEvent(id int) error {
+	if e, ok := m.events[id]; ok {
+		e.IsActive = false
+	}
+	return nil
+}
+
+func (m *mockEventRepo) MarkEventCompleted(id int) error {
+	if e, ok := m.events[id]; ok {
+		e.IsCompleted = true
+	}
+	return nil
+}
+
 func (m *mockEventRepo) AddEventCategories(eventID int, categoryIDs []int) error {
 	m.categories[eventID] = categoryIDs
 	return nil
@@ -222,3 +261,89 @@ func newEvent(id, creatorID int, title string, isActive, isCompleted bool) *mode
 		IsCompleted: isCompleted,
 	}
 }
+
+func (m *mockEventRepo) GetEventMedia(eventID int) ([]models.EventMedia, error) {
+	return append([]models.EventMedia{}, m.media[eventID]...), nil
+}
+
+func (m *mockEventRepo) GetEventMediaByID(eventID, mediaID int) (*models.EventMedia, error) {
+	for _, item := range m.media[eventID] {
+		if item.ID == mediaID {
+			return &item, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockEventRepo) AddEventMedia(eventID int, media []models.EventMedia, maxMedia int) error {
+	current := m.media[eventID]
+	if len(current)+len(media) > maxMedia {
+		return repository.ErrMediaLimit
+	}
+	for _, item := range media {
+		for _, existing := range current {
+			if existing.ImageID == item.ImageID {
+				return repository.ErrMediaDuplicate
+			}
+			if existing.Type == models.MediaTypePoster && item.Type == models.MediaTypePoster {
+				return repository.ErrPosterExists
+			}
+		}
+	}
+	for i := range media {
+		media[i].ID = m.nextMediaID
+		m.nextMediaID++
+		media[i].EventID = eventID
+		media[i].Position = len(current) + i
+	}
+	m.media[eventID] = append(current, media...)
+	return nil
+}
+
+func (m *mockEventRepo) UpdateEventMediaCaption(eventID, mediaID int, caption string) error {
+	for i := range m.media[eventID] {
+		if m.media[eventID][i].ID == mediaID {
+			m.media[eventID][i].Caption = caption
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockEventRepo) ReorderEventMedia(eventID int, mediaIDs []int) error {
+	current := m.media[eventID]
+	if len(mediaIDs) != len(current) {
+		return repository.ErrMediaMismatch
+	}
+	byID := make(map[int]models.EventMedia, len(current))
+	for _, item := range current {
+		byID[item.ID] = item
+	}
+	reordered := make([]models.EventMedia, 0, len(current))
+	for position, id := range mediaIDs {
+		item, ok := byID[id]
+		if !ok {
+			return repository.ErrMediaMismatch
+		}
+		delete(byID, id)
+		item.Position = position
+		reordered = append(reordered, item)
+	}
+	m.media[eventID] = reordered
+	return nil
+}
+
+func (m *mockEventRepo) DeleteEventMedia(eventID, mediaID int) error {
+	current := m.media[eventID]
+	for i, item := range current {
+		if item.ID == mediaID {
+			rest := append(current[:i:i], current[i+1:]...)
+			for j := range rest {
+				rest[j].Position = j
+			}
+			m.media[eventID] = rest
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"strconv"
+
+	"gateway/internal/routing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProxyHandler проксирует запрос в upstream маршрута, найденного routing.Middleware.
+// Доступ к маршруту к этому моменту уже проверен AuthMiddleware.
+func ProxyHandler(upstreams *routing.Upstreams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, ok := routing.FromContext(c)
+		if !ok {
+			c.JSON(404, errResponse("NOT_FOUND", "Route not found"))
+			return
+		}
+
+		proxy, ok := upstreams.Proxy(route.Upstream)
+		if !ok {
+			c.JSON(503, errResponse("SERVICE_UNAVAILABLE", "Service not configured"))
+			return
+		}
+
+		c.Request.URL.Path = route.RewritePath(c.Request.URL.Path)
+		c.Request.URL.RawPath = ""
+
+		// Заголовки личности выставляет только gateway: присланные клиентом отбрасываются
+		c.Request.Header.Del("X-User-ID")
+		c.Request.Header.Del("X-User-Role")
+		if userID, exists := c.Get("user_id"); exists {
+			if id, ok := userID.(int); ok {
+				c.Request.Header.Set("X-User-ID", strconv.Itoa(id))
+			}
+		}
+		if role, exists := c.Get("role"); exists {
+			if roleStr, ok := role.(string); ok {
+				c.Request.Header.Set("X-User-Role", roleStr)
+			}
+		}
+
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+}
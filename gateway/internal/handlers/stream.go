@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"gateway/internal/realtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+const streamHeartbeat = 25 * time.Second
+
+// StreamTicketResponse — одноразовый билет на подключение к /api/stream
+type StreamTicketResponse struct {
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// StreamTicketHandler godoc
+// @Summary      Билет на подключение к realtime-стриму
+// @Description  Выдает одноразовый билет для GET /api/stream?ticket=; билет живет несколько секунд
+// @Tags         realtime
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201 {object} StreamTicketResponse
+// @Failure      401 {object} map[string]interface{}
+// @Router       /api/stream/ticket [post]
+func StreamTicketHandler(tickets *realtime.Tickets) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(401, errResponse("UNAUTHORIZED", "Unauthorized"))
+			return
+		}
+		role, _ := c.Get("role")
+		roleStr, _ := role.(string)
+
+		ticket, err := tickets.Issue(c.Request.Context(), userID.(int), roleStr)
+		if err != nil {
+			log.Printf("realtime: failed to issue ticket: %v", err)
+			c.JSON(503, errResponse("SERVICE_UNAVAILABLE", "Failed to issue stream ticket"))
+			return
+		}
+		c.JSON(201, StreamTicketResponse{Ticket: ticket, ExpiresIn: int(realtime.TicketTTL / time.Second)})
+	}
+}
+
+// StreamHandler — Server-Sent Events канал с событиями текущего пользователя:
+// изменения статусов заявок, новые сообщения в переписках, уведомления.
+// Браузерный EventSource не умеет слать заголовки, поэтому вместо access token
+// подключение открывается по одноразовому билету: ?ticket= из StreamTicketHandler.
+func StreamHandler(hub *realtime.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := c.Get("user_id")
+		if !ok {
+			c.JSON(401, errResponse("UNAUTHORIZED", "Unauthorized"))
+			return
+		}
+
+		// Общий WriteTimeout сервера оборвал бы долгоживущее соединение
+		rc := http.NewResponseController(c.Writer)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			c.JSON(500, errResponse("INTERNAL_ERROR", "Streaming not supported"))
+			return
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		sub := hub.Subscribe(userID.(int))
+		defer hub.Unsubscribe(sub)
+
+		fmt.Fprint(c.Writer, "retry: 5000\n\n")
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		ctx := c.Request.Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hub.Done():
+				return
+			case event := <-sub.Events:
+				fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, event.Payload)
+				c.Writer.Flush()
+			case <-heartbeat.C:
+				fmt.Fprint(c.Writer, ": ping\n\n")
+				c.Writer.Flush()
+			}
+		}
+	}
+}
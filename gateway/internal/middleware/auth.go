@@ -1,12 +1,14 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"strings"
 
+	"gateway/internal/routing"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -19,89 +21,77 @@ func errorResponse(code, message string) gin.H {
 	}
 }
 
-func AuthMiddleware(c *gin.Context) {
-	path := c.Request.URL.Path
-	method := c.Request.Method
-	if isPublicRoute(path, method) {
-		c.Next()
-		return
-	}
-
-	token := c.GetHeader("Authorization")
-	if token == "" {
-		c.JSON(401, errorResponse("UNAUTHORIZED", "Missing authorization token"))
-		c.Abort()
-		return
-	}
-
-	claims, err := validateToken(token)
-	if err != nil {
-		c.JSON(401, errorResponse("INVALID_TOKEN", "Invalid or expired token"))
-		c.Abort()
-		return
-	}
-
-	c.Set("user_id", claims.UserID)
-	c.Set("role", claims.Role)
-	c.Next()
+// TicketRedeemer погашает одноразовые билеты realtime-стрима
+type TicketRedeemer interface {
+	Redeem(ctx context.Context, ticket string) (int, string, error)
 }
 
-func isPublicRoute(path, method string) bool {
-	publicRoutes := map[string]bool{
-		"/health":             true,
-		"/swagger":            true,
-		"/swagger/index.html": true,
-	}
-
-	if publicRoutes[path] {
-		return true
-	}
-
-	allowedPrefixes := []string{
-		"/api/auth/",
-		"/api/user/auth/",
-		"/swagger/",
-		"/swagger-user/",
-		"/swagger-event/",
-		"/swagger-application/",
-	}
-
-	for _, prefix := range allowedPrefixes {
-		if strings.HasPrefix(path, prefix) {
-			return true
+// AuthMiddleware применяет политику доступа до проксирования: публичные запросы
+// пропускаются без токена, для остальных проверяются токен и роль. Запрос, не описанный
+// политикой в режиме deny, отклоняется, даже если у пользователя валидный токен.
+func AuthMiddleware(policy *routing.Policy, tickets TicketRedeemer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := policy.Decide(c.Request.URL.Path, c.Request.Method)
+		if !ok {
+			c.JSON(403, errorResponse("ACCESS_DENIED", "Access to this route is not allowed"))
+			c.Abort()
+			return
+		}
+		if rule.Public {
+			c.Next()
+			return
 		}
-	}
 
-	if path == "/api/auth" || path == "/api/user/auth" {
-		return true
-	}
+		token := c.GetHeader("Authorization")
+		// EventSource в браузере не передаёт заголовки — для таких маршрутов
+		// вместо токена принимается одноразовый билет из query-параметра
+		if token == "" && rule.Ticket {
+			if ticket := c.Query("ticket"); ticket != "" {
+				authorizeTicket(c, policy, rule, tickets, ticket)
+				return
+			}
+		}
+		if token == "" {
+			c.JSON(401, errorResponse("UNAUTHORIZED", "Missing authorization token"))
+			c.Abort()
+			return
+		}
 
-	// GET /api/event/categories публичные, POST/PUT/DELETE требуют токен (только admin)
-	if method == http.MethodGet && strings.HasPrefix(path, "/api/event/categories") {
-		return true
-	}
+		claims, err := validateToken(token)
+		if err != nil {
+			c.JSON(401, errorResponse("INVALID_TOKEN", "Invalid or expired token"))
+			c.Abort()
+			return
+		}
 
-	// Newsletter: subscribe и unsubscribe публичные
-	if path == "/api/user/newsletter/subscribe" || path == "/api/user/newsletter/unsubscribe" {
-		return true
-	}
+		// Сервисы доверяют X-User-Role, поэтому дальше проходят только известные роли
+		if !policy.KnownRole(claims.Role) || !rule.AllowsRole(claims.Role) {
+			c.JSON(403, errorResponse("ACCESS_DENIED", "Insufficient permissions"))
+			c.Abort()
+			return
+		}
 
-	// Публичные профили (без личных контактов)
-	if method == http.MethodGet && strings.HasPrefix(path, "/api/user/public/") {
-		return true
+		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Next()
 	}
+}
 
-	// Публичный каталог мероприятий (только is_active=true)
-	if method == http.MethodGet && strings.HasPrefix(path, "/api/event/public/") {
-		return true
+func authorizeTicket(c *gin.Context, policy *routing.Policy, rule *routing.Rule, tickets TicketRedeemer, ticket string) {
+	userID, role, err := tickets.Redeem(c.Request.Context(), ticket)
+	if err != nil {
+		c.JSON(401, errorResponse("INVALID_TICKET", "Invalid or expired ticket"))
+		c.Abort()
+		return
 	}
-
-	// Публичное получение изображений
-	if method == http.MethodGet && strings.HasPrefix(path, "/api/user/users/images/") {
-		return true
+	if !policy.KnownRole(role) || !rule.AllowsRole(role) {
+		c.JSON(403, errorResponse("ACCESS_DENIED", "Insufficient permissions"))
+		c.Abort()
+		return
 	}
-
-	return false
+	c.Set("user_id", userID)
+	c.Set("role", role)
+	c.Next()
 }
 
 func validateToken(tokenString string) (*Claims, error) {
@@ -4,6 +4,8 @@ import (
 	"strconv"
 	"time"
 
+	"gateway/internal/routing"
+
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -50,8 +52,12 @@ func PrometheusMiddleware(serviceName string) gin.HandlerFunc {
 		// Вычисляем длительность
 		duration := time.Since(start).Seconds()
 
-		// Получаем endpoint (путь без query параметров)
+		// Получаем endpoint (путь без query параметров). Проксируемые запросы
+		// группируются по маршруту из таблицы, а не по фактическому пути.
 		endpoint := c.FullPath()
+		if route, ok := routing.FromContext(c); ok && endpoint == "" {
+			endpoint = route.Path
+		}
 		if endpoint == "" {
 			endpoint = c.Request.URL.Path
 		}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"gateway/internal/routing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitTimeout — сколько запрос ждет Redis, прежде чем пройти без проверки лимита
+const rateLimitTimeout = 100 * time.Millisecond
+
+// fixedWindowScript увеличивает счетчик окна и возвращает его значение и остаток окна в мс.
+// PTTL < 0 бывает, если ключ остался без срока жизни — тогда окно начинается заново.
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// RateLimitMiddleware ограничивает частоту запросов по бюджетам из конфигурации.
+// Счетчики лежат в Redis и общие для всех экземпляров gateway; аутентифицированные
+// запросы считаются по пользователю, анонимные — по IP клиента, поэтому middleware
+// должен стоять после AuthMiddleware. Если Redis недоступен, запрос пропускается:
+// лимиты не должны останавливать весь API.
+func RateLimitMiddleware(limits *routing.RateLimits, client *redis.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, ok := limits.Match(c.Request.URL.Path, c.Request.Method)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		subject := "ip:" + c.ClientIP()
+		if userID, exists := c.Get("user_id"); exists {
+			if id, ok := userID.(int); ok {
+				subject = "user:" + strconv.Itoa(id)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), rateLimitTimeout)
+		defer cancel()
+		res, err := fixedWindowScript.Run(ctx, client,
+			[]string{"ratelimit:" + limit.Key + ":" + subject},
+			limit.Window.Milliseconds(),
+		).Int64Slice()
+		if err != nil || len(res) != 2 {
+			log.Printf("ratelimit: check skipped for %s: %v", limit.Key, err)
+			c.Next()
+			return
+		}
+		count, ttl := res[0], time.Duration(res[1])*time.Millisecond
+
+		// Секунды округляем вверх, чтобы клиент не повторил запрос до конца окна
+		reset := int64((ttl + time.Second - 1) / time.Second)
+		remaining := int64(limit.Limit) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		h := c.Writer.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(limit.Limit))
+		h.Set("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		h.Set("RateLimit-Reset", strconv.FormatInt(reset, 10))
+		h.Set("RateLimit-Policy", strconv.Itoa(limit.Limit)+";w="+strconv.FormatInt(int64(limit.Window/time.Second), 10))
+
+		if count > int64(limit.Limit) {
+			h.Set("Retry-After", strconv.FormatInt(reset, 10))
+			c.JSON(429, errorResponse("RATE_LIMIT_EXCEEDED", "Too many requests, try again later"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
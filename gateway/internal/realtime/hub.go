@@ -0,0 +1,128 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ChannelPrefix — сервисы публикуют события пользователя в канал realtime:user:<id>
+const ChannelPrefix = "realtime:user:"
+
+// subscriberBuffer — сколько событий может накопиться у медленного клиента,
+// прежде чем новые начнут отбрасываться
+const subscriberBuffer = 32
+
+// Event — событие для отправки клиенту. Payload — исходный JSON-конверт
+// от сервиса ({"type": ..., "data": ..., "created_at": ...}).
+type Event struct {
+	Type    string
+	Payload []byte
+}
+
+type Subscriber struct {
+	userID int
+	Events chan Event
+}
+
+// Hub держит одну pattern-подписку на Redis и раздаёт события
+// по локальным SSE-подключениям конкретного пользователя.
+type Hub struct {
+	rdb         *redis.Client
+	mu          sync.RWMutex
+	subscribers map[int]map[*Subscriber]struct{}
+	done        chan struct{}
+}
+
+func NewHub(rdb *redis.Client) *Hub {
+	return &Hub{
+		rdb:         rdb,
+		subscribers: make(map[int]map[*Subscriber]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Run слушает Redis до отмены контекста. go-redis сам переподключается
+// и восстанавливает подписку при обрыве соединения.
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+
+	pubsub := h.rdb.PSubscribe(ctx, ChannelPrefix+"*")
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			h.dispatch(msg)
+		}
+	}
+}
+
+// Done закрывается, когда hub остановлен; открытые стримы должны завершиться.
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+func (h *Hub) dispatch(msg *redis.Message) {
+	userID, err := strconv.Atoi(strings.TrimPrefix(msg.Channel, ChannelPrefix))
+	if err != nil {
+		log.Printf("realtime: unexpected channel %q", msg.Channel)
+		return
+	}
+
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil || envelope.Type == "" {
+		log.Printf("realtime: malformed event on %q: %v", msg.Channel, err)
+		return
+	}
+
+	event := Event{Type: envelope.Type, Payload: []byte(msg.Payload)}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub.Events <- event:
+		default:
+			log.Printf("realtime: dropping %s for user %d, client is too slow", event.Type, userID)
+		}
+	}
+}
+
+func (h *Hub) Subscribe(userID int) *Subscriber {
+	sub := &Subscriber{userID: userID, Events: make(chan Event, subscriberBuffer)}
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*Subscriber]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	activeStreams.Inc()
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	delete(h.subscribers[sub.userID], sub)
+	if len(h.subscribers[sub.userID]) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+	h.mu.Unlock()
+
+	activeStreams.Dec()
+}
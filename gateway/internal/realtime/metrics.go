@@ -0,0 +1,13 @@
+package realtime
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var activeStreams = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "gateway_realtime_streams",
+		Help: "Current number of open realtime (SSE) connections",
+	},
+)
@@ -0,0 +1,64 @@
+package realtime
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TicketTTL — сколько живет билет на подключение к стриму: клиент получает его
+// и сразу открывает EventSource
+const TicketTTL = 30 * time.Second
+
+const ticketKeyPrefix = "realtime:ticket:"
+
+var ErrInvalidTicket = errors.New("ticket is invalid or expired")
+
+// Tickets выдает одноразовые билеты для SSE. EventSource не умеет слать заголовки,
+// а access token в URL попадал бы в логи nginx и gateway; билет в URL безопасен —
+// он одноразовый, живет секунды и ни на что, кроме стрима, не годится.
+type Tickets struct {
+	rdb *redis.Client
+}
+
+func NewTickets(rdb *redis.Client) *Tickets {
+	return &Tickets{rdb: rdb}
+}
+
+// Issue выдает билет пользователю
+func (t *Tickets) Issue(ctx context.Context, userID int, role string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	ticket := hex.EncodeToString(buf)
+	value := strconv.Itoa(userID) + ":" + role
+	if err := t.rdb.Set(ctx, ticketKeyPrefix+ticket, value, TicketTTL).Err(); err != nil {
+		return "", fmt.Errorf("failed to store ticket: %v", err)
+	}
+	return ticket, nil
+}
+
+// Redeem погашает билет и возвращает его владельца; повторно билет не принимается
+func (t *Tickets) Redeem(ctx context.Context, ticket string) (int, string, error) {
+	value, err := t.rdb.GetDel(ctx, ticketKeyPrefix+ticket).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, "", ErrInvalidTicket
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	idStr, role, ok := strings.Cut(value, ":")
+	userID, err := strconv.Atoi(idStr)
+	if !ok || err != nil {
+		return 0, "", ErrInvalidTicket
+	}
+	return userID, role, nil
+}
@@ -0,0 +1,91 @@
+package routing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultRoutes — маршруты и политика доступа по умолчанию; заменяются файлом из GATEWAY_ROUTES_FILE
+//
+//go:embed routes.json
+var defaultRoutes []byte
+
+// Config — описание upstream-сервисов, таблица маршрутов, политика доступа и
+// лимиты запросов gateway
+type Config struct {
+	Upstreams  map[string]UpstreamConfig `json:"upstreams"`
+	Routes     []Route                   `json:"routes"`
+	Policy     PolicyConfig              `json:"policy"`
+	RateLimits RateLimitConfig           `json:"rate_limits"`
+}
+
+// UpstreamConfig — сервис, на который проксируются запросы. Адрес берется из
+// переменной окружения URLEnv, чтобы одна таблица подходила всем окружениям.
+type UpstreamConfig struct {
+	URLEnv                string   `json:"url_env"`
+	ResponseHeaderTimeout Duration `json:"response_header_timeout"`
+	IdleConnTimeout       Duration `json:"idle_conn_timeout"`
+	MaxIdleConnsPerHost   int      `json:"max_idle_conns_per_host"`
+}
+
+// Duration — time.Duration, записанная в JSON строкой ("10s", "1m30s")
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %v", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Load читает маршруты и политику доступа из файла path, а если путь не задан — встроенные
+func Load(path string) (*Config, error) {
+	data := defaultRoutes
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read routes file: %v", err)
+		}
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse routes: %v", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for name, u := range c.Upstreams {
+		if u.URLEnv == "" {
+			return fmt.Errorf("upstream %q: url_env is required", name)
+		}
+	}
+	for i := range c.Routes {
+		r := &c.Routes[i]
+		if !strings.HasPrefix(r.Path, "/") {
+			return fmt.Errorf("route %d: path must start with /", i)
+		}
+		if _, ok := c.Upstreams[r.Upstream]; !ok {
+			return fmt.Errorf("route %s: unknown upstream %q", r.Path, r.Upstream)
+		}
+		upperMethods(r.Methods)
+	}
+	if err := c.Policy.validate(); err != nil {
+		return err
+	}
+	return c.RateLimits.validate()
+}
@@ -0,0 +1,165 @@
+package routing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultDeny — запрос, не описанный ни одним правилом, отклоняется
+	DefaultDeny = "deny"
+	// DefaultAuthenticated — неописанный запрос доступен любому аутентифицированному пользователю
+	DefaultAuthenticated = "authenticated"
+)
+
+// PolicyConfig — политика доступа gateway: правила проверяются по порядку,
+// применяется первое совпавшее. Это единственное место, где проверяются роли:
+// сервисы доступны только через gateway и доверяют X-User-Role, не перепроверяя его.
+type PolicyConfig struct {
+	Default string `json:"default"`
+	// Roles — роли, которые gateway признает; токен с другой ролью отклоняется,
+	// поэтому сервисы получают в X-User-Role только известные значения
+	Roles []string `json:"roles"`
+	Rules []Rule   `json:"rules"`
+}
+
+// Rule — правило доступа.
+//
+// Pattern — путь запроса по сегментам: "*" совпадает с одним сегментом, "**" в конце —
+// с любым числом сегментов, включая ноль. "/api/event/events/*" совпадает с
+// "/api/event/events/42", но не с "/api/event/events/42/media".
+type Rule struct {
+	Pattern string `json:"pattern"`
+	// Methods — методы, к которым относится правило; пусто — любые
+	Methods []string `json:"methods,omitempty"`
+	// Public — запрос пропускается без access token
+	Public bool `json:"public,omitempty"`
+	// Ticket — вместо access token принимается одноразовый билет ?ticket=
+	// (EventSource не шлет заголовки, а токен в URL попал бы в логи)
+	Ticket bool `json:"ticket,omitempty"`
+	// Roles — кому доступен запрос; пусто — любому аутентифицированному пользователю
+	Roles []string `json:"roles,omitempty"`
+
+	segments []string
+}
+
+// AllowsRole сообщает, разрешен ли запрос пользователю с ролью role
+func (r *Rule) AllowsRole(role string) bool {
+	return len(r.Roles) == 0 || slices.Contains(r.Roles, role)
+}
+
+func (r *Rule) matches(segments []string, method string) bool {
+	return methodAllowed(r.Methods, method) && matchSegments(r.segments, segments)
+}
+
+// Policy — скомпилированная политика доступа
+type Policy struct {
+	deny  bool
+	roles []string
+	rules []Rule
+}
+
+// authenticatedRule применяется к неописанным запросам в режиме DefaultAuthenticated
+var authenticatedRule = Rule{}
+
+func NewPolicy(cfg PolicyConfig) *Policy {
+	return &Policy{
+		deny:  cfg.Default != DefaultAuthenticated,
+		roles: cfg.Roles,
+		rules: cfg.Rules,
+	}
+}
+
+// Decide возвращает правило для запроса; false — запрос не описан политикой
+// и должен быть отклонен
+func (p *Policy) Decide(path, method string) (*Rule, bool) {
+	segments := splitPath(path)
+	for i := range p.rules {
+		if p.rules[i].matches(segments, method) {
+			return &p.rules[i], true
+		}
+	}
+	if p.deny {
+		return nil, false
+	}
+	return &authenticatedRule, true
+}
+
+// KnownRole сообщает, признает ли gateway роль из токена
+func (p *Policy) KnownRole(role string) bool {
+	return slices.Contains(p.roles, role)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// compilePattern разбивает шаблон пути на сегменты и проверяет, что "**" стоит в конце
+func compilePattern(pattern string) ([]string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("pattern %q must start with /", pattern)
+	}
+	segments := splitPath(pattern)
+	for i, s := range segments {
+		if s == "**" && i != len(segments)-1 {
+			return nil, fmt.Errorf("pattern %s: ** is allowed only at the end", pattern)
+		}
+	}
+	return segments, nil
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for i, s := range pattern {
+		if s == "**" {
+			return true
+		}
+		if i >= len(segments) || (s != "*" && s != segments[i]) {
+			return false
+		}
+	}
+	return len(segments) == len(pattern)
+}
+
+func methodAllowed(methods []string, method string) bool {
+	return len(methods) == 0 || slices.Contains(methods, method)
+}
+
+func upperMethods(methods []string) {
+	for i, m := range methods {
+		methods[i] = strings.ToUpper(m)
+	}
+}
+
+func (c *PolicyConfig) validate() error {
+	switch c.Default {
+	case DefaultDeny, DefaultAuthenticated:
+	default:
+		return fmt.Errorf("policy: default must be %q or %q", DefaultDeny, DefaultAuthenticated)
+	}
+	if len(c.Roles) == 0 {
+		return fmt.Errorf("policy: roles are required")
+	}
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		segments, err := compilePattern(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("policy rule %d: %v", i, err)
+		}
+		r.segments = segments
+		if r.Public && len(r.Roles) > 0 {
+			return fmt.Errorf("policy rule %s: public rule cannot restrict roles", r.Pattern)
+		}
+		for _, role := range r.Roles {
+			if !slices.Contains(c.Roles, role) {
+				return fmt.Errorf("policy rule %s: unknown role %q", r.Pattern, role)
+			}
+		}
+		upperMethods(r.Methods)
+	}
+	return nil
+}
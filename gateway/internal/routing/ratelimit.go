@@ -0,0 +1,98 @@
+package routing
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitConfig — лимиты запросов: именованные бюджеты и правила, по которым
+// запрос относится к бюджету. Правила проверяются по порядку, применяется первое
+// совпавшее; запрос без правила или с правилом без бюджета не ограничивается.
+type RateLimitConfig struct {
+	Budgets map[string]Budget `json:"budgets"`
+	Rules   []RateLimitRule   `json:"rules"`
+}
+
+// Budget — сколько запросов разрешено за окно. Счетчик ведется отдельно для каждого
+// пользователя (анонимных — по IP) и каждого правила.
+type Budget struct {
+	Limit  int      `json:"limit"`
+	Window Duration `json:"window"`
+}
+
+// RateLimitRule относит запросы к бюджету; шаблон пути — как в правилах доступа
+type RateLimitRule struct {
+	Pattern string   `json:"pattern"`
+	Methods []string `json:"methods,omitempty"`
+	Budget  string   `json:"budget,omitempty"`
+
+	segments []string
+}
+
+// Limit — бюджет, который применяется к запросу
+type Limit struct {
+	// Key различает счетчики правил; в него входят бюджет и шаблон правила
+	Key    string
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimits — скомпилированные лимиты запросов
+type RateLimits struct {
+	rules  []RateLimitRule
+	limits []Limit
+}
+
+func NewRateLimits(cfg RateLimitConfig) *RateLimits {
+	limits := make([]Limit, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		if r.Budget == "" {
+			continue
+		}
+		b := cfg.Budgets[r.Budget]
+		limits[i] = Limit{
+			Key:    r.Budget + ":" + r.Pattern,
+			Limit:  b.Limit,
+			Window: time.Duration(b.Window),
+		}
+	}
+	return &RateLimits{rules: cfg.Rules, limits: limits}
+}
+
+// Match возвращает лимит для запроса; false — запрос не ограничивается
+func (l *RateLimits) Match(path, method string) (*Limit, bool) {
+	segments := splitPath(path)
+	for i := range l.rules {
+		r := &l.rules[i]
+		if methodAllowed(r.Methods, method) && matchSegments(r.segments, segments) {
+			if r.Budget == "" {
+				return nil, false
+			}
+			return &l.limits[i], true
+		}
+	}
+	return nil, false
+}
+
+func (c *RateLimitConfig) validate() error {
+	for name, b := range c.Budgets {
+		if b.Limit <= 0 || time.Duration(b.Window) < time.Second {
+			return fmt.Errorf("rate limit budget %q: limit must be positive and window at least 1s", name)
+		}
+	}
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		segments, err := compilePattern(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("rate limit rule %d: %v", i, err)
+		}
+		r.segments = segments
+		if r.Budget != "" {
+			if _, ok := c.Budgets[r.Budget]; !ok {
+				return fmt.Errorf("rate limit rule %s: unknown budget %q", r.Pattern, r.Budget)
+			}
+		}
+		upperMethods(r.Methods)
+	}
+	return nil
+}
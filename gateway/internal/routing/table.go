@@ -0,0 +1,89 @@
+package routing
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contextKey — ключ, под которым найденный маршрут лежит в gin.Context
+const contextKey = "route"
+
+// Route — запись таблицы маршрутов.
+//
+// Path — префикс пути: "/api/user/" совпадает со всем, что начинается с него,
+// "/api/auth/logout" — с самим путем и путями под ним, но не с "/api/auth/logout-all".
+// Таблица отвечает только за то, куда проксировать запрос; кому он доступен,
+// решает политика доступа (Policy).
+type Route struct {
+	Path     string `json:"path"`
+	Upstream string `json:"upstream"`
+	// Rewrite заменяет совпавший префикс Path; пусто — путь не меняется
+	Rewrite string `json:"rewrite,omitempty"`
+	// Methods — допустимые методы; пусто — любые
+	Methods []string `json:"methods,omitempty"`
+}
+
+// RewritePath возвращает путь запроса к upstream
+func (r *Route) RewritePath(path string) string {
+	if r.Rewrite == "" {
+		return path
+	}
+	return r.Rewrite + strings.TrimPrefix(path, r.Path)
+}
+
+func (r *Route) matches(path, method string) bool {
+	if len(r.Methods) > 0 && !slices.Contains(r.Methods, method) {
+		return false
+	}
+	if strings.HasSuffix(r.Path, "/") {
+		return strings.HasPrefix(path, r.Path)
+	}
+	return path == r.Path || strings.HasPrefix(path, r.Path+"/")
+}
+
+// Table — таблица маршрутов, упорядоченная от более длинных префиксов к коротким
+type Table struct {
+	routes []Route
+}
+
+func NewTable(routes []Route) *Table {
+	sorted := slices.Clone(routes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Path) > len(sorted[j].Path)
+	})
+	return &Table{routes: sorted}
+}
+
+// Match возвращает самый конкретный маршрут для пути и метода. Если префикс совпал,
+// но метод не разрешен, поиск продолжается среди более общих маршрутов.
+func (t *Table) Match(path, method string) (*Route, bool) {
+	for i := range t.routes {
+		if t.routes[i].matches(path, method) {
+			return &t.routes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Middleware находит маршрут запроса и кладет его в контекст для метрик и прокси
+func (t *Table) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if route, ok := t.Match(c.Request.URL.Path, c.Request.Method); ok {
+			c.Set(contextKey, route)
+		}
+		c.Next()
+	}
+}
+
+// FromContext возвращает маршрут, найденный Middleware
+func FromContext(c *gin.Context) (*Route, bool) {
+	value, ok := c.Get(contextKey)
+	if !ok {
+		return nil, false
+	}
+	route, ok := value.(*Route)
+	return route, ok
+}
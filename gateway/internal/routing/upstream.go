@@ -0,0 +1,87 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	defaultResponseHeaderTimeout = 10 * time.Second
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultMaxIdleConnsPerHost   = 32
+)
+
+// Upstreams — по одному долгоживущему reverse proxy (и одному пулу соединений) на сервис
+type Upstreams struct {
+	proxies map[string]*httputil.ReverseProxy
+}
+
+// NewUpstreams создает прокси для всех upstream из конфигурации. Сервис без адреса
+// в окружении не мешает старту gateway: его маршруты отвечают 503.
+func NewUpstreams(cfg *Config) (*Upstreams, error) {
+	proxies := make(map[string]*httputil.ReverseProxy, len(cfg.Upstreams))
+	for name, u := range cfg.Upstreams {
+		rawURL := os.Getenv(u.URLEnv)
+		if rawURL == "" {
+			log.Printf("routing: upstream %s is not configured (%s is empty)", name, u.URLEnv)
+			continue
+		}
+		target, err := url.Parse(rawURL)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return nil, fmt.Errorf("upstream %s: invalid %s %q", name, u.URLEnv, rawURL)
+		}
+		proxies[name] = newProxy(target, u)
+	}
+	return &Upstreams{proxies: proxies}, nil
+}
+
+// Proxy возвращает прокси сервиса; false — сервис не настроен
+func (u *Upstreams) Proxy(name string) (*httputil.ReverseProxy, bool) {
+	proxy, ok := u.proxies[name]
+	return proxy, ok
+}
+
+func newProxy(target *url.URL, cfg UpstreamConfig) *httputil.ReverseProxy {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = durationOr(cfg.ResponseHeaderTimeout, defaultResponseHeaderTimeout)
+	transport.IdleConnTimeout = durationOr(cfg.IdleConnTimeout, defaultIdleConnTimeout)
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	return &httputil.ReverseProxy{
+		Transport: transport,
+		// Путь уже переписан по таблице маршрутов, здесь меняется только хост
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Host = pr.In.Host
+			// Цепочку от nginx сохраняем, SetXForwarded допишет адрес клиента в конец
+			pr.Out.Header["X-Forwarded-For"] = pr.In.Header["X-Forwarded-For"]
+			pr.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("routing: %s %s via %s failed: %v", r.Method, r.URL.Path, target.Host, err)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]any{
+				"errors": []map[string]string{
+					{"code": "SERVICE_UNAVAILABLE", "message": "Service temporarily unavailable"},
+				},
+			})
+		},
+	}
+}
+
+func durationOr(d Duration, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return time.Duration(d)
+	}
+	return fallback
+}
@@ -7,14 +7,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"gateway/internal/handlers"
 	"gateway/internal/middleware"
+	"gateway/internal/realtime"
+	"gateway/internal/routing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
@@ -52,13 +56,64 @@ func main() {
 		docs.SwaggerInfo.Host = apiHost
 	}
 
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis:6379"
+	}
+	redisClient := redis.NewClient(&redis.Options{Addr: redisURL})
+	defer redisClient.Close()
+
+	// Redis недоступен — gateway всё равно стартует, realtime-канал
+	// заработает после переподключения
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Printf("Redis is not reachable yet: %v", err)
+	}
+
+	hubCtx, stopHub := context.WithCancel(context.Background())
+	defer stopHub()
+	hub := realtime.NewHub(redisClient)
+	go hub.Run(hubCtx)
+	tickets := realtime.NewTickets(redisClient)
+
+	// Таблица маршрутов (upstream, переписывание пути, методы), политика доступа и лимиты
+	routesCfg, err := routing.Load(os.Getenv("GATEWAY_ROUTES_FILE"))
+	if err != nil {
+		log.Fatalf("Failed to load routes: %v", err)
+	}
+	routes := routing.NewTable(routesCfg.Routes)
+	policy := routing.NewPolicy(routesCfg.Policy)
+	rateLimits := routing.NewRateLimits(routesCfg.RateLimits)
+	upstreams, err := routing.NewUpstreams(routesCfg)
+	if err != nil {
+		log.Fatalf("Failed to configure upstreams: %v", err)
+	}
+
 	r := gin.Default()
+	// Адрес клиента для лимитов берем из X-Real-IP, который nginx перезаписывает
+	// сам; X-Forwarded-For клиент может подделать. Заголовку верим, только если
+	// запрос пришел от nginx: TRUSTED_PROXIES — его адреса или подсети через запятую.
+	// Без них адресом клиента считается адрес соединения.
+	r.RemoteIPHeaders = []string{"X-Real-IP"}
+	var trustedProxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			trustedProxies = append(trustedProxies, p)
+		}
+	}
+	if len(trustedProxies) == 0 {
+		log.Println("TRUSTED_PROXIES is not set, X-Real-IP is ignored and clients are limited by connection address")
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(middleware.PrometheusMiddleware("gateway"))
 	r.Use(middleware.CORSMiddleware)
-	r.Use(middleware.AuthMiddleware)
+	r.Use(routes.Middleware())
+	r.Use(middleware.AuthMiddleware(policy, tickets))
+	r.Use(middleware.RateLimitMiddleware(rateLimits, redisClient))
 
 	// Prometheus metrics endpoint
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -69,18 +124,14 @@ func main() {
 
 	r.GET("/swagger-user/*any", handlers.UserSwaggerHandler)
 	r.GET("/swagger-event/*any", handlers.EventSwaggerHandler)
-	r.GET("/swagger-application/*any", handlers.ApplicationSwaggerHandler)
-
-	// Auth routes (публичные - не требуют access token)
-	r.POST("/api/auth/refresh", handlers.RefreshTokenHandler)
-	r.POST("/api/auth/logout", handlers.LogoutHandler)
+	// /swagger-application/ отдается через upstream application по таблице маршрутов
 
-	// Protected auth route (требует access token)
-	r.POST("/api/auth/logout-all", handlers.LogoutAllHandler)
+	// Realtime-события пользователя (SSE) и билеты на подключение к ним
+	r.GET("/api/stream", handlers.StreamHandler(hub))
+	r.POST("/api/stream/ticket", handlers.StreamTicketHandler(tickets))
 
-	r.Any("/api/user/*path", handlers.UserHandler)
-	r.Any("/api/event/*path", handlers.EventHandler)
-	r.Any("/api/application/*path", handlers.ApplicationHandler)
+	// Все остальное проксируется по таблице маршрутов
+	r.NoRoute(handlers.ProxyHandler(upstreams))
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -95,6 +146,8 @@ func main() {
 		IdleTimeout:    60 * time.Second,
 		MaxHeaderBytes: 1 << 20,
 	}
+	// Останавливаем hub при Shutdown, чтобы открытые SSE-соединения завершились
+	srv.RegisterOnShutdown(stopHub)
 
 	go func() {
 		log.Printf("Starting gateway on port %s", port)
@@ -0,0 +1,195 @@
+package unit
+
+import (
+	"testing"
+
+	"gateway/internal/routing"
+)
+
+// loadPolicy компилирует политику через routing.Load, как при старте gateway
+func loadPolicy(t *testing.T, policyJSON string) *routing.Policy {
+	t.Helper()
+	cfg, err := routing.Load(writeRoutesFile(t, `{
+  "upstreams": {"user": {"url_env": "USER_SERVICE_URL"}},
+  "routes": [{"path": "/api/", "upstream": "user"}],
+  "policy": `+policyJSON+`
+}`))
+	if err != nil {
+		t.Fatalf("failed to load policy: %v", err)
+	}
+	return routing.NewPolicy(cfg.Policy)
+}
+
+const testPolicy = `{
+  "default": "deny",
+  "roles": ["creator", "venue", "admin"],
+  "rules": [
+    {"pattern": "/health", "methods": ["GET"], "public": true},
+    {"pattern": "/api/events/favorites/**", "roles": ["venue"]},
+    {"pattern": "/api/events/*", "methods": ["GET"]},
+    {"pattern": "/api/events/*", "methods": ["put", "delete"], "roles": ["creator"]},
+    {"pattern": "/api/events/**", "roles": ["admin"]},
+    {"pattern": "/api/public/**", "methods": ["GET"], "public": true}
+  ]
+}`
+
+func TestPolicyDecide(t *testing.T) {
+	policy := loadPolicy(t, testPolicy)
+
+	cases := []struct {
+		name    string
+		path    string
+		method  string
+		found   bool
+		public  bool
+		allowed []string // роли, которым разрешен запрос
+		denied  []string
+	}{
+		{name: "public rule", path: "/health", method: "GET", found: true, public: true},
+		{name: "public rule, other method", path: "/health", method: "POST"},
+		{name: "* matches one segment", path: "/api/events/42", method: "GET", found: true, allowed: []string{"creator", "venue", "admin"}},
+		{name: "* does not match nested path", path: "/api/events/42/media", method: "GET", found: true, allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		{name: "** matches zero segments", path: "/api/events", method: "GET", found: true, allowed: []string{"admin"}, denied: []string{"creator"}},
+		{name: "** matches many segments", path: "/api/public/a/b/c", method: "GET", found: true, public: true},
+		{name: "trailing slash is ignored", path: "/api/events/42/", method: "GET", found: true, allowed: []string{"venue"}},
+		{name: "first match wins over later rules", path: "/api/events/favorites", method: "GET", found: true, allowed: []string{"venue"}, denied: []string{"admin", "creator"}},
+		{name: "method filter picks the rule", path: "/api/events/42", method: "DELETE", found: true, allowed: []string{"creator"}, denied: []string{"venue", "admin"}},
+		{name: "method filter falls through", path: "/api/events/42", method: "PATCH", found: true, allowed: []string{"admin"}, denied: []string{"creator"}},
+		{name: "default deny", path: "/api/unknown", method: "GET"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, ok := policy.Decide(tc.path, tc.method)
+			if ok != tc.found {
+				t.Fatalf("expected found=%v, got %v (%+v)", tc.found, ok, rule)
+			}
+			if !ok {
+				return
+			}
+			if rule.Public != tc.public {
+				t.Errorf("expected public=%v, got %v", tc.public, rule.Public)
+			}
+			for _, role := range tc.allowed {
+				if !rule.AllowsRole(role) {
+					t.Errorf("expected %s to be allowed by %s", role, rule.Pattern)
+				}
+			}
+			for _, role := range tc.denied {
+				if rule.AllowsRole(role) {
+					t.Errorf("expected %s to be denied by %s", role, rule.Pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyDecide_DefaultAuthenticated(t *testing.T) {
+	policy := loadPolicy(t, `{"default": "authenticated", "roles": ["creator"], "rules": []}`)
+
+	rule, ok := policy.Decide("/api/anything", "POST")
+	if !ok {
+		t.Fatal("expected undescribed request to be allowed for authenticated users")
+	}
+	if rule.Public || !rule.AllowsRole("creator") {
+		t.Errorf("expected an authenticated-only rule, got %+v", rule)
+	}
+}
+
+func TestPolicyKnownRole(t *testing.T) {
+	policy := loadPolicy(t, testPolicy)
+
+	for _, role := range []string{"creator", "venue", "admin"} {
+		if !policy.KnownRole(role) {
+			t.Errorf("expected %s to be known", role)
+		}
+	}
+	for _, role := range []string{"", "guest", "Admin"} {
+		if policy.KnownRole(role) {
+			t.Errorf("expected %q to be unknown", role)
+		}
+	}
+}
+
+func TestPolicyValidation(t *testing.T) {
+	cases := map[string]string{
+		"unknown default":       `{"default": "allow", "roles": ["creator"]}`,
+		"no roles":              `{"default": "deny", "roles": []}`,
+		"public rule with role": `{"default": "deny", "roles": ["creator"], "rules": [{"pattern": "/a", "public": true, "roles": ["creator"]}]}`,
+		"unknown rule role":     `{"default": "deny", "roles": ["creator"], "rules": [{"pattern": "/a", "roles": ["guest"]}]}`,
+		"** in the middle":      `{"default": "deny", "roles": ["creator"], "rules": [{"pattern": "/a/**/b"}]}`,
+		"relative pattern":      `{"default": "deny", "roles": ["creator"], "rules": [{"pattern": "a/*"}]}`,
+	}
+	for name, policyJSON := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := writeRoutesFile(t, `{
+  "upstreams": {"user": {"url_env": "USER_SERVICE_URL"}},
+  "routes": [],
+  "policy": `+policyJSON+`
+}`)
+			if _, err := routing.Load(path); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+// ─── Встроенная политика ─────────────────────────────────────────────────────
+
+func TestEmbeddedPolicy(t *testing.T) {
+	cfg, err := routing.Load("")
+	if err != nil {
+		t.Fatalf("failed to load embedded routes: %v", err)
+	}
+	policy := routing.NewPolicy(cfg.Policy)
+
+	cases := []struct {
+		path    string
+		method  string
+		allowed []string
+		denied  []string
+	}{
+		// Полные профили создателей видят площадки и админы; остальным — /public
+		{path: "/api/user/users/creators", method: "GET", allowed: []string{"venue", "admin"}, denied: []string{"creator"}},
+		{path: "/api/user/users/creators/7", method: "GET", allowed: []string{"venue", "admin"}, denied: []string{"creator"}},
+		{path: "/api/user/users/creators/7", method: "PUT", allowed: []string{"creator"}, denied: []string{"venue", "admin"}},
+		{path: "/api/user/users/me/favorites/venues/3", method: "PUT", allowed: []string{"creator"}, denied: []string{"venue"}},
+		{path: "/api/user/newsletter/subscribers", method: "GET", allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		{path: "/api/event/events/favorites", method: "GET", allowed: []string{"venue"}, denied: []string{"creator"}},
+		{path: "/api/event/events", method: "POST", allowed: []string{"creator"}, denied: []string{"venue", "admin"}},
+		{path: "/api/event/categories", method: "POST", allowed: []string{"admin"}, denied: []string{"creator"}},
+		{path: "/api/application/reviews/5/moderation", method: "PATCH", allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		// Роли аналитики проверяет только gateway: сам analytics-service их не смотрит
+		{path: "/api/analytics/stats/funnel", method: "GET", allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		{path: "/api/analytics/reports/3", method: "DELETE", allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		{path: "/api/analytics/run-etl", method: "POST", allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		{path: "/api/analytics/etl/runs/5", method: "GET", allowed: []string{"admin"}, denied: []string{"creator", "venue"}},
+		{path: "/api/analytics/me/stats", method: "GET", allowed: []string{"creator", "venue"}},
+	}
+	for _, tc := range cases {
+		rule, ok := policy.Decide(tc.path, tc.method)
+		if !ok {
+			t.Errorf("%s %s: expected a rule", tc.method, tc.path)
+			continue
+		}
+		for _, role := range tc.allowed {
+			if !rule.AllowsRole(role) {
+				t.Errorf("%s %s: expected %s to be allowed", tc.method, tc.path, role)
+			}
+		}
+		for _, role := range tc.denied {
+			if rule.AllowsRole(role) {
+				t.Errorf("%s %s: expected %s to be denied", tc.method, tc.path, role)
+			}
+		}
+	}
+
+	if _, ok := policy.Decide("/api/unknown", "GET"); ok {
+		t.Error("expected undescribed request to be denied")
+	}
+	// Внутренние ручки сервисов доступны только из сети сервисов
+	for _, path := range []string{"/api/user/internal/images", "/api/application/internal/ratings"} {
+		if _, ok := policy.Decide(path, "GET"); ok {
+			t.Errorf("%s: expected internal endpoints to be denied", path)
+		}
+	}
+}
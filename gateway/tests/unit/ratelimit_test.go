@@ -0,0 +1,190 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gateway/internal/middleware"
+	"gateway/internal/routing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const testRateLimits = `{
+  "budgets": {
+    "login": {"limit": 2, "window": "1m"},
+    "read": {"limit": 100, "window": "10s"}
+  },
+  "rules": [
+    {"pattern": "/health"},
+    {"pattern": "/api/auth/login", "methods": ["post"], "budget": "login"},
+    {"pattern": "/api/**", "methods": ["GET"], "budget": "read"}
+  ]
+}`
+
+func loadRateLimits(t *testing.T) *routing.RateLimits {
+	t.Helper()
+	cfg, err := routing.Load(writeRoutesFile(t, `{
+  "upstreams": {"user": {"url_env": "USER_SERVICE_URL"}},
+  "routes": [{"path": "/api/", "upstream": "user"}],
+  "policy": {"default": "deny", "roles": ["creator"]},
+  "rate_limits": `+testRateLimits+`
+}`))
+	if err != nil {
+		t.Fatalf("failed to load rate limits: %v", err)
+	}
+	return routing.NewRateLimits(cfg.RateLimits)
+}
+
+// ─── RateLimits.Match ────────────────────────────────────────────────────────
+
+func TestRateLimitsMatch(t *testing.T) {
+	limits := loadRateLimits(t)
+
+	cases := []struct {
+		name   string
+		path   string
+		method string
+		key    string // пусто — запрос не ограничивается
+		limit  int
+		window time.Duration
+	}{
+		{name: "budget by method", path: "/api/auth/login", method: "POST", key: "login:/api/auth/login", limit: 2, window: time.Minute},
+		{name: "method mismatch falls to the next rule", path: "/api/auth/login", method: "GET", key: "read:/api/**", limit: 100, window: 10 * time.Second},
+		{name: "catch-all rule", path: "/api/event/events/1", method: "GET", key: "read:/api/**", limit: 100, window: 10 * time.Second},
+		{name: "rule without budget", path: "/health", method: "GET"},
+		{name: "no rule", path: "/api/event/events", method: "DELETE"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			limit, ok := limits.Match(tc.path, tc.method)
+			if tc.key == "" {
+				if ok {
+					t.Fatalf("expected no limit, got %+v", limit)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected a limit, got none")
+			}
+			if limit.Key != tc.key || limit.Limit != tc.limit || limit.Window != tc.window {
+				t.Errorf("expected %s %d/%v, got %+v", tc.key, tc.limit, tc.window, limit)
+			}
+		})
+	}
+}
+
+// ─── RateLimitMiddleware ─────────────────────────────────────────────────────
+
+// newRateLimitedRouter — роутер с лимитами; заголовок X-Test-User имитирует
+// пользователя, которого нашел бы AuthMiddleware
+func newRateLimitedRouter(t *testing.T, client *redis.Client) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader("X-Test-User") == "7" {
+			c.Set("user_id", 7)
+		}
+		c.Next()
+	})
+	r.Use(middleware.RateLimitMiddleware(loadRateLimits(t), client))
+	r.Any("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func doLimited(r *gin.Engine, method, path, remoteAddr, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware_FixedWindow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	r := newRateLimitedRouter(t, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		w := doLimited(r, "POST", "/api/auth/login", "192.0.2.1:1000", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: expected RateLimit-Remaining %s, got %q", i+1, wantRemaining, got)
+		}
+		if got := w.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+			t.Errorf("expected RateLimit-Policy 2;w=60, got %q", got)
+		}
+	}
+
+	w := doLimited(r, "POST", "/api/auth/login", "192.0.2.1:1001", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the limit, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("expected Retry-After 60, got %q", got)
+	}
+
+	// Другой IP и другой бюджет считаются отдельно
+	if w := doLimited(r, "POST", "/api/auth/login", "192.0.2.2:1000", ""); w.Code != http.StatusOK {
+		t.Errorf("expected another IP to be allowed, got %d", w.Code)
+	}
+	if w := doLimited(r, "GET", "/api/auth/login", "192.0.2.1:1000", ""); w.Code != http.StatusOK {
+		t.Errorf("expected another budget to be allowed, got %d", w.Code)
+	}
+
+	// Окно закончилось — счетчик начинается заново
+	mr.FastForward(time.Minute)
+	if w := doLimited(r, "POST", "/api/auth/login", "192.0.2.1:1000", ""); w.Code != http.StatusOK {
+		t.Errorf("expected a new window to allow the request, got %d", w.Code)
+	}
+}
+
+func TestRateLimitMiddleware_Keys(t *testing.T) {
+	mr := miniredis.RunT(t)
+	r := newRateLimitedRouter(t, redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	doLimited(r, "POST", "/api/auth/login", "192.0.2.1:1000", "")
+	doLimited(r, "POST", "/api/auth/login", "192.0.2.1:1000", "7")
+	doLimited(r, "GET", "/health", "192.0.2.1:1000", "")
+
+	for _, key := range []string{
+		"ratelimit:login:/api/auth/login:ip:192.0.2.1",
+		"ratelimit:login:/api/auth/login:user:7",
+	} {
+		if !mr.Exists(key) {
+			t.Errorf("expected counter %s, got keys %v", key, mr.Keys())
+		}
+		if ttl := mr.TTL(key); ttl <= 0 || ttl > time.Minute {
+			t.Errorf("expected %s to expire within the window, got ttl %v", key, ttl)
+		}
+	}
+	if len(mr.Keys()) != 2 {
+		t.Errorf("expected requests without a budget not to be counted, got keys %v", mr.Keys())
+	}
+}
+
+func TestRateLimitMiddleware_FailsOpen(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	r := newRateLimitedRouter(t, client)
+	mr.Close()
+
+	for i := 0; i < 3; i++ {
+		w := doLimited(r, "POST", "/api/auth/login", "192.0.2.1:1000", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 while Redis is down, got %d", i+1, w.Code)
+		}
+		if w.Header().Get("RateLimit-Limit") != "" {
+			t.Errorf("expected no rate limit headers without Redis, got %q", w.Header().Get("RateLimit-Limit"))
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package unit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gateway/internal/handlers"
+	"gateway/internal/middleware"
+	"gateway/internal/realtime"
+	"gateway/internal/routing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return mr, rdb
+}
+
+// ─── Tickets ─────────────────────────────────────────────────────────────────
+
+func TestTickets_RedeemOnce(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	tickets := realtime.NewTickets(rdb)
+	ctx := context.Background()
+
+	ticket, err := tickets.Issue(ctx, 7, "venue")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ttl := mr.TTL("realtime:ticket:" + ticket); ttl != realtime.TicketTTL {
+		t.Errorf("expected ticket TTL %v, got %v", realtime.TicketTTL, ttl)
+	}
+
+	userID, role, err := tickets.Redeem(ctx, ticket)
+	if err != nil || userID != 7 || role != "venue" {
+		t.Fatalf("expected user 7 with role venue, got %d %q (err %v)", userID, role, err)
+	}
+	if _, _, err := tickets.Redeem(ctx, ticket); !errors.Is(err, realtime.ErrInvalidTicket) {
+		t.Errorf("expected ErrInvalidTicket on second redeem, got %v", err)
+	}
+}
+
+func TestTickets_Expired(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	tickets := realtime.NewTickets(rdb)
+
+	ticket, err := tickets.Issue(context.Background(), 7, "venue")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	mr.FastForward(realtime.TicketTTL + time.Second)
+
+	if _, _, err := tickets.Redeem(context.Background(), ticket); !errors.Is(err, realtime.ErrInvalidTicket) {
+		t.Errorf("expected ErrInvalidTicket for expired ticket, got %v", err)
+	}
+}
+
+func TestTickets_RejectsUnknownAndMalformed(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	tickets := realtime.NewTickets(rdb)
+	mr.Set("realtime:ticket:broken", "not-a-user")
+
+	for _, ticket := range []string{"unknown", "broken"} {
+		if _, _, err := tickets.Redeem(context.Background(), ticket); !errors.Is(err, realtime.ErrInvalidTicket) {
+			t.Errorf("%s: expected ErrInvalidTicket, got %v", ticket, err)
+		}
+	}
+}
+
+// ─── Hub ─────────────────────────────────────────────────────────────────────
+
+// startHub запускает hub и ждет, пока его pattern-подписка появится в Redis
+func startHub(t *testing.T, mr *miniredis.Miniredis, rdb *redis.Client) *realtime.Hub {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := realtime.NewHub(rdb)
+	go hub.Run(ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-hub.Done()
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for mr.PubSubNumPat() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("hub did not subscribe in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return hub
+}
+
+func publish(t *testing.T, rdb *redis.Client, userID, payload string) {
+	t.Helper()
+	if err := rdb.Publish(context.Background(), realtime.ChannelPrefix+userID, payload).Err(); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+}
+
+func receive(t *testing.T, sub *realtime.Subscriber) realtime.Event {
+	t.Helper()
+	select {
+	case event := <-sub.Events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an event")
+		return realtime.Event{}
+	}
+}
+
+func TestHub_FanOutToUserStreams(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	hub := startHub(t, mr, rdb)
+
+	first := hub.Subscribe(1)
+	second := hub.Subscribe(1)
+	other := hub.Subscribe(2)
+	defer hub.Unsubscribe(first)
+	defer hub.Unsubscribe(second)
+	defer hub.Unsubscribe(other)
+
+	// Битые события отбрасываются, следующее доходит
+	publish(t, rdb, "1", `not json`)
+	publish(t, rdb, "1", `{"data": {}}`)
+	publish(t, rdb, "1", `{"type": "message_created", "data": {"id": 5}}`)
+
+	for _, sub := range []*realtime.Subscriber{first, second} {
+		event := receive(t, sub)
+		if event.Type != "message_created" || !strings.Contains(string(event.Payload), `"id": 5`) {
+			t.Errorf("unexpected event: %s %s", event.Type, event.Payload)
+		}
+	}
+
+	// События обрабатываются по порядку: когда пришло событие второму пользователю,
+	// событие первого уже разослано
+	publish(t, rdb, "2", `{"type": "ping"}`)
+	receive(t, other)
+	if len(other.Events) != 0 || len(first.Events) != 0 {
+		t.Error("expected events to reach only the addressed user")
+	}
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	hub := startHub(t, mr, rdb)
+
+	gone := hub.Subscribe(1)
+	stays := hub.Subscribe(1)
+	defer hub.Unsubscribe(stays)
+	hub.Unsubscribe(gone)
+
+	publish(t, rdb, "1", `{"type": "first"}`)
+	publish(t, rdb, "1", `{"type": "second"}`)
+	receive(t, stays)
+	receive(t, stays)
+	if len(gone.Events) != 0 {
+		t.Errorf("expected no events after unsubscribe, got %d", len(gone.Events))
+	}
+}
+
+func TestHub_DoneAfterStop(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := realtime.NewHub(rdb)
+	go hub.Run(ctx)
+	cancel()
+
+	select {
+	case <-hub.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected hub to stop after context cancel")
+	}
+}
+
+// ─── Stream ──────────────────────────────────────────────────────────────────
+
+func newStreamServer(t *testing.T, hub *realtime.Hub, tickets *realtime.Tickets) *httptest.Server {
+	t.Helper()
+	cfg, err := routing.Load("")
+	if err != nil {
+		t.Fatalf("failed to load embedded routes: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.AuthMiddleware(routing.NewPolicy(cfg.Policy), tickets))
+	r.GET("/api/stream", handlers.StreamHandler(hub))
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStream_RejectsMissingAndInvalidTickets(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	tickets := realtime.NewTickets(rdb)
+	srv := newStreamServer(t, startHub(t, mr, rdb), tickets)
+
+	expired, _ := tickets.Issue(context.Background(), 7, "venue")
+	mr.FastForward(realtime.TicketTTL + time.Second)
+	unknownRole, _ := tickets.Issue(context.Background(), 7, "guest")
+
+	cases := map[string]struct {
+		query string
+		code  int
+	}{
+		"no ticket":      {query: "", code: http.StatusUnauthorized},
+		"unknown ticket": {query: "?ticket=unknown", code: http.StatusUnauthorized},
+		"expired ticket": {query: "?ticket=" + expired, code: http.StatusUnauthorized},
+		"unknown role":   {query: "?ticket=" + unknownRole, code: http.StatusForbidden},
+	}
+	for name, tc := range cases {
+		resp, err := http.Get(srv.URL + "/api/stream" + tc.query)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.code {
+			t.Errorf("%s: expected %d, got %d", name, tc.code, resp.StatusCode)
+		}
+	}
+}
+
+func TestStream_DeliversEventsAndBurnsTicket(t *testing.T) {
+	mr, rdb := newTestRedis(t)
+	tickets := realtime.NewTickets(rdb)
+	srv := newStreamServer(t, startHub(t, mr, rdb), tickets)
+
+	ticket, err := tickets.Issue(context.Background(), 7, "creator")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/stream?ticket="+ticket, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// retry приходит после подписки, дальше события пользователя идут в стрим
+	lines := bufio.NewScanner(resp.Body)
+	if !lines.Scan() || lines.Text() != "retry: 5000" {
+		t.Fatalf("expected retry line, got %q", lines.Text())
+	}
+	publish(t, rdb, "7", `{"type": "application_accepted"}`)
+
+	var got []string
+	for len(got) < 2 && lines.Scan() {
+		if lines.Text() != "" {
+			got = append(got, lines.Text())
+		}
+	}
+	if len(got) != 2 || got[0] != "event: application_accepted" || got[1] != `data: {"type": "application_accepted"}` {
+		t.Errorf("unexpected stream output: %q", got)
+	}
+
+	// Билет одноразовый: второе подключение с ним не пускается
+	again, err := http.Get(srv.URL + "/api/stream?ticket=" + ticket)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	again.Body.Close()
+	if again.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a used ticket, got %d", again.StatusCode)
+	}
+}
+
+func TestStreamHandler_RequiresUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/stream", handlers.StreamHandler(realtime.NewHub(nil)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stream", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without an authorized user, got %d", w.Code)
+	}
+}
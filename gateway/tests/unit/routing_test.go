@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gateway/internal/routing"
+)
+
+// ─── Table.Match ─────────────────────────────────────────────────────────────
+
+func newTestTable() *routing.Table {
+	return routing.NewTable([]routing.Route{
+		{Path: "/api/user/", Upstream: "user", Rewrite: "/"},
+		{Path: "/api/auth/logout", Upstream: "user", Rewrite: "/auth/logout", Methods: []string{"POST"}},
+		{Path: "/api/auth/logout-all", Upstream: "user", Rewrite: "/auth/logout-all", Methods: []string{"POST"}},
+		{Path: "/api/user/users/creators/", Upstream: "creators", Methods: []string{"GET"}},
+		{Path: "/api/", Upstream: "fallback"},
+	})
+}
+
+func TestTableMatch(t *testing.T) {
+	table := newTestTable()
+
+	cases := []struct {
+		name     string
+		path     string
+		method   string
+		upstream string // пусто — маршрут не найден
+		route    string
+	}{
+		{name: "longest prefix wins", path: "/api/user/users/creators/7", method: "GET", upstream: "creators", route: "/api/user/users/creators/"},
+		{name: "method mismatch falls back to shorter prefix", path: "/api/user/users/creators/7", method: "DELETE", upstream: "user", route: "/api/user/"},
+		{name: "prefix route", path: "/api/user/users/me", method: "GET", upstream: "user", route: "/api/user/"},
+		{name: "exact route", path: "/api/auth/logout", method: "POST", upstream: "user", route: "/api/auth/logout"},
+		{name: "exact route does not swallow longer names", path: "/api/auth/logout-all", method: "POST", upstream: "user", route: "/api/auth/logout-all"},
+		{name: "exact route matches subpaths", path: "/api/auth/logout/now", method: "POST", upstream: "user", route: "/api/auth/logout"},
+		{name: "exact route with wrong method", path: "/api/auth/logout", method: "GET", upstream: "fallback", route: "/api/"},
+		{name: "prefix requires trailing slash", path: "/api/user", method: "GET", upstream: "fallback", route: "/api/"},
+		{name: "no route", path: "/health", method: "GET"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			route, ok := table.Match(tc.path, tc.method)
+			if tc.upstream == "" {
+				if ok {
+					t.Fatalf("expected no route, got %+v", route)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected a route, got none")
+			}
+			if route.Upstream != tc.upstream || route.Path != tc.route {
+				t.Errorf("expected %s via %s, got %s via %s", tc.route, tc.upstream, route.Path, route.Upstream)
+			}
+		})
+	}
+}
+
+// ─── Route.RewritePath ───────────────────────────────────────────────────────
+
+func TestRouteRewritePath(t *testing.T) {
+	cases := []struct {
+		name  string
+		route routing.Route
+		path  string
+		want  string
+	}{
+		{name: "prefix to root", route: routing.Route{Path: "/api/user/", Rewrite: "/"}, path: "/api/user/users/me", want: "/users/me"},
+		{name: "bare prefix", route: routing.Route{Path: "/api/user/", Rewrite: "/"}, path: "/api/user/", want: "/"},
+		{name: "trailing slash kept", route: routing.Route{Path: "/api/user/", Rewrite: "/"}, path: "/api/user/users/", want: "/users/"},
+		{name: "prefix to prefix", route: routing.Route{Path: "/swagger-application/", Rewrite: "/swagger/"}, path: "/swagger-application/index.html", want: "/swagger/index.html"},
+		{name: "exact path", route: routing.Route{Path: "/api/auth/logout", Rewrite: "/auth/logout"}, path: "/api/auth/logout", want: "/auth/logout"},
+		{name: "exact path subpath", route: routing.Route{Path: "/api/auth/logout", Rewrite: "/auth/logout"}, path: "/api/auth/logout/now", want: "/auth/logout/now"},
+		{name: "no rewrite", route: routing.Route{Path: "/api/user/"}, path: "/api/user/users/me", want: "/api/user/users/me"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.route.RewritePath(tc.path); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// ─── routing.Load ────────────────────────────────────────────────────────────
+
+const validRoutesConfig = `{
+  "upstreams": {"user": {"url_env": "USER_SERVICE_URL", "response_header_timeout": "5s"}},
+  "routes": [{"path": "/api/user/", "upstream": "user", "rewrite": "/", "methods": ["get"]}],
+  "policy": {"default": "deny", "roles": ["creator"], "rules": [{"pattern": "/api/user/**", "methods": ["get"]}]},
+  "rate_limits": {
+    "budgets": {"read": {"limit": 10, "window": "1m"}},
+    "rules": [{"pattern": "/api/user/**", "budget": "read"}]
+  }
+}`
+
+func writeRoutesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write routes file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_EmbeddedRoutesAreValid(t *testing.T) {
+	cfg, err := routing.Load("")
+	if err != nil {
+		t.Fatalf("expected embedded routes to load, got %v", err)
+	}
+	if len(cfg.Routes) == 0 || len(cfg.Policy.Rules) == 0 {
+		t.Errorf("expected routes and policy rules, got %+v", cfg)
+	}
+}
+
+func TestLoad_NormalizesMethods(t *testing.T) {
+	cfg, err := routing.Load(writeRoutesFile(t, validRoutesConfig))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Routes[0].Methods[0] != "GET" || cfg.Policy.Rules[0].Methods[0] != "GET" {
+		t.Errorf("expected methods in upper case, got %v and %v", cfg.Routes[0].Methods, cfg.Policy.Rules[0].Methods)
+	}
+}
+
+func TestLoad_RejectsInvalidConfig(t *testing.T) {
+	cases := []struct {
+		name    string
+		old     string
+		new     string
+		wantErr string
+	}{
+		{name: "unknown upstream", old: `"upstream": "user"`, new: `"upstream": "billing"`, wantErr: `unknown upstream "billing"`},
+		{name: "missing url_env", old: `"url_env": "USER_SERVICE_URL", `, new: ``, wantErr: "url_env is required"},
+		{name: "relative route path", old: `"path": "/api/user/"`, new: `"path": "api/user/"`, wantErr: "path must start with /"},
+		{name: "bad duration", old: `"5s"`, new: `"soon"`, wantErr: "failed to parse routes"},
+		{name: "invalid policy default", old: `"default": "deny"`, new: `"default": "allow"`, wantErr: "policy: default"},
+		{name: "policy rule with unknown role", old: `"methods": ["get"]}]}`, new: `"roles": ["admin"]}]}`, wantErr: `unknown role "admin"`},
+		{name: "unknown rate limit budget", old: `"budget": "read"`, new: `"budget": "write"`, wantErr: `unknown budget "write"`},
+		{name: "zero rate limit", old: `"limit": 10`, new: `"limit": 0`, wantErr: "limit must be positive"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !strings.Contains(validRoutesConfig, tc.old) {
+				t.Fatalf("test config does not contain %q", tc.old)
+			}
+			content := strings.Replace(validRoutesConfig, tc.old, tc.new, 1)
+
+			_, err := routing.Load(writeRoutesFile(t, content))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := routing.Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing routes file")
+	}
+}
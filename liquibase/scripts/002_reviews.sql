@@ -0,0 +1,26 @@
+CREATE TABLE "reviews" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "collaboration_id" INT NOT NULL,
+  "event_id" INT NOT NULL,
+  "author_id" INT NOT NULL,
+  "author_type" VARCHAR(20) NOT NULL,
+  "target_id" INT NOT NULL,
+  "target_type" VARCHAR(20) NOT NULL,
+  "rating" SMALLINT NOT NULL,
+  "text" TEXT,
+  "is_hidden" BOOLEAN NOT NULL DEFAULT false,
+  "moderation_reason" VARCHAR(500),
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  CHECK (rating BETWEEN 1 AND 5),
+  CHECK (author_type IN ('creator', 'venue')),
+  CHECK (target_type IN ('creator', 'venue'))
+);
+
+CREATE UNIQUE INDEX uq_reviews_collaboration_author ON reviews (collaboration_id, author_id);
+CREATE INDEX idx_reviews_target ON reviews (target_id) WHERE is_hidden = false;
+
+ALTER TABLE "reviews" ADD FOREIGN KEY ("collaboration_id") REFERENCES "collaborations" ("id") ON DELETE CASCADE;
+ALTER TABLE "reviews" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+ALTER TABLE "reviews" ADD FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "reviews" ADD FOREIGN KEY ("target_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,35 @@
+CREATE TABLE "conversations" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "application_id" INT NOT NULL,
+  "collaboration_id" INT,
+  "event_id" INT NOT NULL,
+  "sender_id" INT NOT NULL,
+  "receiver_id" INT NOT NULL,
+  "last_message_at" TIMESTAMP,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX uq_conversations_application ON conversations (application_id);
+CREATE INDEX idx_conversations_sender ON conversations (sender_id);
+CREATE INDEX idx_conversations_receiver ON conversations (receiver_id);
+
+CREATE TABLE "messages" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "conversation_id" INT NOT NULL,
+  "sender_id" INT NOT NULL,
+  "body" TEXT NOT NULL,
+  "read_at" TIMESTAMP,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_messages_conversation ON messages (conversation_id, id DESC);
+CREATE INDEX idx_messages_unread ON messages (conversation_id, sender_id) WHERE read_at IS NULL;
+
+ALTER TABLE "conversations" ADD FOREIGN KEY ("application_id") REFERENCES "applications" ("id") ON DELETE CASCADE;
+ALTER TABLE "conversations" ADD FOREIGN KEY ("collaboration_id") REFERENCES "collaborations" ("id") ON DELETE SET NULL;
+ALTER TABLE "conversations" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE;
+ALTER TABLE "conversations" ADD FOREIGN KEY ("sender_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "conversations" ADD FOREIGN KEY ("receiver_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "messages" ADD FOREIGN KEY ("conversation_id") REFERENCES "conversations" ("id") ON DELETE CASCADE;
+ALTER TABLE "messages" ADD FOREIGN KEY ("sender_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,25 @@
+CREATE TABLE "application_outbox" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "event_type" VARCHAR(100) NOT NULL,
+  "aggregate_id" INT NOT NULL,
+  "payload" JSONB NOT NULL,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  "published_at" TIMESTAMP,
+  "attempts" INT NOT NULL DEFAULT 0,
+  "last_error" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_application_outbox_unpublished ON application_outbox (id) WHERE published_at IS NULL;
+
+CREATE TABLE "event_outbox" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "event_type" VARCHAR(100) NOT NULL,
+  "aggregate_id" INT NOT NULL,
+  "payload" JSONB NOT NULL,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  "published_at" TIMESTAMP,
+  "attempts" INT NOT NULL DEFAULT 0,
+  "last_error" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_event_outbox_unpublished ON event_outbox (id) WHERE published_at IS NULL;
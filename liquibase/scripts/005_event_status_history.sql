@@ -0,0 +1,24 @@
+CREATE TABLE "event_status_history" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "event_id" INT NOT NULL REFERENCES "events" ("id") ON DELETE CASCADE,
+  "from_status" VARCHAR(20),
+  "to_status" VARCHAR(20) NOT NULL,
+  "reason" VARCHAR(50) NOT NULL,
+  "changed_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_event_status_history_event ON event_status_history (event_id, changed_at);
+CREATE INDEX idx_event_status_history_changed_at ON event_status_history (changed_at);
+
+-- Существующие мероприятия: запись о создании и, если статус уже сменился,
+-- переход в текущий статус на момент последнего изменения
+INSERT INTO event_status_history (event_id, from_status, to_status, reason, changed_at)
+SELECT id, NULL, 'active', 'created', created_at FROM events;
+
+INSERT INTO event_status_history (event_id, from_status, to_status, reason, changed_at)
+SELECT id, 'active',
+       CASE WHEN is_completed THEN 'completed' ELSE 'inactive' END,
+       CASE WHEN is_completed THEN 'collaboration_completed' ELSE 'application_accepted' END,
+       updated_at
+FROM events
+WHERE is_completed OR NOT is_active;
@@ -0,0 +1,30 @@
+CREATE TABLE "analytics_reports" (
+  "id" SERIAL PRIMARY KEY,
+  "name" VARCHAR(200) NOT NULL,
+  "kind" VARCHAR(50) NOT NULL,
+  "period_days" INT NOT NULL DEFAULT 30,
+  "granularity" VARCHAR(10) NOT NULL DEFAULT 'day',
+  "top_limit" INT NOT NULL DEFAULT 10,
+  "formats" JSONB NOT NULL DEFAULT '["csv"]',
+  "schedule" VARCHAR(100) NOT NULL,
+  "recipients" JSONB NOT NULL DEFAULT '[]',
+  "enabled" BOOLEAN NOT NULL DEFAULT true,
+  "created_by" INT NOT NULL,
+  "last_run_at" TIMESTAMP,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "analytics_report_runs" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "report_id" INT NOT NULL REFERENCES "analytics_reports" ("id") ON DELETE CASCADE,
+  "trigger" VARCHAR(20) NOT NULL,
+  "status" VARCHAR(20) NOT NULL,
+  "rows" INT NOT NULL DEFAULT 0,
+  "files" JSONB NOT NULL DEFAULT '[]',
+  "error" TEXT NOT NULL DEFAULT '',
+  "started_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  "finished_at" TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_report_runs_report ON analytics_report_runs (report_id, started_at DESC);
@@ -0,0 +1,6 @@
+-- Списки отдаются keyset-пагинацией по (created_at DESC, id DESC)
+CREATE INDEX idx_events_created_id ON events (created_at DESC, id DESC);
+CREATE INDEX idx_creators_created_id ON creators (created_at DESC, id DESC);
+CREATE INDEX idx_venues_created_id ON venues (created_at DESC, id DESC);
+CREATE INDEX idx_applications_created_id ON applications (created_at DESC, id DESC);
+CREATE INDEX idx_collaborations_created_id ON collaborations (created_at DESC, id DESC);
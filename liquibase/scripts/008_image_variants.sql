@@ -0,0 +1,12 @@
+-- Уменьшенные WebP-копии изображений; объекты лежат в бакете оригинала
+CREATE TABLE "image_variants" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "image_id" uuid NOT NULL REFERENCES "images" ("id") ON DELETE CASCADE,
+  "width" INT NOT NULL,
+  "height" INT NOT NULL,
+  "format" VARCHAR(10) NOT NULL,
+  "file_path" VARCHAR(500) NOT NULL,
+  "size" BIGINT NOT NULL,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE ("image_id", "format", "width")
+);
@@ -0,0 +1,10 @@
+-- Загрузки проходят карантин: до проверки сканером файл лежит в бакете image-quarantine
+ALTER TABLE "images"
+  ADD COLUMN "status" VARCHAR(20) NOT NULL DEFAULT 'ready',
+  ADD COLUMN "width" INT,
+  ADD COLUMN "height" INT,
+  ADD COLUMN "scan_signature" VARCHAR(255),
+  ADD COLUMN "scanned_at" TIMESTAMP,
+  ADD COLUMN "scan_attempted_at" TIMESTAMP;
+
+CREATE INDEX idx_images_quarantined ON images (created_at) WHERE status = 'quarantined';
@@ -0,0 +1,22 @@
+-- Владелец изображения и размер оригинала для квот
+ALTER TABLE "images"
+  ADD COLUMN "owner_user_id" INT REFERENCES "users" ("id") ON DELETE SET NULL,
+  ADD COLUMN "size" BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_images_owner ON images (owner_user_id);
+
+-- Владельцы уже загруженных изображений восстанавливаются по текущим ссылкам.
+-- Размер старых файлов неизвестен (0) и в квоту не попадает.
+UPDATE images i SET owner_user_id = c.user_id FROM creators c WHERE c.photo_id = i.id;
+UPDATE images i SET owner_user_id = c.user_id FROM creator_photos cp JOIN creators c ON c.id = cp.creator_id WHERE cp.image_id = i.id AND i.owner_user_id IS NULL;
+UPDATE images i SET owner_user_id = v.user_id FROM venues v WHERE (v.logo_id = i.id OR v.cover_photo_id = i.id) AND i.owner_user_id IS NULL;
+UPDATE images i SET owner_user_id = v.user_id FROM venue_photos vp JOIN venues v ON v.id = vp.venue_id WHERE vp.image_id = i.id AND i.owner_user_id IS NULL;
+UPDATE images i SET owner_user_id = e.creator_id FROM events e WHERE e.cover_photo_id = i.id AND i.owner_user_id IS NULL;
+
+-- Сборщик ищет непривязанные изображения по этим ссылкам
+CREATE INDEX IF NOT EXISTS idx_creators_photo_id ON creators (photo_id);
+CREATE INDEX IF NOT EXISTS idx_creator_photos_image_id ON creator_photos (image_id);
+CREATE INDEX IF NOT EXISTS idx_venues_logo_id ON venues (logo_id);
+CREATE INDEX IF NOT EXISTS idx_venues_cover_photo_id ON venues (cover_photo_id);
+CREATE INDEX IF NOT EXISTS idx_venue_photos_image_id ON venue_photos (image_id);
+CREATE INDEX IF NOT EXISTS idx_events_cover_photo_id ON events (cover_photo_id);
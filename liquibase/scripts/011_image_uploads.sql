@@ -0,0 +1,15 @@
+-- Прямые загрузки в MinIO: выданные presigned-ссылки, ожидающие finalize
+CREATE TABLE "image_uploads" (
+  "id" UUID PRIMARY KEY,
+  "owner_user_id" INT NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "image_type" VARCHAR(50) NOT NULL,
+  "file_name" VARCHAR(255) NOT NULL,
+  "object_name" VARCHAR(255) NOT NULL,
+  "size" BIGINT NOT NULL,
+  "multipart_upload_id" VARCHAR(255),
+  "part_count" INT NOT NULL DEFAULT 0,
+  "expires_at" TIMESTAMP NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_image_uploads_expires_at ON image_uploads (expires_at);
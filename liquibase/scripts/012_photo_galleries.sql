@@ -0,0 +1,36 @@
+-- Упорядоченные галереи площадок и создателей: позиция, подпись, alt-текст, обложка
+ALTER TABLE "venue_photos"
+  ADD COLUMN "position" INT,
+  ADD COLUMN "caption" VARCHAR(500),
+  ADD COLUMN "alt_text" VARCHAR(300),
+  ADD COLUMN "is_cover" BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN "created_at" TIMESTAMP NOT NULL DEFAULT NOW();
+
+ALTER TABLE "creator_photos"
+  ADD COLUMN "position" INT,
+  ADD COLUMN "caption" VARCHAR(500),
+  ADD COLUMN "alt_text" VARCHAR(300),
+  ADD COLUMN "is_cover" BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN "created_at" TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Существующие фото выстраиваются в порядке добавления
+UPDATE venue_photos p SET position = o.rn - 1
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY venue_id ORDER BY id) AS rn FROM venue_photos) o
+WHERE p.id = o.id;
+
+UPDATE creator_photos p SET position = o.rn - 1
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY creator_id ORDER BY id) AS rn FROM creator_photos) o
+WHERE p.id = o.id;
+
+ALTER TABLE "venue_photos" ALTER COLUMN "position" SET NOT NULL;
+ALTER TABLE "creator_photos" ALTER COLUMN "position" SET NOT NULL;
+
+-- Проверка уникальности отложена до конца транзакции, чтобы перестановка
+-- позиций не спотыкалась о промежуточные дубли
+ALTER TABLE "venue_photos"
+  ADD CONSTRAINT uq_venue_photos_position UNIQUE (venue_id, position) DEFERRABLE INITIALLY DEFERRED;
+ALTER TABLE "creator_photos"
+  ADD CONSTRAINT uq_creator_photos_position UNIQUE (creator_id, position) DEFERRABLE INITIALLY DEFERRED;
+
+CREATE UNIQUE INDEX uq_venue_photos_cover ON venue_photos (venue_id) WHERE is_cover;
+CREATE UNIQUE INDEX uq_creator_photos_cover ON creator_photos (creator_id) WHERE is_cover;
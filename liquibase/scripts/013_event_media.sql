@@ -0,0 +1,21 @@
+-- Медиа мероприятия: фото галереи, постер и документы (райдер, техпаспорт) из images
+CREATE TABLE "event_media" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "event_id" INT NOT NULL REFERENCES "events" ("id") ON DELETE CASCADE,
+  "image_id" UUID NOT NULL REFERENCES "images" ("id"),
+  "type" VARCHAR(20) NOT NULL CHECK ("type" IN ('photo', 'poster', 'rider')),
+  "position" INT NOT NULL,
+  "caption" VARCHAR(500),
+  "created_at" TIMESTAMP NOT NULL DEFAULT NOW(),
+  UNIQUE ("event_id", "image_id")
+);
+
+-- Как и в галереях профилей, уникальность позиции проверяется при коммите
+ALTER TABLE "event_media"
+  ADD CONSTRAINT uq_event_media_position UNIQUE (event_id, position) DEFERRABLE INITIALLY DEFERRED;
+
+-- Постер у мероприятия один
+CREATE UNIQUE INDEX uq_event_media_poster ON event_media (event_id) WHERE type = 'poster';
+
+-- Сборщик непривязанных изображений ищет ссылки по image_id
+CREATE INDEX idx_event_media_image_id ON event_media (image_id);
@@ -0,0 +1,6 @@
+-- Когда сборщик впервые увидел изображение без ссылок. Льготный срок перед удалением
+-- отсчитывается от этого момента, а не от загрузки: иначе отвязанное старое фото
+-- удалялось бы сразу. Повторная привязка сбрасывает отметку.
+ALTER TABLE "images" ADD COLUMN "unreferenced_since" TIMESTAMP;
+
+CREATE INDEX idx_images_unreferenced_since ON images (unreferenced_since) WHERE unreferenced_since IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Имя и тип файла медиа хранятся у мероприятия: event-service получает их из
+-- user-service при добавлении медиа и не читает таблицу images
+ALTER TABLE "event_media" ADD COLUMN "file_name" VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "event_media" ADD COLUMN "file_type" VARCHAR(100);
+
+UPDATE "event_media" em
+SET "file_name" = i."file_name", "file_type" = i."file_type"
+FROM "images" i
+WHERE i."id" = em."image_id";
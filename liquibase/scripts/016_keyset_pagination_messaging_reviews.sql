@@ -0,0 +1,3 @@
+-- Переписки и отзывы тоже отдаются keyset-пагинацией по (created_at DESC, id DESC)
+CREATE INDEX idx_conversations_created_id ON conversations (created_at DESC, id DESC);
+CREATE INDEX idx_reviews_created_id ON reviews (created_at DESC, id DESC);
@@ -28,7 +28,7 @@ export default function () {
   const eventsOk = check(eventsRes, {
     'events list 200':      r => r.status === 200,
     'events list has data': r => {
-      try { return r.json().events.length > 0; } catch { return false; }
+      try { return r.json().items.length > 0; } catch { return false; }
     },
   });
   errorRate.add(!eventsOk);
@@ -37,7 +37,7 @@ export default function () {
 
   let eventID = 1;
   try {
-    const events = eventsRes.json().events;
+    const events = eventsRes.json().items;
     if (events && events.length > 0) {
       eventID = events[Math.floor(Math.random() * events.length)].id;
     }
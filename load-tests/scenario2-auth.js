@@ -56,7 +56,7 @@ export default function () {
   // Одно мероприятие
   let eventID = 1;
   try {
-    const events = eventsRes.json().events;
+    const events = eventsRes.json().items;
     if (events && events.length > 0) {
       eventID = events[Math.floor(Math.random() * events.length)].id;
     }
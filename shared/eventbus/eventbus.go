@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Типы доменных событий, которыми обмениваются сервисы
+const (
+	ApplicationAccepted    = "ApplicationAccepted"
+	CollaborationCompleted = "CollaborationCompleted"
+	EventPublished         = "EventPublished"
+	EventCategoriesChanged = "EventCategoriesChanged"
+	EventDeleted           = "EventDeleted"
+	VenueFavoriteChanged   = "VenueFavoriteChanged"
+)
+
+// Event — конверт доменного события. ID уникален в пределах источника,
+// поэтому потребители могут использовать пару (Source, ID) для дедупликации.
+type Event struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Source      string          `json:"source"`
+	AggregateID int             `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+// Handler обрабатывает событие. Ошибка означает, что событие будет доставлено повторно,
+// поэтому обработчики должны быть идемпотентными.
+type Handler func(ctx context.Context, event Event) error
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type Bus interface {
+	Publisher
+	// Subscribe обрабатывает события в рамках consumer group до отмены ctx.
+	// Каждое событие получает один потребитель группы.
+	Subscribe(ctx context.Context, group string, handler Handler) error
+}
+
+type ApplicationAcceptedPayload struct {
+	ApplicationID   int `json:"application_id"`
+	CollaborationID int `json:"collaboration_id"`
+	EventID         int `json:"event_id"`
+	CreatorUserID   int `json:"creator_user_id"`
+	VenueUserID     int `json:"venue_user_id"`
+}
+
+type CollaborationCompletedPayload struct {
+	CollaborationID int `json:"collaboration_id"`
+	EventID         int `json:"event_id"`
+	CreatorUserID   int `json:"creator_user_id"`
+	VenueUserID     int `json:"venue_user_id"`
+}
+
+type EventPublishedPayload struct {
+	EventID   int `json:"event_id"`
+	CreatorID int `json:"creator_id"`
+}
+
+type EventCategoriesChangedPayload struct {
+	EventID     int   `json:"event_id"`
+	CategoryIDs []int `json:"category_ids"`
+}
+
+type EventDeletedPayload struct {
+	EventID int `json:"event_id"`
+}
+
+// VenueFavoriteChangedPayload — площадка добавила мероприятие в избранное
+// (Favorited = true) или убрала его оттуда
+type VenueFavoriteChangedPayload struct {
+	EventID     int  `json:"event_id"`
+	VenueUserID int  `json:"venue_user_id"`
+	Favorited   bool `json:"favorited"`
+}
@@ -0,0 +1,59 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory — in-process Bus для тестов. Publish синхронно вызывает обработчики
+// всех групп и возвращает первую ошибку обработчика.
+type Memory struct {
+	mu        sync.Mutex
+	handlers  map[string]Handler
+	published []Event
+}
+
+func NewMemory() *Memory {
+	return &Memory{handlers: make(map[string]Handler)}
+}
+
+func (m *Memory) Publish(ctx context.Context, event Event) error {
+	m.mu.Lock()
+	m.published = append(m.published, event)
+	handlers := make([]Handler, 0, len(m.handlers))
+	for _, h := range m.handlers {
+		handlers = append(handlers, h)
+	}
+	m.mu.Unlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle регистрирует обработчик группы без блокировки
+func (m *Memory) Handle(group string, handler Handler) {
+	m.mu.Lock()
+	m.handlers[group] = handler
+	m.mu.Unlock()
+}
+
+func (m *Memory) Subscribe(ctx context.Context, group string, handler Handler) error {
+	m.Handle(group, handler)
+	<-ctx.Done()
+
+	m.mu.Lock()
+	delete(m.handlers, group)
+	m.mu.Unlock()
+	return nil
+}
+
+// Published возвращает копию всех опубликованных событий
+func (m *Memory) Published() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event(nil), m.published...)
+}
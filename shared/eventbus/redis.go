@@ -0,0 +1,146 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultStream — общий Redis Stream доменных событий всех сервисов
+const DefaultStream = "domain-events"
+
+const (
+	streamMaxLen  = 100000
+	readBatch     = 50
+	readBlock     = 5 * time.Second
+	claimMinIdle  = time.Minute
+	retryInterval = time.Second
+)
+
+// RedisStreams — Bus поверх Redis Streams и consumer groups.
+// Необработанные сообщения остаются в pending и через claimMinIdle
+// забираются повторно (at-least-once).
+type RedisStreams struct {
+	rdb      *redis.Client
+	stream   string
+	consumer string
+}
+
+func NewRedisStreams(rdb *redis.Client, stream, consumer string) *RedisStreams {
+	return &RedisStreams{rdb: rdb, stream: stream, consumer: consumer}
+}
+
+func (b *RedisStreams) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.stream,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": data},
+	}).Err()
+}
+
+func (b *RedisStreams) Subscribe(ctx context.Context, group string, handler Handler) error {
+	err := b.rdb.XGroupCreateMkStream(ctx, b.stream, group, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	reclaim := time.NewTicker(claimMinIdle)
+	defer reclaim.Stop()
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		select {
+		case <-reclaim.C:
+			b.reclaimPending(ctx, group, handler)
+		default:
+		}
+
+		streams, err := b.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{b.stream, ">"},
+			Count:    readBatch,
+			Block:    readBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("eventbus: read from %s failed: %v", b.stream, err)
+			time.Sleep(retryInterval)
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				b.handle(ctx, group, msg, handler)
+			}
+		}
+	}
+}
+
+// reclaimPending забирает сообщения, которые другой потребитель группы
+// получил, но так и не подтвердил (упал или обработчик вернул ошибку).
+func (b *RedisStreams) reclaimPending(ctx context.Context, group string, handler Handler) {
+	start := "0-0"
+	for {
+		msgs, next, err := b.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   b.stream,
+			Group:    group,
+			Consumer: b.consumer,
+			MinIdle:  claimMinIdle,
+			Start:    start,
+			Count:    readBatch,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("eventbus: reclaim on %s failed: %v", b.stream, err)
+			}
+			return
+		}
+		for _, msg := range msgs {
+			b.handle(ctx, group, msg, handler)
+		}
+		if next == "0-0" || len(msgs) == 0 {
+			return
+		}
+		start = next
+	}
+}
+
+func (b *RedisStreams) handle(ctx context.Context, group string, msg redis.XMessage, handler Handler) {
+	raw, _ := msg.Values["event"].(string)
+
+	var event Event
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		// Битое сообщение не станет корректным при повторе — подтверждаем и пропускаем
+		log.Printf("eventbus: dropping malformed message %s: %v", msg.ID, err)
+		b.rdb.XAck(ctx, b.stream, group, msg.ID)
+		return
+	}
+
+	if err := handler(ctx, event); err != nil {
+		log.Printf("eventbus: %s handler failed for %s (%s): %v", group, event.Type, event.ID, err)
+		return
+	}
+
+	if err := b.rdb.XAck(ctx, b.stream, group, msg.ID).Err(); err != nil {
+		log.Printf("eventbus: ack %s failed: %v", msg.ID, err)
+	}
+}
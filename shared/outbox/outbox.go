@@ -0,0 +1,154 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"shared/eventbus"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	relayInterval  = time.Second
+	relayBatchSize = 100
+	retention      = 7 * 24 * time.Hour
+	cleanupEvery   = time.Hour
+)
+
+type Message struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	EventType   string    `gorm:"not null"`
+	AggregateID int       `gorm:"not null"`
+	Payload     string    `gorm:"type:jsonb;not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	PublishedAt *time.Time
+	Attempts    int
+	LastError   string
+}
+
+// Outbox — таблица outbox одного сервиса. У каждого сервиса своя таблица,
+// а source попадает в eventbus.Event.Source.
+type Outbox struct {
+	table  string
+	source string
+}
+
+func New(table, source string) *Outbox {
+	return &Outbox{table: table, source: source}
+}
+
+// Enqueue сохраняет доменное событие в outbox. Вызывается внутри той же
+// транзакции, что и изменение состояния, поэтому событие публикуется
+// тогда и только тогда, когда изменение закоммичено.
+func (o *Outbox) Enqueue(tx *gorm.DB, eventType string, aggregateID int, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", eventType, err)
+	}
+	return tx.Table(o.table).Create(&Message{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     string(data),
+	}).Error
+}
+
+// Relay переносит неопубликованные сообщения из outbox в шину событий.
+// Реплик может быть несколько, но пачку в каждый момент публикует только одна:
+// она держит advisory lock таблицы до конца транзакции, остальные пропускают тик.
+// Так события уходят в шину строго в порядке записи.
+type Relay struct {
+	outbox    *Outbox
+	db        *gorm.DB
+	publisher eventbus.Publisher
+}
+
+func (o *Outbox) NewRelay(db *gorm.DB, publisher eventbus.Publisher) *Relay {
+	return &Relay{outbox: o, db: db, publisher: publisher}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.ProcessBatch(ctx); err != nil {
+				log.Printf("outbox: relay batch failed: %v", err)
+			}
+			if time.Since(lastCleanup) > cleanupEvery {
+				r.cleanup()
+				lastCleanup = time.Now()
+			}
+		}
+	}
+}
+
+// ProcessBatch публикует очередную пачку сообщений в порядке записи и
+// возвращает число опубликованных. На первой ошибке публикации пачка
+// прерывается, чтобы не нарушать порядок событий. Если пачку уже публикует
+// другая реплика, возвращает 0.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	table := r.outbox.table
+	published := 0
+	var publishErr error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", table).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var msgs []Message
+		if err := tx.Table(table).
+			Where("published_at IS NULL").
+			Order("id").
+			Limit(relayBatchSize).
+			Find(&msgs).Error; err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			event := eventbus.Event{
+				ID:          fmt.Sprintf("%s-%d", r.outbox.source, msg.ID),
+				Type:        msg.EventType,
+				Source:      r.outbox.source,
+				AggregateID: msg.AggregateID,
+				Payload:     json.RawMessage(msg.Payload),
+				OccurredAt:  msg.CreatedAt,
+			}
+
+			if publishErr = r.publisher.Publish(ctx, event); publishErr != nil {
+				return tx.Table(table).Where("id = ?", msg.ID).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": publishErr.Error(),
+				}).Error
+			}
+
+			if err := tx.Table(table).Where("id = ?", msg.ID).Update("published_at", time.Now()).Error; err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return published, err
+	}
+	return published, publishErr
+}
+
+func (r *Relay) cleanup() {
+	err := r.db.Table(r.outbox.table).Where("published_at < ?", time.Now().Add(-retention)).Delete(&Message{}).Error
+	if err != nil {
+		log.Printf("outbox: cleanup failed: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxLimit — максимальный размер страницы для всех списков
+const MaxLimit = 100
+
+var ErrInvalidCursor = errors.New("INVALID_CURSOR")
+
+// Cursor — позиция keyset-пагинации: последний отданный элемент страницы.
+// Списки отсортированы по (created_at DESC, id DESC), поэтому пара однозначно
+// задает место, с которого продолжать, даже если данные меняются между запросами.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int       `json:"i"`
+}
+
+// Encode возвращает непрозрачную строку курсора для next_cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Params — параметры запроса страницы
+type Params struct {
+	Limit     int
+	After     *Cursor
+	WithTotal bool
+}
+
+// FromQuery читает limit, cursor и include_total из query-параметров.
+// Некорректный limit заменяется на defaultLimit, слишком большой — на MaxLimit.
+func FromQuery(query url.Values, defaultLimit int) (Params, error) {
+	p := Params{Limit: defaultLimit}
+	if v := query.Get("limit"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil {
+			p.Limit = limit
+		}
+	}
+	if v := query.Get("cursor"); v != "" {
+		after, err := Decode(v)
+		if err != nil {
+			return Params{}, err
+		}
+		p.After = after
+	}
+	p.WithTotal, _ = strconv.ParseBool(query.Get("include_total"))
+	return p.Normalize(defaultLimit), nil
+}
+
+// Normalize приводит limit к диапазону [1; MaxLimit]
+func (p Params) Normalize(defaultLimit int) Params {
+	if p.Limit <= 0 {
+		p.Limit = defaultLimit
+	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+	return p
+}
+
+// Includes сообщает, попадает ли элемент в выборку после курсора
+func (p Params) Includes(createdAt time.Time, id int) bool {
+	if p.After == nil {
+		return true
+	}
+	if createdAt.Equal(p.After.CreatedAt) {
+		return id < p.After.ID
+	}
+	return createdAt.Before(p.After.CreatedAt)
+}
+
+// Apply добавляет к запросу условие после курсора, порядок (created_at DESC, id DESC)
+// и limit+1: лишняя строка говорит о том, что есть следующая страница.
+// table квалифицирует колонки, если в запросе есть JOIN.
+func Apply(query *gorm.DB, table string, p Params) *gorm.DB {
+	createdAt, id := table+".created_at", table+".id"
+	if p.After != nil {
+		query = query.Where(fmt.Sprintf("(%s, %s) < (?, ?)", createdAt, id), p.After.CreatedAt, p.After.ID)
+	}
+	return query.Order(createdAt + " DESC").Order(id + " DESC").Limit(p.Limit + 1)
+}
+
+// Count считает total для include_total=true. Запрос должен содержать только
+// фильтры — без курсора, сортировки и limit.
+func Count(query *gorm.DB, p Params) (*int64, error) {
+	if !p.WithTotal {
+		return nil, nil
+	}
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
+// Page — общий конверт ответа для списков
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      *int64  `json:"total,omitempty"`
+	Limit      int     `json:"limit"`
+}
+
+// NewPage обрезает выборку из Apply до p.Limit и выставляет next_cursor,
+// если за страницей есть еще элементы.
+func NewPage[T any](items []T, total *int64, p Params, cursorOf func(*T) Cursor) Page[T] {
+	page := Page[T]{Items: items, Total: total, Limit: p.Limit}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(page.Items) > p.Limit {
+		page.Items = page.Items[:p.Limit]
+		next := cursorOf(&page.Items[p.Limit-1]).Encode()
+		page.NextCursor = &next
+	}
+	return page
+}
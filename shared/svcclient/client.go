@@ -0,0 +1,140 @@
+// Package svcclient — HTTP-клиент внутренних вызовов между сервисами:
+// таймаут на попытку, повторы с экспоненциальной паузой и circuit breaker.
+// Типизированные клиенты сервисов строятся поверх Client.
+package svcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound — ресурс отсутствует в сервисе-источнике (HTTP 404)
+	ErrNotFound = errors.New("not found")
+	// ErrCircuitOpen — сервис недавно отказывал, запрос не выполнялся
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// Внутренние вызовы идут мимо gateway, поэтому user context выставляем сами:
+// сервисы требуют заголовки, но на ручках чтения не проверяют роль.
+const (
+	internalUserID = "0"
+	internalRole   = "service"
+)
+
+type Options struct {
+	Timeout          time.Duration // на одну попытку
+	Retries          int           // дополнительные попытки при сетевых ошибках и 5xx
+	Backoff          time.Duration // пауза перед первым повтором, далее удваивается
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Timeout:          2 * time.Second,
+		Retries:          2,
+		Backoff:          100 * time.Millisecond,
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+	}
+}
+
+// statusError — ответ сервиса с неожиданным статусом
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.code)
+}
+
+// retryable: повторяем сетевые ошибки, таймауты и 5xx.
+// 4xx означает, что сервис жив и ответил осмысленно.
+func retryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= 500
+	}
+	return !errors.Is(err, ErrNotFound)
+}
+
+// Client — клиент одного сервиса со своим breaker'ом
+type Client struct {
+	name    string
+	baseURL string
+	http    *http.Client
+	opts    Options
+	breaker *Breaker
+}
+
+// New создает клиент сервиса name; name попадает в тексты ошибок
+func New(name, baseURL string, opts Options) *Client {
+	return &Client{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: opts.Timeout},
+		opts:    opts,
+		breaker: NewBreaker(opts.FailureThreshold, opts.Cooldown),
+	}
+}
+
+// GetJSON выполняет GET с повторами и декодирует ответ в out.
+// path может содержать query-строку.
+func (c *Client) GetJSON(ctx context.Context, path string, out interface{}) error {
+	var lastErr error
+	backoff := c.opts.Backoff
+
+	for attempt := 0; attempt <= c.opts.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if !c.breaker.Allow() {
+			return fmt.Errorf("%s: %w", c.name, ErrCircuitOpen)
+		}
+
+		err := c.doGet(ctx, path, out)
+		if err == nil || !retryable(err) {
+			c.breaker.Success()
+			return err
+		}
+		c.breaker.Failure()
+		lastErr = err
+	}
+	return fmt.Errorf("%s GET %s: %w", c.name, path, lastErr)
+}
+
+func (c *Client) doGet(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-User-ID", internalUserID)
+	req.Header.Set("X-User-Role", internalRole)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return &statusError{code: resp.StatusCode}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
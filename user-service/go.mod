@@ -3,6 +3,7 @@ module user-service
 go 1.25.0
 
 require (
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/gin-gonic/gin v1.10.0
 	github.com/go-playground/validator/v10 v10.20.0
@@ -18,8 +19,10 @@ require (
 	github.com/testcontainers/testcontainers-go/modules/postgres v0.37.0
 	github.com/testcontainers/testcontainers-go/modules/redis v0.37.0
 	golang.org/x/crypto v0.49.0
+	golang.org/x/image v0.38.0
 	gorm.io/driver/postgres v1.5.11
 	gorm.io/gorm v1.25.12
+	shared v0.0.0
 )
 
 require (
@@ -132,3 +135,5 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	sigs.k8s.io/yaml v1.3.0 // indirect
 )
+
+replace shared => ../shared
@@ -0,0 +1,46 @@
+package clients
+
+import (
+	"context"
+	"shared/svcclient"
+	"strconv"
+	"strings"
+)
+
+// Rating — агрегированная оценка пользователя по видимым отзывам из application-service
+type Rating struct {
+	UserID  int     `json:"user_id"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type ApplicationClient interface {
+	// GetRatings возвращает оценки пользователей из userIDs; для пользователей
+	// без отзывов application-service отдает нулевую оценку
+	GetRatings(ctx context.Context, userIDs []int) ([]Rating, error)
+}
+
+type HTTPApplicationClient struct {
+	c *svcclient.Client
+}
+
+func NewApplicationClient(baseURL string, opts svcclient.Options) *HTTPApplicationClient {
+	return &HTTPApplicationClient{c: svcclient.New("application-service", baseURL, opts)}
+}
+
+func (a *HTTPApplicationClient) GetRatings(ctx context.Context, userIDs []int) ([]Rating, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	var resp struct {
+		Ratings []Rating `json:"ratings"`
+	}
+	if err := a.c.GetJSON(ctx, "/internal/ratings?user_ids="+strings.Join(ids, ","), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Ratings, nil
+}
@@ -1,6 +1,10 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 type Config struct {
 	Port           string
@@ -14,6 +18,29 @@ type Config struct {
 	MinioAccessKey string
 	MinioSecretKey string
 	MinioUseSSL    bool
+	MinioRegion    string
+
+	// Адрес MinIO, доступный клиентам, — на него выписываются presigned-ссылки
+	MinioPublicEndpoint string
+	MinioPublicUseSSL   bool
+
+	ImageURLTTL     time.Duration
+	ImageQuotaBytes int64
+	// Сколько непривязанное изображение живет до удаления сборщиком
+	ImageOrphanGrace time.Duration
+
+	// Прямые загрузки в MinIO: предельный размер файла и время жизни выданных ссылок
+	ImageDirectUploadMaxBytes int64
+	ImageUploadTTL            time.Duration
+
+	// Адрес clamd (host:port); пустой — используется локальный сигнатурный сканер
+	ClamdAddr string
+
+	// Сколько фото может быть в галерее одного профиля
+	GalleryMaxPhotos int
+
+	// Адрес application-service — источник оценок пользователей по отзывам
+	ApplicationServiceURL string
 }
 
 func Load() *Config {
@@ -28,6 +55,23 @@ func Load() *Config {
 		MinioAccessKey: getEnv("MINIO_ACCESS_KEY", ""),
 		MinioSecretKey: getEnv("MINIO_SECRET_KEY", ""),
 		MinioUseSSL:    getEnv("MINIO_USE_SSL", "false") == "true",
+		MinioRegion:    getEnv("MINIO_REGION", "us-east-1"),
+
+		MinioPublicEndpoint: getEnv("MINIO_PUBLIC_ENDPOINT", getEnv("MINIO_ENDPOINT", "minio:9000")),
+		MinioPublicUseSSL:   getEnv("MINIO_PUBLIC_USE_SSL", getEnv("MINIO_USE_SSL", "false")) == "true",
+
+		ImageURLTTL:      getDuration("IMAGE_URL_TTL", 15*time.Minute),
+		ImageQuotaBytes:  getInt64("IMAGE_QUOTA_MB", 500) * 1024 * 1024,
+		ImageOrphanGrace: getDuration("IMAGE_ORPHAN_GRACE", 24*time.Hour),
+
+		ImageDirectUploadMaxBytes: getInt64("IMAGE_DIRECT_UPLOAD_MAX_MB", 50) * 1024 * 1024,
+		ImageUploadTTL:            getDuration("IMAGE_UPLOAD_TTL", time.Hour),
+
+		ClamdAddr: getEnv("CLAMD_ADDR", ""),
+
+		GalleryMaxPhotos: int(getInt64("GALLERY_MAX_PHOTOS", 30)),
+
+		ApplicationServiceURL: getEnv("APPLICATION_SERVICE_URL", "http://application-service:8083"),
 	}
 }
 
@@ -37,3 +81,17 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getInt64(key string, defaultValue int64) int64 {
+	if v, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultValue
+}
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return defaultValue
+}
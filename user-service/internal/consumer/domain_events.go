@@ -0,0 +1,32 @@
+package consumer
+
+import (
+	"context"
+	"shared/eventbus"
+	"user-service/internal/service"
+)
+
+// Group — consumer group user-service в шине доменных событий
+const Group = "user-service"
+
+// DomainEventConsumer сбрасывает кэш похожих создателей, когда в event-service
+// меняются данные, по которым он считается: мероприятия, их категории и
+// избранное площадок. Сброс идемпотентен, повторная доставка безопасна.
+type DomainEventConsumer struct {
+	similar *service.SimilarService
+}
+
+func NewDomainEventConsumer(similar *service.SimilarService) *DomainEventConsumer {
+	return &DomainEventConsumer{similar: similar}
+}
+
+func (c *DomainEventConsumer) Handle(ctx context.Context, event eventbus.Event) error {
+	switch event.Type {
+	case eventbus.EventPublished,
+		eventbus.EventCategoriesChanged,
+		eventbus.EventDeleted,
+		eventbus.VenueFavoriteChanged:
+		c.similar.InvalidateCreators()
+	}
+	return nil
+}
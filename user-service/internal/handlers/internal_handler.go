@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"user-service/internal/apperror"
+	"user-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImageRefsResponse struct {
+	Images []service.ImageRef `json:"images"`
+}
+
+// GetImageRefs godoc
+// @Summary      Сведения об изображениях для других сервисов
+// @Description  Внутренняя ручка: владелец, имя и тип файла изображений из ids. Отклоненные и отсутствующие изображения в ответ не попадают. Через gateway недоступна.
+// @Tags         internal
+// @Produce      json
+// @Param        ids query string true "UUID изображений через запятую"
+// @Success      200 {object} ImageRefsResponse
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Router       /internal/images [get]
+func (h *UserHandler) GetImageRefs(c *gin.Context) {
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid image ID: "+id))
+			return
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 || len(ids) > service.MaxImageRefs {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_PARAM", fmt.Sprintf("ids must list from 1 to %d image IDs", service.MaxImageRefs)))
+		return
+	}
+
+	refs, err := h.imageService.GetImageRefs(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get images"))
+		return
+	}
+
+	c.JSON(http.StatusOK, ImageRefsResponse{Images: refs})
+}
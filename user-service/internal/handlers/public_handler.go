@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"shared/pagination"
 	"strconv"
 	"time"
 	"user-service/internal/apperror"
 	"user-service/internal/models"
+	"user-service/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,32 +28,34 @@ type PublicCreatorResponse struct {
 	UpdatedAt   time.Time             `json:"updated_at"`
 	Photo       *models.Image         `json:"photo,omitempty"`
 	Photos      []models.CreatorPhoto `json:"photos,omitempty"`
+	Rating      models.RatingSummary  `json:"rating"`
 }
 
 type PublicVenueResponse struct {
-	ID            int                 `json:"id"`
-	UserID        int                 `json:"user_id"`
-	Name          string              `json:"name"`
-	Description   string              `json:"description,omitempty"`
-	StreetAddress string              `json:"street_address,omitempty"`
-	CityID        *int                `json:"city_id,omitempty"`
-	OpeningHours  string              `json:"opening_hours,omitempty"`
-	Capacity      int                 `json:"capacity,omitempty"`
-	LogoID        *string             `json:"logo_id,omitempty"`
-	CoverPhotoID  *string             `json:"cover_photo_id,omitempty"`
-	TgChannel     string              `json:"tg_channel_link,omitempty"`
-	VkLink        string              `json:"vk_link,omitempty"`
-	TiktokLink    string              `json:"tiktok_link,omitempty"`
-	YoutubeLink   string              `json:"youtube_link,omitempty"`
-	DzenLink      string              `json:"dzen_link,omitempty"`
-	CreatedAt     time.Time           `json:"created_at"`
-	UpdatedAt     time.Time           `json:"updated_at"`
-	Logo          *models.Image       `json:"logo,omitempty"`
-	CoverPhoto    *models.Image       `json:"cover_photo,omitempty"`
-	Photos        []models.VenuePhoto `json:"photos,omitempty"`
+	ID            int                  `json:"id"`
+	UserID        int                  `json:"user_id"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description,omitempty"`
+	StreetAddress string               `json:"street_address,omitempty"`
+	CityID        *int                 `json:"city_id,omitempty"`
+	OpeningHours  string               `json:"opening_hours,omitempty"`
+	Capacity      int                  `json:"capacity,omitempty"`
+	LogoID        *string              `json:"logo_id,omitempty"`
+	CoverPhotoID  *string              `json:"cover_photo_id,omitempty"`
+	TgChannel     string               `json:"tg_channel_link,omitempty"`
+	VkLink        string               `json:"vk_link,omitempty"`
+	TiktokLink    string               `json:"tiktok_link,omitempty"`
+	YoutubeLink   string               `json:"youtube_link,omitempty"`
+	DzenLink      string               `json:"dzen_link,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	Logo          *models.Image        `json:"logo,omitempty"`
+	CoverPhoto    *models.Image        `json:"cover_photo,omitempty"`
+	Photos        []models.VenuePhoto  `json:"photos,omitempty"`
+	Rating        models.RatingSummary `json:"rating"`
 }
 
-func toPublicCreator(c *models.Creator) PublicCreatorResponse {
+func toPublicCreator(c *models.Creator, rating models.RatingSummary) PublicCreatorResponse {
 	return PublicCreatorResponse{
 		ID:          c.ID,
 		UserID:      c.UserID,
@@ -66,10 +71,11 @@ func toPublicCreator(c *models.Creator) PublicCreatorResponse {
 		UpdatedAt:   c.UpdatedAt,
 		Photo:       c.Photo,
 		Photos:      c.Photos,
+		Rating:      rating,
 	}
 }
 
-func toPublicVenue(v *models.Venue) PublicVenueResponse {
+func toPublicVenue(v *models.Venue, rating models.RatingSummary) PublicVenueResponse {
 	return PublicVenueResponse{
 		ID:            v.ID,
 		UserID:        v.UserID,
@@ -91,18 +97,21 @@ func toPublicVenue(v *models.Venue) PublicVenueResponse {
 		Logo:          v.Logo,
 		CoverPhoto:    v.CoverPhoto,
 		Photos:        v.Photos,
+		Rating:        rating,
 	}
 }
 
 // GetPublicCreator godoc
 // @Summary      Публичный профиль создателя
-// @Description  Возвращает профиль создателя без личных контактов (phone, email, личный TG)
+// @Description  Возвращает профиль создателя без личных контактов (phone, email, личный TG) с агрегированным рейтингом по отзывам
 // @Tags         public
 // @Produce      json
 // @Param        user_id path int true "User ID"
 // @Success      200 {object} PublicCreatorResponse
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Failure      503 {object} apperror.ErrorResponse
 // @Router       /public/creators/{user_id} [get]
 func (h *UserHandler) GetPublicCreator(c *gin.Context) {
 	userID, err := strconv.Atoi(c.Param("user_id"))
@@ -113,11 +122,21 @@ func (h *UserHandler) GetPublicCreator(c *gin.Context) {
 
 	creator, err := h.userService.GetCreatorByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator not found"))
+		if errors.Is(err, service.ErrCreatorNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get creator"))
+		return
+	}
+
+	ratings, err := h.userService.GetRatingSummaries([]int{creator.UserID})
+	if err != nil {
+		respondRatingError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, toPublicCreator(creator))
+	c.JSON(http.StatusOK, toPublicCreator(creator, ratings[creator.UserID]))
 }
 
 // ListPublicCreators godoc
@@ -125,42 +144,61 @@ func (h *UserHandler) GetPublicCreator(c *gin.Context) {
 // @Description  Возвращает список создателей без личных контактов
 // @Tags         public
 // @Produce      json
-// @Param        limit  query int false "Количество элементов (по умолчанию 20)"
-// @Param        offset query int false "Смещение (по умолчанию 0)"
-// @Success      200 {object} map[string]interface{}
+// @Param        limit         query int    false "Количество элементов (по умолчанию 20, максимум 100)"
+// @Param        cursor        query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param        include_total query bool   false "Посчитать общее количество (total)"
+// @Success      200 {object} pagination.Page[PublicCreatorResponse]
+// @Failure      400 {object} apperror.ErrorResponse
 // @Failure      500 {object} apperror.ErrorResponse
+// @Failure      503 {object} apperror.ErrorResponse
 // @Router       /public/creators [get]
 func (h *UserHandler) ListPublicCreators(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	creators, err := h.userService.ListCreators(limit, offset)
+	creators, err := h.userService.ListCreators(page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch creators"))
 		return
 	}
 
-	public := make([]PublicCreatorResponse, len(creators))
-	for i := range creators {
-		public[i] = toPublicCreator(&creators[i])
+	userIDs := make([]int, len(creators.Items))
+	for i := range creators.Items {
+		userIDs[i] = creators.Items[i].UserID
+	}
+	ratings, err := h.userService.GetRatingSummaries(userIDs)
+	if err != nil {
+		respondRatingError(c, err)
+		return
+	}
+
+	public := make([]PublicCreatorResponse, len(creators.Items))
+	for i := range creators.Items {
+		public[i] = toPublicCreator(&creators.Items[i], ratings[creators.Items[i].UserID])
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"creators": public,
-		"limit":    limit,
-		"offset":   offset,
+	c.JSON(http.StatusOK, pagination.Page[PublicCreatorResponse]{
+		Items:      public,
+		NextCursor: creators.NextCursor,
+		Total:      creators.Total,
+		Limit:      creators.Limit,
 	})
 }
 
 // GetPublicVenue godoc
 // @Summary      Публичный профиль площадки
-// @Description  Возвращает профиль площадки без личных контактов (phone, email, личный TG)
+// @Description  Возвращает профиль площадки без личных контактов (phone, email, личный TG) с агрегированным рейтингом по отзывам
 // @Tags         public
 // @Produce      json
 // @Param        user_id path int true "User ID"
 // @Success      200 {object} PublicVenueResponse
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Failure      503 {object} apperror.ErrorResponse
 // @Router       /public/venues/{user_id} [get]
 func (h *UserHandler) GetPublicVenue(c *gin.Context) {
 	userID, err := strconv.Atoi(c.Param("user_id"))
@@ -171,11 +209,21 @@ func (h *UserHandler) GetPublicVenue(c *gin.Context) {
 
 	venue, err := h.userService.GetVenueByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue not found"))
+		if errors.Is(err, service.ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get venue"))
 		return
 	}
 
-	c.JSON(http.StatusOK, toPublicVenue(venue))
+	ratings, err := h.userService.GetRatingSummaries([]int{venue.UserID})
+	if err != nil {
+		respondRatingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPublicVenue(venue, ratings[venue.UserID]))
 }
 
 // ListPublicVenues godoc
@@ -183,30 +231,174 @@ func (h *UserHandler) GetPublicVenue(c *gin.Context) {
 // @Description  Возвращает список площадок без личных контактов
 // @Tags         public
 // @Produce      json
-// @Param        limit  query int false "Количество элементов (по умолчанию 20)"
-// @Param        offset query int false "Смещение (по умолчанию 0)"
-// @Success      200 {object} map[string]interface{}
+// @Param        limit         query int    false "Количество элементов (по умолчанию 20, максимум 100)"
+// @Param        cursor        query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param        include_total query bool   false "Посчитать общее количество (total)"
+// @Success      200 {object} pagination.Page[PublicVenueResponse]
+// @Failure      400 {object} apperror.ErrorResponse
 // @Failure      500 {object} apperror.ErrorResponse
+// @Failure      503 {object} apperror.ErrorResponse
 // @Router       /public/venues [get]
 func (h *UserHandler) ListPublicVenues(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	venues, err := h.userService.ListVenues(limit, offset)
+	venues, err := h.userService.ListVenues(page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch venues"))
 		return
 	}
 
-	public := make([]PublicVenueResponse, len(venues))
-	for i := range venues {
-		public[i] = toPublicVenue(&venues[i])
+	userIDs := make([]int, len(venues.Items))
+	for i := range venues.Items {
+		userIDs[i] = venues.Items[i].UserID
+	}
+	ratings, err := h.userService.GetRatingSummaries(userIDs)
+	if err != nil {
+		respondRatingError(c, err)
+		return
+	}
+
+	public := make([]PublicVenueResponse, len(venues.Items))
+	for i := range venues.Items {
+		public[i] = toPublicVenue(&venues.Items[i], ratings[venues.Items[i].UserID])
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"venues": public,
-		"limit":  limit,
-		"offset": offset,
+	c.JSON(http.StatusOK, pagination.Page[PublicVenueResponse]{
+		Items:      public,
+		NextCursor: venues.NextCursor,
+		Total:      venues.Total,
+		Limit:      venues.Limit,
 	})
 }
 
+type SimilarCreatorResponse struct {
+	PublicCreatorResponse
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type SimilarVenueResponse struct {
+	PublicVenueResponse
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// GetSimilarCreators godoc
+// @Summary      Похожие создатели
+// @Description  Возвращает создателей, похожих на данного, по категориям мероприятий, городам сотрудничеств и площадкам, которые добавили в избранное мероприятия обоих. В reasons перечислены сработавшие сигналы
+// @Tags         public
+// @Produce      json
+// @Param        user_id path  int true  "User ID"
+// @Param        limit   query int false "Количество элементов (по умолчанию 6, максимум 20)"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Failure      503 {object} apperror.ErrorResponse
+// @Router       /public/creators/{user_id}/similar [get]
+func (h *UserHandler) GetSimilarCreators(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid user ID"))
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	similar, err := h.similarService.SimilarCreators(userID, limit)
+	if errors.Is(err, service.ErrCreatorNotFound) {
+		c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator not found"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch similar creators"))
+		return
+	}
+
+	userIDs := make([]int, len(similar))
+	for i := range similar {
+		userIDs[i] = similar[i].Creator.UserID
+	}
+	ratings, err := h.userService.GetRatingSummaries(userIDs)
+	if err != nil {
+		respondRatingError(c, err)
+		return
+	}
+
+	creators := make([]SimilarCreatorResponse, len(similar))
+	for i := range similar {
+		creators[i] = SimilarCreatorResponse{
+			PublicCreatorResponse: toPublicCreator(&similar[i].Creator, ratings[similar[i].Creator.UserID]),
+			Score:                 similar[i].Match.Score,
+			Reasons:               similar[i].Match.Reasons,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"creators": creators})
+}
+
+// GetSimilarVenues godoc
+// @Summary      Похожие площадки
+// @Description  Возвращает площадки, похожие на данную, по категориям, городу, вместимости и создателям, которые добавили в избранное обе площадки. В reasons перечислены сработавшие сигналы
+// @Tags         public
+// @Produce      json
+// @Param        user_id path  int true  "User ID"
+// @Param        limit   query int false "Количество элементов (по умолчанию 6, максимум 20)"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Failure      503 {object} apperror.ErrorResponse
+// @Router       /public/venues/{user_id}/similar [get]
+func (h *UserHandler) GetSimilarVenues(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid user ID"))
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	similar, err := h.similarService.SimilarVenues(userID, limit)
+	if errors.Is(err, service.ErrVenueNotFound) {
+		c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue not found"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch similar venues"))
+		return
+	}
+
+	userIDs := make([]int, len(similar))
+	for i := range similar {
+		userIDs[i] = similar[i].Venue.UserID
+	}
+	ratings, err := h.userService.GetRatingSummaries(userIDs)
+	if err != nil {
+		respondRatingError(c, err)
+		return
+	}
+
+	venues := make([]SimilarVenueResponse, len(similar))
+	for i := range similar {
+		venues[i] = SimilarVenueResponse{
+			PublicVenueResponse: toPublicVenue(&similar[i].Venue, ratings[similar[i].Venue.UserID]),
+			Score:               similar[i].Match.Score,
+			Reasons:             similar[i].Match.Reasons,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"venues": venues})
+}
+
+// respondRatingError отвечает на ошибку получения оценок: их считает
+// application-service, и его недоступность — не внутренняя ошибка user-service
+func respondRatingError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrDependencyUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, apperror.One("DEPENDENCY_UNAVAILABLE", "Ratings are temporarily unavailable, try again later"))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch ratings"))
+}
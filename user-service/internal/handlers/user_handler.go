@@ -4,23 +4,28 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"shared/pagination"
 	"strconv"
+	"time"
 	"user-service/internal/apperror"
 	"user-service/internal/middleware"
+	"user-service/internal/models"
 	"user-service/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserHandler struct {
-	userService  *service.UserService
-	imageService *service.ImageService
+	userService    *service.UserService
+	imageService   *service.ImageService
+	similarService *service.SimilarService
 }
 
-func NewUserHandler(userService *service.UserService, imageService *service.ImageService) *UserHandler {
+func NewUserHandler(userService *service.UserService, imageService *service.ImageService, similarService *service.SimilarService) *UserHandler {
 	return &UserHandler{
-		userService:  userService,
-		imageService: imageService,
+		userService:    userService,
+		imageService:   imageService,
+		similarService: similarService,
 	}
 }
 
@@ -38,6 +43,8 @@ func NewUserHandler(userService *service.UserService, imageService *service.Imag
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      409 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Router       /users/creators [post]
 func (h *UserHandler) CreateCreator(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
@@ -58,6 +65,9 @@ func (h *UserHandler) CreateCreator(c *gin.Context) {
 
 	creator, err := h.userService.CreateCreator(userID, &req)
 	if err != nil {
+		if respondImageReferenceError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrProfileAlreadyExists) {
 			c.JSON(http.StatusConflict, apperror.One("PROFILE_ALREADY_EXISTS", "Creator profile already exists for this user"))
 			return
@@ -79,6 +89,7 @@ func (h *UserHandler) CreateCreator(c *gin.Context) {
 // @Success      200 {object} models.Creator "Профиль создателя"
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
 // @Router       /users/creators/{user_id} [get]
 func (h *UserHandler) GetCreator(c *gin.Context) {
 	userID, err := strconv.Atoi(c.Param("user_id"))
@@ -89,7 +100,11 @@ func (h *UserHandler) GetCreator(c *gin.Context) {
 
 	creator, err := h.userService.GetCreatorByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator not found"))
+		if errors.Is(err, service.ErrCreatorNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get creator"))
 		return
 	}
 
@@ -140,6 +155,7 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      403 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Router       /users/creators/{user_id} [put]
 func (h *UserHandler) UpdateCreator(c *gin.Context) {
 	currentUserID, ok := middleware.GetUserID(c)
@@ -166,6 +182,9 @@ func (h *UserHandler) UpdateCreator(c *gin.Context) {
 
 	creator, err := h.userService.UpdateCreatorByUserID(targetUserID, currentUserID, &req)
 	if err != nil {
+		if respondImageReferenceError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrAccessDenied) {
 			c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You can only edit your own profile"))
 			return
@@ -219,26 +238,27 @@ func (h *UserHandler) DeleteCreator(c *gin.Context) {
 // @Tags         creators
 // @Produce      json
 // @Security     BearerAuth
-// @Param        limit query int false "Количество элементов (по умолчанию 20, максимум 100)"
-// @Param        offset query int false "Смещение (по умолчанию 0)"
-// @Success      200 {object} map[string]interface{} "Список создателей"
+// @Param        limit         query int    false "Количество элементов (по умолчанию 20, максимум 100)"
+// @Param        cursor        query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param        include_total query bool   false "Посчитать общее количество (total)"
+// @Success      200 {object} pagination.Page[models.Creator] "Список создателей"
+// @Failure      400 {object} apperror.ErrorResponse
 // @Failure      500 {object} apperror.ErrorResponse
 // @Router       /users/creators [get]
 func (h *UserHandler) ListCreators(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	creators, err := h.userService.ListCreators(limit, offset)
+	creators, err := h.userService.ListCreators(page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch creators"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"creators": creators,
-		"limit":    limit,
-		"offset":   offset,
-	})
+	c.JSON(http.StatusOK, creators)
 }
 
 // Venue handlers
@@ -255,6 +275,8 @@ func (h *UserHandler) ListCreators(c *gin.Context) {
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      409 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Router       /users/venues [post]
 func (h *UserHandler) CreateVenue(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
@@ -275,6 +297,9 @@ func (h *UserHandler) CreateVenue(c *gin.Context) {
 
 	venue, err := h.userService.CreateVenue(userID, &req)
 	if err != nil {
+		if respondImageReferenceError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrProfileAlreadyExists) {
 			c.JSON(http.StatusConflict, apperror.One("PROFILE_ALREADY_EXISTS", "Venue profile already exists for this user"))
 			return
@@ -296,6 +321,7 @@ func (h *UserHandler) CreateVenue(c *gin.Context) {
 // @Success      200 {object} models.Venue "Профиль площадки"
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
 // @Router       /users/venues/{user_id} [get]
 func (h *UserHandler) GetVenue(c *gin.Context) {
 	userID, err := strconv.Atoi(c.Param("user_id"))
@@ -306,7 +332,11 @@ func (h *UserHandler) GetVenue(c *gin.Context) {
 
 	venue, err := h.userService.GetVenueByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue not found"))
+		if errors.Is(err, service.ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get venue"))
 		return
 	}
 
@@ -319,26 +349,27 @@ func (h *UserHandler) GetVenue(c *gin.Context) {
 // @Tags         venues
 // @Produce      json
 // @Security     BearerAuth
-// @Param        limit query int false "Количество элементов (по умолчанию 20, максимум 100)"
-// @Param        offset query int false "Смещение (по умолчанию 0)"
-// @Success      200 {object} map[string]interface{} "Список площадок"
+// @Param        limit         query int    false "Количество элементов (по умолчанию 20, максимум 100)"
+// @Param        cursor        query string false "Курсор следующей страницы (next_cursor из предыдущего ответа)"
+// @Param        include_total query bool   false "Посчитать общее количество (total)"
+// @Success      200 {object} pagination.Page[models.Venue] "Список площадок"
+// @Failure      400 {object} apperror.ErrorResponse
 // @Failure      500 {object} apperror.ErrorResponse
 // @Router       /users/venues [get]
 func (h *UserHandler) ListVenues(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := pagination.FromQuery(c.Request.URL.Query(), service.DefaultListLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_CURSOR", "Invalid cursor"))
+		return
+	}
 
-	venues, err := h.userService.ListVenues(limit, offset)
+	venues, err := h.userService.ListVenues(page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to fetch venues"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"venues": venues,
-		"limit":  limit,
-		"offset": offset,
-	})
+	c.JSON(http.StatusOK, venues)
 }
 
 // UpdateVenue godoc
@@ -355,6 +386,7 @@ func (h *UserHandler) ListVenues(c *gin.Context) {
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      403 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Router       /users/venues/{user_id} [put]
 func (h *UserHandler) UpdateVenue(c *gin.Context) {
 	currentUserID, ok := middleware.GetUserID(c)
@@ -381,6 +413,9 @@ func (h *UserHandler) UpdateVenue(c *gin.Context) {
 
 	venue, err := h.userService.UpdateVenueByUserID(targetUserID, currentUserID, &req)
 	if err != nil {
+		if respondImageReferenceError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrAccessDenied) {
 			c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You can only edit your own profile"))
 			return
@@ -430,16 +465,18 @@ func (h *UserHandler) DeleteVenue(c *gin.Context) {
 
 // AddCreatorPhoto godoc
 // @Summary      Добавить фото создателя
-// @Description  Привязывает загруженное изображение к галерее создателя
+// @Description  Добавляет загруженное изображение в конец галереи создателя с подписью и alt-текстом
 // @Tags         creators
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        request body map[string]string true "UUID изображения" example({"image_id": "550e8400-e29b-41d4-a716-446655440000"})
+// @Param        request body service.GalleryPhotoInput true "Изображение, подпись и alt-текст"
 // @Success      201 {object} models.CreatorPhoto "Фото добавлено"
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Router       /users/creators/photos [post]
 func (h *UserHandler) AddCreatorPhoto(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
@@ -448,9 +485,7 @@ func (h *UserHandler) AddCreatorPhoto(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		ImageID string `json:"image_id" binding:"required"`
-	}
+	var req service.GalleryPhotoInput
 	if err := c.ShouldBindJSON(&req); err != nil {
 		if resp, ok := apperror.FromValidation(err); ok {
 			c.JSON(http.StatusBadRequest, resp)
@@ -460,8 +495,11 @@ func (h *UserHandler) AddCreatorPhoto(c *gin.Context) {
 		return
 	}
 
-	photo, err := h.userService.AddCreatorPhoto(userID, req.ImageID)
+	photo, err := h.userService.AddCreatorPhoto(userID, req)
 	if err != nil {
+		if respondImageReferenceError(c, err) || respondGalleryError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrCreatorNotFound) {
 			c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator profile not found"))
 			return
@@ -473,6 +511,56 @@ func (h *UserHandler) AddCreatorPhoto(c *gin.Context) {
 	c.JSON(http.StatusCreated, photo)
 }
 
+// AddCreatorPhotos godoc
+// @Summary      Добавить несколько фото создателя
+// @Description  Добавляет изображения в конец галереи создателя в переданном порядке. Добавляются все или ни одного; галерея не может превысить лимит фото.
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body object true "Список фото" example({"photos": [{"image_id": "550e8400-e29b-41d4-a716-446655440000", "caption": "Главный зал", "alt_text": "Сцена и зрительный зал"}]})
+// @Success      201 {array} models.CreatorPhoto "Фото добавлены"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
+// @Router       /users/creators/photos/batch [post]
+func (h *UserHandler) AddCreatorPhotos(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		Photos []service.GalleryPhotoInput `json:"photos" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	photos, err := h.userService.AddCreatorPhotos(userID, req.Photos)
+	if err != nil {
+		if respondImageReferenceError(c, err) || respondGalleryError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrCreatorNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator profile not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to add photos"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, photos)
+}
+
 // DeleteCreatorPhoto godoc
 // @Summary      Удалить фото создателя
 // @Description  Удаляет фото из галереи создателя (только своё фото)
@@ -514,18 +602,166 @@ func (h *UserHandler) DeleteCreatorPhoto(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// UpdateCreatorPhoto godoc
+// @Summary      Изменить фото создателя
+// @Description  Меняет подпись, alt-текст и флаг обложки фото галереи. Новая обложка снимает флаг с предыдущей.
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        photo_id path int true "ID записи фото"
+// @Param        request body service.UpdateGalleryPhotoRequest true "Изменяемые поля"
+// @Success      200 {object} models.CreatorPhoto
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Router       /users/creators/photos/{photo_id} [patch]
+func (h *UserHandler) UpdateCreatorPhoto(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	photoID, err := strconv.Atoi(c.Param("photo_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid photo ID"))
+		return
+	}
+
+	var req service.UpdateGalleryPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	photo, err := h.userService.UpdateCreatorPhoto(userID, photoID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You can only edit your own photos"))
+			return
+		}
+		if errors.Is(err, service.ErrPhotoNotFound) || errors.Is(err, service.ErrCreatorNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("PHOTO_NOT_FOUND", "Photo not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to update photo"))
+		return
+	}
+
+	c.JSON(http.StatusOK, photo)
+}
+
+// ReorderCreatorPhotos godoc
+// @Summary      Переставить фото создателя
+// @Description  Задает новый порядок галереи: photo_ids должен содержать все фото галереи ровно по одному разу
+// @Tags         creators
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body object true "ID фото в новом порядке" example({"photo_ids": [3, 1, 2]})
+// @Success      200 {array} models.CreatorPhoto "Галерея в новом порядке"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Router       /users/creators/photos/order [put]
+func (h *UserHandler) ReorderCreatorPhotos(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		PhotoIDs []int `json:"photo_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	photos, err := h.userService.ReorderCreatorPhotos(userID, req.PhotoIDs)
+	if err != nil {
+		if respondGalleryError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrCreatorNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("CREATOR_NOT_FOUND", "Creator profile not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to reorder photos"))
+		return
+	}
+
+	c.JSON(http.StatusOK, photos)
+}
+
+// DeleteCreatorPhotos godoc
+// @Summary      Удалить несколько фото создателя
+// @Description  Удаляет фото из галереи одной операцией: если хоть одно фото не принадлежит галерее, не удаляется ни одно. Оставшиеся фото сдвигаются без пропусков.
+// @Tags         creators
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body object true "ID удаляемых фото" example({"photo_ids": [1, 2]})
+// @Success      204 "Фото удалены"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Router       /users/creators/photos/batch-delete [post]
+func (h *UserHandler) DeleteCreatorPhotos(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		PhotoIDs []int `json:"photo_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	if err := h.userService.DeleteCreatorPhotos(userID, req.PhotoIDs); err != nil {
+		if errors.Is(err, service.ErrPhotoNotFound) || errors.Is(err, service.ErrCreatorNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("PHOTO_NOT_FOUND", "Photo not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to delete photos"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // AddVenuePhoto godoc
 // @Summary      Добавить фото площадки
-// @Description  Привязывает загруженное изображение к галерее площадки
+// @Description  Добавляет загруженное изображение в конец галереи площадки с подписью и alt-текстом
 // @Tags         venues
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        request body map[string]string true "UUID изображения" example({"image_id": "550e8400-e29b-41d4-a716-446655440000"})
+// @Param        request body service.GalleryPhotoInput true "Изображение, подпись и alt-текст"
 // @Success      201 {object} models.VenuePhoto "Фото добавлено"
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Router       /users/venues/photos [post]
 func (h *UserHandler) AddVenuePhoto(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
@@ -534,9 +770,7 @@ func (h *UserHandler) AddVenuePhoto(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		ImageID string `json:"image_id" binding:"required"`
-	}
+	var req service.GalleryPhotoInput
 	if err := c.ShouldBindJSON(&req); err != nil {
 		if resp, ok := apperror.FromValidation(err); ok {
 			c.JSON(http.StatusBadRequest, resp)
@@ -546,8 +780,11 @@ func (h *UserHandler) AddVenuePhoto(c *gin.Context) {
 		return
 	}
 
-	photo, err := h.userService.AddVenuePhoto(userID, req.ImageID)
+	photo, err := h.userService.AddVenuePhoto(userID, req)
 	if err != nil {
+		if respondImageReferenceError(c, err) || respondGalleryError(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrVenueNotFound) {
 			c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue profile not found"))
 			return
@@ -559,6 +796,56 @@ func (h *UserHandler) AddVenuePhoto(c *gin.Context) {
 	c.JSON(http.StatusCreated, photo)
 }
 
+// AddVenuePhotos godoc
+// @Summary      Добавить несколько фото площадки
+// @Description  Добавляет изображения в конец галереи площадки в переданном порядке. Добавляются все или ни одного; галерея не может превысить лимит фото.
+// @Tags         venues
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body object true "Список фото" example({"photos": [{"image_id": "550e8400-e29b-41d4-a716-446655440000", "caption": "Главный зал", "alt_text": "Сцена и зрительный зал"}]})
+// @Success      201 {array} models.VenuePhoto "Фото добавлены"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
+// @Router       /users/venues/photos/batch [post]
+func (h *UserHandler) AddVenuePhotos(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		Photos []service.GalleryPhotoInput `json:"photos" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	photos, err := h.userService.AddVenuePhotos(userID, req.Photos)
+	if err != nil {
+		if respondImageReferenceError(c, err) || respondGalleryError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue profile not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to add photos"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, photos)
+}
+
 // DeleteVenuePhoto godoc
 // @Summary      Удалить фото площадки
 // @Description  Удаляет фото из галереи площадки (только своё фото)
@@ -600,22 +887,171 @@ func (h *UserHandler) DeleteVenuePhoto(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// UpdateVenuePhoto godoc
+// @Summary      Изменить фото площадки
+// @Description  Меняет подпись, alt-текст и флаг обложки фото галереи. Новая обложка снимает флаг с предыдущей.
+// @Tags         venues
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        photo_id path int true "ID записи фото"
+// @Param        request body service.UpdateGalleryPhotoRequest true "Изменяемые поля"
+// @Success      200 {object} models.VenuePhoto
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      403 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Router       /users/venues/photos/{photo_id} [patch]
+func (h *UserHandler) UpdateVenuePhoto(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	photoID, err := strconv.Atoi(c.Param("photo_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_ID", "Invalid photo ID"))
+		return
+	}
+
+	var req service.UpdateGalleryPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	photo, err := h.userService.UpdateVenuePhoto(userID, photoID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAccessDenied) {
+			c.JSON(http.StatusForbidden, apperror.One("ACCESS_DENIED", "You can only edit your own photos"))
+			return
+		}
+		if errors.Is(err, service.ErrPhotoNotFound) || errors.Is(err, service.ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("PHOTO_NOT_FOUND", "Photo not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to update photo"))
+		return
+	}
+
+	c.JSON(http.StatusOK, photo)
+}
+
+// ReorderVenuePhotos godoc
+// @Summary      Переставить фото площадки
+// @Description  Задает новый порядок галереи: photo_ids должен содержать все фото галереи ровно по одному разу
+// @Tags         venues
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body object true "ID фото в новом порядке" example({"photo_ids": [3, 1, 2]})
+// @Success      200 {array} models.VenuePhoto "Галерея в новом порядке"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Router       /users/venues/photos/order [put]
+func (h *UserHandler) ReorderVenuePhotos(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		PhotoIDs []int `json:"photo_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	photos, err := h.userService.ReorderVenuePhotos(userID, req.PhotoIDs)
+	if err != nil {
+		if respondGalleryError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("VENUE_NOT_FOUND", "Venue profile not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to reorder photos"))
+		return
+	}
+
+	c.JSON(http.StatusOK, photos)
+}
+
+// DeleteVenuePhotos godoc
+// @Summary      Удалить несколько фото площадки
+// @Description  Удаляет фото из галереи одной операцией: если хоть одно фото не принадлежит галерее, не удаляется ни одно. Оставшиеся фото сдвигаются без пропусков.
+// @Tags         venues
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body object true "ID удаляемых фото" example({"photo_ids": [1, 2]})
+// @Success      204 "Фото удалены"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Router       /users/venues/photos/batch-delete [post]
+func (h *UserHandler) DeleteVenuePhotos(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		PhotoIDs []int `json:"photo_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	if err := h.userService.DeleteVenuePhotos(userID, req.PhotoIDs); err != nil {
+		if errors.Is(err, service.ErrPhotoNotFound) || errors.Is(err, service.ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, apperror.One("PHOTO_NOT_FOUND", "Photo not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to delete photos"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // UploadImage godoc
 // @Summary      Загрузить изображение
-// @Description  Загружает изображение в MinIO и возвращает метаданные
+// @Description  Проверяет файл по содержимому (сигнатура, декодирование, размеры) и сканером, затем сохраняет в MinIO вместе с WebP-вариантами. Пока проверка не пройдена, изображение находится в карантине (status=quarantined, ответ 202) и не отдается.
 // @Tags         images
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
 // @Param        file formData file true "Файл изображения (jpg, jpeg, png, gif, webp, максимум 10MB)"
-// @Param        type formData string true "Тип изображения" Enums(avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover)
+// @Param        type formData string true "Тип изображения" Enums(avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover, event-photo, event-poster, event-document)
 // @Success      201 {object} models.Image "Изображение загружено"
+// @Success      202 {object} models.Image "Изображение в карантине до проверки"
+// @Failure      413 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
 // @Failure      400 {object} apperror.ErrorResponse
 // @Failure      401 {object} apperror.ErrorResponse
 // @Failure      500 {object} apperror.ErrorResponse
 // @Router       /users/upload [post]
 func (h *UserHandler) UploadImage(c *gin.Context) {
-	_, ok := middleware.GetUserID(c)
+	userID, ok := middleware.GetUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
 		return
@@ -623,7 +1059,7 @@ func (h *UserHandler) UploadImage(c *gin.Context) {
 
 	imageType := c.PostForm("type")
 	if imageType == "" {
-		c.JSON(http.StatusBadRequest, apperror.One("FIELD_REQUIRED", "Image type is required (avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover)"))
+		c.JSON(http.StatusBadRequest, apperror.One("FIELD_REQUIRED", "Image type is required (avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover, event-photo, event-poster, event-document)"))
 		return
 	}
 
@@ -633,48 +1069,369 @@ func (h *UserHandler) UploadImage(c *gin.Context) {
 		return
 	}
 
-	image, err := h.imageService.UploadImage(file, imageType)
+	image, err := h.imageService.UploadImage(userID, file, imageType)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidFileType) {
-			c.JSON(http.StatusBadRequest, apperror.One("INVALID_FILE_TYPE", "Invalid file type, allowed: jpg, jpeg, png, gif, webp"))
-			return
-		}
 		if errors.Is(err, service.ErrFileTooLarge) {
 			c.JSON(http.StatusBadRequest, apperror.One("FILE_TOO_LARGE", "File too large, maximum size is 10MB"))
 			return
 		}
-		if errors.Is(err, service.ErrInvalidImageType) {
-			c.JSON(http.StatusBadRequest, apperror.One("INVALID_IMAGE_TYPE", "Invalid image type, allowed: avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover"))
+		if respondUploadError(c, err) {
 			return
 		}
 		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to upload image"))
 		return
 	}
 
+	respondUploadedImage(c, image)
+}
+
+// CreateDirectUpload godoc
+// @Summary      Начать прямую загрузку в хранилище
+// @Description  Выдает presigned-ссылки для загрузки файла прямо в MinIO, минуя gateway и user-service. Файлы до 8MB загружаются одной формой POST (method=post: сначала поля form_data, последним — file), крупнее — частями по part_size байт (method=multipart: PUT каждой части на свою ссылку). После загрузки вызывается POST /users/uploads/{id}/complete.
+// @Tags         images
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body object true "Тип изображения, имя и точный размер файла" example({"type": "venue-cover", "file_name": "cover.jpg", "size": 24117248})
+// @Success      201 {object} service.DirectUpload
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      413 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Router       /users/uploads [post]
+func (h *UserHandler) CreateDirectUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	var req struct {
+		Type     string `json:"type" binding:"required"`
+		FileName string `json:"file_name" binding:"required"`
+		Size     int64  `json:"size" binding:"required,gt=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if resp, ok := apperror.FromValidation(err); ok {
+			c.JSON(http.StatusBadRequest, resp)
+			return
+		}
+		c.JSON(http.StatusBadRequest, apperror.One("VALIDATION_ERROR", err.Error()))
+		return
+	}
+
+	upload, err := h.imageService.InitDirectUpload(userID, req.Type, req.FileName, req.Size)
+	if err != nil {
+		if errors.Is(err, service.ErrFileTooLarge) {
+			c.JSON(http.StatusBadRequest, apperror.One("FILE_TOO_LARGE", "File too large for direct upload"))
+			return
+		}
+		if respondUploadError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to start upload"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// GetDirectUpload godoc
+// @Summary      Продолжить прямую загрузку
+// @Description  Заново выдает ссылки незавершенной загрузки. Для multipart ссылки выдаются только на части, которых еще нет в хранилище (uploaded=true у загруженных).
+// @Tags         images
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "UUID загрузки"
+// @Success      200 {object} service.DirectUpload
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Router       /users/uploads/{id} [get]
+func (h *UserHandler) GetDirectUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	upload, err := h.imageService.GetDirectUpload(userID, c.Param("id"))
+	if err != nil {
+		if respondUploadError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get upload"))
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// CompleteDirectUpload godoc
+// @Summary      Завершить прямую загрузку
+// @Description  Проверяет загруженный объект (размер, заголовок файла, квота) и создает изображение с ID загрузки в карантине. Проверка сканером и построение WebP-вариантов выполняются в фоне; когда изображение будет готово, GET /users/images/{id} перестанет отвечать 409.
+// @Tags         images
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path string true "UUID загрузки"
+// @Success      202 {object} models.Image "Изображение в карантине до проверки"
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      409 {object} apperror.ErrorResponse
+// @Failure      413 {object} apperror.ErrorResponse
+// @Failure      422 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Router       /users/uploads/{id}/complete [post]
+func (h *UserHandler) CompleteDirectUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	image, err := h.imageService.FinalizeDirectUpload(userID, c.Param("id"))
+	if err != nil {
+		if respondUploadError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to complete upload"))
+		return
+	}
+
+	respondUploadedImage(c, image)
+}
+
+// AbortDirectUpload godoc
+// @Summary      Отменить прямую загрузку
+// @Description  Удаляет незавершенную загрузку и все загруженные части
+// @Tags         images
+// @Security     BearerAuth
+// @Param        id path string true "UUID загрузки"
+// @Success      204 "Загрузка отменена"
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Router       /users/uploads/{id} [delete]
+func (h *UserHandler) AbortDirectUpload(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	if err := h.imageService.AbortDirectUpload(userID, c.Param("id")); err != nil {
+		if respondUploadError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to abort upload"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondUploadedImage отвечает 201 на опубликованное изображение и 202 — если
+// сканер не успел дать вердикт: файл принят, но до проверки не отдается
+func respondUploadedImage(c *gin.Context, image *models.Image) {
+	if image.Status == models.ImageStatusQuarantined {
+		c.JSON(http.StatusAccepted, image)
+		return
+	}
 	c.JSON(http.StatusCreated, image)
 }
 
+// respondUploadError отвечает на ошибки проверки загружаемого файла, общие для
+// обычной и прямой загрузки. Возвращает false, если ошибка не из их числа.
+func respondUploadError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidFileType):
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_FILE_TYPE", "Invalid file type, allowed: jpg, jpeg, png, gif, webp; pdf for event-document"))
+	case errors.Is(err, service.ErrInvalidImageType):
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_IMAGE_TYPE", "Invalid image type, allowed: avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover, event-photo, event-poster, event-document"))
+	case errors.Is(err, service.ErrImageDimensionsTooLarge):
+		c.JSON(http.StatusBadRequest, apperror.One("IMAGE_DIMENSIONS_TOO_LARGE", "Image dimensions are too large"))
+	case errors.Is(err, service.ErrStorageQuotaExceeded):
+		c.JSON(http.StatusRequestEntityTooLarge, apperror.One("STORAGE_QUOTA_EXCEEDED", "Storage quota exceeded, delete unused images"))
+	case errors.Is(err, service.ErrMalwareDetected):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("MALWARE_DETECTED", "File was rejected by the malware scanner"))
+	case errors.Is(err, service.ErrUploadNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("UPLOAD_NOT_FOUND", "Upload not found or expired"))
+	case errors.Is(err, service.ErrUploadIncomplete):
+		c.JSON(http.StatusConflict, apperror.One("UPLOAD_INCOMPLETE", "Not all of the file has been uploaded yet"))
+	case errors.Is(err, service.ErrUploadSizeMismatch):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("UPLOAD_SIZE_MISMATCH", "Uploaded file does not match the declared size"))
+	default:
+		return false
+	}
+	return true
+}
+
+// GetStorageUsage godoc
+// @Summary      Использование хранилища
+// @Description  Возвращает объем, занятый изображениями пользователя (оригиналы и варианты), и квоту
+// @Tags         images
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} service.StorageUsage
+// @Failure      401 {object} apperror.ErrorResponse
+// @Failure      500 {object} apperror.ErrorResponse
+// @Router       /users/storage [get]
+func (h *UserHandler) GetStorageUsage(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, apperror.One("UNAUTHORIZED", "Unauthorized"))
+		return
+	}
+
+	usage, err := h.imageService.GetStorageUsage(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get storage usage"))
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
 // GetImage godoc
 // @Summary      Получить изображение
-// @Description  Возвращает изображение напрямую из хранилища.
+// @Description  Потоково отдает изображение из хранилища с поддержкой ETag/If-None-Match, Last-Modified и Range. Параметры w и format выбирают WebP-вариант подходящей ширины; без них отдается оригинал. С redirect=true отвечает 302 на короткоживущую presigned-ссылку MinIO.
 // @Tags         images
 // @Produce      image/jpeg,image/png,image/gif,image/webp
 // @Param        id path string true "UUID изображения"
+// @Param        w query int false "Желаемая ширина в пикселях"
+// @Param        format query string false "Формат" Enums(webp, original)
+// @Param        redirect query bool false "Перенаправить на presigned-ссылку"
 // @Success      200 {file} binary "Изображение"
+// @Success      206 {file} binary "Запрошенный диапазон"
+// @Success      302 "Перенаправление на presigned-ссылку"
+// @Success      304 "Не изменилось"
+// @Failure      400 {object} apperror.ErrorResponse
 // @Failure      404 {object} apperror.ErrorResponse
+// @Failure      409 {object} apperror.ErrorResponse
 // @Router       /users/images/{id} [get]
 func (h *UserHandler) GetImage(c *gin.Context) {
 	imageID := c.Param("id")
 
-	image, data, err := h.imageService.GetImage(imageID)
+	width, format, ok := parseImageQuery(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("redirect") == "true" {
+		presigned, err := h.imageService.PresignImage(imageID, width, format)
+		if err != nil {
+			respondImageError(c, err)
+			return
+		}
+		// Ссылку можно переиспользовать, пока она заведомо не истекла
+		maxAge := int(time.Until(presigned.ExpiresAt).Seconds()) / 2
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+		c.Redirect(http.StatusFound, presigned.URL)
+		return
+	}
+
+	object, err := h.imageService.OpenImage(imageID, width, format)
 	if err != nil {
-		c.JSON(http.StatusNotFound, apperror.One("IMAGE_NOT_FOUND", "Image not found"))
+		respondImageError(c, err)
 		return
 	}
+	defer object.Body.Close()
 
-	c.Header("Content-Type", image.FileType)
-	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", image.FileName))
+	c.Header("Content-Type", object.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", object.FileName))
+	// Объекты неизменяемы (имя — UUID), но валидаторы позволяют клиенту дешево перепроверить кэш
 	c.Header("Cache-Control", "public, max-age=31536000")
+	if object.ETag != "" {
+		c.Header("ETag", `"`+object.ETag+`"`)
+	}
+
+	// ServeContent сам обрабатывает If-None-Match, If-Modified-Since, Range и If-Range
+	http.ServeContent(c.Writer, c.Request, object.FileName, object.LastModified, object.Body)
+}
+
+// GetImageURL godoc
+// @Summary      Получить ссылку на изображение
+// @Description  Возвращает короткоживущую presigned-ссылку, по которой изображение скачивается напрямую из MinIO
+// @Tags         images
+// @Produce      json
+// @Param        id path string true "UUID изображения"
+// @Param        w query int false "Желаемая ширина в пикселях"
+// @Param        format query string false "Формат" Enums(webp, original)
+// @Success      200 {object} service.PresignedImage
+// @Failure      400 {object} apperror.ErrorResponse
+// @Failure      404 {object} apperror.ErrorResponse
+// @Failure      409 {object} apperror.ErrorResponse
+// @Router       /users/images/{id}/url [get]
+func (h *UserHandler) GetImageURL(c *gin.Context) {
+	width, format, ok := parseImageQuery(c)
+	if !ok {
+		return
+	}
 
-	c.Data(http.StatusOK, image.FileType, data)
+	presigned, err := h.imageService.PresignImage(c.Param("id"), width, format)
+	if err != nil {
+		respondImageError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, presigned)
+}
+
+// parseImageQuery разбирает ?w= и ?format=; при ошибке сам отвечает 400
+func parseImageQuery(c *gin.Context) (int, string, bool) {
+	width := 0
+	if raw := c.Query("w"); raw != "" {
+		w, err := strconv.Atoi(raw)
+		if err != nil || w <= 0 {
+			c.JSON(http.StatusBadRequest, apperror.One("INVALID_PARAM", "Invalid w, expected a positive integer"))
+			return 0, "", false
+		}
+		width = w
+	}
+	return width, c.Query("format"), true
+}
+
+// respondGalleryError отвечает на ошибки изменения галереи профиля.
+// Возвращает false, если ошибка не из их числа.
+func respondGalleryError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrDuplicatePhoto):
+		c.JSON(http.StatusBadRequest, apperror.One("DUPLICATE_PHOTO", "The same image is listed more than once"))
+	case errors.Is(err, service.ErrInvalidPhotoOrder):
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_PHOTO_ORDER", "photo_ids must list every photo of the gallery exactly once"))
+	case errors.Is(err, service.ErrGalleryLimitExceeded):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("GALLERY_LIMIT_EXCEEDED", "Gallery photo limit exceeded"))
+	default:
+		return false
+	}
+	return true
+}
+
+// respondImageReferenceError отвечает на ошибку ссылки профиля на чужое или
+// несуществующее изображение; false — ошибка другого рода
+func respondImageReferenceError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrImageNotOwned):
+		c.JSON(http.StatusForbidden, apperror.One("IMAGE_NOT_OWNED", "You can only use images you uploaded"))
+	case errors.Is(err, service.ErrImageNotFound):
+		c.JSON(http.StatusUnprocessableEntity, apperror.One("IMAGE_NOT_FOUND", "Referenced image not found"))
+	default:
+		return false
+	}
+	return true
+}
+
+func respondImageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidImageFormat):
+		c.JSON(http.StatusBadRequest, apperror.One("INVALID_IMAGE_FORMAT", "Invalid format, allowed: webp, original"))
+	case errors.Is(err, service.ErrImageNotFound):
+		c.JSON(http.StatusNotFound, apperror.One("IMAGE_NOT_FOUND", "Image not found"))
+	case errors.Is(err, service.ErrImageNotReady):
+		c.JSON(http.StatusConflict, apperror.One("IMAGE_NOT_READY", "Image is being checked and is not available yet"))
+	default:
+		c.JSON(http.StatusInternalServerError, apperror.One("INTERNAL_ERROR", "Failed to get image"))
+	}
 }
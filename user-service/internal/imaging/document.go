@@ -0,0 +1,57 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const FormatPDF = "pdf"
+
+var ErrUnsupportedDocument = errors.New("unsupported document")
+
+// pdfTrailerWindow — в каком хвосте файла искать маркер конца %%EOF
+// (после него бывают переводы строк и мусор от генераторов)
+const pdfTrailerWindow = 1024
+
+// pdfActiveContent — ключи, запускающие код или внешние программы при открытии.
+// Документы отдаются inline, поэтому такие PDF не принимаем.
+var pdfActiveContent = [][]byte{
+	[]byte("/JavaScript"),
+	[]byte("/JS"),
+	[]byte("/Launch"),
+	[]byte("/EmbeddedFile"),
+}
+
+// SniffDocument проверяет по первым байтам файла, что это PDF. Полную проверку
+// делает InspectDocument, которому нужен весь файл.
+func SniffDocument(header []byte) (*Info, error) {
+	contentType := http.DetectContentType(header)
+	if contentType != "application/pdf" {
+		return nil, fmt.Errorf("%w: detected %s", ErrUnsupportedDocument, contentType)
+	}
+	return &Info{Format: FormatPDF, ContentType: contentType}, nil
+}
+
+// InspectDocument проверяет, что файл — PDF: по сигнатуре %PDF-, наличию маркера
+// конца файла и отсутствию активного содержимого. Размеры в Info не заполняются.
+func InspectDocument(data []byte) (*Info, error) {
+	info, err := SniffDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tail := data[max(0, len(data)-pdfTrailerWindow):]
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return nil, fmt.Errorf("%w: missing %%%%EOF", ErrUnsupportedDocument)
+	}
+
+	for _, key := range pdfActiveContent {
+		if bytes.Contains(data, key) {
+			return nil, fmt.Errorf("%w: contains %s", ErrUnsupportedDocument, key)
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,144 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // регистрирует декодер GIF для image.Decode
+	"image/jpeg"
+	"image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // регистрирует декодер WebP для image.Decode
+)
+
+const (
+	FormatWebP = "webp"
+
+	// Качество перекодированного JPEG-оригинала
+	jpegQuality = 90
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image")
+
+// Variant — уменьшенная копия изображения в WebP
+type Variant struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Result — результат обработки загруженного файла
+type Result struct {
+	// Original — оригинал без метаданных (EXIF, XMP); для GIF совпадает со входом
+	Original    []byte
+	Format      string
+	ContentType string
+	Width       int
+	Height      int
+	Variants    []Variant
+}
+
+// Process проверяет файл (Inspect с DefaultLimits), полностью декодирует его,
+// применяет EXIF-ориентацию, вычищает метаданные из оригинала и строит WebP-варианты
+// указанной ширины. Изображение никогда не увеличивается: ширины больше оригинала
+// схлопываются в один вариант исходного размера.
+func Process(data []byte, widths []int) (*Result, error) {
+	info, err := Inspect(data, DefaultLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	// Полное декодирование ловит файлы с корректным заголовком, но битыми данными
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	original, err := stripMetadata(data, img, format)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	result := &Result{
+		Original:    original,
+		Format:      info.Format,
+		ContentType: info.ContentType,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}
+
+	seen := make(map[int]bool, len(widths))
+	for _, w := range widths {
+		if w <= 0 {
+			continue
+		}
+		if w > result.Width {
+			w = result.Width
+		}
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+
+		resized := resize(img, w)
+		var buf bytes.Buffer
+		if err := nativewebp.Encode(&buf, resized, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode webp variant: %v", err)
+		}
+		result.Variants = append(result.Variants, Variant{
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+			Data:   buf.Bytes(),
+		})
+	}
+
+	return result, nil
+}
+
+// resize масштабирует изображение до ширины width с сохранением пропорций
+func resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width == bounds.Dx() {
+		return img
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// stripMetadata возвращает оригинал без EXIF/XMP. JPEG и PNG перекодируются
+// (для JPEG — уже с примененной ориентацией), у WebP вырезаются чанки метаданных,
+// GIF метаданных с геолокацией не несет и остается как есть (сохраняется анимация).
+func stripMetadata(data []byte, img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to re-encode jpeg: %v", err)
+		}
+		return buf.Bytes(), nil
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to re-encode png: %v", err)
+		}
+		return buf.Bytes(), nil
+	case "webp":
+		return stripWebPMetadata(data), nil
+	case "gif":
+		return data, nil
+	}
+	return nil, ErrUnsupportedImage
+}
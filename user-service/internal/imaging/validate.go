@@ -0,0 +1,76 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+)
+
+var ErrImageTooLarge = errors.New("image dimensions exceed limits")
+
+// Limits ограничивает размеры принимаемых изображений. Проверяется по заголовку
+// до полного декодирования, поэтому файл в пару килобайт с заявленными 50000×50000
+// («декомпрессионная бомба») отклоняется без выделения памяти под пиксели.
+type Limits struct {
+	MaxWidth  int
+	MaxHeight int
+	MaxPixels int
+}
+
+// DefaultLimits — с запасом для фото с телефонов и зеркалок; NRGBA-буфер
+// такого изображения занимает не больше ~100MB
+var DefaultLimits = Limits{
+	MaxWidth:  10000,
+	MaxHeight: 10000,
+	MaxPixels: 25_000_000,
+}
+
+// Info — сведения о файле, полученные по сигнатуре и заголовку
+type Info struct {
+	Format      string // jpeg, png, gif, webp; pdf для документов
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// sniffedFormats сопоставляет MIME-тип, определенный по сигнатуре, с именем декодера
+var sniffedFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// Inspect определяет формат по magic bytes (расширение и заявленный тип не учитываются),
+// сверяет его с заголовком изображения и проверяет размеры
+func Inspect(data []byte, limits Limits) (*Info, error) {
+	contentType := http.DetectContentType(data)
+	format, ok := sniffedFormats[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: detected %s", ErrUnsupportedImage, contentType)
+	}
+
+	cfg, decodedFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if decodedFormat != format {
+		return nil, fmt.Errorf("%w: signature %s does not match header %s", ErrUnsupportedImage, format, decodedFormat)
+	}
+
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrUnsupportedImage)
+	}
+	if cfg.Width > limits.MaxWidth || cfg.Height > limits.MaxHeight || cfg.Width*cfg.Height > limits.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	return &Info{
+		Format:      format,
+		ContentType: contentType,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+	}, nil
+}
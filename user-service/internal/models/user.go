@@ -40,11 +40,16 @@ type Creator struct {
 	Photos []CreatorPhoto `gorm:"foreignKey:CreatorID" json:"photos,omitempty"`
 }
 
-// CreatorPhoto - фотографии с проведенных мероприятий создателя
+// CreatorPhoto - фотографии с проведенных мероприятий создателя (упорядоченная галерея)
 type CreatorPhoto struct {
-	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
-	CreatorID int    `gorm:"not null" json:"creator_id"`
-	ImageID   string `gorm:"type:uuid;not null" json:"image_id"`
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatorID int       `gorm:"not null" json:"creator_id"`
+	ImageID   string    `gorm:"type:uuid;not null" json:"image_id"`
+	Position  int       `gorm:"not null" json:"position"` // 0, 1, 2... без пропусков
+	Caption   string    `json:"caption,omitempty"`
+	AltText   string    `json:"alt_text,omitempty"`
+	IsCover   bool      `gorm:"not null;default:false" json:"is_cover"` // не больше одной на галерею
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	Image Image `gorm:"foreignKey:ImageID" json:"image"`
 }
@@ -80,11 +85,16 @@ type Venue struct {
 	Categories  []int         `gorm:"-" json:"category_ids,omitempty"` // Только для передачи данных
 }
 
-// VenuePhoto - дополнительные фото площадки
+// VenuePhoto - дополнительные фото площадки (упорядоченная галерея)
 type VenuePhoto struct {
-	ID      int    `gorm:"primaryKey;autoIncrement" json:"id"`
-	VenueID int    `gorm:"not null" json:"venue_id"`
-	ImageID string `gorm:"type:uuid;not null" json:"image_id"`
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	VenueID   int       `gorm:"not null" json:"venue_id"`
+	ImageID   string    `gorm:"type:uuid;not null" json:"image_id"`
+	Position  int       `gorm:"not null" json:"position"` // 0, 1, 2... без пропусков
+	Caption   string    `json:"caption,omitempty"`
+	AltText   string    `json:"alt_text,omitempty"`
+	IsCover   bool      `gorm:"not null;default:false" json:"is_cover"` // не больше одной на галерею
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	Image Image `gorm:"foreignKey:ImageID" json:"image"`
 }
@@ -101,9 +111,59 @@ type Image struct {
 	FileName   string    `gorm:"not null" json:"file_name"`
 	FilePath   string    `gorm:"not null" json:"file_path"`
 	FileType   string    `json:"file_type,omitempty"`
-	ImageType  string    `gorm:"not null" json:"image_type"` // avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover
+	ImageType  string    `gorm:"not null" json:"image_type"` // avatar, venue-logo, venue-cover, venue-photo, creator-photo, event-cover, event-photo, event-poster, event-document (PDF)
 	BucketName string    `gorm:"not null" json:"bucket_name"`
+	Status     string    `gorm:"not null;default:ready" json:"status"` // quarantined, ready, rejected
+	Width      int       `json:"width,omitempty"`
+	Height     int       `json:"height,omitempty"`
+	// OwnerUserID - кто загрузил; ссылаться на изображение может только владелец
+	OwnerUserID *int  `json:"owner_user_id,omitempty"`
+	Size        int64 `gorm:"not null;default:0" json:"size"` // байт в оригинале, без вариантов
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	ScanSignature   string     `json:"-"`
+	ScannedAt       *time.Time `json:"-"`
+	ScanAttemptedAt *time.Time `json:"-"`
+
+	// UnreferencedSince — когда сборщик впервые увидел изображение без ссылок
+	UnreferencedSince *time.Time `json:"-"`
+
+	Variants []ImageVariant `gorm:"foreignKey:ImageID" json:"variants,omitempty"`
+}
+
+// Статусы изображения: загруженный файл лежит в карантинном бакете, пока не пройдет проверку
+const (
+	ImageStatusQuarantined = "quarantined"
+	ImageStatusReady       = "ready"
+	ImageStatusRejected    = "rejected"
+)
+
+// ImageVariant - уменьшенная копия изображения, лежит в том же бакете, что и оригинал
+type ImageVariant struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ImageID   string    `gorm:"type:uuid;not null" json:"image_id"`
+	Width     int       `gorm:"not null" json:"width"`
+	Height    int       `gorm:"not null" json:"height"`
+	Format    string    `gorm:"not null" json:"format"` // webp
+	FilePath  string    `gorm:"not null" json:"file_path"`
+	Size      int64     `gorm:"not null" json:"size"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ImageUpload - прямая загрузка в карантинный бакет MinIO по presigned-ссылкам.
+// Запись живет от выдачи ссылок до finalize; незавершенные загрузки удаляет сборщик.
+type ImageUpload struct {
+	ID          string `gorm:"type:uuid;primaryKey" json:"id"` // станет ID изображения
+	OwnerUserID int    `gorm:"not null" json:"owner_user_id"`
+	ImageType   string `gorm:"not null" json:"image_type"`
+	FileName    string `gorm:"not null" json:"file_name"`
+	ObjectName  string `gorm:"not null" json:"-"`
+	Size        int64  `gorm:"not null" json:"size"` // заявленный клиентом размер, сверяется при finalize
+	// MultipartUploadID - uploadId S3 multipart; пустой, если файл грузится одним POST
+	MultipartUploadID string    `json:"-"`
+	PartCount         int       `gorm:"not null;default:0" json:"part_count"`
+	ExpiresAt         time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
 // CreatorFavoriteVenue - избранные площадки создателя
@@ -125,6 +185,24 @@ type NewsletterSubscription struct {
 
 func (NewsletterSubscription) TableName() string { return "newsletter_subscriptions" }
 
+// RatingSummary — агрегированная оценка пользователя по видимым отзывам
+// (отзывы ведет application-service, оценки приходят из его внутренней ручки)
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// SimilarityCandidate — сигналы похожести профиля на исходный.
+// Для создателей категории берутся из их мероприятий, а capacity_fit не используется.
+type SimilarityCandidate struct {
+	UserID           int
+	SharedCategories int
+	UnionCategories  int
+	SameCity         bool
+	CapacityFit      bool
+	CoFavorites      int
+}
+
 // TableName overrides
 func (User) TableName() string          { return "users" }
 func (Creator) TableName() string       { return "creators" }
@@ -133,3 +211,5 @@ func (Venue) TableName() string         { return "venues" }
 func (VenuePhoto) TableName() string    { return "venue_photos" }
 func (VenueCategory) TableName() string { return "venue_categories" }
 func (Image) TableName() string         { return "images" }
+func (ImageVariant) TableName() string  { return "image_variants" }
+func (ImageUpload) TableName() string   { return "image_uploads" }
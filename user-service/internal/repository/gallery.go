@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrGalleryFull — после добавления в галерее стало бы больше фото, чем разрешено
+	ErrGalleryFull = errors.New("gallery is full")
+	// ErrGalleryMismatch — переданные ID фото не совпадают с фото галереи
+	// (чужие, несуществующие, повторы или, при перестановке, не все фото)
+	ErrGalleryMismatch = errors.New("photos do not match the gallery")
+)
+
+// gallery описывает таблицу фото галереи и профиль-владелец. Имена подставляются
+// в SQL напрямую, поэтому берутся только из констант ниже.
+type gallery struct {
+	table       string
+	ownerTable  string
+	ownerColumn string
+}
+
+var (
+	creatorGallery = gallery{table: "creator_photos", ownerTable: "creators", ownerColumn: "creator_id"}
+	venueGallery   = gallery{table: "venue_photos", ownerTable: "venues", ownerColumn: "venue_id"}
+)
+
+func orderedPhotos(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// lockGallery блокирует строку профиля до конца транзакции: изменения одной галереи
+// выполняются строго по очереди, и лимит с позициями не разъезжаются
+func lockGallery(tx *gorm.DB, g gallery, ownerID int) error {
+	var id int
+	err := tx.Raw("SELECT id FROM "+g.ownerTable+" WHERE id = ? FOR UPDATE", ownerID).Scan(&id).Error
+	if err == nil && id == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+// reserveGalleryPositions проверяет лимит и возвращает позицию для первого из n новых фото
+func reserveGalleryPositions(tx *gorm.DB, g gallery, ownerID, n, maxPhotos int) (int, error) {
+	if err := lockGallery(tx, g, ownerID); err != nil {
+		return 0, err
+	}
+	var count int
+	if err := tx.Raw("SELECT COUNT(*) FROM "+g.table+" WHERE "+g.ownerColumn+" = ?", ownerID).Scan(&count).Error; err != nil {
+		return 0, err
+	}
+	if count+n > maxPhotos {
+		return 0, ErrGalleryFull
+	}
+	// Позиции идут без пропусков, поэтому следующая равна числу фото
+	return count, nil
+}
+
+func clearGalleryCover(tx *gorm.DB, g gallery, ownerID, exceptPhotoID int) error {
+	return tx.Exec("UPDATE "+g.table+" SET is_cover = FALSE WHERE "+g.ownerColumn+" = ? AND is_cover AND id <> ?",
+		ownerID, exceptPhotoID).Error
+}
+
+// reorderGallery расставляет фото в порядке photoIDs. Список должен содержать
+// все фото галереи ровно по одному разу.
+func reorderGallery(tx *gorm.DB, g gallery, ownerID int, photoIDs []int) error {
+	if err := lockGallery(tx, g, ownerID); err != nil {
+		return err
+	}
+	var current []int
+	if err := tx.Raw("SELECT id FROM "+g.table+" WHERE "+g.ownerColumn+" = ?", ownerID).Scan(&current).Error; err != nil {
+		return err
+	}
+	if !sameIDs(current, photoIDs) {
+		return ErrGalleryMismatch
+	}
+	// Уникальность (owner, position) проверяется при коммите, промежуточные дубли допустимы
+	for position, id := range photoIDs {
+		if err := tx.Exec("UPDATE "+g.table+" SET position = ? WHERE id = ?", position, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteGalleryPhotos удаляет фото галереи (все или ни одного) и сдвигает
+// оставшиеся, чтобы позиции снова шли без пропусков
+func deleteGalleryPhotos(tx *gorm.DB, g gallery, ownerID int, photoIDs []int) error {
+	if err := lockGallery(tx, g, ownerID); err != nil {
+		return err
+	}
+	result := tx.Exec("DELETE FROM "+g.table+" WHERE "+g.ownerColumn+" = ? AND id IN ?", ownerID, photoIDs)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(photoIDs)) {
+		return ErrGalleryMismatch
+	}
+	return tx.Exec(`
+		UPDATE `+g.table+` p SET position = o.rn - 1
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS rn
+			FROM `+g.table+` WHERE `+g.ownerColumn+` = ?
+		) o
+		WHERE p.id = o.id AND p.position <> o.rn - 1`, ownerID).Error
+}
+
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[int]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+
+	"user-service/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrQuotaExceeded — новый файл не помещается в квоту хранилища владельца
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// lockImageOwner блокирует строку пользователя до конца транзакции: загрузки одного
+// владельца проверяют квоту строго по очереди и не превышают ее вместе
+func lockImageOwner(tx *gorm.DB, ownerID int) error {
+	var id int
+	err := tx.Raw("SELECT id FROM users WHERE id = ? FOR UPDATE", ownerID).Scan(&id).Error
+	if err == nil && id == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+// imageStorageUsage считает объем хранилища пользователя: оригиналы и варианты.
+// Отклоненные сканером файлы не учитываются.
+func imageStorageUsage(db *gorm.DB, ownerID int) (int64, error) {
+	var usage int64
+	err := db.Raw(`
+		SELECT COALESCE(SUM(i.size), 0) + COALESCE((
+			SELECT SUM(v.size) FROM image_variants v
+			JOIN images iv ON iv.id = v.image_id
+			WHERE iv.owner_user_id = @owner AND iv.status <> @rejected
+		), 0)
+		FROM images i
+		WHERE i.owner_user_id = @owner AND i.status <> @rejected`,
+		map[string]interface{}{"owner": ownerID, "rejected": models.ImageStatusRejected},
+	).Scan(&usage).Error
+	return usage, err
+}
+
+// reserveImageQuota под блокировкой владельца проверяет, что еще size байт поместятся
+// в квоту. Незавершенные прямые загрузки занимают заявленный размер с момента init,
+// иначе параллельно начатые загрузки вместе превысили бы квоту.
+func reserveImageQuota(tx *gorm.DB, ownerID int, size, quotaBytes int64) error {
+	if err := lockImageOwner(tx, ownerID); err != nil {
+		return err
+	}
+	usage, err := imageStorageUsage(tx, ownerID)
+	if err != nil {
+		return err
+	}
+	var pending int64
+	err = tx.Raw("SELECT COALESCE(SUM(size), 0) FROM image_uploads WHERE owner_user_id = ? AND expires_at > NOW()", ownerID).
+		Scan(&pending).Error
+	if err != nil {
+		return err
+	}
+	if usage+pending+size > quotaBytes {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "user-service/internal/models"
+import (
+	"shared/pagination"
+	"time"
+	"user-service/internal/models"
+)
 
 type UserRepositoryInterface interface {
 	// User
@@ -14,31 +18,51 @@ type UserRepositoryInterface interface {
 	GetCreatorByUserID(userID int) (*models.Creator, error)
 	UpdateCreator(creator *models.Creator) error
 	DeleteCreator(id int) error
-	ListCreators(limit, offset int) ([]models.Creator, error)
+	ListCreators(page pagination.Params) ([]models.Creator, *int64, error)
 
 	// CreatorPhoto
-	AddCreatorPhoto(creatorID int, imageID string) (*models.CreatorPhoto, error)
+	AddCreatorPhotos(creatorID int, photos []models.CreatorPhoto, maxPhotos int) ([]models.CreatorPhoto, error)
 	GetCreatorPhoto(photoID int) (*models.CreatorPhoto, error)
-	DeleteCreatorPhoto(photoID int) error
+	GetCreatorPhotos(creatorID int) ([]models.CreatorPhoto, error)
+	UpdateCreatorPhoto(photo *models.CreatorPhoto) error
+	ReorderCreatorPhotos(creatorID int, photoIDs []int) error
+	DeleteCreatorPhotos(creatorID int, photoIDs []int) error
 
 	// Venue
 	CreateVenue(venue *models.Venue) error
 	GetVenueByID(id int) (*models.Venue, error)
 	GetVenueByUserID(userID int) (*models.Venue, error)
-	ListVenues(limit, offset int) ([]models.Venue, error)
+	ListVenues(page pagination.Params) ([]models.Venue, *int64, error)
 	UpdateVenue(venue *models.Venue) error
 	DeleteVenue(id int) error
 
 	// VenuePhoto
-	AddVenuePhoto(venueID int, imageID string) (*models.VenuePhoto, error)
+	AddVenuePhotos(venueID int, photos []models.VenuePhoto, maxPhotos int) ([]models.VenuePhoto, error)
 	GetVenuePhoto(photoID int) (*models.VenuePhoto, error)
-	DeleteVenuePhoto(photoID int) error
+	GetVenuePhotos(venueID int) ([]models.VenuePhoto, error)
+	UpdateVenuePhoto(photo *models.VenuePhoto) error
+	ReorderVenuePhotos(venueID int, photoIDs []int) error
+	DeleteVenuePhotos(venueID int, photoIDs []int) error
 
 	// Image
 	CreateImage(image *models.Image) error
+	CreateImageWithinQuota(image *models.Image, quotaBytes int64) error
 	GetImageByID(id string) (*models.Image, error)
+	UpdateImage(image *models.Image) error
+	ClaimQuarantinedImages(staleBefore time.Time, limit int) ([]models.Image, error)
+	GetImagesByIDs(ids []string) ([]models.Image, error)
+	GetImageStorageUsage(ownerUserID int) (int64, error)
+	MarkUnreferencedImages(now time.Time) error
+	DeleteOrphanImages(unreferencedBefore time.Time, limit int) ([]models.Image, error)
 	DeleteImage(id string) error
 
+	// ImageUpload
+	CreateImageUpload(upload *models.ImageUpload, quotaBytes int64) error
+	GetImageUpload(id string) (*models.ImageUpload, error)
+	FinalizeImageUpload(uploadID string, image *models.Image, quotaBytes int64) error
+	DeleteImageUpload(id string) error
+	DeleteExpiredImageUploads(expiredBefore time.Time, limit int) ([]models.ImageUpload, error)
+
 	// VenueCategory
 	AddVenueCategories(venueID int, categoryIDs []int) error
 	GetVenueCategories(venueID int) ([]int, error)
@@ -48,6 +72,12 @@ type UserRepositoryInterface interface {
 	RemoveCreatorFavoriteVenue(creatorUserID, venueUserID int) error
 	ListCreatorFavoriteVenues(creatorUserID int) ([]models.Venue, error)
 
+	// Similar profiles
+	FindSimilarVenueCandidates(venueUserID, limit int) ([]models.SimilarityCandidate, error)
+	FindSimilarCreatorCandidates(creatorUserID, limit int) ([]models.SimilarityCandidate, error)
+	GetVenuesByUserIDs(userIDs []int) ([]models.Venue, error)
+	GetCreatorsByUserIDs(userIDs []int) ([]models.Creator, error)
+
 	// Newsletter
 	CreateNewsletterSubscription(sub *models.NewsletterSubscription) error
 	GetNewsletterSubscriptionByEmail(email string) (*models.NewsletterSubscription, error)
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"shared/pagination"
+	"time"
 	"user-service/internal/models"
 
 	"gorm.io/gorm"
@@ -41,13 +43,13 @@ func (r *UserRepository) CreateCreator(creator *models.Creator) error {
 
 func (r *UserRepository) GetCreatorByID(id int) (*models.Creator, error) {
 	var creator models.Creator
-	err := r.db.Preload("Photo").Preload("Photos.Image").First(&creator, id).Error
+	err := r.db.Preload("Photo").Preload("Photos", orderedPhotos).Preload("Photos.Image").First(&creator, id).Error
 	return &creator, err
 }
 
 func (r *UserRepository) GetCreatorByUserID(userID int) (*models.Creator, error) {
 	var creator models.Creator
-	err := r.db.Where("user_id = ?", userID).Preload("Photo").Preload("Photos.Image").First(&creator).Error
+	err := r.db.Where("user_id = ?", userID).Preload("Photo").Preload("Photos", orderedPhotos).Preload("Photos.Image").First(&creator).Error
 	return &creator, err
 }
 
@@ -59,29 +61,45 @@ func (r *UserRepository) DeleteCreator(id int) error {
 	return r.db.Delete(&models.Creator{}, id).Error
 }
 
-func (r *UserRepository) ListCreators(limit, offset int) ([]models.Creator, error) {
+func (r *UserRepository) ListCreators(page pagination.Params) ([]models.Creator, *int64, error) {
+	total, err := pagination.Count(r.db.Model(&models.Creator{}), page)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var creators []models.Creator
-	err := r.db.Limit(limit).
-		Offset(offset).
+	err = pagination.Apply(r.db, "creators", page).
 		Preload("Photo").
+		Preload("Photos", orderedPhotos).
 		Preload("Photos.Image").
 		Find(&creators).Error
-	return creators, err
+	return creators, total, err
 }
 
 // CreatorPhoto operations
-func (r *UserRepository) AddCreatorPhoto(creatorID int, imageID string) (*models.CreatorPhoto, error) {
-	photo := &models.CreatorPhoto{
-		CreatorID: creatorID,
-		ImageID:   imageID,
-	}
-	if err := r.db.Create(photo).Error; err != nil {
+func (r *UserRepository) AddCreatorPhotos(creatorID int, photos []models.CreatorPhoto, maxPhotos int) ([]models.CreatorPhoto, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		next, err := reserveGalleryPositions(tx, creatorGallery, creatorID, len(photos), maxPhotos)
+		if err != nil {
+			return err
+		}
+		for i := range photos {
+			photos[i].CreatorID = creatorID
+			photos[i].Position = next + i
+		}
+		return tx.Omit("Image").Create(&photos).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-	if err := r.db.Preload("Image").First(photo, photo.ID).Error; err != nil {
-		return nil, err
+
+	ids := make([]int, len(photos))
+	for i, p := range photos {
+		ids[i] = p.ID
 	}
-	return photo, nil
+	var added []models.CreatorPhoto
+	err = r.db.Preload("Image").Where("id IN ?", ids).Order("position ASC").Find(&added).Error
+	return added, err
 }
 
 func (r *UserRepository) GetCreatorPhoto(photoID int) (*models.CreatorPhoto, error) {
@@ -90,23 +108,61 @@ func (r *UserRepository) GetCreatorPhoto(photoID int) (*models.CreatorPhoto, err
 	return &photo, err
 }
 
-func (r *UserRepository) DeleteCreatorPhoto(photoID int) error {
-	return r.db.Delete(&models.CreatorPhoto{}, photoID).Error
+func (r *UserRepository) GetCreatorPhotos(creatorID int) ([]models.CreatorPhoto, error) {
+	var photos []models.CreatorPhoto
+	err := orderedPhotos(r.db.Preload("Image").Where("creator_id = ?", creatorID)).Find(&photos).Error
+	return photos, err
+}
+
+// UpdateCreatorPhoto сохраняет подпись, alt-текст и флаг обложки; новая обложка
+// снимает флаг с предыдущей
+func (r *UserRepository) UpdateCreatorPhoto(photo *models.CreatorPhoto) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if photo.IsCover {
+			if err := clearGalleryCover(tx, creatorGallery, photo.CreatorID, photo.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(photo).Select("caption", "alt_text", "is_cover").Updates(photo).Error
+	})
+}
+
+func (r *UserRepository) ReorderCreatorPhotos(creatorID int, photoIDs []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return reorderGallery(tx, creatorGallery, creatorID, photoIDs)
+	})
+}
+
+func (r *UserRepository) DeleteCreatorPhotos(creatorID int, photoIDs []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteGalleryPhotos(tx, creatorGallery, creatorID, photoIDs)
+	})
 }
 
 // VenuePhoto operations
-func (r *UserRepository) AddVenuePhoto(venueID int, imageID string) (*models.VenuePhoto, error) {
-	photo := &models.VenuePhoto{
-		VenueID: venueID,
-		ImageID: imageID,
-	}
-	if err := r.db.Create(photo).Error; err != nil {
+func (r *UserRepository) AddVenuePhotos(venueID int, photos []models.VenuePhoto, maxPhotos int) ([]models.VenuePhoto, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		next, err := reserveGalleryPositions(tx, venueGallery, venueID, len(photos), maxPhotos)
+		if err != nil {
+			return err
+		}
+		for i := range photos {
+			photos[i].VenueID = venueID
+			photos[i].Position = next + i
+		}
+		return tx.Omit("Image").Create(&photos).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-	if err := r.db.Preload("Image").First(photo, photo.ID).Error; err != nil {
-		return nil, err
+
+	ids := make([]int, len(photos))
+	for i, p := range photos {
+		ids[i] = p.ID
 	}
-	return photo, nil
+	var added []models.VenuePhoto
+	err = r.db.Preload("Image").Where("id IN ?", ids).Order("position ASC").Find(&added).Error
+	return added, err
 }
 
 func (r *UserRepository) GetVenuePhoto(photoID int) (*models.VenuePhoto, error) {
@@ -115,8 +171,35 @@ func (r *UserRepository) GetVenuePhoto(photoID int) (*models.VenuePhoto, error)
 	return &photo, err
 }
 
-func (r *UserRepository) DeleteVenuePhoto(photoID int) error {
-	return r.db.Delete(&models.VenuePhoto{}, photoID).Error
+func (r *UserRepository) GetVenuePhotos(venueID int) ([]models.VenuePhoto, error) {
+	var photos []models.VenuePhoto
+	err := orderedPhotos(r.db.Preload("Image").Where("venue_id = ?", venueID)).Find(&photos).Error
+	return photos, err
+}
+
+// UpdateVenuePhoto сохраняет подпись, alt-текст и флаг обложки; новая обложка
+// снимает флаг с предыдущей
+func (r *UserRepository) UpdateVenuePhoto(photo *models.VenuePhoto) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if photo.IsCover {
+			if err := clearGalleryCover(tx, venueGallery, photo.VenueID, photo.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(photo).Select("caption", "alt_text", "is_cover").Updates(photo).Error
+	})
+}
+
+func (r *UserRepository) ReorderVenuePhotos(venueID int, photoIDs []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return reorderGallery(tx, venueGallery, venueID, photoIDs)
+	})
+}
+
+func (r *UserRepository) DeleteVenuePhotos(venueID int, photoIDs []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteGalleryPhotos(tx, venueGallery, venueID, photoIDs)
+	})
 }
 
 // Venue operations
@@ -128,6 +211,7 @@ func (r *UserRepository) GetVenueByID(id int) (*models.Venue, error) {
 	var venue models.Venue
 	err := r.db.Preload("Logo").
 		Preload("CoverPhoto").
+		Preload("Photos", orderedPhotos).
 		Preload("Photos.Image").
 		First(&venue, id).Error
 	return &venue, err
@@ -138,19 +222,24 @@ func (r *UserRepository) GetVenueByUserID(userID int) (*models.Venue, error) {
 	err := r.db.Where("user_id = ?", userID).
 		Preload("Logo").
 		Preload("CoverPhoto").
+		Preload("Photos", orderedPhotos).
 		Preload("Photos.Image").
 		First(&venue).Error
 	return &venue, err
 }
 
-func (r *UserRepository) ListVenues(limit, offset int) ([]models.Venue, error) {
+func (r *UserRepository) ListVenues(page pagination.Params) ([]models.Venue, *int64, error) {
+	total, err := pagination.Count(r.db.Model(&models.Venue{}), page)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var venues []models.Venue
-	err := r.db.Limit(limit).
-		Offset(offset).
+	err = pagination.Apply(r.db, "venues", page).
 		Preload("Logo").
 		Preload("CoverPhoto").
 		Find(&venues).Error
-	return venues, err
+	return venues, total, err
 }
 
 func (r *UserRepository) UpdateVenue(venue *models.Venue) error {
@@ -166,16 +255,203 @@ func (r *UserRepository) CreateImage(image *models.Image) error {
 	return r.db.Create(image).Error
 }
 
+// CreateImageWithinQuota создает изображение владельца, если оно помещается в квоту
+// quotaBytes; иначе возвращает ErrQuotaExceeded. Проверка и вставка идут в одной
+// транзакции под блокировкой владельца.
+func (r *UserRepository) CreateImageWithinQuota(image *models.Image, quotaBytes int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := reserveImageQuota(tx, *image.OwnerUserID, image.Size, quotaBytes); err != nil {
+			return err
+		}
+		return tx.Create(image).Error
+	})
+}
+
 func (r *UserRepository) GetImageByID(id string) (*models.Image, error) {
 	var image models.Image
-	err := r.db.First(&image, "id = ?", id).Error
+	err := r.db.Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("width ASC")
+	}).First(&image, "id = ?", id).Error
 	return &image, err
 }
 
+// UpdateImage сохраняет поля изображения и добавляет новые варианты (у которых еще нет ID)
+func (r *UserRepository) UpdateImage(image *models.Image) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Variants").Save(image).Error; err != nil {
+			return err
+		}
+		for i := range image.Variants {
+			v := &image.Variants[i]
+			if v.ID != 0 {
+				continue
+			}
+			v.ImageID = image.ID
+			if err := tx.Create(v).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimQuarantinedImages забирает на повторную проверку изображения из карантина,
+// которые не проверялись с staleBefore. Отметка scan_attempted_at ставится в той же
+// команде, поэтому несколько реплик не возьмут одно изображение одновременно.
+func (r *UserRepository) ClaimQuarantinedImages(staleBefore time.Time, limit int) ([]models.Image, error) {
+	var images []models.Image
+	err := r.db.Raw(`
+		UPDATE images SET scan_attempted_at = NOW()
+		WHERE id IN (
+			SELECT id FROM images
+			WHERE status = ? AND (scan_attempted_at IS NULL OR scan_attempted_at < ?)
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, models.ImageStatusQuarantined, staleBefore, limit).Scan(&images).Error
+	return images, err
+}
+
+func (r *UserRepository) GetImagesByIDs(ids []string) ([]models.Image, error) {
+	var images []models.Image
+	if len(ids) == 0 {
+		return images, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&images).Error
+	return images, err
+}
+
+// GetImageStorageUsage возвращает объем хранилища пользователя: оригиналы и варианты.
+// Отклоненные сканером файлы не учитываются.
+func (r *UserRepository) GetImageStorageUsage(ownerUserID int) (int64, error) {
+	return imageStorageUsage(r.db, ownerUserID)
+}
+
+// imageReferencedSQL — есть ли у изображения i ссылка из профиля, фото галереи или мероприятия
+const imageReferencedSQL = `(
+	EXISTS (SELECT 1 FROM creators c WHERE c.photo_id = i.id)
+	OR EXISTS (SELECT 1 FROM creator_photos cp WHERE cp.image_id = i.id)
+	OR EXISTS (SELECT 1 FROM venues v WHERE v.logo_id = i.id OR v.cover_photo_id = i.id)
+	OR EXISTS (SELECT 1 FROM venue_photos vp WHERE vp.image_id = i.id)
+	OR EXISTS (SELECT 1 FROM events e WHERE e.cover_photo_id = i.id)
+	OR EXISTS (SELECT 1 FROM event_media em WHERE em.image_id = i.id)
+)`
+
+// MarkUnreferencedImages отмечает временем now изображения, которые впервые остались
+// без ссылок, и снимает отметку с тех, на которые снова сослались. Изображения
+// в карантине не отмечаются — их судьбу решает повторная проверка.
+func (r *UserRepository) MarkUnreferencedImages(now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE images i SET unreferenced_since = NULL
+			WHERE i.unreferenced_since IS NOT NULL AND ` + imageReferencedSQL).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE images i SET unreferenced_since = ?
+			WHERE i.unreferenced_since IS NULL AND i.status <> ? AND NOT `+imageReferencedSQL,
+			now, models.ImageStatusQuarantined).Error
+	})
+}
+
+// DeleteOrphanImages удаляет записи изображений, оставшихся без ссылок раньше
+// unreferencedBefore (см. MarkUnreferencedImages), и возвращает их вместе с вариантами,
+// чтобы вызывающий удалил объекты из MinIO. Ссылки перепроверяются: изображение,
+// привязанное после отметки, не удаляется.
+func (r *UserRepository) DeleteOrphanImages(unreferencedBefore time.Time, limit int) ([]models.Image, error) {
+	var images []models.Image
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Raw(`
+			SELECT i.id FROM images i
+			WHERE i.unreferenced_since < ? AND i.status <> ?
+			  AND NOT `+imageReferencedSQL+`
+			ORDER BY i.unreferenced_since
+			LIMIT ?
+			FOR UPDATE OF i SKIP LOCKED`, unreferencedBefore, models.ImageStatusQuarantined, limit).Scan(&ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Preload("Variants").Where("id IN ?", ids).Find(&images).Error; err != nil {
+			return err
+		}
+		// Варианты удаляются каскадом
+		return tx.Delete(&models.Image{}, "id IN ?", ids).Error
+	})
+	return images, err
+}
+
 func (r *UserRepository) DeleteImage(id string) error {
 	return r.db.Delete(&models.Image{}, "id = ?", id).Error
 }
 
+// ImageUpload operations
+
+// CreateImageUpload сохраняет загрузку, если ее заявленный размер помещается в квоту
+// quotaBytes; иначе возвращает ErrQuotaExceeded
+func (r *UserRepository) CreateImageUpload(upload *models.ImageUpload, quotaBytes int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := reserveImageQuota(tx, upload.OwnerUserID, upload.Size, quotaBytes); err != nil {
+			return err
+		}
+		return tx.Create(upload).Error
+	})
+}
+
+func (r *UserRepository) GetImageUpload(id string) (*models.ImageUpload, error) {
+	var upload models.ImageUpload
+	err := r.db.First(&upload, "id = ?", id).Error
+	return &upload, err
+}
+
+// FinalizeImageUpload в одной транзакции удаляет запись загрузки и создает изображение.
+// Если загрузку уже завершил параллельный запрос, возвращает gorm.ErrRecordNotFound;
+// если изображение не помещается в квоту quotaBytes — ErrQuotaExceeded, и загрузка остается.
+func (r *UserRepository) FinalizeImageUpload(uploadID string, image *models.Image, quotaBytes int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Владельца блокируем до удаления загрузки, чтобы порядок блокировок совпадал с init
+		if err := lockImageOwner(tx, *image.OwnerUserID); err != nil {
+			return err
+		}
+		result := tx.Delete(&models.ImageUpload{}, "id = ?", uploadID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// Резерв этой загрузки уже снят, вместо него считается само изображение
+		if err := reserveImageQuota(tx, *image.OwnerUserID, image.Size, quotaBytes); err != nil {
+			return err
+		}
+		return tx.Create(image).Error
+	})
+}
+
+func (r *UserRepository) DeleteImageUpload(id string) error {
+	return r.db.Delete(&models.ImageUpload{}, "id = ?", id).Error
+}
+
+// DeleteExpiredImageUploads удаляет загрузки, истекшие до expiredBefore, и возвращает их,
+// чтобы вызывающий убрал из MinIO загруженные объекты и незавершенные multipart-загрузки
+func (r *UserRepository) DeleteExpiredImageUploads(expiredBefore time.Time, limit int) ([]models.ImageUpload, error) {
+	var uploads []models.ImageUpload
+	err := r.db.Raw(`
+		DELETE FROM image_uploads
+		WHERE id IN (
+			SELECT id FROM image_uploads
+			WHERE expires_at < ?
+			ORDER BY expires_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, expiredBefore, limit).Scan(&uploads).Error
+	return uploads, err
+}
+
 // VenueCategory operations
 func (r *UserRepository) AddVenueCategories(venueID int, categoryIDs []int) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
@@ -255,6 +531,142 @@ func (r *UserRepository) ListCreatorFavoriteVenues(creatorUserID int) ([]models.
 	return venues, err
 }
 
+// Similar profiles operations
+
+// similarVenueCandidatesQuery собирает сигналы похожести площадок на исходную:
+// общие категории, тот же город, вместимость в пределах [c/2; 2c] и число
+// создателей, добавивших в избранное обе площадки. Кандидат без общих
+// категорий, города и со-избранного в выборку не попадает.
+const similarVenueCandidatesQuery = `
+WITH target AS (
+	SELECT id, user_id, city_id, COALESCE(capacity, 0) AS capacity
+	FROM venues WHERE user_id = @user_id
+),
+target_categories AS (
+	SELECT vc.category_id FROM venue_categories vc JOIN target t ON t.id = vc.venue_id
+),
+categories AS (
+	SELECT vc.venue_id,
+	       COUNT(*) AS total,
+	       COUNT(*) FILTER (WHERE vc.category_id IN (SELECT category_id FROM target_categories)) AS shared
+	FROM venue_categories vc
+	GROUP BY vc.venue_id
+),
+co_favorites AS (
+	SELECT f2.venue_user_id AS user_id, COUNT(*) AS cnt
+	FROM creator_favorite_venues f1
+	JOIN creator_favorite_venues f2
+	  ON f2.creator_user_id = f1.creator_user_id AND f2.venue_user_id <> f1.venue_user_id
+	WHERE f1.venue_user_id = @user_id
+	GROUP BY f2.venue_user_id
+)
+SELECT v.user_id,
+       COALESCE(c.shared, 0) AS shared_categories,
+       COALESCE(c.total, 0) + (SELECT COUNT(*) FROM target_categories) - COALESCE(c.shared, 0) AS union_categories,
+       COALESCE(v.city_id = t.city_id, false) AS same_city,
+       (t.capacity > 0 AND COALESCE(v.capacity, 0) BETWEEN t.capacity / 2 AND t.capacity * 2) AS capacity_fit,
+       COALESCE(cf.cnt, 0) AS co_favorites
+FROM venues v
+CROSS JOIN target t
+LEFT JOIN categories c ON c.venue_id = v.id
+LEFT JOIN co_favorites cf ON cf.user_id = v.user_id
+WHERE v.user_id <> t.user_id
+  AND (COALESCE(c.shared, 0) > 0 OR cf.cnt IS NOT NULL OR v.city_id = t.city_id)
+ORDER BY COALESCE(cf.cnt, 0) DESC, COALESCE(c.shared, 0) DESC, v.user_id
+LIMIT @limit`
+
+// similarCreatorCandidatesQuery — то же для создателей. Категории создателя —
+// категории его мероприятий, город — города площадок, с которыми он сотрудничал,
+// со-избранное — площадки, добавившие в избранное мероприятия обоих.
+const similarCreatorCandidatesQuery = `
+WITH target_categories AS (
+	SELECT DISTINCT ec.category_id
+	FROM event_categories ec JOIN events e ON e.id = ec.event_id
+	WHERE e.creator_id = @user_id
+),
+categories AS (
+	SELECT e.creator_id AS user_id,
+	       COUNT(DISTINCT ec.category_id) AS total,
+	       COUNT(DISTINCT ec.category_id) FILTER (WHERE ec.category_id IN (SELECT category_id FROM target_categories)) AS shared
+	FROM events e JOIN event_categories ec ON ec.event_id = e.id
+	WHERE e.creator_id <> @user_id
+	GROUP BY e.creator_id
+),
+target_cities AS (
+	SELECT DISTINCT v.city_id
+	FROM collaborations c JOIN venues v ON v.user_id = c.venue_user_id
+	WHERE c.creator_user_id = @user_id AND c.status <> 'cancelled' AND v.city_id IS NOT NULL
+),
+same_city AS (
+	SELECT DISTINCT c.creator_user_id AS user_id
+	FROM collaborations c JOIN venues v ON v.user_id = c.venue_user_id
+	WHERE c.creator_user_id <> @user_id AND c.status <> 'cancelled'
+	  AND v.city_id IN (SELECT city_id FROM target_cities)
+),
+co_favorites AS (
+	SELECT e2.creator_id AS user_id, COUNT(DISTINCT f2.venue_user_id) AS cnt
+	FROM venue_favorite_events f1
+	JOIN events e1 ON e1.id = f1.event_id AND e1.creator_id = @user_id
+	JOIN venue_favorite_events f2 ON f2.venue_user_id = f1.venue_user_id
+	JOIN events e2 ON e2.id = f2.event_id AND e2.creator_id <> @user_id
+	GROUP BY e2.creator_id
+)
+SELECT cr.user_id,
+       COALESCE(c.shared, 0) AS shared_categories,
+       COALESCE(c.total, 0) + (SELECT COUNT(*) FROM target_categories) - COALESCE(c.shared, 0) AS union_categories,
+       (sc.user_id IS NOT NULL) AS same_city,
+       false AS capacity_fit,
+       COALESCE(cf.cnt, 0) AS co_favorites
+FROM creators cr
+LEFT JOIN categories c ON c.user_id = cr.user_id
+LEFT JOIN same_city sc ON sc.user_id = cr.user_id
+LEFT JOIN co_favorites cf ON cf.user_id = cr.user_id
+WHERE cr.user_id <> @user_id
+  AND (COALESCE(c.shared, 0) > 0 OR sc.user_id IS NOT NULL OR cf.cnt IS NOT NULL)
+ORDER BY COALESCE(cf.cnt, 0) DESC, COALESCE(c.shared, 0) DESC, cr.user_id
+LIMIT @limit`
+
+func (r *UserRepository) FindSimilarVenueCandidates(venueUserID, limit int) ([]models.SimilarityCandidate, error) {
+	var candidates []models.SimilarityCandidate
+	err := r.db.Raw(similarVenueCandidatesQuery, map[string]interface{}{
+		"user_id": venueUserID,
+		"limit":   limit,
+	}).Scan(&candidates).Error
+	return candidates, err
+}
+
+func (r *UserRepository) FindSimilarCreatorCandidates(creatorUserID, limit int) ([]models.SimilarityCandidate, error) {
+	var candidates []models.SimilarityCandidate
+	err := r.db.Raw(similarCreatorCandidatesQuery, map[string]interface{}{
+		"user_id": creatorUserID,
+		"limit":   limit,
+	}).Scan(&candidates).Error
+	return candidates, err
+}
+
+func (r *UserRepository) GetVenuesByUserIDs(userIDs []int) ([]models.Venue, error) {
+	if len(userIDs) == 0 {
+		return []models.Venue{}, nil
+	}
+	var venues []models.Venue
+	err := r.db.Where("user_id IN ?", userIDs).
+		Preload("Logo").
+		Preload("CoverPhoto").
+		Find(&venues).Error
+	return venues, err
+}
+
+func (r *UserRepository) GetCreatorsByUserIDs(userIDs []int) ([]models.Creator, error) {
+	if len(userIDs) == 0 {
+		return []models.Creator{}, nil
+	}
+	var creators []models.Creator
+	err := r.db.Where("user_id IN ?", userIDs).
+		Preload("Photo").
+		Find(&creators).Error
+	return creators, err
+}
+
 // Newsletter operations
 
 func (r *UserRepository) CreateNewsletterSubscription(sub *models.NewsletterSubscription) error {
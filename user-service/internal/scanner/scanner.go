@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable — сканер не смог дать вердикт (недоступен, таймаут, ошибка протокола).
+// Файл в этом случае остается в карантине до повторной проверки.
+var ErrUnavailable = errors.New("scanner unavailable")
+
+// Verdict — результат проверки файла
+type Verdict struct {
+	Clean     bool
+	Signature string // имя сработавшей сигнатуры, если файл не чистый
+}
+
+// Scanner проверяет загруженные файлы на вредоносное содержимое
+type Scanner interface {
+	Scan(ctx context.Context, data []byte) (Verdict, error)
+}
+
+// EICARSignature — стандартная тестовая строка антивирусов
+const EICARSignature = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// SignatureScanner — локальная заглушка в духе ClamAV: ищет в файле подстроки
+// из набора сигнатур. По умолчанию знает только EICAR, чего достаточно для тестов
+// и окружений без clamd.
+type SignatureScanner struct {
+	signatures map[string][]byte
+}
+
+func NewSignatureScanner(extra map[string]string) *SignatureScanner {
+	signatures := map[string][]byte{"Eicar-Test-Signature": []byte(EICARSignature)}
+	for name, pattern := range extra {
+		signatures[name] = []byte(pattern)
+	}
+	return &SignatureScanner{signatures: signatures}
+}
+
+func (s *SignatureScanner) Scan(ctx context.Context, data []byte) (Verdict, error) {
+	for name, pattern := range s.signatures {
+		if bytes.Contains(data, pattern) {
+			return Verdict{Clean: false, Signature: name}, nil
+		}
+	}
+	return Verdict{Clean: true}, nil
+}
+
+const (
+	clamdChunkSize = 64 * 1024
+	clamdTimeout   = 30 * time.Second
+)
+
+// ClamdScanner отправляет файл демону clamd по протоколу INSTREAM
+type ClamdScanner struct {
+	addr    string
+	timeout time.Duration
+}
+
+func NewClamdScanner(addr string) *ClamdScanner {
+	return &ClamdScanner{addr: addr, timeout: clamdTimeout}
+}
+
+func (s *ClamdScanner) Scan(ctx context.Context, data []byte) (Verdict, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	// Поток передается кусками: 4 байта длины (big-endian) + данные, в конце нулевая длина
+	size := make([]byte, 4)
+	for offset := 0; offset < len(data); offset += clamdChunkSize {
+		end := min(offset+clamdChunkSize, len(data))
+		binary.BigEndian.PutUint32(size, uint32(end-offset))
+		if _, err := conn.Write(size); err != nil {
+			return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		if _, err := conn.Write(data[offset:end]); err != nil {
+			return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply разбирает ответы вида "stream: OK" и "stream: <Signature> FOUND"
+func parseClamdReply(reply string) (Verdict, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return Verdict{Clean: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Clean: false, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	}
+	return Verdict{}, fmt.Errorf("%w: unexpected clamd reply %q", ErrUnavailable, reply)
+}
@@ -11,14 +11,28 @@ var (
 	ErrCreatorNotFound      = errors.New("CREATOR_NOT_FOUND")
 	ErrVenueNotFound        = errors.New("VENUE_NOT_FOUND")
 	ErrPhotoNotFound        = errors.New("PHOTO_NOT_FOUND")
+	ErrDuplicatePhoto       = errors.New("DUPLICATE_PHOTO")
+	ErrGalleryLimitExceeded = errors.New("GALLERY_LIMIT_EXCEEDED")
+	ErrInvalidPhotoOrder    = errors.New("INVALID_PHOTO_ORDER")
 	ErrProfileNotFound      = errors.New("PROFILE_NOT_FOUND")
 	ErrProfileAlreadyExists = errors.New("PROFILE_ALREADY_EXISTS")
 	ErrUserNotFound         = errors.New("USER_NOT_FOUND")
 	ErrInvalidFileType             = errors.New("INVALID_FILE_TYPE")
 	ErrFileTooLarge                = errors.New("FILE_TOO_LARGE")
 	ErrInvalidImageType            = errors.New("INVALID_IMAGE_TYPE")
+	ErrInvalidImageFormat          = errors.New("INVALID_IMAGE_FORMAT")
+	ErrImageNotFound               = errors.New("IMAGE_NOT_FOUND")
+	ErrImageNotReady               = errors.New("IMAGE_NOT_READY")
+	ErrImageDimensionsTooLarge     = errors.New("IMAGE_DIMENSIONS_TOO_LARGE")
+	ErrMalwareDetected             = errors.New("MALWARE_DETECTED")
+	ErrImageNotOwned               = errors.New("IMAGE_NOT_OWNED")
+	ErrStorageQuotaExceeded        = errors.New("STORAGE_QUOTA_EXCEEDED")
+	ErrUploadNotFound              = errors.New("UPLOAD_NOT_FOUND")
+	ErrUploadIncomplete            = errors.New("UPLOAD_INCOMPLETE")
+	ErrUploadSizeMismatch          = errors.New("UPLOAD_SIZE_MISMATCH")
 	ErrAlreadySubscribed           = errors.New("ALREADY_SUBSCRIBED")
 	ErrInvalidUnsubscribeToken     = errors.New("INVALID_UNSUBSCRIBE_TOKEN")
 	ErrAlreadyFavorited            = errors.New("ALREADY_FAVORITED")
 	ErrFavoriteNotFound            = errors.New("FAVORITE_NOT_FOUND")
+	ErrDependencyUnavailable       = errors.New("DEPENDENCY_UNAVAILABLE")
 )
@@ -9,13 +9,19 @@ import (
 )
 
 type FavoritesService struct {
-	repo repository.UserRepositoryInterface
+	repo    repository.UserRepositoryInterface
+	similar *SimilarService
 }
 
 func NewFavoritesService(repo repository.UserRepositoryInterface) *FavoritesService {
 	return &FavoritesService{repo: repo}
 }
 
+// SetSimilarService подключает сброс кэша похожих площадок: со-избранное — один из сигналов
+func (s *FavoritesService) SetSimilarService(similar *SimilarService) {
+	s.similar = similar
+}
+
 func (s *FavoritesService) AddFavoriteVenue(creatorUserID, venueUserID int) error {
 	_, err := s.repo.GetVenueByUserID(venueUserID)
 	if err != nil {
@@ -29,6 +35,7 @@ func (s *FavoritesService) AddFavoriteVenue(creatorUserID, venueUserID int) erro
 	if alreadyExisted {
 		return ErrAlreadyFavorited
 	}
+	s.similar.InvalidateVenues()
 	return nil
 }
 
@@ -37,7 +44,11 @@ func (s *FavoritesService) RemoveFavoriteVenue(creatorUserID, venueUserID int) e
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return ErrFavoriteNotFound
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	s.similar.InvalidateVenues()
+	return nil
 }
 
 func (s *FavoritesService) ListFavoriteVenues(creatorUserID int) ([]models.Venue, error) {
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"gorm.io/gorm"
+)
+
+// Способы прямой загрузки
+const (
+	DirectUploadPost      = "post"
+	DirectUploadMultipart = "multipart"
+)
+
+const (
+	// Файлы крупнее грузятся частями такого размера (минимум S3 — 5MB, кроме последней)
+	DirectUploadPartSize = 8 * 1024 * 1024
+
+	finalizeTimeout = 2 * time.Minute
+
+	// uploadHeaderSize — сколько байт начала файла читает finalize. Размеры JPEG
+	// записаны после EXIF и ICC-профиля, поэтому берем с запасом.
+	uploadHeaderSize = 512 * 1024
+)
+
+// DirectUpload - инструкция клиенту, как загрузить файл прямо в MinIO.
+// Для method=post файл отправляется одной multipart/form-data формой на URL:
+// сначала все поля FormData, последним — поле file. Для method=multipart каждая часть
+// (по PartSize байт, последняя — остаток) отправляется PUT на свою ссылку из Parts.
+// После загрузки клиент вызывает finalize.
+type DirectUpload struct {
+	ID        string            `json:"id"`
+	Method    string            `json:"method"` // post, multipart
+	URL       string            `json:"url,omitempty"`
+	FormData  map[string]string `json:"form_data,omitempty"`
+	PartSize  int64             `json:"part_size,omitempty"`
+	Parts     []UploadPart      `json:"parts,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// UploadPart - часть multipart-загрузки. Для уже загруженных частей ссылка не выдается,
+// поэтому прерванную загрузку можно продолжить с того места, где она остановилась.
+type UploadPart struct {
+	Number   int    `json:"number"`
+	URL      string `json:"url,omitempty"`
+	Uploaded bool   `json:"uploaded"`
+}
+
+// DirectUploadPartCount - на сколько частей делится файл размера size; 0 — грузится одним POST
+func DirectUploadPartCount(size int64) int {
+	if size <= DirectUploadPartSize {
+		return 0
+	}
+	return int((size + DirectUploadPartSize - 1) / DirectUploadPartSize)
+}
+
+// InitDirectUpload начинает прямую загрузку в карантинный бакет: проверяет тип,
+// заявленный размер и квоту и выдает presigned-политику POST или, для крупных файлов,
+// ссылки на части multipart-загрузки. Ссылки живут cfg.ImageUploadTTL.
+func (s *ImageService) InitDirectUpload(userID int, imageType, fileName string, size int64) (*DirectUpload, error) {
+	if !isValidFileName(imageType, fileName) {
+		return nil, ErrInvalidFileType
+	}
+	if size > s.cfg.ImageDirectUploadMaxBytes {
+		return nil, ErrFileTooLarge
+	}
+	if getBucketByImageType(imageType) == "" {
+		return nil, ErrInvalidImageType
+	}
+	if err := s.checkQuota(userID, size); err != nil {
+		return nil, err
+	}
+
+	// ID загрузки станет ID изображения. Объект без расширения: формат известен
+	// только после проверки содержимого, и publish подставит расширение сам.
+	id := uuid.New().String()
+	upload := &models.ImageUpload{
+		ID:          id,
+		OwnerUserID: userID,
+		ImageType:   imageType,
+		FileName:    filepath.Base(fileName),
+		ObjectName:  id,
+		Size:        size,
+		PartCount:   DirectUploadPartCount(size),
+		ExpiresAt:   time.Now().Add(s.cfg.ImageUploadTTL),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	core := minio.Core{Client: s.minioClient}
+	if upload.PartCount > 0 {
+		uploadID, err := core.NewMultipartUpload(ctx, quarantineBucket, upload.ObjectName, minio.PutObjectOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start multipart upload: %v", err)
+		}
+		upload.MultipartUploadID = uploadID
+	}
+
+	if err := s.repo.CreateImageUpload(upload, s.cfg.ImageQuotaBytes); err != nil {
+		if upload.MultipartUploadID != "" {
+			core.AbortMultipartUpload(context.Background(), quarantineBucket, upload.ObjectName, upload.MultipartUploadID)
+		}
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			return nil, ErrStorageQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to save upload: %v", err)
+	}
+
+	return s.presignUpload(ctx, upload, nil)
+}
+
+// GetDirectUpload заново выдает ссылки для незавершенной загрузки: для multipart —
+// только на части, которых еще нет в MinIO. Срок жизни загрузки не продлевается.
+func (s *ImageService) GetDirectUpload(userID int, uploadID string) (*DirectUpload, error) {
+	upload, err := s.getOwnUpload(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var uploaded map[int]bool
+	if upload.MultipartUploadID != "" {
+		parts, err := s.listUploadedParts(ctx, upload)
+		if err != nil {
+			return nil, err
+		}
+		uploaded = make(map[int]bool, len(parts))
+		for _, p := range parts {
+			uploaded[p.PartNumber] = true
+		}
+	}
+	return s.presignUpload(ctx, upload, uploaded)
+}
+
+// FinalizeDirectUpload завершает прямую загрузку: собирает multipart-объект, сверяет
+// размер с заявленным, проверяет начало файла и создает изображение в карантине.
+// Квота проверяется заново в той же транзакции, что создает изображение.
+// Файл целиком не читается: проверку сканером и публикацию выполняет воркер карантина,
+// поэтому изображение возвращается со статусом quarantined.
+func (s *ImageService) FinalizeDirectUpload(userID int, uploadID string) (*models.Image, error) {
+	upload, err := s.getOwnUpload(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), finalizeTimeout)
+	defer cancel()
+
+	if upload.MultipartUploadID != "" {
+		if err := s.completeMultipart(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := s.minioClient.StatObject(ctx, quarantineBucket, upload.ObjectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrUploadIncomplete
+		}
+		return nil, fmt.Errorf("failed to stat uploaded object: %v", err)
+	}
+	// Политика POST ограничивает размер сама, а части multipart клиент мог загрузить любые
+	if info.Size != upload.Size {
+		s.discardUpload(ctx, upload)
+		return nil, ErrUploadSizeMismatch
+	}
+
+	header, err := s.readObjectHeader(ctx, quarantineBucket, upload.ObjectName, uploadHeaderSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded object: %v", err)
+	}
+
+	imageInfo, err := inspectUploadHeader(upload.ImageType, header)
+	if err != nil {
+		s.discardUpload(ctx, upload)
+		return nil, mapImagingError(err)
+	}
+
+	image := &models.Image{
+		ID:          upload.ID,
+		FileName:    upload.FileName,
+		FilePath:    upload.ObjectName,
+		FileType:    imageInfo.ContentType,
+		ImageType:   upload.ImageType,
+		BucketName:  quarantineBucket,
+		Status:      models.ImageStatusQuarantined,
+		Width:       imageInfo.Width,
+		Height:      imageInfo.Height,
+		OwnerUserID: &userID,
+		Size:        upload.Size,
+	}
+	if err := s.repo.FinalizeImageUpload(upload.ID, image, s.cfg.ImageQuotaBytes); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		// Объект не удаляем: освободив место, пользователь может повторить finalize
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			return nil, ErrStorageQuotaExceeded
+		}
+		return nil, fmt.Errorf("failed to save image metadata: %v", err)
+	}
+
+	s.wakeQuarantineWorker()
+	return image, nil
+}
+
+// AbortDirectUpload отменяет загрузку и удаляет из MinIO все, что успели загрузить
+func (s *ImageService) AbortDirectUpload(userID int, uploadID string) error {
+	upload, err := s.getOwnUpload(userID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.discardUpload(ctx, upload)
+	return nil
+}
+
+// CollectExpiredUploads удаляет истекшие незавершенные загрузки и возвращает их число
+func (s *ImageService) CollectExpiredUploads(ctx context.Context) (int, error) {
+	removed := 0
+	for {
+		uploads, err := s.repo.DeleteExpiredImageUploads(time.Now(), orphanBatchSize)
+		if err != nil {
+			return removed, err
+		}
+		for i := range uploads {
+			s.removeUploadObjects(ctx, &uploads[i])
+		}
+		removed += len(uploads)
+		if len(uploads) < orphanBatchSize {
+			return removed, nil
+		}
+	}
+}
+
+// getOwnUpload возвращает действующую загрузку пользователя; чужая и истекшая
+// неотличимы от несуществующей
+func (s *ImageService) getOwnUpload(userID int, uploadID string) (*models.ImageUpload, error) {
+	upload, err := s.repo.GetImageUpload(uploadID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	if upload.OwnerUserID != userID || !upload.ExpiresAt.After(time.Now()) {
+		return nil, ErrUploadNotFound
+	}
+	return upload, nil
+}
+
+// presignUpload подписывает ссылки на публичный адрес MinIO до конца срока загрузки
+func (s *ImageService) presignUpload(ctx context.Context, upload *models.ImageUpload, uploaded map[int]bool) (*DirectUpload, error) {
+	result := &DirectUpload{ID: upload.ID, ExpiresAt: upload.ExpiresAt.UTC()}
+
+	if upload.MultipartUploadID == "" {
+		policy := minio.NewPostPolicy()
+		if err := policy.SetBucket(quarantineBucket); err != nil {
+			return nil, err
+		}
+		if err := policy.SetKey(upload.ObjectName); err != nil {
+			return nil, err
+		}
+		if err := policy.SetExpires(upload.ExpiresAt.UTC()); err != nil {
+			return nil, err
+		}
+		if err := policy.SetContentLengthRange(upload.Size, upload.Size); err != nil {
+			return nil, err
+		}
+
+		u, formData, err := s.presignClient.PresignedPostPolicy(ctx, policy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to presign upload policy: %v", err)
+		}
+		result.Method = DirectUploadPost
+		result.URL = u.String()
+		result.FormData = formData
+		return result, nil
+	}
+
+	result.Method = DirectUploadMultipart
+	result.PartSize = DirectUploadPartSize
+	ttl := time.Until(upload.ExpiresAt)
+	for n := 1; n <= upload.PartCount; n++ {
+		part := UploadPart{Number: n, Uploaded: uploaded[n]}
+		if !part.Uploaded {
+			params := url.Values{}
+			params.Set("partNumber", strconv.Itoa(n))
+			params.Set("uploadId", upload.MultipartUploadID)
+			u, err := s.presignClient.Presign(ctx, http.MethodPut, quarantineBucket, upload.ObjectName, ttl, params)
+			if err != nil {
+				return nil, fmt.Errorf("failed to presign upload part: %v", err)
+			}
+			part.URL = u.String()
+		}
+		result.Parts = append(result.Parts, part)
+	}
+	return result, nil
+}
+
+func (s *ImageService) listUploadedParts(ctx context.Context, upload *models.ImageUpload) ([]minio.ObjectPart, error) {
+	core := minio.Core{Client: s.minioClient}
+	var parts []minio.ObjectPart
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(ctx, quarantineBucket, upload.ObjectName, upload.MultipartUploadID, marker, 1000)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, result.ObjectParts...)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// completeMultipart собирает объект из загруженных частей. Если uploadId уже не
+// существует, объект был собран предыдущим finalize — дальше решает проверка объекта.
+func (s *ImageService) completeMultipart(ctx context.Context, upload *models.ImageUpload) error {
+	parts, err := s.listUploadedParts(ctx, upload)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchUpload" {
+			return nil
+		}
+		return fmt.Errorf("failed to list uploaded parts: %v", err)
+	}
+	if len(parts) != upload.PartCount {
+		return ErrUploadIncomplete
+	}
+
+	complete := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		complete = append(complete, minio.CompletePart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	core := minio.Core{Client: s.minioClient}
+	if _, err := core.CompleteMultipartUpload(ctx, quarantineBucket, upload.ObjectName, upload.MultipartUploadID, complete, minio.PutObjectOptions{}); err != nil {
+		// Части меньше 5MB (кроме последней) MinIO не принимает
+		if minio.ToErrorResponse(err).Code == "EntityTooSmall" {
+			s.discardUpload(ctx, upload)
+			return ErrUploadSizeMismatch
+		}
+		return fmt.Errorf("failed to complete multipart upload: %v", err)
+	}
+	return nil
+}
+
+// discardUpload удаляет загрузку вместе с объектами: после нее finalize невозможен
+func (s *ImageService) discardUpload(ctx context.Context, upload *models.ImageUpload) {
+	if err := s.repo.DeleteImageUpload(upload.ID); err != nil {
+		log.Printf("images: failed to delete upload %s: %v", upload.ID, err)
+	}
+	s.removeUploadObjects(ctx, upload)
+}
+
+func (s *ImageService) removeUploadObjects(ctx context.Context, upload *models.ImageUpload) {
+	if upload.MultipartUploadID != "" {
+		core := minio.Core{Client: s.minioClient}
+		err := core.AbortMultipartUpload(ctx, quarantineBucket, upload.ObjectName, upload.MultipartUploadID)
+		if err != nil && minio.ToErrorResponse(err).Code != "NoSuchUpload" {
+			log.Printf("images: failed to abort multipart upload %s: %v", upload.ID, err)
+		}
+	}
+	if err := s.minioClient.RemoveObject(ctx, quarantineBucket, upload.ObjectName, minio.RemoveObjectOptions{}); err != nil {
+		log.Printf("images: failed to remove upload %s: %v", upload.ID, err)
+	}
+}
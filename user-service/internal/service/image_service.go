@@ -1,16 +1,22 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
 	"user-service/internal/config"
+	"user-service/internal/imaging"
 	"user-service/internal/models"
 	"user-service/internal/repository"
+	"user-service/internal/scanner"
 
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
@@ -19,11 +25,16 @@ import (
 
 type ImageService struct {
 	minioClient *minio.Client
-	repo        *repository.UserRepository
-	cfg         *config.Config
+	// presignClient подписывает ссылки на публичный адрес MinIO; сам в сеть не ходит
+	presignClient *minio.Client
+	repo          *repository.UserRepository
+	scanner       scanner.Scanner
+	cfg           *config.Config
+	// quarantineWake будит воркер карантина, не дожидаясь очередного тика
+	quarantineWake chan struct{}
 }
 
-func NewImageService(repo *repository.UserRepository, cfg *config.Config) (*ImageService, error) {
+func NewImageService(repo *repository.UserRepository, scanner scanner.Scanner, cfg *config.Config) (*ImageService, error) {
 	// Инициализируем MinIO клиент
 	minioClient, err := minio.New(cfg.MinioEndpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.MinioAccessKey, cfg.MinioSecretKey, ""),
@@ -33,29 +44,48 @@ func NewImageService(repo *repository.UserRepository, cfg *config.Config) (*Imag
 		return nil, fmt.Errorf("failed to initialize MinIO client: %v", err)
 	}
 
+	// С заданным регионом minio-go не запрашивает location бакета при подписи
+	presignClient, err := minio.New(cfg.MinioPublicEndpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.MinioAccessKey, cfg.MinioSecretKey, ""),
+		Secure: cfg.MinioPublicUseSSL,
+		Region: cfg.MinioRegion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize MinIO presign client: %v", err)
+	}
+
 	return &ImageService{
-		minioClient: minioClient,
-		repo:        repo,
-		cfg:         cfg,
+		minioClient:    minioClient,
+		presignClient:  presignClient,
+		repo:           repo,
+		scanner:        scanner,
+		cfg:            cfg,
+		quarantineWake: make(chan struct{}, 1),
 	}, nil
 }
 
-// UploadImage загружает изображение в MinIO и сохраняет метаданные в БД
-// imageType: avatar, creator-photo, venue-logo, venue-cover, venue-photo, event-cover
-func (s *ImageService) UploadImage(file *multipart.FileHeader, imageType string) (*models.Image, error) {
+// UploadImage проверяет файл по содержимому и кладет его в карантинный бакет.
+// После проверки сканером оригинал без EXIF и WebP-варианты стандартных для imageType
+// размеров переносятся в бакет типа, а изображение получает статус ready. Если сканер
+// недоступен, изображение остается в карантине (status=quarantined) и будет
+// перепроверено фоновым воркером.
+// Документы (PDF) проходят тот же путь, но публикуются как есть, без вариантов.
+// Загрузка учитывается в квоте хранилища пользователя userID, он же становится владельцем.
+// imageType: avatar, creator-photo, venue-logo, venue-cover, venue-photo, event-cover,
+// event-photo, event-poster, event-document
+func (s *ImageService) UploadImage(userID int, file *multipart.FileHeader, imageType string) (*models.Image, error) {
 	// Проверяем тип файла
-	if !isValidImageType(file.Filename) {
+	if !isValidFileName(imageType, file.Filename) {
 		return nil, ErrInvalidFileType
 	}
 
 	// Проверяем размер (максимум 10MB)
-	if file.Size > 10*1024*1024 {
+	if file.Size > maxUploadSize {
 		return nil, ErrFileTooLarge
 	}
 
 	// Определяем бакет по типу изображения
-	bucketName := getBucketByImageType(imageType)
-	if bucketName == "" {
+	if getBucketByImageType(imageType) == "" {
 		return nil, ErrInvalidImageType
 	}
 
@@ -66,20 +96,33 @@ func (s *ImageService) UploadImage(file *multipart.FileHeader, imageType string)
 	}
 	defer src.Close()
 
-	// Генерируем уникальное имя файла
-	ext := filepath.Ext(file.Filename)
-	uniqueFileName := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-	objectName := uniqueFileName // Без префикса, просто UUID + расширение
+	// Заявленному размеру не доверяем — читаем не больше лимита
+	data, err := io.ReadAll(io.LimitReader(src, maxUploadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	if len(data) > maxUploadSize {
+		return nil, ErrFileTooLarge
+	}
 
-	// Определяем content-type
-	contentType := getContentType(file.Filename)
+	// Тип определяем по сигнатуре и заголовку, а не по расширению
+	info, err := inspectUpload(imageType, data)
+	if err != nil {
+		return nil, mapImagingError(err)
+	}
+
+	// Быстрый отказ до загрузки в MinIO; окончательно квота проверяется при записи в БД
+	if err := s.checkQuota(userID, int64(len(data))); err != nil {
+		return nil, err
+	}
 
-	// Загружаем в MinIO
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	_, err = s.minioClient.PutObject(ctx, bucketName, objectName, src, file.Size, minio.PutObjectOptions{
-		ContentType: contentType,
+	// Без префикса, просто UUID + расширение по реальному формату
+	objectName := uuid.New().String() + formatExtensions[info.Format]
+	_, err = s.minioClient.PutObject(ctx, quarantineBucket, objectName, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: info.ContentType,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload to MinIO: %v", err)
@@ -87,23 +130,384 @@ func (s *ImageService) UploadImage(file *multipart.FileHeader, imageType string)
 
 	// Сохраняем метаданные в БД (UUID генерируется в Go, не через DB DEFAULT)
 	image := &models.Image{
-		ID:         uuid.New().String(),
-		FileName:   file.Filename,
-		FilePath:   objectName,
-		FileType:   contentType,
-		ImageType:  imageType,
-		BucketName: bucketName,
+		ID:          uuid.New().String(),
+		FileName:    file.Filename,
+		FilePath:    objectName,
+		FileType:    info.ContentType,
+		ImageType:   imageType,
+		BucketName:  quarantineBucket,
+		Status:      models.ImageStatusQuarantined,
+		Width:       info.Width,
+		Height:      info.Height,
+		OwnerUserID: &userID,
+		Size:        int64(len(data)),
 	}
 
-	if err := s.repo.CreateImage(image); err != nil {
+	if err := s.repo.CreateImageWithinQuota(image, s.cfg.ImageQuotaBytes); err != nil {
 		// Если не удалось сохранить в БД, пытаемся удалить файл из MinIO
-		s.minioClient.RemoveObject(context.Background(), bucketName, objectName, minio.RemoveObjectOptions{})
+		s.minioClient.RemoveObject(context.Background(), quarantineBucket, objectName, minio.RemoveObjectOptions{})
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			return nil, ErrStorageQuotaExceeded
+		}
 		return nil, fmt.Errorf("failed to save image metadata: %v", err)
 	}
 
+	return s.scanUploaded(ctx, image, data)
+}
+
+// checkQuota проверяет, что еще size байт поместятся в квоту пользователя.
+// Варианты появятся после проверки и в квоту на этом шаге не входят.
+// Проверка без блокировки нужна только для раннего отказа: атомарно квоту
+// соблюдает запись в БД (repository.ErrQuotaExceeded).
+func (s *ImageService) checkQuota(userID int, size int64) error {
+	usage, err := s.repo.GetImageStorageUsage(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get storage usage: %v", err)
+	}
+	if usage+size > s.cfg.ImageQuotaBytes {
+		return ErrStorageQuotaExceeded
+	}
+	return nil
+}
+
+// scanUploaded проверяет только что принятый в карантин файл. Если сканер не дал
+// вердикт, изображение возвращается как есть, со статусом quarantined.
+func (s *ImageService) scanUploaded(ctx context.Context, image *models.Image, data []byte) (*models.Image, error) {
+	if err := s.scanAndPublish(ctx, image, data); err != nil {
+		if errors.Is(err, errScanPending) {
+			return image, nil
+		}
+		return nil, err
+	}
 	return image, nil
 }
 
+// RunQuarantineWorker проверяет и публикует изображения из карантина: завершенные
+// прямые загрузки и застрявшие из-за недоступности сканера или ошибок публикации.
+// Работает по таймеру и сразу после wakeQuarantineWorker.
+func (s *ImageService) RunQuarantineWorker(ctx context.Context) {
+	ticker := time.NewTicker(quarantineRescanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.rescanQuarantined(ctx)
+		case <-s.quarantineWake:
+			s.rescanQuarantined(ctx)
+		}
+	}
+}
+
+// wakeQuarantineWorker просит воркер карантина пройти очередь сейчас. Если он
+// уже разбужен, повторный вызов ничего не делает.
+func (s *ImageService) wakeQuarantineWorker() {
+	select {
+	case s.quarantineWake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *ImageService) rescanQuarantined(ctx context.Context) {
+	images, err := s.repo.ClaimQuarantinedImages(time.Now().Add(-quarantineRescanInterval), quarantineBatchSize)
+	if err != nil {
+		log.Printf("images: failed to claim quarantined images: %v", err)
+		return
+	}
+
+	for i := range images {
+		image := &images[i]
+		data, err := s.readObject(ctx, image.BucketName, image.FilePath)
+		if err != nil {
+			log.Printf("images: failed to read quarantined image %s: %v", image.ID, err)
+			continue
+		}
+		if err := s.scanAndPublish(ctx, image, data); err != nil && !errors.Is(err, errScanPending) {
+			log.Printf("images: quarantined image %s not published: %v", image.ID, err)
+		}
+	}
+}
+
+// scanAndPublish проверяет файл сканером и при чистом вердикте публикует его.
+// Зараженный файл остается в карантинном бакете для разбора, изображение помечается rejected.
+func (s *ImageService) scanAndPublish(ctx context.Context, image *models.Image, data []byte) error {
+	now := time.Now()
+	image.ScanAttemptedAt = &now
+
+	verdict, err := s.scanner.Scan(ctx, data)
+	if err != nil {
+		log.Printf("images: scan of %s failed, keeping in quarantine: %v", image.ID, err)
+		if err := s.repo.UpdateImage(image); err != nil {
+			log.Printf("images: failed to update image %s: %v", image.ID, err)
+		}
+		return errScanPending
+	}
+
+	image.ScannedAt = &now
+	if !verdict.Clean {
+		log.Printf("images: %s rejected by scanner: %s", image.ID, verdict.Signature)
+		image.Status = models.ImageStatusRejected
+		image.ScanSignature = verdict.Signature
+		if err := s.repo.UpdateImage(image); err != nil {
+			return fmt.Errorf("failed to update image metadata: %v", err)
+		}
+		return ErrMalwareDetected
+	}
+
+	return s.publish(ctx, image, data)
+}
+
+// publish декодирует файл, строит варианты и переносит все в бакет типа изображения
+func (s *ImageService) publish(ctx context.Context, image *models.Image, data []byte) error {
+	quarantinedBucket, quarantinedPath := image.BucketName, image.FilePath
+
+	processed, err := processUpload(image.ImageType, data)
+	if err != nil {
+		if !errors.Is(err, imaging.ErrUnsupportedImage) && !errors.Is(err, imaging.ErrImageTooLarge) &&
+			!errors.Is(err, imaging.ErrUnsupportedDocument) {
+			return fmt.Errorf("failed to process image: %v", err)
+		}
+		// Заголовок был валиден, а данные — нет: такой файл не нужен даже в карантине
+		image.Status = models.ImageStatusRejected
+		if err := s.repo.UpdateImage(image); err != nil {
+			return fmt.Errorf("failed to update image metadata: %v", err)
+		}
+		s.minioClient.RemoveObject(context.Background(), quarantinedBucket, quarantinedPath, minio.RemoveObjectOptions{})
+		return mapImagingError(err)
+	}
+
+	bucketName := getBucketByImageType(image.ImageType)
+	baseName := strings.TrimSuffix(quarantinedPath, filepath.Ext(quarantinedPath))
+	objectName := baseName + formatExtensions[processed.Format]
+
+	uploaded := []string{}
+	put := func(name string, body []byte, contentType string) error {
+		_, err := s.minioClient.PutObject(ctx, bucketName, name, bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{
+			ContentType: contentType,
+		})
+		if err == nil {
+			uploaded = append(uploaded, name)
+		}
+		return err
+	}
+	// При ошибке убираем из MinIO все, что успели загрузить; оригинал остается в карантине
+	cleanup := func() {
+		for _, name := range uploaded {
+			s.minioClient.RemoveObject(context.Background(), bucketName, name, minio.RemoveObjectOptions{})
+		}
+	}
+
+	if err := put(objectName, processed.Original, processed.ContentType); err != nil {
+		return fmt.Errorf("failed to upload to MinIO: %v", err)
+	}
+
+	variants := make([]models.ImageVariant, 0, len(processed.Variants))
+	for _, v := range processed.Variants {
+		name := fmt.Sprintf("%s_w%d.%s", baseName, v.Width, imaging.FormatWebP)
+		if err := put(name, v.Data, "image/webp"); err != nil {
+			cleanup()
+			return fmt.Errorf("failed to upload variant to MinIO: %v", err)
+		}
+		variants = append(variants, models.ImageVariant{
+			Width:    v.Width,
+			Height:   v.Height,
+			Format:   imaging.FormatWebP,
+			FilePath: name,
+			Size:     int64(len(v.Data)),
+		})
+	}
+
+	published := *image
+	published.Status = models.ImageStatusReady
+	published.BucketName = bucketName
+	published.FilePath = objectName
+	published.FileType = processed.ContentType
+	published.Width = processed.Width
+	published.Height = processed.Height
+	published.Size = int64(len(processed.Original))
+	published.Variants = variants
+
+	if err := s.repo.UpdateImage(&published); err != nil {
+		cleanup()
+		return fmt.Errorf("failed to save image metadata: %v", err)
+	}
+	*image = published
+
+	if err := s.minioClient.RemoveObject(context.Background(), quarantinedBucket, quarantinedPath, minio.RemoveObjectOptions{}); err != nil {
+		log.Printf("images: failed to remove %s from quarantine: %v", quarantinedPath, err)
+	}
+	return nil
+}
+
+// StorageUsage - занятый пользователем объем и его квота
+type StorageUsage struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	QuotaBytes int64 `json:"quota_bytes"`
+}
+
+func (s *ImageService) GetStorageUsage(userID int) (*StorageUsage, error) {
+	used, err := s.repo.GetImageStorageUsage(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageUsage{UsedBytes: used, QuotaBytes: s.cfg.ImageQuotaBytes}, nil
+}
+
+// MaxImageRefs — сколько изображений можно запросить у GetImageRefs за раз
+const MaxImageRefs = 100
+
+// ImageRef - сведения об изображении для других сервисов: кто его загрузил
+// и что это за файл. По ним event-service проверяет ссылки мероприятий.
+type ImageRef struct {
+	ID          string `json:"id"`
+	OwnerUserID *int   `json:"owner_user_id"`
+	FileName    string `json:"file_name"`
+	FileType    string `json:"file_type"`
+}
+
+// GetImageRefs возвращает найденные изображения из ids; отклоненные при проверке
+// считаются отсутствующими
+func (s *ImageService) GetImageRefs(ids []string) ([]ImageRef, error) {
+	images, err := s.repo.GetImagesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]ImageRef, 0, len(images))
+	for _, img := range images {
+		if img.Status == models.ImageStatusRejected {
+			continue
+		}
+		refs = append(refs, ImageRef{ID: img.ID, OwnerUserID: img.OwnerUserID, FileName: img.FileName, FileType: img.FileType})
+	}
+	return refs, nil
+}
+
+// RunGarbageCollector периодически удаляет изображения, на которые никто не ссылается
+// дольше cfg.ImageOrphanGrace: загруженные, но не привязанные к профилю, и отвязанные
+// (удаленное фото галереи, замененный аватар, удаленный профиль или мероприятие).
+// Срок отсчитывается с прохода сборщика, который первым увидел изображение без ссылок.
+// Заодно убирает прямые загрузки, которые так и не были завершены до истечения ссылок.
+func (s *ImageService) RunGarbageCollector(ctx context.Context) {
+	ticker := time.NewTicker(orphanCollectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.CollectOrphans(ctx); err != nil {
+				log.Printf("images: orphan collection failed: %v", err)
+			} else if n > 0 {
+				log.Printf("images: removed %d orphan images", n)
+			}
+			if n, err := s.CollectExpiredUploads(ctx); err != nil {
+				log.Printf("images: expired upload collection failed: %v", err)
+			} else if n > 0 {
+				log.Printf("images: removed %d expired uploads", n)
+			}
+		}
+	}
+}
+
+// CollectOrphans удаляет осиротевшие изображения пачками и возвращает их число.
+// Записи удаляются раньше объектов: если MinIO недоступен, в хранилище останется мусор,
+// но не останется записей, указывающих в пустоту.
+func (s *ImageService) CollectOrphans(ctx context.Context) (int, error) {
+	now := time.Now()
+	if err := s.repo.MarkUnreferencedImages(now); err != nil {
+		return 0, err
+	}
+	removed := 0
+	for {
+		images, err := s.repo.DeleteOrphanImages(now.Add(-s.cfg.ImageOrphanGrace), orphanBatchSize)
+		if err != nil {
+			return removed, err
+		}
+		for _, image := range images {
+			s.removeObjects(ctx, &image)
+		}
+		removed += len(images)
+		if len(images) < orphanBatchSize {
+			return removed, nil
+		}
+	}
+}
+
+func (s *ImageService) removeObjects(ctx context.Context, image *models.Image) {
+	names := []string{image.FilePath}
+	for _, v := range image.Variants {
+		names = append(names, v.FilePath)
+	}
+	for _, name := range names {
+		if err := s.minioClient.RemoveObject(ctx, image.BucketName, name, minio.RemoveObjectOptions{}); err != nil {
+			log.Printf("images: failed to remove %s/%s: %v", image.BucketName, name, err)
+		}
+	}
+}
+
+func (s *ImageService) readObject(ctx context.Context, bucket, name string) ([]byte, error) {
+	object, err := s.minioClient.GetObject(ctx, bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(object)
+}
+
+// readObjectHeader читает не больше n первых байт объекта
+func (s *ImageService) readObjectHeader(ctx context.Context, bucket, name string, n int64) ([]byte, error) {
+	object, err := s.minioClient.GetObject(ctx, bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+	return io.ReadAll(io.LimitReader(object, n))
+}
+
+// inspectUpload проверяет содержимое файла по правилам его типа: документы — как PDF,
+// остальное — как изображение
+func inspectUpload(imageType string, data []byte) (*imaging.Info, error) {
+	if documentImageTypes[imageType] {
+		return imaging.InspectDocument(data)
+	}
+	return imaging.Inspect(data, imaging.DefaultLimits)
+}
+
+// inspectUploadHeader проверяет файл по его началу: формат и размеры изображения
+// из заголовка, сигнатуру документа. Остальное проверит publish по полному файлу.
+func inspectUploadHeader(imageType string, header []byte) (*imaging.Info, error) {
+	if documentImageTypes[imageType] {
+		return imaging.SniffDocument(header)
+	}
+	return imaging.Inspect(header, imaging.DefaultLimits)
+}
+
+// processUpload готовит файл к публикации. Документ повторно проверяется (в карантине
+// он мог пролежать с прошлой версии правил) и публикуется без изменений и вариантов.
+func processUpload(imageType string, data []byte) (*imaging.Result, error) {
+	if !documentImageTypes[imageType] {
+		return imaging.Process(data, imageVariantWidths[imageType])
+	}
+	info, err := imaging.InspectDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return &imaging.Result{Original: data, Format: info.Format, ContentType: info.ContentType}, nil
+}
+
+func mapImagingError(err error) error {
+	if errors.Is(err, imaging.ErrImageTooLarge) {
+		return ErrImageDimensionsTooLarge
+	}
+	if errors.Is(err, imaging.ErrUnsupportedImage) || errors.Is(err, imaging.ErrUnsupportedDocument) {
+		return ErrInvalidFileType
+	}
+	return err
+}
+
 // DeleteImage удаляет изображение из MinIO и БД
 func (s *ImageService) DeleteImage(imageID string) error {
 	// Получаем информацию об изображении
@@ -120,6 +524,11 @@ func (s *ImageService) DeleteImage(imageID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete from MinIO: %v", err)
 	}
+	for _, v := range image.Variants {
+		if err := s.minioClient.RemoveObject(ctx, image.BucketName, v.FilePath, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to delete variant from MinIO: %v", err)
+		}
+	}
 
 	// Удаляем из БД
 	if err := s.repo.DeleteImage(imageID); err != nil {
@@ -129,51 +538,215 @@ func (s *ImageService) DeleteImage(imageID string) error {
 	return nil
 }
 
-// GetImage возвращает изображение из MinIO в виде байтов
-func (s *ImageService) GetImage(imageID string) (*models.Image, []byte, error) {
-	image, err := s.repo.GetImageByID(imageID)
+// ImageObject - открытый на чтение объект MinIO (оригинал или вариант) с метаданными
+// для условных запросов. Body поддерживает Seek, поэтому Range отдается без
+// чтения объекта целиком; закрыть Body обязан вызывающий.
+type ImageObject struct {
+	FileName     string
+	ContentType  string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	Body         io.ReadSeekCloser
+}
+
+// PresignedImage - короткоживущая ссылка на объект прямо в MinIO
+type PresignedImage struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// imageTarget - конкретный объект, выбранный по параметрам w/format
+type imageTarget struct {
+	bucket      string
+	objectName  string
+	fileName    string
+	contentType string
+}
+
+// OpenImage открывает изображение в MinIO для потоковой отдачи.
+// width и format выбирают вариант: format "original" — исходный файл, "webp" — вариант;
+// если задана только ширина, подразумевается webp. Без параметров отдается оригинал.
+func (s *ImageService) OpenImage(imageID string, width int, format string) (*ImageObject, error) {
+	target, err := s.resolveImage(imageID, width, format)
 	if err != nil {
-		return nil, nil, fmt.Errorf("image not found in DB: %v", err)
+		return nil, err
 	}
 
-	ctx := context.Background()
-
 	// Получаем объект из MinIO (используем bucket_name из БД)
-	object, err := s.minioClient.GetObject(ctx, image.BucketName, image.FilePath, minio.GetObjectOptions{})
+	object, err := s.minioClient.GetObject(context.Background(), target.bucket, target.objectName, minio.GetObjectOptions{})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get object from MinIO: %v", err)
+		return nil, fmt.Errorf("failed to get object from MinIO: %v", err)
 	}
-	defer object.Close()
 
-	// Читаем весь объект в память
-	data, err := io.ReadAll(object)
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrImageNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object in MinIO: %v", err)
+	}
+
+	return &ImageObject{
+		FileName:     target.fileName,
+		ContentType:  target.contentType,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+		Body:         object,
+	}, nil
+}
+
+// PresignImage выписывает presigned GET-ссылку на выбранный объект, чтобы байты
+// изображения шли клиенту напрямую из MinIO, минуя gateway и user-service
+func (s *ImageService) PresignImage(imageID string, width int, format string) (*PresignedImage, error) {
+	target, err := s.resolveImage(imageID, width, format)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read image data: %v", err)
+		return nil, err
 	}
 
-	if len(data) == 0 {
-		return nil, nil, fmt.Errorf("image data is empty (0 bytes)")
+	params := url.Values{}
+	params.Set("response-content-type", target.contentType)
+	params.Set("response-content-disposition", fmt.Sprintf("inline; filename=\"%s\"", target.fileName))
+
+	ttl := s.cfg.ImageURLTTL
+	u, err := s.presignClient.PresignedGetObject(context.Background(), target.bucket, target.objectName, ttl, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign object: %v", err)
 	}
 
-	return image, data, nil
+	return &PresignedImage{URL: u.String(), ExpiresAt: time.Now().Add(ttl).UTC()}, nil
+}
+
+func (s *ImageService) resolveImage(imageID string, width int, format string) (*imageTarget, error) {
+	switch format {
+	case "":
+		if width > 0 {
+			format = imaging.FormatWebP
+		}
+	case imaging.FormatWebP, ImageFormatOriginal:
+	default:
+		return nil, ErrInvalidImageFormat
+	}
+
+	image, err := s.repo.GetImageByID(imageID)
+	if err != nil || image.Status == models.ImageStatusRejected {
+		return nil, ErrImageNotFound
+	}
+	if image.Status == models.ImageStatusQuarantined {
+		return nil, ErrImageNotReady
+	}
+
+	target := &imageTarget{
+		bucket:      image.BucketName,
+		objectName:  image.FilePath,
+		fileName:    image.FileName,
+		contentType: image.FileType,
+	}
+
+	if format == imaging.FormatWebP {
+		// Изображения, загруженные до появления вариантов, отдаем как есть
+		if v := PickImageVariant(image.Variants, format, width); v != nil {
+			target.objectName = v.FilePath
+			target.contentType = "image/webp"
+			target.fileName = strings.TrimSuffix(image.FileName, filepath.Ext(image.FileName)) + ".webp"
+		}
+	}
+
+	return target, nil
+}
+
+// PickImageVariant выбирает наименьший вариант нужного формата, который не уже width.
+// Если таких нет (или width не задан), возвращается самый крупный вариант; nil — если
+// вариантов этого формата нет вовсе.
+func PickImageVariant(variants []models.ImageVariant, format string, width int) *models.ImageVariant {
+	var best, largest *models.ImageVariant
+	for i := range variants {
+		v := &variants[i]
+		if v.Format != format {
+			continue
+		}
+		if largest == nil || v.Width > largest.Width {
+			largest = v
+		}
+		if width > 0 && v.Width >= width && (best == nil || v.Width < best.Width) {
+			best = v
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return largest
 }
 
 // Вспомогательные функции
 
+// ImageFormatOriginal - значение ?format= для отдачи исходного файла
+const ImageFormatOriginal = "original"
+
+const (
+	maxUploadSize = 10 * 1024 * 1024
+
+	// Бакет, где файлы лежат до прохождения проверки
+	quarantineBucket         = "image-quarantine"
+	quarantineRescanInterval = time.Minute
+	quarantineBatchSize      = 20
+
+	orphanCollectInterval = time.Hour
+	orphanBatchSize       = 100
+)
+
+// errScanPending - сканер не дал вердикт, изображение остается в карантине
+var errScanPending = errors.New("scan pending")
+
+// formatExtensions - расширение объекта по формату, определенному по содержимому
+var formatExtensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+	"webp": ".webp",
+	"pdf":  ".pdf",
+}
+
+// imageVariantWidths - ширины WebP-вариантов, которые нужны каждому типу изображения
+var imageVariantWidths = map[string][]int{
+	"avatar":        {64, 128, 256, 512},
+	"creator-photo": {320, 640, 1280},
+	"venue-logo":    {64, 128, 256},
+	"venue-cover":   {640, 1280, 1920},
+	"venue-photo":   {320, 640, 1280},
+	"event-cover":   {320, 640, 1280, 1920},
+	"event-photo":   {320, 640, 1280},
+	"event-poster":  {320, 640, 1280, 1920},
+}
+
+// documentImageTypes - типы, под которыми хранятся документы (PDF), а не изображения
+var documentImageTypes = map[string]bool{
+	"event-document": true,
+}
+
 func getBucketByImageType(imageType string) string {
 	bucketMap := map[string]string{
-		"avatar":        "creator-avatars",
-		"creator-photo": "creator-photos",
-		"venue-logo":    "venue-logos",
-		"venue-cover":   "venue-cover-photos",
-		"venue-photo":   "venue-photos",
-		"event-cover":   "event-covers",
+		"avatar":         "creator-avatars",
+		"creator-photo":  "creator-photos",
+		"venue-logo":     "venue-logos",
+		"venue-cover":    "venue-cover-photos",
+		"venue-photo":    "venue-photos",
+		"event-cover":    "event-covers",
+		"event-photo":    "event-photos",
+		"event-poster":   "event-posters",
+		"event-document": "event-documents",
 	}
 	return bucketMap[imageType]
 }
 
-func isValidImageType(filename string) bool {
+// isValidFileName - первичная проверка по расширению; для документов допустим только .pdf
+func isValidFileName(imageType, filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
+	if documentImageTypes[imageType] {
+		return ext == ".pdf"
+	}
 	validExtensions := map[string]bool{
 		".jpg":  true,
 		".jpeg": true,
@@ -183,18 +756,3 @@ func isValidImageType(filename string) bool {
 	}
 	return validExtensions[ext]
 }
-
-func getContentType(filename string) string {
-	ext := strings.ToLower(filepath.Ext(filename))
-	contentTypes := map[string]string{
-		".jpg":  "image/jpeg",
-		".jpeg": "image/jpeg",
-		".png":  "image/png",
-		".gif":  "image/gif",
-		".webp": "image/webp",
-	}
-	if ct, ok := contentTypes[ext]; ok {
-		return ct
-	}
-	return "application/octet-stream"
-}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	similarCacheTTL      = time.Hour
+	similarCandidatePool = 200
+	similarMaxLimit      = 20
+	similarDefaultLimit  = 6
+
+	// Веса сигналов; сумма равна 1
+	similarWeightCategories  = 0.40
+	similarWeightCity        = 0.20
+	similarWeightCapacity    = 0.15
+	similarWeightCoFavorites = 0.25
+
+	// Столько общих «поклонников» дают полный вес со-избранного
+	similarCoFavoritesSaturation = 5
+)
+
+// Коды причин, по которым профиль попал в подборку
+const (
+	SimilarReasonSharedCategories = "shared_categories"
+	SimilarReasonSameCity         = "same_city"
+	SimilarReasonCapacity         = "similar_capacity"
+	SimilarReasonCoFavorites      = "co_favorited"
+)
+
+const (
+	similarKindVenues   = "venues"
+	similarKindCreators = "creators"
+)
+
+type SimilarMatch struct {
+	UserID  int      `json:"user_id"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type SimilarVenue struct {
+	Venue models.Venue
+	Match SimilarMatch
+}
+
+type SimilarCreator struct {
+	Creator models.Creator
+	Match   SimilarMatch
+}
+
+// SimilarService подбирает похожие площадки и создателей для публичных профилей.
+// Ранжированные подборки кэшируются в Redis под ключом с номером поколения:
+// любое изменение профиля или избранного увеличивает поколение, и все подборки
+// этого вида перестают читаться (старые ключи истекают по TTL). Изменения
+// мероприятий в event-service приходят через шину событий (см. consumer).
+type SimilarService struct {
+	repo        repository.UserRepositoryInterface
+	redisClient *redis.Client
+}
+
+func NewSimilarService(repo repository.UserRepositoryInterface, redisClient *redis.Client) *SimilarService {
+	return &SimilarService{repo: repo, redisClient: redisClient}
+}
+
+func (s *SimilarService) SimilarVenues(userID, limit int) ([]SimilarVenue, error) {
+	if _, err := s.repo.GetVenueByUserID(userID); err != nil {
+		return nil, ErrVenueNotFound
+	}
+
+	matches, err := s.matches(similarKindVenues, userID, s.repo.FindSimilarVenueCandidates)
+	if err != nil {
+		return nil, err
+	}
+	matches = truncateMatches(matches, limit)
+
+	venues, err := s.repo.GetVenuesByUserIDs(matchUserIDs(matches))
+	if err != nil {
+		return nil, err
+	}
+	byUserID := make(map[int]models.Venue, len(venues))
+	for _, v := range venues {
+		byUserID[v.UserID] = v
+	}
+
+	// Профиль мог быть удален после построения подборки — пропускаем его
+	result := make([]SimilarVenue, 0, len(matches))
+	for _, m := range matches {
+		if v, ok := byUserID[m.UserID]; ok {
+			result = append(result, SimilarVenue{Venue: v, Match: m})
+		}
+	}
+	return result, nil
+}
+
+func (s *SimilarService) SimilarCreators(userID, limit int) ([]SimilarCreator, error) {
+	if _, err := s.repo.GetCreatorByUserID(userID); err != nil {
+		return nil, ErrCreatorNotFound
+	}
+
+	matches, err := s.matches(similarKindCreators, userID, s.repo.FindSimilarCreatorCandidates)
+	if err != nil {
+		return nil, err
+	}
+	matches = truncateMatches(matches, limit)
+
+	creators, err := s.repo.GetCreatorsByUserIDs(matchUserIDs(matches))
+	if err != nil {
+		return nil, err
+	}
+	byUserID := make(map[int]models.Creator, len(creators))
+	for _, c := range creators {
+		byUserID[c.UserID] = c
+	}
+
+	result := make([]SimilarCreator, 0, len(matches))
+	for _, m := range matches {
+		if c, ok := byUserID[m.UserID]; ok {
+			result = append(result, SimilarCreator{Creator: c, Match: m})
+		}
+	}
+	return result, nil
+}
+
+// InvalidateVenues сбрасывает подборки похожих площадок. Безопасен для nil.
+func (s *SimilarService) InvalidateVenues() {
+	s.invalidate(similarKindVenues)
+}
+
+// InvalidateCreators сбрасывает подборки похожих создателей. Безопасен для nil.
+func (s *SimilarService) InvalidateCreators() {
+	s.invalidate(similarKindCreators)
+}
+
+func (s *SimilarService) invalidate(kind string) {
+	if s == nil || s.redisClient == nil {
+		return
+	}
+	if err := s.redisClient.Incr(context.Background(), generationKey(kind)).Err(); err != nil {
+		log.Printf("similar: failed to invalidate %s cache: %v", kind, err)
+	}
+}
+
+// matches возвращает полную (до similarMaxLimit) ранжированную подборку из кэша
+// или строит ее заново. Ошибки Redis не ломают выдачу — подборка просто считается.
+func (s *SimilarService) matches(kind string, userID int, find func(userID, limit int) ([]models.SimilarityCandidate, error)) ([]SimilarMatch, error) {
+	ctx := context.Background()
+
+	key, err := s.cacheKey(ctx, kind, userID)
+	if err != nil {
+		log.Printf("similar: failed to read %s cache generation: %v", kind, err)
+	} else if matches, ok := s.readCache(ctx, key); ok {
+		return matches, nil
+	}
+
+	candidates, err := find(userID, similarCandidatePool)
+	if err != nil {
+		return nil, err
+	}
+	matches := RankSimilar(candidates, similarMaxLimit)
+
+	if key != "" {
+		if data, err := json.Marshal(matches); err == nil {
+			if err := s.redisClient.Set(ctx, key, data, similarCacheTTL).Err(); err != nil {
+				log.Printf("similar: failed to cache %s: %v", key, err)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func (s *SimilarService) cacheKey(ctx context.Context, kind string, userID int) (string, error) {
+	if s.redisClient == nil {
+		return "", errors.New("redis is not configured")
+	}
+	generation, err := s.redisClient.Get(ctx, generationKey(kind)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	return fmt.Sprintf("similar:%s:%d:%d", kind, generation, userID), nil
+}
+
+func (s *SimilarService) readCache(ctx context.Context, key string) ([]SimilarMatch, bool) {
+	data, err := s.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("similar: failed to read %s: %v", key, err)
+		}
+		return nil, false
+	}
+	var matches []SimilarMatch
+	if err := json.Unmarshal(data, &matches); err != nil {
+		return nil, false
+	}
+	return matches, true
+}
+
+func generationKey(kind string) string {
+	return "similar:" + kind + ":generation"
+}
+
+// RankSimilar оценивает кандидатов взвешенной суммой сигналов и возвращает
+// не более limit лучших. Кандидаты с нулевой оценкой отбрасываются.
+func RankSimilar(candidates []models.SimilarityCandidate, limit int) []SimilarMatch {
+	matches := make([]SimilarMatch, 0, len(candidates))
+	for _, c := range candidates {
+		var score float64
+		reasons := []string{}
+
+		if c.SharedCategories > 0 && c.UnionCategories > 0 {
+			score += similarWeightCategories * float64(c.SharedCategories) / float64(c.UnionCategories)
+			reasons = append(reasons, SimilarReasonSharedCategories)
+		}
+		if c.SameCity {
+			score += similarWeightCity
+			reasons = append(reasons, SimilarReasonSameCity)
+		}
+		if c.CapacityFit {
+			score += similarWeightCapacity
+			reasons = append(reasons, SimilarReasonCapacity)
+		}
+		if c.CoFavorites > 0 {
+			saturation := math.Min(float64(c.CoFavorites), similarCoFavoritesSaturation)
+			score += similarWeightCoFavorites * saturation / similarCoFavoritesSaturation
+			reasons = append(reasons, SimilarReasonCoFavorites)
+		}
+
+		if score == 0 {
+			continue
+		}
+		matches = append(matches, SimilarMatch{
+			UserID:  c.UserID,
+			Score:   math.Round(score*1000) / 1000,
+			Reasons: reasons,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].UserID < matches[j].UserID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func truncateMatches(matches []SimilarMatch, limit int) []SimilarMatch {
+	if limit <= 0 {
+		limit = similarDefaultLimit
+	}
+	if limit > similarMaxLimit {
+		limit = similarMaxLimit
+	}
+	if len(matches) > limit {
+		return matches[:limit]
+	}
+	return matches
+}
+
+func matchUserIDs(matches []SimilarMatch) []int {
+	ids := make([]int, len(matches))
+	for i, m := range matches {
+		ids[i] = m.UserID
+	}
+	return ids
+}
@@ -1,15 +1,26 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"shared/pagination"
+	"user-service/internal/clients"
 	"user-service/internal/config"
 	"user-service/internal/models"
 	"user-service/internal/repository"
+
+	"gorm.io/gorm"
 )
 
+// DefaultListLimit — размер страницы списков профилей по умолчанию
+const DefaultListLimit = 20
+
 type UserService struct {
-	repo repository.UserRepositoryInterface
-	cfg  *config.Config
+	repo    repository.UserRepositoryInterface
+	cfg     *config.Config
+	similar *SimilarService
+	ratings clients.ApplicationClient
 }
 
 func NewUserService(repo repository.UserRepositoryInterface, cfg *config.Config) *UserService {
@@ -19,6 +30,16 @@ func NewUserService(repo repository.UserRepositoryInterface, cfg *config.Config)
 	}
 }
 
+// SetSimilarService подключает сброс кэша похожих профилей при изменении профилей
+func (s *UserService) SetSimilarService(similar *SimilarService) {
+	s.similar = similar
+}
+
+// SetRatingClient подключает application-service, который считает оценки по отзывам
+func (s *UserService) SetRatingClient(ratings clients.ApplicationClient) {
+	s.ratings = ratings
+}
+
 // GetMyProfile возвращает профиль текущего пользователя (creator или venue) на основе роли
 func (s *UserService) GetMyProfile(userID int) (map[string]interface{}, error) {
 	// Получаем базовую информацию о пользователе
@@ -91,6 +112,10 @@ func (s *UserService) CreateCreator(userID int, req *CreateCreatorRequest) (*mod
 		return nil, ErrProfileAlreadyExists
 	}
 
+	if err := s.ensureImagesOwned(userID, nil, req.PhotoID); err != nil {
+		return nil, err
+	}
+
 	creator := &models.Creator{
 		UserID:      userID,
 		Name:        req.Name,
@@ -118,7 +143,14 @@ func (s *UserService) GetCreator(id int) (*models.Creator, error) {
 }
 
 func (s *UserService) GetCreatorByUserID(userID int) (*models.Creator, error) {
-	return s.repo.GetCreatorByUserID(userID)
+	creator, err := s.repo.GetCreatorByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCreatorNotFound
+		}
+		return nil, err
+	}
+	return creator, nil
 }
 
 func (s *UserService) UpdateCreator(id, userID int, req *CreateCreatorRequest) (*models.Creator, error) {
@@ -132,6 +164,10 @@ func (s *UserService) UpdateCreator(id, userID int, req *CreateCreatorRequest) (
 		return nil, errors.New("forbidden: not your creator profile")
 	}
 
+	if err := s.ensureImagesOwned(userID, []*string{creator.PhotoID}, req.PhotoID); err != nil {
+		return nil, err
+	}
+
 	// Обновляем поля
 	creator.Name = req.Name
 	creator.Description = req.Description
@@ -148,6 +184,7 @@ func (s *UserService) UpdateCreator(id, userID int, req *CreateCreatorRequest) (
 	if err := s.repo.UpdateCreator(creator); err != nil {
 		return nil, err
 	}
+	s.similar.InvalidateCreators()
 
 	return s.repo.GetCreatorByID(id)
 }
@@ -164,6 +201,10 @@ func (s *UserService) UpdateCreatorByUserID(targetUserID, currentUserID int, req
 		return nil, err
 	}
 
+	if err := s.ensureImagesOwned(currentUserID, []*string{creator.PhotoID}, req.PhotoID); err != nil {
+		return nil, err
+	}
+
 	// Обновляем поля (name только если передан)
 	if req.Name != "" {
 		creator.Name = req.Name
@@ -182,6 +223,7 @@ func (s *UserService) UpdateCreatorByUserID(targetUserID, currentUserID int, req
 	if err := s.repo.UpdateCreator(creator); err != nil {
 		return nil, err
 	}
+	s.similar.InvalidateCreators()
 
 	return s.repo.GetCreatorByUserID(targetUserID)
 }
@@ -196,22 +238,132 @@ func (s *UserService) DeleteCreator(id, userID int) error {
 		return ErrAccessDenied
 	}
 
-	return s.repo.DeleteCreator(id)
+	if err := s.repo.DeleteCreator(id); err != nil {
+		return err
+	}
+	s.similar.InvalidateCreators()
+	return nil
+}
+
+func (s *UserService) ListCreators(page pagination.Params) (pagination.Page[models.Creator], error) {
+	page = page.Normalize(DefaultListLimit)
+	creators, total, err := s.repo.ListCreators(page)
+	if err != nil {
+		return pagination.Page[models.Creator]{}, err
+	}
+	return pagination.NewPage(creators, total, page, creatorCursor), nil
+}
+
+func creatorCursor(c *models.Creator) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+}
+
+// GetRatingSummaries возвращает агрегированные оценки пользователей по отзывам.
+// Отзывы ведет application-service; для пользователей без отзывов возвращается
+// нулевая оценка.
+func (s *UserService) GetRatingSummaries(userIDs []int) (map[int]models.RatingSummary, error) {
+	summaries := make(map[int]models.RatingSummary, len(userIDs))
+	if len(userIDs) == 0 {
+		return summaries, nil
+	}
+	if s.ratings == nil {
+		return nil, fmt.Errorf("%w: rating client is not configured", ErrDependencyUnavailable)
+	}
+
+	ratings, err := s.ratings.GetRatings(context.Background(), userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDependencyUnavailable, err)
+	}
+	for _, r := range ratings {
+		summaries[r.UserID] = models.RatingSummary{Average: r.Average, Count: r.Count}
+	}
+	for _, id := range userIDs {
+		if _, ok := summaries[id]; !ok {
+			summaries[id] = models.RatingSummary{}
+		}
+	}
+	return summaries, nil
+}
+
+// GalleryPhotoInput - фото, добавляемое в галерею профиля
+type GalleryPhotoInput struct {
+	ImageID string `json:"image_id" binding:"required"`
+	Caption string `json:"caption" binding:"omitempty,max=500"`
+	AltText string `json:"alt_text" binding:"omitempty,max=300"`
+}
+
+// UpdateGalleryPhotoRequest - изменение фото галереи; незаданные поля не меняются.
+// is_cover=true делает фото обложкой галереи, снимая флаг с предыдущей.
+type UpdateGalleryPhotoRequest struct {
+	Caption *string `json:"caption" binding:"omitempty,max=500"`
+	AltText *string `json:"alt_text" binding:"omitempty,max=300"`
+	IsCover *bool   `json:"is_cover"`
+}
+
+func (s *UserService) AddCreatorPhoto(userID int, input GalleryPhotoInput) (*models.CreatorPhoto, error) {
+	photos, err := s.AddCreatorPhotos(userID, []GalleryPhotoInput{input})
+	if err != nil {
+		return nil, err
+	}
+	return &photos[0], nil
+}
+
+// AddCreatorPhotos добавляет фото в конец галереи создателя одной операцией:
+// либо все, либо ни одного
+func (s *UserService) AddCreatorPhotos(userID int, inputs []GalleryPhotoInput) ([]models.CreatorPhoto, error) {
+	creator, err := s.repo.GetCreatorByUserID(userID)
+	if err != nil {
+		return nil, ErrCreatorNotFound
+	}
+	if err := s.checkGalleryInputs(userID, inputs); err != nil {
+		return nil, err
+	}
+
+	photos := make([]models.CreatorPhoto, len(inputs))
+	for i, in := range inputs {
+		photos[i] = models.CreatorPhoto{ImageID: in.ImageID, Caption: in.Caption, AltText: in.AltText}
+	}
+	added, err := s.repo.AddCreatorPhotos(creator.ID, photos, s.cfg.GalleryMaxPhotos)
+	if err != nil {
+		return nil, mapGalleryError(err)
+	}
+	return added, nil
 }
 
-func (s *UserService) ListCreators(limit, offset int) ([]models.Creator, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
+func (s *UserService) UpdateCreatorPhoto(userID, photoID int, req *UpdateGalleryPhotoRequest) (*models.CreatorPhoto, error) {
+	creator, err := s.repo.GetCreatorByUserID(userID)
+	if err != nil {
+		return nil, ErrCreatorNotFound
 	}
-	return s.repo.ListCreators(limit, offset)
+
+	photo, err := s.repo.GetCreatorPhoto(photoID)
+	if err != nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.CreatorID != creator.ID {
+		return nil, ErrAccessDenied
+	}
+
+	applyGalleryPhotoUpdate(&photo.Caption, &photo.AltText, &photo.IsCover, req)
+	if err := s.repo.UpdateCreatorPhoto(photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
 }
 
-func (s *UserService) AddCreatorPhoto(userID int, imageID string) (*models.CreatorPhoto, error) {
+// ReorderCreatorPhotos расставляет фото галереи в порядке photoIDs (все фото, каждое один раз)
+func (s *UserService) ReorderCreatorPhotos(userID int, photoIDs []int) ([]models.CreatorPhoto, error) {
 	creator, err := s.repo.GetCreatorByUserID(userID)
 	if err != nil {
 		return nil, ErrCreatorNotFound
 	}
-	return s.repo.AddCreatorPhoto(creator.ID, imageID)
+	if err := s.repo.ReorderCreatorPhotos(creator.ID, photoIDs); err != nil {
+		if errors.Is(err, repository.ErrGalleryMismatch) {
+			return nil, ErrInvalidPhotoOrder
+		}
+		return nil, err
+	}
+	return s.repo.GetCreatorPhotos(creator.ID)
 }
 
 func (s *UserService) DeleteCreatorPhoto(userID, photoID int) error {
@@ -229,7 +381,17 @@ func (s *UserService) DeleteCreatorPhoto(userID, photoID int) error {
 		return ErrAccessDenied
 	}
 
-	return s.repo.DeleteCreatorPhoto(photoID)
+	return mapGalleryError(s.repo.DeleteCreatorPhotos(creator.ID, []int{photoID}))
+}
+
+// DeleteCreatorPhotos удаляет несколько фото галереи: если хоть одно не найдено
+// в галерее создателя, не удаляется ни одно
+func (s *UserService) DeleteCreatorPhotos(userID int, photoIDs []int) error {
+	creator, err := s.repo.GetCreatorByUserID(userID)
+	if err != nil {
+		return ErrCreatorNotFound
+	}
+	return mapGalleryError(s.repo.DeleteCreatorPhotos(creator.ID, photoIDs))
 }
 
 func (s *UserService) DeleteCreatorByUserID(targetUserID, currentUserID int) error {
@@ -243,7 +405,11 @@ func (s *UserService) DeleteCreatorByUserID(targetUserID, currentUserID int) err
 		return err
 	}
 
-	return s.repo.DeleteCreator(creator.ID)
+	if err := s.repo.DeleteCreator(creator.ID); err != nil {
+		return err
+	}
+	s.similar.InvalidateCreators()
+	return nil
 }
 
 // Venue operations
@@ -295,6 +461,10 @@ func (s *UserService) CreateVenue(userID int, req *CreateVenueRequest) (*models.
 		return nil, ErrProfileAlreadyExists
 	}
 
+	if err := s.ensureImagesOwned(userID, nil, req.LogoID, req.CoverPhotoID); err != nil {
+		return nil, err
+	}
+
 	venue := &models.Venue{
 		UserID:        userID,
 		Name:          req.Name,
@@ -325,6 +495,7 @@ func (s *UserService) CreateVenue(userID int, req *CreateVenueRequest) (*models.
 			return nil, err
 		}
 	}
+	s.invalidateSimilarVenues()
 
 	// Получаем venue с категориями
 	result, err := s.repo.GetVenueByID(venue.ID)
@@ -361,6 +532,9 @@ func (s *UserService) GetVenue(id int) (*models.Venue, error) {
 func (s *UserService) GetVenueByUserID(userID int) (*models.Venue, error) {
 	venue, err := s.repo.GetVenueByUserID(userID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVenueNotFound
+		}
 		return nil, err
 	}
 
@@ -374,25 +548,29 @@ func (s *UserService) GetVenueByUserID(userID int) (*models.Venue, error) {
 	return venue, nil
 }
 
-func (s *UserService) ListVenues(limit, offset int) ([]models.Venue, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20 // default
-	}
-	venues, err := s.repo.ListVenues(limit, offset)
+func (s *UserService) ListVenues(page pagination.Params) (pagination.Page[models.Venue], error) {
+	page = page.Normalize(DefaultListLimit)
+	venues, total, err := s.repo.ListVenues(page)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Venue]{}, err
 	}
 
+	result := pagination.NewPage(venues, total, page, venueCursor)
+
 	// Получаем категории для каждой площадки
-	for i := range venues {
-		categoryIDs, err := s.repo.GetVenueCategories(venues[i].ID)
+	for i := range result.Items {
+		categoryIDs, err := s.repo.GetVenueCategories(result.Items[i].ID)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.Venue]{}, err
 		}
-		venues[i].Categories = categoryIDs
+		result.Items[i].Categories = categoryIDs
 	}
 
-	return venues, nil
+	return result, nil
+}
+
+func venueCursor(v *models.Venue) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: v.CreatedAt, ID: v.ID}
 }
 
 func (s *UserService) UpdateVenue(id, userID int, req *CreateVenueRequest) (*models.Venue, error) {
@@ -405,6 +583,10 @@ func (s *UserService) UpdateVenue(id, userID int, req *CreateVenueRequest) (*mod
 		return nil, errors.New("forbidden: not your venue profile")
 	}
 
+	if err := s.ensureImagesOwned(userID, []*string{venue.LogoID, venue.CoverPhotoID}, req.LogoID, req.CoverPhotoID); err != nil {
+		return nil, err
+	}
+
 	// Обновляем поля
 	venue.Name = req.Name
 	venue.Description = req.Description
@@ -433,6 +615,7 @@ func (s *UserService) UpdateVenue(id, userID int, req *CreateVenueRequest) (*mod
 			return nil, err
 		}
 	}
+	s.invalidateSimilarVenues()
 
 	// Получаем обновленную venue с категориями
 	result, err := s.repo.GetVenueByID(id)
@@ -460,6 +643,10 @@ func (s *UserService) UpdateVenueByUserID(targetUserID, currentUserID int, req *
 		return nil, err
 	}
 
+	if err := s.ensureImagesOwned(currentUserID, []*string{venue.LogoID, venue.CoverPhotoID}, req.LogoID, req.CoverPhotoID); err != nil {
+		return nil, err
+	}
+
 	// Обновляем поля (name только если передан)
 	if req.Name != "" {
 		venue.Name = req.Name
@@ -490,6 +677,7 @@ func (s *UserService) UpdateVenueByUserID(targetUserID, currentUserID int, req *
 			return nil, err
 		}
 	}
+	s.invalidateSimilarVenues()
 
 	// Получаем обновленную venue с категориями
 	result, err := s.repo.GetVenueByUserID(targetUserID)
@@ -516,15 +704,77 @@ func (s *UserService) DeleteVenue(id, userID int) error {
 		return ErrAccessDenied
 	}
 
-	return s.repo.DeleteVenue(id)
+	if err := s.repo.DeleteVenue(id); err != nil {
+		return err
+	}
+	s.invalidateSimilarVenues()
+	return nil
+}
+
+func (s *UserService) AddVenuePhoto(userID int, input GalleryPhotoInput) (*models.VenuePhoto, error) {
+	photos, err := s.AddVenuePhotos(userID, []GalleryPhotoInput{input})
+	if err != nil {
+		return nil, err
+	}
+	return &photos[0], nil
+}
+
+// AddVenuePhotos добавляет фото в конец галереи площадки одной операцией:
+// либо все, либо ни одного
+func (s *UserService) AddVenuePhotos(userID int, inputs []GalleryPhotoInput) ([]models.VenuePhoto, error) {
+	venue, err := s.repo.GetVenueByUserID(userID)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+	if err := s.checkGalleryInputs(userID, inputs); err != nil {
+		return nil, err
+	}
+
+	photos := make([]models.VenuePhoto, len(inputs))
+	for i, in := range inputs {
+		photos[i] = models.VenuePhoto{ImageID: in.ImageID, Caption: in.Caption, AltText: in.AltText}
+	}
+	added, err := s.repo.AddVenuePhotos(venue.ID, photos, s.cfg.GalleryMaxPhotos)
+	if err != nil {
+		return nil, mapGalleryError(err)
+	}
+	return added, nil
+}
+
+func (s *UserService) UpdateVenuePhoto(userID, photoID int, req *UpdateGalleryPhotoRequest) (*models.VenuePhoto, error) {
+	venue, err := s.repo.GetVenueByUserID(userID)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+
+	photo, err := s.repo.GetVenuePhoto(photoID)
+	if err != nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.VenueID != venue.ID {
+		return nil, ErrAccessDenied
+	}
+
+	applyGalleryPhotoUpdate(&photo.Caption, &photo.AltText, &photo.IsCover, req)
+	if err := s.repo.UpdateVenuePhoto(photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
 }
 
-func (s *UserService) AddVenuePhoto(userID int, imageID string) (*models.VenuePhoto, error) {
+// ReorderVenuePhotos расставляет фото галереи в порядке photoIDs (все фото, каждое один раз)
+func (s *UserService) ReorderVenuePhotos(userID int, photoIDs []int) ([]models.VenuePhoto, error) {
 	venue, err := s.repo.GetVenueByUserID(userID)
 	if err != nil {
 		return nil, ErrVenueNotFound
 	}
-	return s.repo.AddVenuePhoto(venue.ID, imageID)
+	if err := s.repo.ReorderVenuePhotos(venue.ID, photoIDs); err != nil {
+		if errors.Is(err, repository.ErrGalleryMismatch) {
+			return nil, ErrInvalidPhotoOrder
+		}
+		return nil, err
+	}
+	return s.repo.GetVenuePhotos(venue.ID)
 }
 
 func (s *UserService) DeleteVenuePhoto(userID, photoID int) error {
@@ -542,7 +792,53 @@ func (s *UserService) DeleteVenuePhoto(userID, photoID int) error {
 		return ErrAccessDenied
 	}
 
-	return s.repo.DeleteVenuePhoto(photoID)
+	return mapGalleryError(s.repo.DeleteVenuePhotos(venue.ID, []int{photoID}))
+}
+
+// DeleteVenuePhotos удаляет несколько фото галереи: если хоть одно не найдено
+// в галерее площадки, не удаляется ни одно
+func (s *UserService) DeleteVenuePhotos(userID int, photoIDs []int) error {
+	venue, err := s.repo.GetVenueByUserID(userID)
+	if err != nil {
+		return ErrVenueNotFound
+	}
+	return mapGalleryError(s.repo.DeleteVenuePhotos(venue.ID, photoIDs))
+}
+
+// checkGalleryInputs проверяет, что изображения не повторяются и принадлежат пользователю
+func (s *UserService) checkGalleryInputs(userID int, inputs []GalleryPhotoInput) error {
+	seen := make(map[string]bool, len(inputs))
+	ids := make([]*string, 0, len(inputs))
+	for i := range inputs {
+		if seen[inputs[i].ImageID] {
+			return ErrDuplicatePhoto
+		}
+		seen[inputs[i].ImageID] = true
+		ids = append(ids, &inputs[i].ImageID)
+	}
+	return s.ensureImagesOwned(userID, nil, ids...)
+}
+
+func applyGalleryPhotoUpdate(caption, altText *string, isCover *bool, req *UpdateGalleryPhotoRequest) {
+	if req.Caption != nil {
+		*caption = *req.Caption
+	}
+	if req.AltText != nil {
+		*altText = *req.AltText
+	}
+	if req.IsCover != nil {
+		*isCover = *req.IsCover
+	}
+}
+
+func mapGalleryError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrGalleryFull):
+		return ErrGalleryLimitExceeded
+	case errors.Is(err, repository.ErrGalleryMismatch):
+		return ErrPhotoNotFound
+	}
+	return err
 }
 
 func (s *UserService) DeleteVenueByUserID(targetUserID, currentUserID int) error {
@@ -556,5 +852,58 @@ func (s *UserService) DeleteVenueByUserID(targetUserID, currentUserID int) error
 		return err
 	}
 
-	return s.repo.DeleteVenue(venue.ID)
+	if err := s.repo.DeleteVenue(venue.ID); err != nil {
+		return err
+	}
+	s.invalidateSimilarVenues()
+	return nil
+}
+
+// invalidateSimilarVenues сбрасывает обе подборки: город площадки участвует
+// и в похожести создателей (через их сотрудничества)
+func (s *UserService) invalidateSimilarVenues() {
+	s.similar.InvalidateVenues()
+	s.similar.InvalidateCreators()
+}
+
+// ensureImagesOwned проверяет, что профиль ссылается только на изображения пользователя.
+// Значения из current (то, что уже сохранено в профиле) не перепроверяются — так
+// повторная отправка формы не ломается на изображениях, загруженных до учета владельцев.
+func (s *UserService) ensureImagesOwned(userID int, current []*string, requested ...*string) error {
+	unchanged := make(map[string]bool, len(current))
+	for _, id := range current {
+		if id != nil {
+			unchanged[*id] = true
+		}
+	}
+
+	var ids []string
+	for _, id := range requested {
+		if id != nil && !unchanged[*id] {
+			ids = append(ids, *id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	images, err := s.repo.GetImagesByIDs(ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]models.Image, len(images))
+	for _, img := range images {
+		byID[img.ID] = img
+	}
+
+	for _, id := range ids {
+		img, ok := byID[id]
+		if !ok || img.Status == models.ImageStatusRejected {
+			return ErrImageNotFound
+		}
+		if img.OwnerUserID == nil || *img.OwnerUserID != userID {
+			return ErrImageNotOwned
+		}
+	}
+	return nil
 }
@@ -10,10 +10,15 @@ import (
 	"syscall"
 	"time"
 
+	"shared/eventbus"
+	"shared/svcclient"
+	"user-service/internal/clients"
 	"user-service/internal/config"
+	"user-service/internal/consumer"
 	"user-service/internal/handlers"
 	"user-service/internal/middleware"
 	"user-service/internal/repository"
+	"user-service/internal/scanner"
 	"user-service/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -91,14 +96,40 @@ func main() {
 	// Инициализация слоев приложения
 	userRepo := repository.NewUserRepository(db)
 	userService := service.NewUserService(userRepo, cfg)
+	userService.SetRatingClient(clients.NewApplicationClient(cfg.ApplicationServiceURL, svcclient.DefaultOptions()))
+
+	// Загрузки проверяются clamd, если он настроен; иначе — локальным сигнатурным сканером
+	var uploadScanner scanner.Scanner
+	if cfg.ClamdAddr != "" {
+		uploadScanner = scanner.NewClamdScanner(cfg.ClamdAddr)
+	} else {
+		log.Println("CLAMD_ADDR is not set, uploads are checked by the local signature scanner only")
+		uploadScanner = scanner.NewSignatureScanner(nil)
+	}
 
 	// Инициализация ImageService
-	imageService, err := service.NewImageService(userRepo, cfg)
+	imageService, err := service.NewImageService(userRepo, uploadScanner, cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize image service: %v", err)
 	}
+	go imageService.RunQuarantineWorker(context.Background())
+	go imageService.RunGarbageCollector(context.Background())
 
-	userHandler := handlers.NewUserHandler(userService, imageService)
+	similarService := service.NewSimilarService(userRepo, redisClient)
+	userService.SetSimilarService(similarService)
+
+	// Похожие создатели считаются и по данным event-service (категории мероприятий,
+	// избранное площадок), поэтому кэш сбрасывается по его доменным событиям
+	hostname, _ := os.Hostname()
+	eventBus := eventbus.NewRedisStreams(redisClient, eventbus.DefaultStream, hostname)
+	domainConsumer := consumer.NewDomainEventConsumer(similarService)
+	go func() {
+		if err := eventBus.Subscribe(context.Background(), consumer.Group, domainConsumer.Handle); err != nil {
+			log.Printf("Domain event consumer stopped: %v", err)
+		}
+	}()
+
+	userHandler := handlers.NewUserHandler(userService, imageService, similarService)
 
 	authService := service.NewAuthService(userRepo, cfg, redisClient)
 	authHandler := handlers.NewAuthHandler(authService)
@@ -107,6 +138,7 @@ func main() {
 	newsletterHandler := handlers.NewNewsletterHandler(newsletterService)
 
 	favoritesService := service.NewFavoritesService(userRepo)
+	favoritesService.SetSimilarService(similarService)
 	favoritesHandler := handlers.NewFavoritesHandler(favoritesService)
 
 	// Настройка роутера
@@ -159,6 +191,10 @@ func main() {
 		users.PUT("/creators/:user_id", userHandler.UpdateCreator)
 		users.DELETE("/creators/:user_id", userHandler.DeleteCreator)
 		users.POST("/creators/photos", userHandler.AddCreatorPhoto)
+		users.POST("/creators/photos/batch", userHandler.AddCreatorPhotos)
+		users.POST("/creators/photos/batch-delete", userHandler.DeleteCreatorPhotos)
+		users.PUT("/creators/photos/order", userHandler.ReorderCreatorPhotos)
+		users.PATCH("/creators/photos/:photo_id", userHandler.UpdateCreatorPhoto)
 		users.DELETE("/creators/photos/:photo_id", userHandler.DeleteCreatorPhoto)
 
 		// Профили площадок (venues) - создаются через /auth/register/venue
@@ -167,11 +203,21 @@ func main() {
 		users.PUT("/venues/:user_id", userHandler.UpdateVenue)
 		users.DELETE("/venues/:user_id", userHandler.DeleteVenue)
 		users.POST("/venues/photos", userHandler.AddVenuePhoto)
+		users.POST("/venues/photos/batch", userHandler.AddVenuePhotos)
+		users.POST("/venues/photos/batch-delete", userHandler.DeleteVenuePhotos)
+		users.PUT("/venues/photos/order", userHandler.ReorderVenuePhotos)
+		users.PATCH("/venues/photos/:photo_id", userHandler.UpdateVenuePhoto)
 		users.DELETE("/venues/photos/:photo_id", userHandler.DeleteVenuePhoto)
 
 		// Загрузка изображений
 		users.POST("/upload", userHandler.UploadImage)
+		users.POST("/uploads", userHandler.CreateDirectUpload)
+		users.GET("/uploads/:id", userHandler.GetDirectUpload)
+		users.POST("/uploads/:id/complete", userHandler.CompleteDirectUpload)
+		users.DELETE("/uploads/:id", userHandler.AbortDirectUpload)
+		users.GET("/storage", userHandler.GetStorageUsage)
 		users.GET("/images/:id", userHandler.GetImage)
+		users.GET("/images/:id/url", userHandler.GetImageURL)
 	}
 
 	// Public routes (без авторизации, без личных контактов)
@@ -179,13 +225,23 @@ func main() {
 	{
 		public.GET("/creators", userHandler.ListPublicCreators)
 		public.GET("/creators/:user_id", userHandler.GetPublicCreator)
+		public.GET("/creators/:user_id/similar", userHandler.GetSimilarCreators)
 		public.GET("/venues", userHandler.ListPublicVenues)
 		public.GET("/venues/:user_id", userHandler.GetPublicVenue)
+		public.GET("/venues/:user_id/similar", userHandler.GetSimilarVenues)
+	}
+
+	// Внутренние ручки для других сервисов. Gateway их не пропускает: в политике
+	// доступа нет правила для /api/user/internal/**, а по умолчанию запрос запрещен.
+	internalAPI := r.Group("/internal")
+	{
+		internalAPI.GET("/images", userHandler.GetImageRefs)
 	}
 
 	// Favorites routes (creator → venues)
 	favorites := r.Group("/users/me/favorites")
-	favorites.Use(middleware.ExtractUserContext(), middleware.RequireRole("creator"))
+	// Роль creator проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	favorites.Use(middleware.ExtractUserContext())
 	{
 		favorites.GET("/venues", favoritesHandler.ListFavoriteVenues)
 		favorites.PUT("/venues/:user_id", favoritesHandler.AddFavoriteVenue)
@@ -200,7 +256,8 @@ func main() {
 	}
 
 	newsletterAdmin := r.Group("/newsletter")
-	newsletterAdmin.Use(middleware.ExtractUserContext(), middleware.RequireRole("admin"))
+	// Роль admin проверяет политика доступа gateway (gateway/internal/routing/routes.json)
+	newsletterAdmin.Use(middleware.ExtractUserContext())
 	{
 		newsletterAdmin.GET("/subscribers", newsletterHandler.ListSubscriptions)
 	}
@@ -3,15 +3,30 @@
 package integration
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"shared/pagination"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 	"user-service/internal/config"
+	"user-service/internal/handlers"
+	"user-service/internal/models"
 	"user-service/internal/repository"
+	"user-service/internal/scanner"
 	"user-service/internal/service"
 
+	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/redis/go-redis/v9"
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
 	"github.com/testcontainers/testcontainers-go"
@@ -27,6 +42,11 @@ var (
 	testCfg *config.Config
 )
 
+const (
+	testMinioAccessKey = "minioadmin"
+	testMinioSecretKey = "minioadmin"
+)
+
 func TestMain(m *testing.M) {
 	ctx := context.Background()
 
@@ -79,9 +99,41 @@ func TestMain(m *testing.M) {
 
 	testRDB = redis.NewClient(&redis.Options{Addr: redisAddr[8:]}) // strip "redis://"
 
+	// MinIO — для отдачи изображений и presigned-ссылок
+	minioContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "minio/minio:latest",
+			Cmd:          []string{"server", "/data"},
+			ExposedPorts: []string{"9000/tcp"},
+			Env: map[string]string{
+				"MINIO_ROOT_USER":     testMinioAccessKey,
+				"MINIO_ROOT_PASSWORD": testMinioSecretKey,
+			},
+			WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		fmt.Printf("failed to start minio: %v\n", err)
+		os.Exit(1)
+	}
+	defer minioContainer.Terminate(ctx)
+
+	minioEndpoint, err := minioContainer.PortEndpoint(ctx, "9000/tcp", "")
+	if err != nil {
+		fmt.Printf("failed to get minio endpoint: %v\n", err)
+		os.Exit(1)
+	}
+
 	testCfg = &config.Config{
-		JWTSecret:      "integration-test-secret-32bytes!",
-		AdminSecretKey: "test-admin-secret",
+		JWTSecret:           "integration-test-secret-32bytes!",
+		AdminSecretKey:      "test-admin-secret",
+		MinioEndpoint:       minioEndpoint,
+		MinioAccessKey:      testMinioAccessKey,
+		MinioSecretKey:      testMinioSecretKey,
+		MinioRegion:         "us-east-1",
+		MinioPublicEndpoint: minioEndpoint,
+		ImageURLTTL:         15 * time.Minute,
 	}
 
 	if err := migrateTestDB(testDB); err != nil {
@@ -110,9 +162,43 @@ func migrateTestDB(db *gorm.DB) error {
 			file_type  VARCHAR(100) NOT NULL,
 			image_type VARCHAR(50)  NOT NULL,
 			bucket_name VARCHAR(100) NOT NULL,
+			status     VARCHAR(20)  NOT NULL DEFAULT 'ready',
+			width      INT,
+			height     INT,
+			scan_signature    VARCHAR(255),
+			scanned_at        TIMESTAMPTZ,
+			scan_attempted_at TIMESTAMPTZ,
+			owner_user_id INT REFERENCES users(id) ON DELETE SET NULL,
+			size       BIGINT       NOT NULL DEFAULT 0,
+			unreferenced_since TIMESTAMPTZ,
 			created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
 		);
 
+		CREATE TABLE IF NOT EXISTS image_variants (
+			id         SERIAL PRIMARY KEY,
+			image_id   UUID         NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+			width      INT          NOT NULL,
+			height     INT          NOT NULL,
+			format     VARCHAR(10)  NOT NULL,
+			file_path  VARCHAR(500) NOT NULL,
+			size       BIGINT       NOT NULL,
+			created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+			UNIQUE (image_id, format, width)
+		);
+
+		CREATE TABLE IF NOT EXISTS image_uploads (
+			id            UUID PRIMARY KEY,
+			owner_user_id INT          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			image_type    VARCHAR(50)  NOT NULL,
+			file_name     VARCHAR(255) NOT NULL,
+			object_name   VARCHAR(255) NOT NULL,
+			size          BIGINT       NOT NULL,
+			multipart_upload_id VARCHAR(255),
+			part_count    INT          NOT NULL DEFAULT 0,
+			expires_at    TIMESTAMPTZ  NOT NULL,
+			created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+		);
+
 		CREATE TABLE IF NOT EXISTS creators (
 			id           SERIAL PRIMARY KEY,
 			user_id      INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -164,14 +250,28 @@ func migrateTestDB(db *gorm.DB) error {
 		CREATE TABLE IF NOT EXISTS creator_photos (
 			id         SERIAL PRIMARY KEY,
 			creator_id INT    NOT NULL REFERENCES creators(id) ON DELETE CASCADE,
-			image_id   UUID   NOT NULL REFERENCES images(id) ON DELETE CASCADE
+			image_id   UUID   NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+			position   INT    NOT NULL,
+			caption    VARCHAR(500),
+			alt_text   VARCHAR(300),
+			is_cover   BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CONSTRAINT uq_creator_photos_position UNIQUE (creator_id, position) DEFERRABLE INITIALLY DEFERRED
 		);
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_creator_photos_cover ON creator_photos (creator_id) WHERE is_cover;
 
 		CREATE TABLE IF NOT EXISTS venue_photos (
 			id       SERIAL PRIMARY KEY,
 			venue_id INT  NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
-			image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE
+			image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+			position INT  NOT NULL,
+			caption  VARCHAR(500),
+			alt_text VARCHAR(300),
+			is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CONSTRAINT uq_venue_photos_position UNIQUE (venue_id, position) DEFERRABLE INITIALLY DEFERRED
 		);
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_venue_photos_cover ON venue_photos (venue_id) WHERE is_cover;
 
 		CREATE TABLE IF NOT EXISTS creator_favorite_venues (
 			creator_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -179,12 +279,54 @@ func migrateTestDB(db *gorm.DB) error {
 			created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 			PRIMARY KEY (creator_user_id, venue_user_id)
 		);
+
+		CREATE TABLE IF NOT EXISTS venue_categories (
+			venue_id    INT NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
+			category_id INT NOT NULL,
+			PRIMARY KEY (venue_id, category_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS events (
+			id         SERIAL PRIMARY KEY,
+			creator_id INT NOT NULL,
+			title      VARCHAR(255) NOT NULL,
+			cover_photo_id UUID REFERENCES images(id)
+		);
+
+		CREATE TABLE IF NOT EXISTS event_media (
+			id         SERIAL PRIMARY KEY,
+			event_id   INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			image_id   UUID NOT NULL REFERENCES images(id),
+			type       VARCHAR(20) NOT NULL,
+			position   INT NOT NULL,
+			caption    VARCHAR(500),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS event_categories (
+			event_id    INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			category_id INT NOT NULL,
+			PRIMARY KEY (event_id, category_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS collaborations (
+			id              SERIAL PRIMARY KEY,
+			creator_user_id INT NOT NULL,
+			venue_user_id   INT NOT NULL,
+			status          VARCHAR(20) DEFAULT 'pending'
+		);
+
+		CREATE TABLE IF NOT EXISTS venue_favorite_events (
+			venue_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			event_id      INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			PRIMARY KEY (venue_user_id, event_id)
+		);
 	`).Error
 }
 
 func resetDB(t *testing.T) {
 	t.Helper()
-	testDB.Exec("TRUNCATE creator_favorite_venues, newsletter_subscriptions, creators, venues, users RESTART IDENTITY CASCADE")
+	testDB.Exec("TRUNCATE image_uploads, image_variants, images, venue_favorite_events, event_media, event_categories, events, collaborations, venue_categories, creator_favorite_venues, newsletter_subscriptions, creators, venues, users RESTART IDENTITY CASCADE")
 	testRDB.FlushAll(context.Background())
 }
 
@@ -455,3 +597,714 @@ func TestIntegration_FavoriteVenues(t *testing.T) {
 		t.Errorf("expected 0 favorites after remove, got %d", len(venues))
 	}
 }
+
+// ─── Similar profiles ─────────────────────────────────────────────────────────
+
+func TestIntegration_SimilarVenues(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+	city, otherCity := 1, 2
+
+	target, _ := authSvc.RegisterVenue(&service.RegisterVenueRequest{
+		Email: "target@test.com", Password: "pass1234", Name: "Target",
+		CityID: &city, Capacity: 100, CategoryIDs: []int{1, 2},
+	})
+	twin, _ := authSvc.RegisterVenue(&service.RegisterVenueRequest{
+		Email: "twin@test.com", Password: "pass1234", Name: "Twin",
+		CityID: &city, Capacity: 150, CategoryIDs: []int{1, 2},
+	})
+	faraway, _ := authSvc.RegisterVenue(&service.RegisterVenueRequest{
+		Email: "far@test.com", Password: "pass1234", Name: "Far",
+		CityID: &otherCity, Capacity: 1000, CategoryIDs: []int{2, 3},
+	})
+	authSvc.RegisterVenue(&service.RegisterVenueRequest{
+		Email: "unrelated@test.com", Password: "pass1234", Name: "Unrelated",
+		CityID: &otherCity, CategoryIDs: []int{4},
+	})
+	creator, _ := authSvc.RegisterCreator(&service.RegisterCreatorRequest{
+		Email: "creator@test.com", Password: "pass1234", Name: "Creator",
+	})
+
+	repo := repository.NewUserRepository(testDB)
+	similar := service.NewSimilarService(repo, testRDB)
+	favSvc := service.NewFavoritesService(repo)
+	favSvc.SetSimilarService(similar)
+
+	result, err := similar.SimilarVenues(target.User.ID, 10)
+	if err != nil {
+		t.Fatalf("similar venues failed: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 similar venues, got %d", len(result))
+	}
+	if result[0].Venue.UserID != twin.User.ID || result[1].Venue.UserID != faraway.User.ID {
+		t.Errorf("unexpected order: %d, %d", result[0].Venue.UserID, result[1].Venue.UserID)
+	}
+
+	// Со-избранное поднимает оценку, кэш сбрасывается изменением избранного
+	before := result[1].Match.Score
+	favSvc.AddFavoriteVenue(creator.User.ID, target.User.ID)
+	favSvc.AddFavoriteVenue(creator.User.ID, faraway.User.ID)
+
+	result, _ = similar.SimilarVenues(target.User.ID, 10)
+	for _, r := range result {
+		if r.Venue.UserID == faraway.User.ID && r.Match.Score <= before {
+			t.Errorf("expected co-favorite to raise score above %v, got %v", before, r.Match.Score)
+		}
+	}
+}
+
+func TestIntegration_SimilarCreators(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+
+	target, _ := authSvc.RegisterCreator(&service.RegisterCreatorRequest{
+		Email: "target@test.com", Password: "pass1234", Name: "Target",
+	})
+	peer, _ := authSvc.RegisterCreator(&service.RegisterCreatorRequest{
+		Email: "peer@test.com", Password: "pass1234", Name: "Peer",
+	})
+	authSvc.RegisterCreator(&service.RegisterCreatorRequest{
+		Email: "other@test.com", Password: "pass1234", Name: "Other",
+	})
+	venue, _ := authSvc.RegisterVenue(&service.RegisterVenueRequest{
+		Email: "venue@test.com", Password: "pass1234", Name: "Venue",
+	})
+
+	testDB.Exec("INSERT INTO events (id, creator_id, title) VALUES (1, ?, 'a'), (2, ?, 'b')", target.User.ID, peer.User.ID)
+	testDB.Exec("INSERT INTO event_categories (event_id, category_id) VALUES (1, 5), (2, 5)")
+	testDB.Exec("INSERT INTO venue_favorite_events (venue_user_id, event_id) VALUES (?, 1), (?, 2)", venue.User.ID, venue.User.ID)
+
+	similar := service.NewSimilarService(repository.NewUserRepository(testDB), testRDB)
+	result, err := similar.SimilarCreators(target.User.ID, 10)
+	if err != nil {
+		t.Fatalf("similar creators failed: %v", err)
+	}
+	if len(result) != 1 || result[0].Creator.UserID != peer.User.ID {
+		t.Fatalf("expected only peer, got %+v", result)
+	}
+	if len(result[0].Match.Reasons) != 2 {
+		t.Errorf("expected shared categories and co-favorite reasons, got %v", result[0].Match.Reasons)
+	}
+}
+
+// ─── Pagination ───────────────────────────────────────────────────────────────
+
+func TestIntegration_ListCreators_CursorPagination(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+	for i := 0; i < 3; i++ {
+		authSvc.RegisterCreator(&service.RegisterCreatorRequest{
+			Email: fmt.Sprintf("c%d@test.com", i), Password: "pass1234", Name: fmt.Sprintf("Creator %d", i),
+		})
+	}
+	// Одинаковый created_at: порядок задает id
+	testDB.Exec("UPDATE creators SET created_at = (SELECT MAX(created_at) FROM creators)")
+
+	svc := service.NewUserService(repository.NewUserRepository(testDB), testCfg)
+
+	var seen []int
+	page := pagination.Params{Limit: 2, WithTotal: true}
+	for {
+		result, err := svc.ListCreators(page)
+		if err != nil {
+			t.Fatalf("list creators failed: %v", err)
+		}
+		if page.WithTotal && (result.Total == nil || *result.Total != 3) {
+			t.Fatalf("expected total 3, got %v", result.Total)
+		}
+		for _, c := range result.Items {
+			seen = append(seen, c.ID)
+		}
+		if result.NextCursor == nil {
+			break
+		}
+		if page.After, err = pagination.Decode(*result.NextCursor); err != nil {
+			t.Fatalf("unexpected cursor error: %v", err)
+		}
+		page.WithTotal = false
+	}
+
+	if fmt.Sprint(seen) != "[3 2 1]" {
+		t.Errorf("expected [3 2 1], got %v", seen)
+	}
+}
+
+func TestIntegration_ImageVariants(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewUserRepository(testDB)
+
+	image := &models.Image{
+		ID: "6f1c1a52-7d7a-4f43-9b0e-0d2f3c1b2a10", FileName: "cover.jpg", FilePath: "abc.jpg",
+		FileType: "image/jpeg", ImageType: "venue-cover", BucketName: "venue-cover-photos",
+		Variants: []models.ImageVariant{
+			{Width: 1280, Height: 720, Format: "webp", FilePath: "abc_w1280.webp", Size: 2048},
+			{Width: 640, Height: 360, Format: "webp", FilePath: "abc_w640.webp", Size: 1024},
+		},
+	}
+	if err := repo.CreateImage(image); err != nil {
+		t.Fatalf("create image failed: %v", err)
+	}
+
+	got, err := repo.GetImageByID(image.ID)
+	if err != nil {
+		t.Fatalf("get image failed: %v", err)
+	}
+	if len(got.Variants) != 2 || got.Variants[0].Width != 640 || got.Variants[1].Width != 1280 {
+		t.Fatalf("expected variants ordered by width, got %+v", got.Variants)
+	}
+
+	if err := repo.DeleteImage(image.ID); err != nil {
+		t.Fatalf("delete image failed: %v", err)
+	}
+	var count int64
+	testDB.Model(&models.ImageVariant{}).Where("image_id = ?", image.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("expected variants to be deleted with the image, got %d", count)
+	}
+}
+
+// ─── Отдача изображений: ETag, Range, presigned-ссылки ───────────────────────
+
+var testImageContent = bytes.Repeat([]byte("0123456789"), 100)
+
+// storeTestImage кладет оригинал в MinIO и создает готовое изображение в БД
+func storeTestImage(t *testing.T, id string) {
+	t.Helper()
+	ctx := context.Background()
+	client, err := minio.New(testCfg.MinioEndpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(testCfg.MinioAccessKey, testCfg.MinioSecretKey, ""),
+	})
+	if err != nil {
+		t.Fatalf("minio client: %v", err)
+	}
+	const bucket = "venue-cover-photos"
+	if exists, _ := client.BucketExists(ctx, bucket); !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			t.Fatalf("make bucket: %v", err)
+		}
+	}
+	objectName := id + ".jpg"
+	if _, err := client.PutObject(ctx, bucket, objectName, bytes.NewReader(testImageContent), int64(len(testImageContent)),
+		minio.PutObjectOptions{ContentType: "image/jpeg"}); err != nil {
+		t.Fatalf("put object: %v", err)
+	}
+
+	repo := repository.NewUserRepository(testDB)
+	if err := repo.CreateImage(&models.Image{
+		ID: id, FileName: "cover.jpg", FilePath: objectName, FileType: "image/jpeg",
+		ImageType: "venue-cover", BucketName: bucket, Status: models.ImageStatusReady,
+		Size: int64(len(testImageContent)),
+	}); err != nil {
+		t.Fatalf("create image failed: %v", err)
+	}
+}
+
+func newImageRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	repo := repository.NewUserRepository(testDB)
+	imageService, err := service.NewImageService(repo, scanner.NewSignatureScanner(nil), testCfg)
+	if err != nil {
+		t.Fatalf("image service: %v", err)
+	}
+	h := handlers.NewUserHandler(service.NewUserService(repo, testCfg), imageService, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/users/images/:id", h.GetImage)
+	r.GET("/users/images/:id/url", h.GetImageURL)
+	r.GET("/internal/images", h.GetImageRefs)
+	return r
+}
+
+func serveImage(r *gin.Engine, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func fetchPresigned(t *testing.T, rawURL string) []byte {
+	t.Helper()
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Fatalf("presigned GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from presigned URL, got %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "image/jpeg" {
+		t.Errorf("expected presigned response Content-Type image/jpeg, got %q", ct)
+	}
+	return body
+}
+
+func TestIntegration_GetImage_ConditionalAndRange(t *testing.T) {
+	resetDB(t)
+	const id = "7a2d3b63-8e8b-4a54-9c1f-1e3a4d2c3b20"
+	storeTestImage(t, id)
+	r := newImageRouter(t)
+
+	w := serveImage(r, "/users/images/"+id, nil)
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), testImageContent) {
+		t.Fatalf("expected 200 with the original, got %d (%d bytes)", w.Code, w.Body.Len())
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+
+	w = serveImage(r, "/users/images/"+id, map[string]string{"If-None-Match": etag})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected 304 without body for matching ETag, got %d (%d bytes)", w.Code, w.Body.Len())
+	}
+
+	w = serveImage(r, "/users/images/"+id, map[string]string{"If-None-Match": `"stale"`})
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 for stale ETag, got %d", w.Code)
+	}
+
+	w = serveImage(r, "/users/images/"+id, map[string]string{"Range": "bytes=100-199"})
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected 206 for Range, got %d", w.Code)
+	}
+	if cr := w.Header().Get("Content-Range"); cr != fmt.Sprintf("bytes 100-199/%d", len(testImageContent)) {
+		t.Errorf("unexpected Content-Range %q", cr)
+	}
+	if !bytes.Equal(w.Body.Bytes(), testImageContent[100:200]) {
+		t.Errorf("unexpected range body %q", w.Body.String())
+	}
+}
+
+func TestIntegration_GetImage_RedirectAndPresign(t *testing.T) {
+	resetDB(t)
+	const id = "7a2d3b63-8e8b-4a54-9c1f-1e3a4d2c3b21"
+	storeTestImage(t, id)
+	r := newImageRouter(t)
+
+	w := serveImage(r, "/users/images/"+id+"?redirect=true", nil)
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected 302 for redirect=true, got %d: %s", w.Code, w.Body.String())
+	}
+	location := w.Header().Get("Location")
+	if !strings.HasPrefix(location, "http://"+testCfg.MinioPublicEndpoint+"/") || !strings.Contains(location, "X-Amz-Signature=") {
+		t.Fatalf("expected presigned MinIO URL, got %q", location)
+	}
+	if cc := w.Header().Get("Cache-Control"); !strings.HasPrefix(cc, "private, max-age=") {
+		t.Errorf("unexpected Cache-Control %q", cc)
+	}
+	if body := fetchPresigned(t, location); !bytes.Equal(body, testImageContent) {
+		t.Errorf("presigned redirect returned %d bytes, expected the original", len(body))
+	}
+
+	w = serveImage(r, "/users/images/"+id+"/url", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /url, got %d: %s", w.Code, w.Body.String())
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "no-store" {
+		t.Errorf("expected Cache-Control no-store, got %q", cc)
+	}
+	var presigned service.PresignedImage
+	if err := json.Unmarshal(w.Body.Bytes(), &presigned); err != nil {
+		t.Fatalf("decode presign response: %v", err)
+	}
+	if until := time.Until(presigned.ExpiresAt); until <= 0 || until > testCfg.ImageURLTTL {
+		t.Errorf("expected expires_at within the URL TTL, got %v", presigned.ExpiresAt)
+	}
+	if body := fetchPresigned(t, presigned.URL); !bytes.Equal(body, testImageContent) {
+		t.Errorf("presigned URL returned %d bytes, expected the original", len(body))
+	}
+
+	w = serveImage(r, "/users/images/00000000-0000-4000-8000-000000000000/url", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown image, got %d", w.Code)
+	}
+}
+
+func TestIntegration_InternalImageRefs(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewUserRepository(testDB)
+	owner := 7
+	for _, img := range []models.Image{
+		{ID: "5c1e2f40-0000-4000-8000-000000000001", FileName: "rider.pdf", FileType: "application/pdf", Status: models.ImageStatusQuarantined},
+		{ID: "5c1e2f40-0000-4000-8000-000000000002", FileName: "bad.png", FileType: "image/png", Status: models.ImageStatusRejected},
+	} {
+		img.FilePath = img.ID
+		img.ImageType = "event-document"
+		img.BucketName = "event-documents"
+		img.OwnerUserID = &owner
+		if err := repo.CreateImage(&img); err != nil {
+			t.Fatalf("create image failed: %v", err)
+		}
+	}
+	r := newImageRouter(t)
+
+	w := serveImage(r, "/internal/images?ids=5c1e2f40-0000-4000-8000-000000000001,5c1e2f40-0000-4000-8000-000000000002,5c1e2f40-0000-4000-8000-000000000003", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp handlers.ImageRefsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	// Отклоненные и отсутствующие изображения в ответ не попадают
+	if len(resp.Images) != 1 || resp.Images[0].FileType != "application/pdf" || resp.Images[0].FileName != "rider.pdf" ||
+		resp.Images[0].OwnerUserID == nil || *resp.Images[0].OwnerUserID != owner {
+		t.Errorf("expected only the quarantined document, got %+v", resp.Images)
+	}
+
+	for _, target := range []string{"/internal/images", "/internal/images?ids=not-a-uuid"} {
+		if w := serveImage(r, target, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestIntegration_ClaimQuarantinedImages(t *testing.T) {
+	resetDB(t)
+	repo := repository.NewUserRepository(testDB)
+
+	recent := time.Now()
+	for i, img := range []models.Image{
+		{ID: "0b8f7a8e-1c0a-4e1b-9d55-000000000001", Status: models.ImageStatusQuarantined},
+		{ID: "0b8f7a8e-1c0a-4e1b-9d55-000000000002", Status: models.ImageStatusQuarantined, ScanAttemptedAt: &recent},
+		{ID: "0b8f7a8e-1c0a-4e1b-9d55-000000000003", Status: models.ImageStatusReady},
+	} {
+		img.FileName = fmt.Sprintf("f%d.png", i)
+		img.FilePath = img.ID + ".png"
+		img.FileType = "image/png"
+		img.ImageType = "avatar"
+		img.BucketName = "image-quarantine"
+		if err := repo.CreateImage(&img); err != nil {
+			t.Fatalf("create image failed: %v", err)
+		}
+	}
+
+	claimed, err := repo.ClaimQuarantinedImages(time.Now().Add(-time.Minute), 10)
+	if err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != "0b8f7a8e-1c0a-4e1b-9d55-000000000001" {
+		t.Fatalf("expected only the never-scanned quarantined image, got %+v", claimed)
+	}
+
+	// Только что взятое изображение не отдается повторно до истечения интервала
+	again, err := repo.ClaimQuarantinedImages(time.Now().Add(-time.Minute), 10)
+	if err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("expected claimed image to be skipped, got %+v", again)
+	}
+}
+
+func TestIntegration_OrphanImagesAndStorageUsage(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+	if _, err := authSvc.RegisterCreator(&service.RegisterCreatorRequest{Email: "owner@test.com", Password: "pass1234", Name: "Owner"}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	var ownerID int
+	testDB.Raw("SELECT id FROM users WHERE email = 'owner@test.com'").Scan(&ownerID)
+
+	repo := repository.NewUserRepository(testDB)
+	ids := map[string]string{
+		"avatar":   "5a0e1c7e-0000-4000-8000-000000000001",
+		"gallery":  "5a0e1c7e-0000-4000-8000-000000000002",
+		"cover":    "5a0e1c7e-0000-4000-8000-000000000003",
+		"orphan":   "5a0e1c7e-0000-4000-8000-000000000004",
+		"unlinked": "5a0e1c7e-0000-4000-8000-000000000005",
+		"rider":    "5a0e1c7e-0000-4000-8000-000000000006",
+	}
+	for name, id := range ids {
+		img := &models.Image{
+			ID: id, FileName: name + ".png", FilePath: id + ".png", FileType: "image/png",
+			ImageType: "avatar", BucketName: "creator-avatars", OwnerUserID: &ownerID, Size: 1000,
+		}
+		if name == "orphan" {
+			img.Variants = []models.ImageVariant{{Width: 64, Height: 64, Format: "webp", FilePath: id + "_w64.webp", Size: 100}}
+		}
+		if err := repo.CreateImage(img); err != nil {
+			t.Fatalf("create image failed: %v", err)
+		}
+	}
+	// Все изображения загружены давно: льготный срок считается не от загрузки
+	testDB.Exec("UPDATE images SET created_at = NOW() - INTERVAL '2 days'")
+
+	usage, err := repo.GetImageStorageUsage(ownerID)
+	if err != nil {
+		t.Fatalf("usage failed: %v", err)
+	}
+	if usage != 6100 {
+		t.Errorf("expected usage 6100 (6 originals + 1 variant), got %d", usage)
+	}
+
+	creator, _ := repo.GetCreatorByUserID(ownerID)
+	testDB.Exec("UPDATE creators SET photo_id = ? WHERE id = ?", ids["avatar"], creator.ID)
+	if _, err := repo.AddCreatorPhotos(creator.ID, []models.CreatorPhoto{{ImageID: ids["gallery"]}}, 10); err != nil {
+		t.Fatalf("add photo failed: %v", err)
+	}
+	testDB.Exec("INSERT INTO events (creator_id, title, cover_photo_id) VALUES (?, 'Event', ?)", ownerID, ids["cover"])
+	testDB.Exec("INSERT INTO event_media (event_id, image_id, type, position) VALUES (1, ?, 'rider', 0)", ids["rider"])
+
+	// orphan остался без ссылок два дня назад, gallery тогда же был отвязан, но
+	// снова привязан; unlinked сборщик видит без ссылок впервые
+	testDB.Exec("UPDATE images SET unreferenced_since = NOW() - INTERVAL '2 days' WHERE id IN ?", []string{ids["orphan"], ids["gallery"]})
+
+	now := time.Now()
+	if err := repo.MarkUnreferencedImages(now); err != nil {
+		t.Fatalf("mark unreferenced failed: %v", err)
+	}
+	var marked []string
+	testDB.Raw("SELECT id FROM images WHERE unreferenced_since IS NOT NULL ORDER BY id").Scan(&marked)
+	if len(marked) != 2 || marked[0] != ids["orphan"] || marked[1] != ids["unlinked"] {
+		t.Fatalf("expected orphan and unlinked to be marked, got %v", marked)
+	}
+
+	removed, err := repo.DeleteOrphanImages(now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("delete orphans failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0].ID != ids["orphan"] || len(removed[0].Variants) != 1 {
+		t.Fatalf("expected only the long unreferenced image with its variant, got %+v", removed)
+	}
+
+	var left int64
+	testDB.Model(&models.Image{}).Count(&left)
+	if left != 5 {
+		t.Errorf("expected 5 images to remain, got %d", left)
+	}
+}
+
+func TestIntegration_FinalizeAndExpireImageUploads(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+	if _, err := authSvc.RegisterCreator(&service.RegisterCreatorRequest{Email: "uploader@test.com", Password: "pass1234", Name: "Uploader"}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	var ownerID int
+	testDB.Raw("SELECT id FROM users WHERE email = 'uploader@test.com'").Scan(&ownerID)
+
+	repo := repository.NewUserRepository(testDB)
+	active := &models.ImageUpload{
+		ID: "6b1f2d8f-0000-4000-8000-000000000001", OwnerUserID: ownerID, ImageType: "venue-cover",
+		FileName: "cover.jpg", ObjectName: "6b1f2d8f-0000-4000-8000-000000000001", Size: 20 << 20,
+		MultipartUploadID: "upload-1", PartCount: 3, ExpiresAt: time.Now().Add(time.Hour),
+	}
+	expired := &models.ImageUpload{
+		ID: "6b1f2d8f-0000-4000-8000-000000000002", OwnerUserID: ownerID, ImageType: "avatar",
+		FileName: "me.png", ObjectName: "6b1f2d8f-0000-4000-8000-000000000002", Size: 1000,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	for _, u := range []*models.ImageUpload{active, expired} {
+		if err := repo.CreateImageUpload(u, unlimitedQuota); err != nil {
+			t.Fatalf("create upload failed: %v", err)
+		}
+	}
+
+	got, err := repo.GetImageUpload(active.ID)
+	if err != nil || got.MultipartUploadID != "upload-1" || got.PartCount != 3 {
+		t.Fatalf("expected stored multipart upload, got %+v (err %v)", got, err)
+	}
+
+	image := &models.Image{
+		ID: active.ID, FileName: active.FileName, FilePath: active.ObjectName, FileType: "image/jpeg",
+		ImageType: active.ImageType, BucketName: "image-quarantine", Status: models.ImageStatusQuarantined,
+		OwnerUserID: &ownerID, Size: active.Size,
+	}
+	if err := repo.FinalizeImageUpload(active.ID, image, unlimitedQuota); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if _, err := repo.GetImageByID(active.ID); err != nil {
+		t.Errorf("expected image with upload ID to exist: %v", err)
+	}
+	// Повторный finalize не должен создать второе изображение
+	if err := repo.FinalizeImageUpload(active.ID, image, unlimitedQuota); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound on second finalize, got %v", err)
+	}
+
+	removed, err := repo.DeleteExpiredImageUploads(time.Now(), 10)
+	if err != nil {
+		t.Fatalf("delete expired failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0].ID != expired.ID || removed[0].ObjectName != expired.ObjectName {
+		t.Fatalf("expected only the expired upload, got %+v", removed)
+	}
+	if _, err := repo.GetImageUpload(expired.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected expired upload to be gone, got %v", err)
+	}
+}
+
+// unlimitedQuota — квота, в которую заведомо помещаются файлы тестов
+const unlimitedQuota = 1 << 40
+
+func TestIntegration_ImageQuotaIsAtomic(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+	if _, err := authSvc.RegisterCreator(&service.RegisterCreatorRequest{Email: "quota@test.com", Password: "pass1234", Name: "Quota"}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	var ownerID int
+	testDB.Raw("SELECT id FROM users WHERE email = 'quota@test.com'").Scan(&ownerID)
+
+	repo := repository.NewUserRepository(testDB)
+	const quota = 3000
+
+	// Параллельные загрузки и начатые прямые загрузки по 1000 байт: в квоту влезают ровно три
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("7c2e3a9f-0000-4000-8000-%012d", i+1)
+			if i%2 == 0 {
+				errs <- repo.CreateImageWithinQuota(&models.Image{
+					ID: id, FileName: "a.png", FilePath: id, FileType: "image/png", ImageType: "avatar",
+					BucketName: "image-quarantine", Status: models.ImageStatusQuarantined, OwnerUserID: &ownerID, Size: 1000,
+				}, quota)
+				return
+			}
+			errs <- repo.CreateImageUpload(&models.ImageUpload{
+				ID: id, OwnerUserID: ownerID, ImageType: "avatar", FileName: "a.png", ObjectName: id,
+				Size: 1000, ExpiresAt: time.Now().Add(time.Hour),
+			}, quota)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, repository.ErrQuotaExceeded):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if created != 3 {
+		t.Errorf("expected exactly 3 files to fit into the quota, got %d", created)
+	}
+
+	// Finalize заменяет резерв загрузки изображением и не считает его дважды
+	var uploadID string
+	testDB.Raw("SELECT id FROM image_uploads WHERE owner_user_id = ? LIMIT 1", ownerID).Scan(&uploadID)
+	if uploadID != "" {
+		image := &models.Image{
+			ID: uploadID, FileName: "a.png", FilePath: uploadID, FileType: "image/png", ImageType: "avatar",
+			BucketName: "image-quarantine", Status: models.ImageStatusQuarantined, OwnerUserID: &ownerID, Size: 1000,
+		}
+		if err := repo.FinalizeImageUpload(uploadID, image, quota); err != nil {
+			t.Errorf("expected finalize within the reservation to succeed, got %v", err)
+		}
+	}
+
+	// Квоту уменьшили: finalize отказывает и оставляет загрузку для повтора
+	testDB.Exec("DELETE FROM images WHERE owner_user_id = ?", ownerID)
+	testDB.Exec("DELETE FROM image_uploads WHERE owner_user_id = ?", ownerID)
+	upload := &models.ImageUpload{
+		ID: "7c2e3a9f-0000-4000-8000-000000000100", OwnerUserID: ownerID, ImageType: "avatar", FileName: "b.png",
+		ObjectName: "b", Size: 2000, ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := repo.CreateImageUpload(upload, quota); err != nil {
+		t.Fatalf("create upload failed: %v", err)
+	}
+	image := &models.Image{
+		ID: upload.ID, FileName: "b.png", FilePath: "b", FileType: "image/png", ImageType: "avatar",
+		BucketName: "image-quarantine", Status: models.ImageStatusQuarantined, OwnerUserID: &ownerID, Size: 2000,
+	}
+	if err := repo.FinalizeImageUpload(upload.ID, image, 1000); !errors.Is(err, repository.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := repo.GetImageUpload(upload.ID); err != nil {
+		t.Errorf("expected upload to stay after a quota failure: %v", err)
+	}
+}
+
+func TestIntegration_VenueGallery(t *testing.T) {
+	resetDB(t)
+	authSvc := newAuthSvc()
+	if _, err := authSvc.RegisterVenue(&service.RegisterVenueRequest{Email: "gallery@test.com", Password: "pass1234", Name: "Gallery Venue"}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	var ownerID int
+	testDB.Raw("SELECT id FROM users WHERE email = 'gallery@test.com'").Scan(&ownerID)
+
+	repo := repository.NewUserRepository(testDB)
+	venue, err := repo.GetVenueByUserID(ownerID)
+	if err != nil {
+		t.Fatalf("get venue failed: %v", err)
+	}
+
+	var photos []models.VenuePhoto
+	for i := 1; i <= 4; i++ {
+		id := fmt.Sprintf("7c2e3f90-0000-4000-8000-00000000000%d", i)
+		if err := repo.CreateImage(&models.Image{
+			ID: id, FileName: "p.jpg", FilePath: id + ".jpg", FileType: "image/jpeg",
+			ImageType: "venue-photo", BucketName: "venue-photos", OwnerUserID: &ownerID,
+		}); err != nil {
+			t.Fatalf("create image failed: %v", err)
+		}
+		photos = append(photos, models.VenuePhoto{ImageID: id, Caption: fmt.Sprintf("Photo %d", i)})
+	}
+
+	added, err := repo.AddVenuePhotos(venue.ID, photos[:3], 3)
+	if err != nil {
+		t.Fatalf("add photos failed: %v", err)
+	}
+	if len(added) != 3 || added[0].Position != 0 || added[2].Position != 2 || added[1].Image.ID != photos[1].ImageID {
+		t.Fatalf("expected three photos at positions 0..2 with images, got %+v", added)
+	}
+	if _, err := repo.AddVenuePhotos(venue.ID, photos[3:], 3); !errors.Is(err, repository.ErrGalleryFull) {
+		t.Fatalf("expected ErrGalleryFull, got %v", err)
+	}
+
+	// Перестановка: обмен позициями проходит благодаря отложенной проверке уникальности
+	order := []int{added[2].ID, added[0].ID, added[1].ID}
+	if err := repo.ReorderVenuePhotos(venue.ID, order); err != nil {
+		t.Fatalf("reorder failed: %v", err)
+	}
+	if err := repo.ReorderVenuePhotos(venue.ID, order[:2]); !errors.Is(err, repository.ErrGalleryMismatch) {
+		t.Errorf("expected ErrGalleryMismatch for partial order, got %v", err)
+	}
+
+	// Обложка может быть только одна
+	first, second := added[0], added[1]
+	first.IsCover = true
+	if err := repo.UpdateVenuePhoto(&first); err != nil {
+		t.Fatalf("update first failed: %v", err)
+	}
+	second.IsCover = true
+	second.AltText = "Вид со сцены"
+	if err := repo.UpdateVenuePhoto(&second); err != nil {
+		t.Fatalf("update second failed: %v", err)
+	}
+
+	if err := repo.DeleteVenuePhotos(venue.ID, []int{added[2].ID, 999999}); !errors.Is(err, repository.ErrGalleryMismatch) {
+		t.Errorf("expected ErrGalleryMismatch for foreign photo, got %v", err)
+	}
+	if err := repo.DeleteVenuePhotos(venue.ID, []int{added[2].ID}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	loaded, err := repo.GetVenueByUserID(ownerID)
+	if err != nil {
+		t.Fatalf("reload venue failed: %v", err)
+	}
+	if len(loaded.Photos) != 2 {
+		t.Fatalf("expected 2 photos after delete, got %d", len(loaded.Photos))
+	}
+	got := loaded.Photos
+	if got[0].ID != added[0].ID || got[0].Position != 0 || got[1].ID != added[1].ID || got[1].Position != 1 {
+		t.Errorf("expected compacted order [%d %d], got %+v", added[0].ID, added[1].ID, got)
+	}
+	if got[0].IsCover || !got[1].IsCover || got[1].AltText != "Вид со сцены" {
+		t.Errorf("expected only the second photo to be the cover, got %+v", got)
+	}
+}
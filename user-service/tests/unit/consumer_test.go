@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"context"
+	"shared/eventbus"
+	"testing"
+	"user-service/internal/consumer"
+	"user-service/internal/models"
+	"user-service/internal/service"
+)
+
+func TestDomainEventConsumer_InvalidatesSimilarCreators(t *testing.T) {
+	eventTypes := []string{
+		eventbus.EventPublished,
+		eventbus.EventCategoriesChanged,
+		eventbus.EventDeleted,
+		eventbus.VenueFavoriteChanged,
+	}
+	for _, eventType := range eventTypes {
+		t.Run(eventType, func(t *testing.T) {
+			repo := newMockUserRepo()
+			repo.creators[1] = &models.Creator{ID: 1, UserID: 1, Name: "Target"}
+			repo.creators[3] = &models.Creator{ID: 3, UserID: 3, Name: "Peer"}
+			repo.similarCandidates = []models.SimilarityCandidate{{UserID: 3, SharedCategories: 1, UnionCategories: 2}}
+			similar := service.NewSimilarService(repo, newTestRedis(t))
+			c := consumer.NewDomainEventConsumer(similar)
+
+			similar.SimilarCreators(1, 5)
+			if err := c.Handle(context.Background(), eventbus.Event{Type: eventType, AggregateID: 42}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			similar.SimilarCreators(1, 5)
+
+			if repo.similarSearchCalls != 2 {
+				t.Errorf("expected cache to be invalidated by %s, got %d searches", eventType, repo.similarSearchCalls)
+			}
+		})
+	}
+}
+
+func TestDomainEventConsumer_IgnoresUnrelatedEvents(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.creators[1] = &models.Creator{ID: 1, UserID: 1, Name: "Target"}
+	similar := service.NewSimilarService(repo, newTestRedis(t))
+	c := consumer.NewDomainEventConsumer(similar)
+
+	similar.SimilarCreators(1, 5)
+	if err := c.Handle(context.Background(), eventbus.Event{Type: eventbus.ApplicationAccepted}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	similar.SimilarCreators(1, 5)
+
+	if repo.similarSearchCalls != 1 {
+		t.Errorf("expected cache to survive unrelated event, got %d searches", repo.similarSearchCalls)
+	}
+}
@@ -0,0 +1,26 @@
+package unit
+
+import (
+	"testing"
+	"user-service/internal/service"
+)
+
+func TestDirectUploadPartCount(t *testing.T) {
+	const part = service.DirectUploadPartSize
+
+	cases := []struct {
+		size int64
+		want int
+	}{
+		{size: 1, want: 0},
+		{size: part, want: 0}, // ровно одна часть — обычный POST
+		{size: part + 1, want: 2},
+		{size: 3 * part, want: 3},
+		{size: 50 * 1024 * 1024, want: 7},
+	}
+	for _, tc := range cases {
+		if got := service.DirectUploadPartCount(tc.size); got != tc.want {
+			t.Errorf("size=%d: expected %d parts, got %d", tc.size, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"user-service/internal/service"
+)
+
+func newGallerySvc(t *testing.T, maxPhotos int) (*service.UserService, *mockUserRepo) {
+	t.Helper()
+	repo := newMockUserRepo()
+	repo.venues[1] = newVenue(1, 1, "Owner")
+	repo.venues[2] = newVenue(2, 2, "Other")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		repo.images[id] = ownedImage(id, 1)
+	}
+	cfg := newTestConfig()
+	cfg.GalleryMaxPhotos = maxPhotos
+	return service.NewUserService(repo, cfg), repo
+}
+
+// ─── UserService: venue gallery ──────────────────────────────────────────────
+
+func TestAddVenuePhotos_AppendsInOrderWithinLimit(t *testing.T) {
+	svc, _ := newGallerySvc(t, 3)
+
+	added, err := svc.AddVenuePhotos(1, []service.GalleryPhotoInput{
+		{ImageID: "a", Caption: "Зал"},
+		{ImageID: "b", AltText: "Сцена"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(added) != 2 || added[0].Position != 0 || added[1].Position != 1 || added[0].Caption != "Зал" {
+		t.Fatalf("unexpected photos: %+v", added)
+	}
+
+	_, err = svc.AddVenuePhotos(1, []service.GalleryPhotoInput{{ImageID: "c"}, {ImageID: "d"}})
+	if !errors.Is(err, service.ErrGalleryLimitExceeded) {
+		t.Errorf("expected ErrGalleryLimitExceeded, got %v", err)
+	}
+}
+
+func TestAddVenuePhotos_RejectsDuplicates(t *testing.T) {
+	svc, repo := newGallerySvc(t, 10)
+
+	_, err := svc.AddVenuePhotos(1, []service.GalleryPhotoInput{{ImageID: "a"}, {ImageID: "a"}})
+	if !errors.Is(err, service.ErrDuplicatePhoto) {
+		t.Errorf("expected ErrDuplicatePhoto, got %v", err)
+	}
+	if len(repo.venuePhotos[1]) != 0 {
+		t.Errorf("expected nothing to be added, got %+v", repo.venuePhotos[1])
+	}
+}
+
+func TestReorderVenuePhotos(t *testing.T) {
+	svc, _ := newGallerySvc(t, 10)
+	added, _ := svc.AddVenuePhotos(1, []service.GalleryPhotoInput{{ImageID: "a"}, {ImageID: "b"}, {ImageID: "c"}})
+
+	photos, err := svc.ReorderVenuePhotos(1, []int{added[2].ID, added[0].ID, added[1].ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if photos[0].ImageID != "c" || photos[1].ImageID != "a" || photos[2].Position != 2 {
+		t.Errorf("unexpected order: %+v", photos)
+	}
+
+	if _, err := svc.ReorderVenuePhotos(1, []int{added[0].ID, added[1].ID}); !errors.Is(err, service.ErrInvalidPhotoOrder) {
+		t.Errorf("expected ErrInvalidPhotoOrder for partial list, got %v", err)
+	}
+}
+
+func TestUpdateVenuePhoto_CoverAndOwnership(t *testing.T) {
+	svc, repo := newGallerySvc(t, 10)
+	added, _ := svc.AddVenuePhotos(1, []service.GalleryPhotoInput{{ImageID: "a"}, {ImageID: "b"}})
+
+	cover := true
+	if _, err := svc.UpdateVenuePhoto(1, added[0].ID, &service.UpdateGalleryPhotoRequest{IsCover: &cover}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	caption := "Терраса"
+	updated, err := svc.UpdateVenuePhoto(1, added[1].ID, &service.UpdateGalleryPhotoRequest{Caption: &caption, IsCover: &cover})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Caption != "Терраса" || !updated.IsCover {
+		t.Errorf("unexpected photo: %+v", updated)
+	}
+	if gallery := repo.venuePhotos[1]; gallery[0].IsCover || !gallery[1].IsCover {
+		t.Errorf("expected the cover to move to the second photo, got %+v", gallery)
+	}
+
+	if _, err := svc.UpdateVenuePhoto(2, added[0].ID, &service.UpdateGalleryPhotoRequest{Caption: &caption}); !errors.Is(err, service.ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied for foreign photo, got %v", err)
+	}
+}
+
+func TestDeleteVenuePhotos_AllOrNothing(t *testing.T) {
+	svc, repo := newGallerySvc(t, 10)
+	added, _ := svc.AddVenuePhotos(1, []service.GalleryPhotoInput{{ImageID: "a"}, {ImageID: "b"}, {ImageID: "c"}})
+
+	if err := svc.DeleteVenuePhotos(1, []int{added[0].ID, 999}); !errors.Is(err, service.ErrPhotoNotFound) {
+		t.Errorf("expected ErrPhotoNotFound, got %v", err)
+	}
+	if err := svc.DeleteVenuePhotos(1, []int{added[0].ID}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	gallery := repo.venuePhotos[1]
+	if len(gallery) != 2 || gallery[0].ImageID != "b" || gallery[0].Position != 0 {
+		t.Errorf("expected compacted gallery, got %+v", gallery)
+	}
+}
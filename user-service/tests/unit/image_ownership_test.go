@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"user-service/internal/models"
+	"user-service/internal/service"
+)
+
+func ownedImage(id string, ownerUserID int) *models.Image {
+	return &models.Image{ID: id, OwnerUserID: &ownerUserID, Status: models.ImageStatusReady}
+}
+
+// ─── UserService: image ownership ────────────────────────────────────────────
+
+func TestUpdateCreatorByUserID_OwnImage(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.creators[1] = &models.Creator{ID: 1, UserID: 1, Name: "Test"}
+	repo.images["img-1"] = ownedImage("img-1", 1)
+	svc := service.NewUserService(repo, newTestConfig())
+
+	photoID := "img-1"
+	updated, err := svc.UpdateCreatorByUserID(1, 1, &service.UpdateCreatorRequest{PhotoID: &photoID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.PhotoID == nil || *updated.PhotoID != "img-1" {
+		t.Errorf("expected photo to be set, got %v", updated.PhotoID)
+	}
+}
+
+func TestUpdateCreatorByUserID_ForeignImage(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.creators[1] = &models.Creator{ID: 1, UserID: 1, Name: "Test"}
+	repo.images["img-2"] = ownedImage("img-2", 2)
+	svc := service.NewUserService(repo, newTestConfig())
+
+	photoID := "img-2"
+	_, err := svc.UpdateCreatorByUserID(1, 1, &service.UpdateCreatorRequest{PhotoID: &photoID})
+	if !errors.Is(err, service.ErrImageNotOwned) {
+		t.Errorf("expected ErrImageNotOwned, got %v", err)
+	}
+}
+
+func TestUpdateCreatorByUserID_KeepsLegacyImage(t *testing.T) {
+	// Изображение загружено до учета владельцев и уже стоит в профиле
+	legacy := "legacy"
+	repo := newMockUserRepo()
+	repo.creators[1] = &models.Creator{ID: 1, UserID: 1, Name: "Test", PhotoID: &legacy}
+	repo.images[legacy] = &models.Image{ID: legacy, Status: models.ImageStatusReady}
+	svc := service.NewUserService(repo, newTestConfig())
+
+	photoID := legacy
+	if _, err := svc.UpdateCreatorByUserID(1, 1, &service.UpdateCreatorRequest{Name: "Renamed", PhotoID: &photoID}); err != nil {
+		t.Errorf("expected unchanged legacy photo to be accepted, got %v", err)
+	}
+}
+
+func TestUpdateVenueByUserID_RejectsMissingAndRejectedImages(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.venues[1] = newVenue(1, 1, "Test")
+	rejected := ownedImage("bad", 1)
+	rejected.Status = models.ImageStatusRejected
+	repo.images["bad"] = rejected
+	svc := service.NewUserService(repo, newTestConfig())
+
+	for _, id := range []string{"missing", "bad"} {
+		logoID := id
+		_, err := svc.UpdateVenueByUserID(1, 1, &service.UpdateVenueRequest{Name: "Test", LogoID: &logoID})
+		if !errors.Is(err, service.ErrImageNotFound) {
+			t.Errorf("%s: expected ErrImageNotFound, got %v", id, err)
+		}
+	}
+}
+
+func TestAddVenuePhoto_ForeignImage(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.venues[1] = newVenue(1, 1, "Test")
+	repo.images["img-2"] = ownedImage("img-2", 2)
+	svc := service.NewUserService(repo, newTestConfig())
+
+	if _, err := svc.AddVenuePhoto(1, service.GalleryPhotoInput{ImageID: "img-2"}); !errors.Is(err, service.ErrImageNotOwned) {
+		t.Errorf("expected ErrImageNotOwned, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"user-service/internal/imaging"
+	"user-service/internal/models"
+	"user-service/internal/service"
+
+	"golang.org/x/image/webp"
+)
+
+func newTestImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegWithOrientation кодирует JPEG и вставляет после SOI сегмент APP1 с EXIF-тегом Orientation
+func jpegWithOrientation(t *testing.T, w, h int, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, newTestImage(w, h), nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08") // big-endian, IFD0 по смещению 8
+	entry := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(entry[0:], 1)      // одна запись
+	binary.BigEndian.PutUint16(entry[2:], 0x0112) // Orientation
+	binary.BigEndian.PutUint16(entry[4:], 3)      // SHORT
+	binary.BigEndian.PutUint32(entry[6:], 1)
+	binary.BigEndian.PutUint16(entry[10:], orientation)
+	payload := append([]byte("Exif\x00\x00"), append(tiff, entry...)...)
+
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	app1 = append(app1, payload...)
+
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, app1...), data[2:]...)
+}
+
+// ─── imaging.Process ─────────────────────────────────────────────────────────
+
+func TestProcess_BuildsWebPVariantsWithoutUpscaling(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, newTestImage(200, 100)); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+
+	result, err := imaging.Process(buf.Bytes(), []int{64, 128, 512, 1024})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// 512 и 1024 шире оригинала и схлопываются в один вариант 200px
+	wantWidths := []int{64, 128, 200}
+	if len(result.Variants) != len(wantWidths) {
+		t.Fatalf("expected %d variants, got %+v", len(wantWidths), result.Variants)
+	}
+	for i, v := range result.Variants {
+		if v.Width != wantWidths[i] || v.Height != wantWidths[i]/2 {
+			t.Errorf("variant %d: expected %dx%d, got %dx%d", i, wantWidths[i], wantWidths[i]/2, v.Width, v.Height)
+		}
+		cfg, err := webp.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil {
+			t.Fatalf("variant %d is not a valid webp: %v", i, err)
+		}
+		if cfg.Width != v.Width || cfg.Height != v.Height {
+			t.Errorf("variant %d: encoded size %dx%d mismatch", i, cfg.Width, cfg.Height)
+		}
+	}
+}
+
+func TestProcess_StripsExifAndAppliesOrientation(t *testing.T) {
+	// Orientation=6: снимок сделан «на боку», показывать надо повернутым на 90°
+	data := jpegWithOrientation(t, 40, 20, 6)
+
+	result, err := imaging.Process(data, []int{10})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if bytes.Contains(result.Original, []byte("Exif\x00\x00")) {
+		t.Error("expected EXIF to be stripped from the original")
+	}
+	if result.Width != 20 || result.Height != 40 {
+		t.Errorf("expected rotated 20x40 original, got %dx%d", result.Width, result.Height)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(result.Original))
+	if err != nil {
+		t.Fatalf("original is not a valid jpeg: %v", err)
+	}
+	if cfg.Width != 20 || cfg.Height != 40 {
+		t.Errorf("expected stored original to be 20x40, got %dx%d", cfg.Width, cfg.Height)
+	}
+	if len(result.Variants) != 1 || result.Variants[0].Height != 20 {
+		t.Errorf("expected one 10x20 variant, got %+v", result.Variants)
+	}
+}
+
+func TestProcess_RejectsNonImage(t *testing.T) {
+	if _, err := imaging.Process([]byte("definitely not an image"), []int{64}); err == nil {
+		t.Error("expected error for non-image data")
+	}
+}
+
+// ─── PickImageVariant ────────────────────────────────────────────────────────
+
+func TestPickImageVariant(t *testing.T) {
+	variants := []models.ImageVariant{
+		{Width: 320, Format: "webp", FilePath: "a_w320.webp"},
+		{Width: 1280, Format: "webp", FilePath: "a_w1280.webp"},
+		{Width: 640, Format: "webp", FilePath: "a_w640.webp"},
+	}
+
+	cases := []struct {
+		width int
+		want  int
+	}{
+		{width: 100, want: 320},
+		{width: 320, want: 320},
+		{width: 500, want: 640},
+		{width: 4000, want: 1280}, // шире всех — самый крупный
+		{width: 0, want: 1280},
+	}
+	for _, tc := range cases {
+		v := service.PickImageVariant(variants, "webp", tc.width)
+		if v == nil || v.Width != tc.want {
+			t.Errorf("w=%d: expected %d, got %+v", tc.width, tc.want, v)
+		}
+	}
+
+	if v := service.PickImageVariant(variants, "avif", 320); v != nil {
+		t.Errorf("expected nil for unknown format, got %+v", v)
+	}
+}
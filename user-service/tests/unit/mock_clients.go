@@ -0,0 +1,31 @@
+package unit
+
+import (
+	"context"
+	"user-service/internal/clients"
+)
+
+// mockApplicationClient отдает оценки, как их вернул бы application-service
+type mockApplicationClient struct {
+	ratings map[int]clients.Rating
+	err     error
+	calls   [][]int
+}
+
+func newMockApplicationClient() *mockApplicationClient {
+	return &mockApplicationClient{ratings: make(map[int]clients.Rating)}
+}
+
+func (m *mockApplicationClient) GetRatings(ctx context.Context, userIDs []int) ([]clients.Rating, error) {
+	m.calls = append(m.calls, userIDs)
+	if m.err != nil {
+		return nil, m.err
+	}
+	var ratings []clients.Rating
+	for _, id := range userIDs {
+		if r, ok := m.ratings[id]; ok {
+			ratings = append(ratings, r)
+		}
+	}
+	return ratings, nil
+}
@@ -2,7 +2,11 @@ package unit
 
 import (
 	"errors"
+	"shared/pagination"
+	"sort"
+	"time"
 	"user-service/internal/models"
+	"user-service/internal/repository"
 
 	"gorm.io/gorm"
 )
@@ -15,17 +19,24 @@ type mockUserRepo struct {
 	venues        map[int]*models.Venue   // keyed by userID
 	subscriptions map[string]*models.NewsletterSubscription
 	favorites     map[int][]int // creatorUserID -> []venueUserID
+	images        map[string]*models.Image
+	venuePhotos   map[int][]models.VenuePhoto // keyed by venueID
 	nextUserID    int
 	nextCreatorID int
 	nextVenueID   int
 	nextSubID     int
+	nextPhotoID   int
 
 	errCreateUser    error
 	errGetByEmail    error
 	errCreateCreator error
 	errCreateVenue   error
 	errAddFav        error
+	errGetProfile    error
 	alreadyFaved     bool
+
+	similarCandidates  []models.SimilarityCandidate
+	similarSearchCalls int
 }
 
 func newMockUserRepo() *mockUserRepo {
@@ -35,6 +46,8 @@ func newMockUserRepo() *mockUserRepo {
 		venues:        make(map[int]*models.Venue),
 		subscriptions: make(map[string]*models.NewsletterSubscription),
 		favorites:     make(map[int][]int),
+		images:        make(map[string]*models.Image),
+		venuePhotos:   make(map[int][]models.VenuePhoto),
 		nextUserID:    1,
 		nextCreatorID: 1,
 		nextVenueID:   1,
@@ -97,9 +110,12 @@ func (m *mockUserRepo) GetCreatorByID(id int) (*models.Creator, error) {
 }
 
 func (m *mockUserRepo) GetCreatorByUserID(userID int) (*models.Creator, error) {
+	if m.errGetProfile != nil {
+		return nil, m.errGetProfile
+	}
 	c, ok := m.creators[userID]
 	if !ok {
-		return nil, errNotFound
+		return nil, gorm.ErrRecordNotFound
 	}
 	cp := *c
 	return &cp, nil
@@ -121,23 +137,36 @@ func (m *mockUserRepo) DeleteCreator(id int) error {
 	return nil
 }
 
-func (m *mockUserRepo) ListCreators(limit, offset int) ([]models.Creator, error) {
+func (m *mockUserRepo) ListCreators(page pagination.Params) ([]models.Creator, *int64, error) {
 	var result []models.Creator
 	for _, c := range m.creators {
 		result = append(result, *c)
 	}
-	return result, nil
+	items, total := paginate(result, page, func(c *models.Creator) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+	})
+	return items, total, nil
 }
 
-func (m *mockUserRepo) AddCreatorPhoto(creatorID int, imageID string) (*models.CreatorPhoto, error) {
-	return &models.CreatorPhoto{ID: 1, CreatorID: creatorID, ImageID: imageID}, nil
+func (m *mockUserRepo) AddCreatorPhotos(creatorID int, photos []models.CreatorPhoto, maxPhotos int) ([]models.CreatorPhoto, error) {
+	for i := range photos {
+		photos[i].ID = i + 1
+		photos[i].CreatorID = creatorID
+		photos[i].Position = i
+	}
+	return photos, nil
 }
 
 func (m *mockUserRepo) GetCreatorPhoto(photoID int) (*models.CreatorPhoto, error) {
 	return nil, errNotFound
 }
 
-func (m *mockUserRepo) DeleteCreatorPhoto(photoID int) error { return nil }
+func (m *mockUserRepo) GetCreatorPhotos(creatorID int) ([]models.CreatorPhoto, error) {
+	return nil, nil
+}
+func (m *mockUserRepo) UpdateCreatorPhoto(photo *models.CreatorPhoto) error      { return nil }
+func (m *mockUserRepo) ReorderCreatorPhotos(creatorID int, photoIDs []int) error { return nil }
+func (m *mockUserRepo) DeleteCreatorPhotos(creatorID int, photoIDs []int) error  { return nil }
 
 func (m *mockUserRepo) CreateVenue(venue *models.Venue) error {
 	if m.errCreateVenue != nil {
@@ -161,20 +190,26 @@ func (m *mockUserRepo) GetVenueByID(id int) (*models.Venue, error) {
 }
 
 func (m *mockUserRepo) GetVenueByUserID(userID int) (*models.Venue, error) {
+	if m.errGetProfile != nil {
+		return nil, m.errGetProfile
+	}
 	v, ok := m.venues[userID]
 	if !ok {
-		return nil, errNotFound
+		return nil, gorm.ErrRecordNotFound
 	}
 	cp := *v
 	return &cp, nil
 }
 
-func (m *mockUserRepo) ListVenues(limit, offset int) ([]models.Venue, error) {
+func (m *mockUserRepo) ListVenues(page pagination.Params) ([]models.Venue, *int64, error) {
 	var result []models.Venue
 	for _, v := range m.venues {
 		result = append(result, *v)
 	}
-	return result, nil
+	items, total := paginate(result, page, func(v *models.Venue) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: v.CreatedAt, ID: v.ID}
+	})
+	return items, total, nil
 }
 
 func (m *mockUserRepo) UpdateVenue(venue *models.Venue) error {
@@ -193,22 +228,138 @@ func (m *mockUserRepo) DeleteVenue(id int) error {
 	return nil
 }
 
-func (m *mockUserRepo) AddVenuePhoto(venueID int, imageID string) (*models.VenuePhoto, error) {
-	return &models.VenuePhoto{ID: 1, VenueID: venueID, ImageID: imageID}, nil
+// Галерея площадки хранится в памяти и ведет себя как репозиторий: лимит,
+// позиции без пропусков, одна обложка
+func (m *mockUserRepo) AddVenuePhotos(venueID int, photos []models.VenuePhoto, maxPhotos int) ([]models.VenuePhoto, error) {
+	gallery := m.venuePhotos[venueID]
+	if len(gallery)+len(photos) > maxPhotos {
+		return nil, repository.ErrGalleryFull
+	}
+	for i := range photos {
+		m.nextPhotoID++
+		photos[i].ID = m.nextPhotoID
+		photos[i].VenueID = venueID
+		photos[i].Position = len(gallery)
+		gallery = append(gallery, photos[i])
+	}
+	m.venuePhotos[venueID] = gallery
+	return photos, nil
 }
 
 func (m *mockUserRepo) GetVenuePhoto(photoID int) (*models.VenuePhoto, error) {
+	for _, gallery := range m.venuePhotos {
+		for i := range gallery {
+			if gallery[i].ID == photoID {
+				photo := gallery[i]
+				return &photo, nil
+			}
+		}
+	}
 	return nil, errNotFound
 }
 
-func (m *mockUserRepo) DeleteVenuePhoto(photoID int) error { return nil }
+func (m *mockUserRepo) GetVenuePhotos(venueID int) ([]models.VenuePhoto, error) {
+	return append([]models.VenuePhoto(nil), m.venuePhotos[venueID]...), nil
+}
+
+func (m *mockUserRepo) UpdateVenuePhoto(photo *models.VenuePhoto) error {
+	gallery := m.venuePhotos[photo.VenueID]
+	for i := range gallery {
+		if photo.IsCover && gallery[i].ID != photo.ID {
+			gallery[i].IsCover = false
+		}
+		if gallery[i].ID == photo.ID {
+			gallery[i].Caption, gallery[i].AltText, gallery[i].IsCover = photo.Caption, photo.AltText, photo.IsCover
+		}
+	}
+	return nil
+}
+
+func (m *mockUserRepo) ReorderVenuePhotos(venueID int, photoIDs []int) error {
+	gallery := m.venuePhotos[venueID]
+	if len(photoIDs) != len(gallery) {
+		return repository.ErrGalleryMismatch
+	}
+	byID := make(map[int]models.VenuePhoto, len(gallery))
+	for _, p := range gallery {
+		byID[p.ID] = p
+	}
+	reordered := make([]models.VenuePhoto, 0, len(gallery))
+	for position, id := range photoIDs {
+		p, ok := byID[id]
+		if !ok {
+			return repository.ErrGalleryMismatch
+		}
+		delete(byID, id)
+		p.Position = position
+		reordered = append(reordered, p)
+	}
+	m.venuePhotos[venueID] = reordered
+	return nil
+}
+
+func (m *mockUserRepo) DeleteVenuePhotos(venueID int, photoIDs []int) error {
+	remove := make(map[int]bool, len(photoIDs))
+	for _, id := range photoIDs {
+		remove[id] = true
+	}
+	var kept []models.VenuePhoto
+	for _, p := range m.venuePhotos[venueID] {
+		if remove[p.ID] {
+			delete(remove, p.ID)
+			continue
+		}
+		p.Position = len(kept)
+		kept = append(kept, p)
+	}
+	if len(remove) > 0 {
+		return repository.ErrGalleryMismatch
+	}
+	m.venuePhotos[venueID] = kept
+	return nil
+}
 
 func (m *mockUserRepo) CreateImage(image *models.Image) error   { return nil }
+func (m *mockUserRepo) CreateImageWithinQuota(image *models.Image, quotaBytes int64) error {
+	return nil
+}
 func (m *mockUserRepo) GetImageByID(id string) (*models.Image, error) {
 	return nil, errNotFound
 }
+func (m *mockUserRepo) UpdateImage(image *models.Image) error { return nil }
+func (m *mockUserRepo) ClaimQuarantinedImages(staleBefore time.Time, limit int) ([]models.Image, error) {
+	return nil, nil
+}
+func (m *mockUserRepo) GetImagesByIDs(ids []string) ([]models.Image, error) {
+	var result []models.Image
+	for _, id := range ids {
+		if img, ok := m.images[id]; ok {
+			result = append(result, *img)
+		}
+	}
+	return result, nil
+}
+func (m *mockUserRepo) GetImageStorageUsage(ownerUserID int) (int64, error) { return 0, nil }
+func (m *mockUserRepo) MarkUnreferencedImages(now time.Time) error { return nil }
+func (m *mockUserRepo) DeleteOrphanImages(unreferencedBefore time.Time, limit int) ([]models.Image, error) {
+	return nil, nil
+}
 func (m *mockUserRepo) DeleteImage(id string) error { return nil }
 
+func (m *mockUserRepo) CreateImageUpload(upload *models.ImageUpload, quotaBytes int64) error {
+	return nil
+}
+func (m *mockUserRepo) GetImageUpload(id string) (*models.ImageUpload, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockUserRepo) FinalizeImageUpload(uploadID string, image *models.Image, quotaBytes int64) error {
+	return nil
+}
+func (m *mockUserRepo) DeleteImageUpload(id string) error                             { return nil }
+func (m *mockUserRepo) DeleteExpiredImageUploads(expiredBefore time.Time, limit int) ([]models.ImageUpload, error) {
+	return nil, nil
+}
+
 func (m *mockUserRepo) AddVenueCategories(venueID int, categoryIDs []int) error  { return nil }
 func (m *mockUserRepo) GetVenueCategories(venueID int) ([]int, error)             { return nil, nil }
 
@@ -244,6 +395,36 @@ func (m *mockUserRepo) ListCreatorFavoriteVenues(creatorUserID int) ([]models.Ve
 	return result, nil
 }
 
+func (m *mockUserRepo) FindSimilarVenueCandidates(venueUserID, limit int) ([]models.SimilarityCandidate, error) {
+	m.similarSearchCalls++
+	return m.similarCandidates, nil
+}
+
+func (m *mockUserRepo) FindSimilarCreatorCandidates(creatorUserID, limit int) ([]models.SimilarityCandidate, error) {
+	m.similarSearchCalls++
+	return m.similarCandidates, nil
+}
+
+func (m *mockUserRepo) GetVenuesByUserIDs(userIDs []int) ([]models.Venue, error) {
+	var result []models.Venue
+	for _, id := range userIDs {
+		if v, ok := m.venues[id]; ok {
+			result = append(result, *v)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockUserRepo) GetCreatorsByUserIDs(userIDs []int) ([]models.Creator, error) {
+	var result []models.Creator
+	for _, id := range userIDs {
+		if c, ok := m.creators[id]; ok {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockUserRepo) CreateNewsletterSubscription(sub *models.NewsletterSubscription) error {
 	sub.ID = m.nextSubID
 	m.nextSubID++
@@ -288,3 +469,29 @@ func (m *mockUserRepo) ListNewsletterSubscriptions() ([]models.NewsletterSubscri
 	}
 	return result, nil
 }
+
+// paginate повторяет pagination.Apply и pagination.Count для данных в памяти:
+// порядок (created_at DESC, id DESC), элементы после курсора, limit+1
+func paginate[T any](items []T, page pagination.Params, cursorOf func(*T) pagination.Cursor) ([]T, *int64) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := cursorOf(&items[i]), cursorOf(&items[j])
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	var result []T
+	for i := range items {
+		c := cursorOf(&items[i])
+		if page.Includes(c.CreatedAt, c.ID) && len(result) <= page.Limit {
+			result = append(result, items[i])
+		}
+	}
+
+	if !page.WithTotal {
+		return result, nil
+	}
+	total := int64(len(items))
+	return result, &total
+}
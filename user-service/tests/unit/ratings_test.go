@@ -0,0 +1,170 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"shared/svcclient"
+	"testing"
+	"time"
+	"user-service/internal/clients"
+	"user-service/internal/config"
+	"user-service/internal/handlers"
+	"user-service/internal/models"
+	"user-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ─── ApplicationClient ────────────────────────────────────────────────────────
+
+func TestApplicationClient_GetRatings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/ratings" || r.URL.Query().Get("user_ids") != "3,7" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"ratings":[{"user_id":3,"average":4.5,"count":2},{"user_id":7,"average":0,"count":0}]}`))
+	}))
+	defer srv.Close()
+
+	opts := svcclient.Options{Timeout: time.Second, Retries: 0, Backoff: time.Millisecond, FailureThreshold: 3, Cooldown: time.Hour}
+	ratings, err := clients.NewApplicationClient(srv.URL, opts).GetRatings(context.Background(), []int{3, 7})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(ratings) != 2 || ratings[0] != (clients.Rating{UserID: 3, Average: 4.5, Count: 2}) {
+		t.Errorf("unexpected ratings: %+v", ratings)
+	}
+}
+
+// ─── UserService: GetRatingSummaries ──────────────────────────────────────────
+
+func TestGetRatingSummaries_ZeroForUsersWithoutReviews(t *testing.T) {
+	ratings := newMockApplicationClient()
+	ratings.ratings[3] = clients.Rating{UserID: 3, Average: 4.5, Count: 2}
+	svc := service.NewUserService(newMockUserRepo(), &config.Config{})
+	svc.SetRatingClient(ratings)
+
+	summaries, err := svc.GetRatingSummaries([]int{3, 7})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if summaries[3] != (models.RatingSummary{Average: 4.5, Count: 2}) {
+		t.Errorf("unexpected rating for user 3: %+v", summaries[3])
+	}
+	if r, ok := summaries[7]; !ok || r != (models.RatingSummary{}) {
+		t.Errorf("expected zero rating for user 7, got %+v (present=%v)", r, ok)
+	}
+}
+
+func TestGetRatingSummaries_EmptyDoesNotCallApplicationService(t *testing.T) {
+	ratings := newMockApplicationClient()
+	svc := service.NewUserService(newMockUserRepo(), &config.Config{})
+	svc.SetRatingClient(ratings)
+
+	summaries, err := svc.GetRatingSummaries(nil)
+	if err != nil || len(summaries) != 0 {
+		t.Fatalf("expected empty result, got %v, %v", summaries, err)
+	}
+	if len(ratings.calls) != 0 {
+		t.Errorf("expected no calls, got %v", ratings.calls)
+	}
+}
+
+func TestGetRatingSummaries_ApplicationServiceUnavailable(t *testing.T) {
+	ratings := newMockApplicationClient()
+	ratings.err = svcclient.ErrCircuitOpen
+	svc := service.NewUserService(newMockUserRepo(), &config.Config{})
+	svc.SetRatingClient(ratings)
+
+	if _, err := svc.GetRatingSummaries([]int{3}); !errors.Is(err, service.ErrDependencyUnavailable) {
+		t.Errorf("expected ErrDependencyUnavailable, got %v", err)
+	}
+}
+
+// ─── Публичный профиль: оценка в ответе ───────────────────────────────────────
+
+func newPublicRouter(repo *mockUserRepo, ratings *mockApplicationClient) *gin.Engine {
+	svc := service.NewUserService(repo, &config.Config{})
+	svc.SetRatingClient(ratings)
+	h := handlers.NewUserHandler(svc, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/public/creators/:user_id", h.GetPublicCreator)
+	r.GET("/public/venues", h.ListPublicVenues)
+	return r
+}
+
+func servePublic(r *gin.Engine, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestGetPublicCreator_IncludesRating(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.creators[3] = &models.Creator{ID: 1, UserID: 3, Name: "Band"}
+	ratings := newMockApplicationClient()
+	ratings.ratings[3] = clients.Rating{UserID: 3, Average: 4.67, Count: 3}
+
+	w := servePublic(newPublicRouter(repo, ratings), "/public/creators/3")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var resp handlers.PublicCreatorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Rating != (models.RatingSummary{Average: 4.67, Count: 3}) {
+		t.Errorf("unexpected rating: %+v", resp.Rating)
+	}
+}
+
+func TestListPublicVenues_MergesRatingsByUser(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.venues[5] = newVenue(1, 5, "Club")
+	repo.venues[6] = newVenue(2, 6, "Bar")
+	ratings := newMockApplicationClient()
+	ratings.ratings[6] = clients.Rating{UserID: 6, Average: 3, Count: 1}
+
+	w := servePublic(newPublicRouter(repo, ratings), "/public/venues")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Items []handlers.PublicVenueResponse `json:"items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Items) != 2 {
+		t.Fatalf("expected 2 venues, got %+v", resp.Items)
+	}
+	for _, v := range resp.Items {
+		want := models.RatingSummary{}
+		if v.UserID == 6 {
+			want = models.RatingSummary{Average: 3, Count: 1}
+		}
+		if v.Rating != want {
+			t.Errorf("venue %d: expected rating %+v, got %+v", v.UserID, want, v.Rating)
+		}
+	}
+	if len(ratings.calls) != 1 || len(ratings.calls[0]) != 2 {
+		t.Errorf("expected one batched call for both venues, got %v", ratings.calls)
+	}
+}
+
+func TestGetPublicCreator_ApplicationServiceUnavailable(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.creators[3] = &models.Creator{ID: 1, UserID: 3, Name: "Band"}
+	ratings := newMockApplicationClient()
+	ratings.err = errors.New("connection refused")
+
+	w := servePublic(newPublicRouter(repo, ratings), "/public/creators/3")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d: %s", w.Code, w.Body)
+	}
+}
@@ -2,7 +2,10 @@ package unit
 
 import (
 	"errors"
+	"fmt"
+	"shared/pagination"
 	"testing"
+	"time"
 	"user-service/internal/config"
 	"user-service/internal/models"
 	"user-service/internal/service"
@@ -384,6 +387,64 @@ func TestDeleteVenueByUserID_AccessDenied(t *testing.T) {
 	}
 }
 
+func TestListVenues_CursorPagination(t *testing.T) {
+	repo := newMockUserRepo()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id := 1; id <= 3; id++ {
+		v := newVenue(id, id, fmt.Sprintf("Venue %d", id))
+		v.CreatedAt = created
+		repo.venues[id] = v
+	}
+	svc := service.NewUserService(repo, newTestConfig())
+
+	first, err := svc.ListVenues(pagination.Params{Limit: 2, WithTotal: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(first.Items) != 2 || first.Items[0].ID != 3 || first.NextCursor == nil {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	if first.Total == nil || *first.Total != 3 {
+		t.Errorf("expected total 3, got %v", first.Total)
+	}
+
+	after, _ := pagination.Decode(*first.NextCursor)
+	second, err := svc.ListVenues(pagination.Params{Limit: 2, After: after})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != 1 || second.NextCursor != nil || second.Total != nil {
+		t.Errorf("unexpected last page: %+v", second)
+	}
+}
+
+// ─── UserService: profile lookup ─────────────────────────────────────────────
+
+func TestGetProfileByUserID_NotFound(t *testing.T) {
+	svc := service.NewUserService(newMockUserRepo(), newTestConfig())
+
+	if _, err := svc.GetCreatorByUserID(1); !errors.Is(err, service.ErrCreatorNotFound) {
+		t.Errorf("expected ErrCreatorNotFound, got %v", err)
+	}
+	if _, err := svc.GetVenueByUserID(1); !errors.Is(err, service.ErrVenueNotFound) {
+		t.Errorf("expected ErrVenueNotFound, got %v", err)
+	}
+}
+
+func TestGetProfileByUserID_DatabaseError(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.errGetProfile = errors.New("connection refused")
+	svc := service.NewUserService(repo, newTestConfig())
+
+	// Сбой БД не должен выглядеть как отсутствие профиля: клиенты по 404 решают, что роли нет
+	if _, err := svc.GetCreatorByUserID(1); err == nil || errors.Is(err, service.ErrCreatorNotFound) {
+		t.Errorf("expected a database error, got %v", err)
+	}
+	if _, err := svc.GetVenueByUserID(1); err == nil || errors.Is(err, service.ErrVenueNotFound) {
+		t.Errorf("expected a database error, got %v", err)
+	}
+}
+
 // ─── UserService: ProfileAlreadyExists ───────────────────────────────────────
 
 func TestCreateCreator_ProfileAlreadyExists(t *testing.T) {
@@ -0,0 +1,154 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+	"user-service/internal/models"
+	"user-service/internal/service"
+)
+
+// ─── SimilarService: ranking ─────────────────────────────────────────────────
+
+func TestRankSimilar_OrdersByWeightedScore(t *testing.T) {
+	matches := service.RankSimilar([]models.SimilarityCandidate{
+		{UserID: 2, SameCity: true},
+		{UserID: 3, SharedCategories: 2, UnionCategories: 2, SameCity: true, CapacityFit: true},
+		{UserID: 4, CoFavorites: 10},
+		{UserID: 5},
+	}, 10)
+
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 matches (zero score dropped), got %+v", matches)
+	}
+	if matches[0].UserID != 3 || matches[0].Score != 0.75 {
+		t.Errorf("expected user 3 with score 0.75 first, got %+v", matches[0])
+	}
+	if matches[1].UserID != 4 || matches[1].Score != 0.25 {
+		t.Errorf("expected saturated co-favorites score 0.25, got %+v", matches[1])
+	}
+	want := []string{service.SimilarReasonSharedCategories, service.SimilarReasonSameCity, service.SimilarReasonCapacity}
+	if len(matches[0].Reasons) != len(want) {
+		t.Fatalf("expected reasons %v, got %v", want, matches[0].Reasons)
+	}
+	for i := range want {
+		if matches[0].Reasons[i] != want[i] {
+			t.Errorf("expected reasons %v, got %v", want, matches[0].Reasons)
+		}
+	}
+}
+
+func TestRankSimilar_RespectsLimit(t *testing.T) {
+	matches := service.RankSimilar([]models.SimilarityCandidate{
+		{UserID: 2, SameCity: true},
+		{UserID: 3, SameCity: true},
+		{UserID: 4, SameCity: true},
+	}, 2)
+
+	if len(matches) != 2 || matches[0].UserID != 2 || matches[1].UserID != 3 {
+		t.Errorf("expected ties ordered by user id and cut to 2, got %+v", matches)
+	}
+}
+
+// ─── SimilarService: cache ───────────────────────────────────────────────────
+
+func TestSimilarVenues_NotFound(t *testing.T) {
+	svc := service.NewSimilarService(newMockUserRepo(), newTestRedis(t))
+
+	_, err := svc.SimilarVenues(999, 5)
+	if !errors.Is(err, service.ErrVenueNotFound) {
+		t.Errorf("expected ErrVenueNotFound, got %v", err)
+	}
+}
+
+func TestSimilarVenues_CachedUntilProfileUpdate(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.venues[1] = newVenue(1, 1, "Target")
+	repo.venues[2] = newVenue(2, 2, "Neighbour")
+	repo.similarCandidates = []models.SimilarityCandidate{{UserID: 2, SameCity: true}}
+
+	similar := service.NewSimilarService(repo, newTestRedis(t))
+	users := service.NewUserService(repo, newTestConfig())
+	users.SetSimilarService(similar)
+
+	result, err := similar.SimilarVenues(1, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 1 || result[0].Venue.Name != "Neighbour" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	similar.SimilarVenues(1, 5)
+	if repo.similarSearchCalls != 1 {
+		t.Fatalf("expected cached result on second call, got %d searches", repo.similarSearchCalls)
+	}
+
+	if _, err := users.UpdateVenueByUserID(2, 2, &service.UpdateVenueRequest{Name: "Renamed"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	similar.SimilarVenues(1, 5)
+	if repo.similarSearchCalls != 2 {
+		t.Errorf("expected cache to be invalidated by profile update, got %d searches", repo.similarSearchCalls)
+	}
+}
+
+func TestSimilarVenues_SkipsDeletedProfiles(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.venues[1] = newVenue(1, 1, "Target")
+	repo.similarCandidates = []models.SimilarityCandidate{{UserID: 2, SameCity: true}}
+	svc := service.NewSimilarService(repo, newTestRedis(t))
+
+	result, err := svc.SimilarVenues(1, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected missing profile to be skipped, got %+v", result)
+	}
+}
+
+func TestSimilarCreators_InvalidatedByFavoritesAndProfile(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.creators[1] = &models.Creator{ID: 1, UserID: 1, Name: "Target"}
+	repo.creators[3] = &models.Creator{ID: 3, UserID: 3, Name: "Peer"}
+	repo.similarCandidates = []models.SimilarityCandidate{{UserID: 3, SharedCategories: 1, UnionCategories: 2}}
+
+	similar := service.NewSimilarService(repo, newTestRedis(t))
+	users := service.NewUserService(repo, newTestConfig())
+	users.SetSimilarService(similar)
+
+	result, err := similar.SimilarCreators(1, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 1 || result[0].Match.Score != 0.2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if _, err := users.UpdateCreatorByUserID(3, 3, &service.UpdateCreatorRequest{Name: "Peer 2"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	similar.SimilarCreators(1, 5)
+	if repo.similarSearchCalls != 2 {
+		t.Errorf("expected cache to be invalidated by creator update, got %d searches", repo.similarSearchCalls)
+	}
+}
+
+func TestAddFavoriteVenue_InvalidatesSimilarVenues(t *testing.T) {
+	repo := newMockUserRepo()
+	repo.venues[1] = newVenue(1, 1, "Target")
+	repo.venues[2] = newVenue(2, 2, "Neighbour")
+	similar := service.NewSimilarService(repo, newTestRedis(t))
+	favorites := service.NewFavoritesService(repo)
+	favorites.SetSimilarService(similar)
+
+	similar.SimilarVenues(1, 5)
+	if err := favorites.AddFavoriteVenue(10, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	similar.SimilarVenues(1, 5)
+
+	if repo.similarSearchCalls != 2 {
+		t.Errorf("expected cache to be invalidated by favorites change, got %d searches", repo.similarSearchCalls)
+	}
+}
@@ -0,0 +1,236 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image/png"
+	"io"
+	"net"
+	"testing"
+	"user-service/internal/imaging"
+	"user-service/internal/scanner"
+)
+
+// pngWithDeclaredSize кодирует крошечный PNG и переписывает размеры в IHDR
+// (с пересчетом CRC) — так выглядит «декомпрессионная бомба» по заголовку
+func pngWithDeclaredSize(t *testing.T, w, h uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, newTestImage(2, 2)); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	data := buf.Bytes()
+	// 8 байт сигнатуры, 4 — длина, 4 — "IHDR", далее ширина и высота
+	binary.BigEndian.PutUint32(data[16:20], w)
+	binary.BigEndian.PutUint32(data[20:24], h)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+// ─── imaging.Inspect ─────────────────────────────────────────────────────────
+
+func TestInspect_DetectsFormatByContent(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, newTestImage(30, 20))
+
+	info, err := imaging.Inspect(buf.Bytes(), imaging.DefaultLimits)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Format != "png" || info.ContentType != "image/png" || info.Width != 30 || info.Height != 20 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestInspect_RejectsNonImageRenamedToJpg(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"text":       []byte("<html><body>not an image</body></html>"),
+		"executable": append([]byte("MZ\x90\x00"), make([]byte, 64)...),
+		"pdf":        []byte("%PDF-1.4\n%fake"),
+	} {
+		if _, err := imaging.Inspect(data, imaging.DefaultLimits); !errors.Is(err, imaging.ErrUnsupportedImage) {
+			t.Errorf("%s: expected ErrUnsupportedImage, got %v", name, err)
+		}
+	}
+}
+
+func TestInspect_RejectsTruncatedHeader(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, newTestImage(30, 20))
+
+	if _, err := imaging.Inspect(buf.Bytes()[:20], imaging.DefaultLimits); !errors.Is(err, imaging.ErrUnsupportedImage) {
+		t.Errorf("expected ErrUnsupportedImage, got %v", err)
+	}
+}
+
+func TestInspect_HeaderPrefixIsEnough(t *testing.T) {
+	// finalize прямой загрузки проверяет только начало файла
+	var buf bytes.Buffer
+	png.Encode(&buf, newTestImage(300, 200))
+
+	info, err := imaging.Inspect(buf.Bytes()[:64], imaging.DefaultLimits)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Width != 300 || info.Height != 200 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestInspect_RejectsDecompressionBomb(t *testing.T) {
+	data := pngWithDeclaredSize(t, 50000, 50000)
+
+	if _, err := imaging.Inspect(data, imaging.DefaultLimits); !errors.Is(err, imaging.ErrImageTooLarge) {
+		t.Errorf("expected ErrImageTooLarge, got %v", err)
+	}
+	// Каждая сторона в пределах, но площадь превышает лимит пикселей
+	data = pngWithDeclaredSize(t, 9000, 9000)
+	if _, err := imaging.Inspect(data, imaging.DefaultLimits); !errors.Is(err, imaging.ErrImageTooLarge) {
+		t.Errorf("expected ErrImageTooLarge for pixel count, got %v", err)
+	}
+}
+
+func TestProcess_RejectsCorruptPixelData(t *testing.T) {
+	// Заголовок обещает 9000×100, а данных на 2×2 — проходит Inspect, но не полное декодирование
+	data := pngWithDeclaredSize(t, 9000, 100)
+
+	if _, err := imaging.Process(data, []int{64}); !errors.Is(err, imaging.ErrUnsupportedImage) {
+		t.Errorf("expected ErrUnsupportedImage, got %v", err)
+	}
+}
+
+// ─── imaging.InspectDocument ─────────────────────────────────────────────────
+
+func TestInspectDocument(t *testing.T) {
+	const pdf = "%PDF-1.7\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n"
+
+	info, err := imaging.InspectDocument([]byte(pdf))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Format != imaging.FormatPDF || info.ContentType != "application/pdf" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	for name, data := range map[string]string{
+		"png":        "\x89PNG\r\n\x1a\n%%EOF",
+		"truncated":  "%PDF-1.7\n1 0 obj\n<< /Type /Catalog",
+		"javascript": "%PDF-1.7\n<< /OpenAction << /S /JavaScript /JS (app.alert(1)) >> >>\n%%EOF\n",
+		"launch":     "%PDF-1.7\n<< /OpenAction << /S /Launch /F (calc.exe) >> >>\n%%EOF\n",
+	} {
+		if _, err := imaging.InspectDocument([]byte(data)); !errors.Is(err, imaging.ErrUnsupportedDocument) {
+			t.Errorf("%s: expected ErrUnsupportedDocument, got %v", name, err)
+		}
+	}
+}
+
+func TestSniffDocument(t *testing.T) {
+	// Начало PDF без маркера конца: по заголовку документ принимается,
+	// полную проверку делает InspectDocument
+	info, err := imaging.SniffDocument([]byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Format != imaging.FormatPDF || info.ContentType != "application/pdf" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	if _, err := imaging.SniffDocument([]byte("\x89PNG\r\n\x1a\n")); !errors.Is(err, imaging.ErrUnsupportedDocument) {
+		t.Errorf("expected ErrUnsupportedDocument, got %v", err)
+	}
+}
+
+// ─── scanner ─────────────────────────────────────────────────────────────────
+
+func TestSignatureScanner_DetectsEICAR(t *testing.T) {
+	s := scanner.NewSignatureScanner(map[string]string{"Test-Custom": "BADBYTES"})
+
+	cases := map[string]struct {
+		data      []byte
+		clean     bool
+		signature string
+	}{
+		"clean":  {data: []byte("\x89PNG plain image"), clean: true},
+		"eicar":  {data: []byte("GIF89a" + scanner.EICARSignature), signature: "Eicar-Test-Signature"},
+		"custom": {data: []byte("xxBADBYTESxx"), signature: "Test-Custom"},
+	}
+	for name, tc := range cases {
+		verdict, err := s.Scan(context.Background(), tc.data)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if verdict.Clean != tc.clean || verdict.Signature != tc.signature {
+			t.Errorf("%s: unexpected verdict %+v", name, verdict)
+		}
+	}
+}
+
+// fakeClamd принимает одно INSTREAM-соединение, вычитывает поток и отвечает reply
+func fakeClamd(t *testing.T, reply string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		cmd := make([]byte, len("zINSTREAM\x00"))
+		if _, err := io.ReadFull(conn, cmd); err != nil {
+			return
+		}
+		size := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(conn, size); err != nil {
+				return
+			}
+			n := binary.BigEndian.Uint32(size)
+			if n == 0 {
+				break
+			}
+			if _, err := io.CopyN(io.Discard, conn, int64(n)); err != nil {
+				return
+			}
+		}
+		conn.Write([]byte(reply + "\x00"))
+	}()
+	return ln.Addr().String()
+}
+
+func TestClamdScanner_Verdicts(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 200*1024) // несколько чанков INSTREAM
+
+	verdict, err := scanner.NewClamdScanner(fakeClamd(t, "stream: OK")).Scan(context.Background(), data)
+	if err != nil || !verdict.Clean {
+		t.Errorf("expected clean verdict, got %+v, %v", verdict, err)
+	}
+
+	verdict, err = scanner.NewClamdScanner(fakeClamd(t, "stream: Win.Test.EICAR_HDB-1 FOUND")).Scan(context.Background(), data)
+	if err != nil || verdict.Clean || verdict.Signature != "Win.Test.EICAR_HDB-1" {
+		t.Errorf("expected infected verdict, got %+v, %v", verdict, err)
+	}
+
+	_, err = scanner.NewClamdScanner(fakeClamd(t, "INSTREAM size limit exceeded. ERROR")).Scan(context.Background(), data)
+	if !errors.Is(err, scanner.ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable for error reply, got %v", err)
+	}
+}
+
+func TestClamdScanner_Unreachable(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := scanner.NewClamdScanner(addr).Scan(context.Background(), []byte("x")); !errors.Is(err, scanner.ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable, got %v", err)
+	}
+}